@@ -2,12 +2,14 @@ package algorithm
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/vd09/trading-algorithm-backtesting-system/constraint"
 	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
 	"github.com/vd09/trading-algorithm-backtesting-system/logger"
 	"github.com/vd09/trading-algorithm-backtesting-system/model"
 	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
 )
 
 const (
@@ -26,6 +28,17 @@ type TradingAlgorithmMetrics struct {
 	monitor       monitor.Monitoring
 }
 
+func init() {
+	registry.RegisterAlgorithm(registry.AlgorithmEntry{
+		Name:         "combination",
+		Description:  "Acts when all adaptors agree",
+		UsesAdaptors: true,
+		New: func(ctx context.Context, p registry.Params, adaptors []indicator_adaptor.IndicatorAdaptor, m monitor.Monitoring) (TradingAlgorithm, error) {
+			return NewCombinationTradingAlgorithm(ctx, adaptors, m), nil
+		},
+	})
+}
+
 func NewCombinationTradingAlgorithm(ctx context.Context, adaptors []indicator_adaptor.IndicatorAdaptor, monitor monitor.Monitoring) *CombinationTradingAlgorithm {
 	algo := &CombinationTradingAlgorithm{
 		adaptors: adaptors,
@@ -53,7 +66,11 @@ func (ta *CombinationTradingAlgorithm) Name() string {
 	return name[:len(name)-1] // Remove the trailing underscore
 }
 
-// Evaluate evaluates the signals from all adaptors and determines the action
+// Evaluate feeds the data point to every adaptor and acts only when all of them agree.
+// Adaptors are fed even after one of them waits, so each warms up on the same bars as it would
+// alone and the combination fires on the first bar they all agree on.
+// The combined signal averages the adaptors' strengths and keeps the most conservative exits.
+// When the context carries an audit recorder, the votes behind every decision are recorded to it.
 func (ta *CombinationTradingAlgorithm) Evaluate(ctx context.Context, data model.DataPoint) (result model.TradingSignal) {
 	ctx = ta.getUpdateContext(ctx)
 	defer func() {
@@ -61,27 +78,69 @@ func (ta *CombinationTradingAlgorithm) Evaluate(ctx context.Context, data model.
 	}()
 	ta.metrics.ClosePrice.SetValue(ctx, data.Close, nil)
 
+	signals := make([]model.TradingSignal, len(ta.adaptors))
 	buyCount, sellCount := 0, 0
-	for _, adaptor := range ta.adaptors {
+	for i, adaptor := range ta.adaptors {
 		adaptor.AddDataPoint(ctx, data)
-		signal := adaptor.GetSignal(ctx)
-		switch signal {
+		signals[i] = adaptor.GetSignal(ctx)
+		switch signals[i].Action {
 		case model.Buy:
 			buyCount++
 		case model.Sell:
 			sellCount++
-		case model.Wait:
-			return model.TradingSignal{Time: data.Time, Action: model.Wait}
 		}
 	}
 
 	if buyCount == len(ta.adaptors) {
-		return model.TradingSignal{Time: data.Time, Action: model.Buy}
+		result = combineSignals(data.Time, model.Buy, signals)
 	} else if sellCount == len(ta.adaptors) {
-		return model.TradingSignal{Time: data.Time, Action: model.Sell}
+		result = combineSignals(data.Time, model.Sell, signals)
 	} else {
-		return model.TradingSignal{Time: data.Time, Action: model.Wait}
+		result = model.TradingSignal{Time: data.Time, Action: model.Wait}
 	}
+	reason := fmt.Sprintf("all %d adaptors voted %s", len(ta.adaptors), result.Action)
+	if result.Action == model.Wait {
+		reason = fmt.Sprintf("no agreement: %d buy, %d sell, %d wait", buyCount, sellCount, len(ta.adaptors)-buyCount-sellCount)
+	}
+	recordDecision(ctx, ta.logger, ta.Name(), data, result, reason, adaptorVotes(ta.adaptors, signals))
+	return result
+}
+
+// combineSignals merges agreeing signals. The stop loss and target closest to the price win,
+// so the combined position exits as soon as any adaptor would.
+func combineSignals(time int64, action model.StockAction, signals []model.TradingSignal) model.TradingSignal {
+	combined := model.TradingSignal{Time: time, Action: action}
+	for _, signal := range signals {
+		combined.Strength += signal.Strength / float64(len(signals))
+		combined.Rationale = append(combined.Rationale, signal.Rationale...)
+		if isCloserLevel(combined.StopLoss, signal.StopLoss, action == model.Buy) {
+			combined.StopLoss = signal.StopLoss
+		}
+		if isCloserLevel(combined.Target, signal.Target, action == model.Sell) {
+			combined.Target = signal.Target
+		}
+	}
+	return combined
+}
+
+// isCloserLevel reports whether candidate is closer to the price than current, for levels below
+// or above the price. A zero level is unset.
+func isCloserLevel(current, candidate float64, belowPrice bool) bool {
+	if candidate == 0 {
+		return false
+	}
+	if current == 0 {
+		return true
+	}
+	if belowPrice {
+		return candidate > current
+	}
+	return candidate < current
+}
+
+// Close releases the adaptors holding resources, such as plugin processes.
+func (ta *CombinationTradingAlgorithm) Close() error {
+	return closeAll(ta.adaptors)
 }
 
 // Function to retrieve and update the slice from context
@@ -89,27 +148,36 @@ func (ta *CombinationTradingAlgorithm) getUpdateContext(ctx context.Context) con
 	return getUpdatedCommonLabelsContext(ctx, ta.Name())
 }
 
-func CreateCombinationTradingAlgorithms(rootCtx context.Context, adaptors []indicator_adaptor.IndicatorAdaptor) []TradingAlgorithm {
+// CreateCombinationTradingAlgorithms builds a CombinationTradingAlgorithm for every non-empty subset
+// of adaptors. Each subset gets its own clones of the adaptors and registers its metrics with m.
+func CreateCombinationTradingAlgorithms(rootCtx context.Context, adaptors []indicator_adaptor.IndicatorAdaptor, m monitor.Monitoring) []TradingAlgorithm {
 	var tradingAlgorithms []TradingAlgorithm
-	var generate func(context.Context, []indicator_adaptor.IndicatorAdaptor, int)
-	promMetric := monitor.NewPrometheusMonitoring()
+	for _, subset := range AdaptorSubsets(rootCtx, adaptors) {
+		tradingAlgorithms = append(tradingAlgorithms, NewCombinationTradingAlgorithm(rootCtx, subset, m))
+	}
+	return tradingAlgorithms
+}
 
-	generate = func(ctx context.Context, currentCombination []indicator_adaptor.IndicatorAdaptor, start int) {
+// AdaptorSubsets returns every non-empty subset of adaptors, each holding its own clones of the adaptors.
+func AdaptorSubsets(rootCtx context.Context, adaptors []indicator_adaptor.IndicatorAdaptor) [][]indicator_adaptor.IndicatorAdaptor {
+	var subsets [][]indicator_adaptor.IndicatorAdaptor
+	var generate func([]indicator_adaptor.IndicatorAdaptor, int)
+
+	generate = func(currentCombination []indicator_adaptor.IndicatorAdaptor, start int) {
 		if len(currentCombination) > 0 {
-			combo := append([]indicator_adaptor.IndicatorAdaptor{}, currentCombination...)
-			clonedCombo := make([]indicator_adaptor.IndicatorAdaptor, len(combo))
-			for i, adaptor := range combo {
-				clonedCombo[i] = adaptor.Clone(getUpdatedCommonLabelsContext(ctx, ""))
+			clonedCombo := make([]indicator_adaptor.IndicatorAdaptor, len(currentCombination))
+			for i, adaptor := range currentCombination {
+				clonedCombo[i] = adaptor.Clone(getUpdatedCommonLabelsContext(rootCtx, ""))
 			}
-			tradingAlgorithms = append(tradingAlgorithms, NewCombinationTradingAlgorithm(ctx, clonedCombo, promMetric))
+			subsets = append(subsets, clonedCombo)
 		}
 		for i := start; i < len(adaptors); i++ {
-			generate(ctx, append(currentCombination, adaptors[i]), i+1)
+			generate(append(currentCombination, adaptors[i]), i+1)
 		}
 	}
 
-	generate(rootCtx, []indicator_adaptor.IndicatorAdaptor{}, 0)
-	return tradingAlgorithms
+	generate([]indicator_adaptor.IndicatorAdaptor{}, 0)
+	return subsets
 }
 
 func getUpdatedCommonLabelsContext(ctx context.Context, name string) context.Context {
@@ -2,9 +2,11 @@ package algorithm_test
 
 import (
 	"context"
+	"path/filepath"
 	"testing"
 
 	"github.com/vd09/trading-algorithm-backtesting-system/algorithm"
+	"github.com/vd09/trading-algorithm-backtesting-system/audit"
 	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
 	"github.com/vd09/trading-algorithm-backtesting-system/model"
 	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
@@ -14,8 +16,14 @@ var ctx context.Context = context.Background()
 
 // MockIndicatorAdaptor is a mock implementation of the IndicatorAdaptor interface
 type MockIndicatorAdaptor struct {
-	name   string
-	signal model.StockAction
+	name       string
+	signal     model.StockAction
+	strength   float64
+	stopLoss   float64
+	target     float64
+	dataPoints int
+	// readyAfter is the number of data points the adaptor waits for before giving its signal.
+	readyAfter int
 }
 
 func (m *MockIndicatorAdaptor) Name() string {
@@ -27,11 +35,21 @@ func (m *MockIndicatorAdaptor) Clone(ctx context.Context) indicator_adaptor.Indi
 }
 
 func (m *MockIndicatorAdaptor) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	m.dataPoints++
 	return nil
 }
 
-func (m *MockIndicatorAdaptor) GetSignal(ctx context.Context) model.StockAction {
-	return m.signal
+func (m *MockIndicatorAdaptor) GetSignal(ctx context.Context) model.TradingSignal {
+	if m.dataPoints < m.readyAfter {
+		return model.TradingSignal{Action: model.Wait}
+	}
+	return model.TradingSignal{
+		Action:    m.signal,
+		Strength:  m.strength,
+		StopLoss:  m.stopLoss,
+		Target:    m.target,
+		Rationale: []model.Rationale{{Source: m.name, Indicator: m.name, Event: "fired"}},
+	}
 }
 
 func TestCreateCombinationTradingAlgorithms(t *testing.T) {
@@ -42,7 +60,7 @@ func TestCreateCombinationTradingAlgorithms(t *testing.T) {
 	adaptor5 := &MockIndicatorAdaptor{name: "Adaptor5", signal: model.Wait}
 
 	adaptors := []indicator_adaptor.IndicatorAdaptor{adaptor1, adaptor2, adaptor3, adaptor4, adaptor5}
-	tradingAlgorithms := algorithm.CreateCombinationTradingAlgorithms(context.Background(), adaptors)
+	tradingAlgorithms := algorithm.CreateCombinationTradingAlgorithms(context.Background(), adaptors, test_utils.NewMockMetricsCollector(t))
 
 	expectedNames := []string{
 		"Adaptor1",
@@ -122,3 +140,98 @@ func TestCombinationTradingAlgorithm_EvaluateMixed(t *testing.T) {
 	// Assert that the signal is a wait signal
 	test_utils.AssertEqual(t, model.Wait, signal.Action, "Expected Wait signal")
 }
+
+func TestCombinationTradingAlgorithm_EvaluateAggregates(t *testing.T) {
+	adaptor1 := &MockIndicatorAdaptor{name: "Adaptor1", signal: model.Buy, strength: 0.6, stopLoss: 95, target: 120}
+	adaptor2 := &MockIndicatorAdaptor{name: "Adaptor2", signal: model.Buy, strength: 1, stopLoss: 97, target: 110}
+	adaptor3 := &MockIndicatorAdaptor{name: "Adaptor3", signal: model.Buy, strength: 0.8}
+
+	adaptors := []indicator_adaptor.IndicatorAdaptor{adaptor1, adaptor2, adaptor3}
+	algorithm := algorithm.NewCombinationTradingAlgorithm(context.Background(), adaptors, test_utils.NewMockMetricsCollector(t))
+
+	signal := algorithm.Evaluate(ctx, model.DataPoint{Time: 1625097600, Close: 100})
+	test_utils.AssertEqual(t, 0.8, signal.Strength, "Expected the average strength")
+	test_utils.AssertEqual(t, 97.0, signal.StopLoss, "Expected the tightest stop loss")
+	test_utils.AssertEqual(t, 110.0, signal.Target, "Expected the closest target")
+	test_utils.AssertEqual(t, 3, len(signal.Rationale), "Expected the rationale of every adaptor")
+}
+
+func TestCombinationTradingAlgorithm_EvaluateFeedsAllAdaptors(t *testing.T) {
+	adaptor1 := &MockIndicatorAdaptor{name: "Adaptor1", signal: model.Wait}
+	adaptor2 := &MockIndicatorAdaptor{name: "Adaptor2", signal: model.Buy}
+
+	adaptors := []indicator_adaptor.IndicatorAdaptor{adaptor1, adaptor2}
+	algorithm := algorithm.NewCombinationTradingAlgorithm(context.Background(), adaptors, test_utils.NewMockMetricsCollector(t))
+
+	algorithm.Evaluate(ctx, model.DataPoint{Time: 1})
+	algorithm.Evaluate(ctx, model.DataPoint{Time: 2})
+
+	// Adaptors after a waiting one still need every data point to keep their indicators up to date.
+	test_utils.AssertEqual(t, 2, adaptor2.dataPoints, "Expected every adaptor to receive every data point")
+}
+
+func TestCombinationTradingAlgorithm_FiresOnFirstAgreement(t *testing.T) {
+	adaptor1 := &MockIndicatorAdaptor{name: "Adaptor1", signal: model.Buy, readyAfter: 2}
+	adaptor2 := &MockIndicatorAdaptor{name: "Adaptor2", signal: model.Buy, readyAfter: 2}
+
+	adaptors := []indicator_adaptor.IndicatorAdaptor{adaptor1, adaptor2}
+	algorithm := algorithm.NewCombinationTradingAlgorithm(context.Background(), adaptors, test_utils.NewMockMetricsCollector(t))
+
+	// Both adaptors warm up over two bars. Adaptor2 is fed while Adaptor1 still waits on the first bar,
+	// so they agree on the second bar rather than on the third.
+	var actions []model.StockAction
+	for time := int64(1); time <= 3; time++ {
+		actions = append(actions, algorithm.Evaluate(ctx, model.DataPoint{Time: time}).Action)
+	}
+	test_utils.AssertEqual(t, []model.StockAction{model.Wait, model.Buy, model.Buy}, actions, "Expected a buy from the second bar")
+}
+
+// ValuedIndicatorAdaptor reports a fixed indicator value.
+type ValuedIndicatorAdaptor struct {
+	*MockIndicatorAdaptor
+}
+
+func (v ValuedIndicatorAdaptor) IndicatorValues() map[string]float64 {
+	return map[string]float64{"level": 42}
+}
+
+// DecisionLog keeps the recorded decisions in memory.
+type DecisionLog []audit.Decision
+
+func (l *DecisionLog) Record(decision audit.Decision) error {
+	*l = append(*l, decision)
+	return nil
+}
+
+func TestCombinationTradingAlgorithm_RecordsDecisions(t *testing.T) {
+	adaptor1 := ValuedIndicatorAdaptor{&MockIndicatorAdaptor{name: "Adaptor1", signal: model.Buy, strength: 0.6, stopLoss: 95}}
+	adaptor2 := &MockIndicatorAdaptor{name: "Adaptor2", signal: model.Buy, strength: 1}
+	algo := algorithm.NewCombinationTradingAlgorithm(context.Background(), []indicator_adaptor.IndicatorAdaptor{adaptor1, adaptor2}, test_utils.NewMockMetricsCollector(t))
+
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	journal, err := audit.OpenJournal(path, "run-1")
+	test_utils.AssertEqual(t, nil, err, "Expected the journal to open")
+	auditCtx := audit.WithRecorder(ctx, journal)
+	algo.Evaluate(auditCtx, model.DataPoint{Time: 1, Close: 100})
+	adaptor2.signal = model.Wait
+	algo.Evaluate(auditCtx, model.DataPoint{Time: 2, Close: 101})
+	algo.Evaluate(ctx, model.DataPoint{Time: 3, Close: 102})
+	test_utils.AssertEqual(t, nil, journal.Close(), "Expected the journal to close")
+
+	decisions, err := audit.Query(path, audit.Filter{RunID: "run-1", Algorithm: "Adaptor1"})
+	test_utils.AssertEqual(t, nil, err, "Expected the journal to be readable")
+	test_utils.AssertEqual(t, 2, len(decisions), "Expected a decision for every evaluation under the recorder")
+
+	buy := decisions[0]
+	test_utils.AssertEqual(t, "Adaptor1_Adaptor2", buy.Algorithm, "Expected the algorithm name")
+	test_utils.AssertEqual(t, model.StockAction(model.Buy), buy.Action, "Expected the final decision")
+	test_utils.AssertEqual(t, 95.0, buy.StopLoss, "Expected the decision's stop loss")
+	test_utils.AssertEqual(t, "all 2 adaptors voted buy", buy.Reason, "Expected the reason")
+	test_utils.AssertEqual(t, 42.0, buy.Votes[0].Values["level"], "Expected the indicator values of the adaptor")
+	test_utils.AssertEqual(t, []string{"Adaptor1 fired"}, buy.Votes[0].Rationale, "Expected the rationale of the vote")
+
+	wait := decisions[1]
+	test_utils.AssertEqual(t, model.StockAction(model.Wait), wait.Action, "Expected a wait without agreement")
+	test_utils.AssertEqual(t, model.StockAction(model.Wait), wait.Votes[1].Action, "Expected the dissenting vote")
+	test_utils.AssertEqual(t, "no agreement: 1 buy, 0 sell, 1 wait", wait.Reason, "Expected the reason")
+}
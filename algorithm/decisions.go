@@ -0,0 +1,59 @@
+package algorithm
+
+import (
+	"context"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/audit"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
+	"github.com/vd09/trading-algorithm-backtesting-system/logger"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"go.uber.org/zap"
+)
+
+// recordDecision records the result of an algorithm on a bar to the audit recorder of ctx, if any.
+// The votes are only collected when a recorder is set.
+func recordDecision(ctx context.Context, log logger.LoggerInterface, algorithm string, data model.DataPoint, result model.TradingSignal, reason string, votes func() []audit.Vote) {
+	recorder := audit.RecorderFromContext(ctx)
+	if recorder == nil {
+		return
+	}
+	decision := audit.Decision{
+		Algorithm: algorithm,
+		Time:      data.Time,
+		Close:     data.Close,
+		Action:    result.Action,
+		Strength:  result.Strength,
+		StopLoss:  result.StopLoss,
+		Target:    result.Target,
+		Reason:    reason,
+	}
+	if votes != nil {
+		decision.Votes = votes()
+	}
+	if err := recorder.Record(decision); err != nil {
+		log.Error(ctx, "Failed to record decision", zap.Error(err))
+	}
+}
+
+// adaptorVotes returns the votes of the adaptors on their signals, with their indicator values.
+func adaptorVotes(adaptors []indicator_adaptor.IndicatorAdaptor, signals []model.TradingSignal) func() []audit.Vote {
+	return func() []audit.Vote {
+		votes := make([]audit.Vote, len(adaptors))
+		for i, adaptor := range adaptors {
+			var values map[string]float64
+			if reporter, ok := adaptor.(indicator_adaptor.ValueReporter); ok {
+				values = reporter.IndicatorValues()
+			}
+			votes[i] = audit.NewVote(adaptor.Name(), signals[i], values)
+		}
+		return votes
+	}
+}
+
+// rationaleReason explains a signal by its rationale, or by fallback when it has none.
+func rationaleReason(signal model.TradingSignal, fallback string) string {
+	if len(signal.Rationale) == 0 {
+		return fallback
+	}
+	return signal.Rationale[0].String()
+}
@@ -0,0 +1,154 @@
+package algorithm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/audit"
+	"github.com/vd09/trading-algorithm-backtesting-system/constraint"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
+	"github.com/vd09/trading-algorithm-backtesting-system/logger"
+	"github.com/vd09/trading-algorithm-backtesting-system/ml"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
+	"go.uber.org/zap"
+)
+
+type MLMetrics struct {
+	SignalCounter monitor.CounterMetric
+	monitor       monitor.Monitoring
+}
+
+// MLTradingAlgorithm buys when a trained model predicts a rise with at least BuyThreshold probability
+// and sells when the probability drops to SellThreshold or lower.
+type MLTradingAlgorithm struct {
+	model         ml.Model
+	features      *ml.FeatureBuilder
+	BuyThreshold  float64
+	SellThreshold float64
+	logger        logger.LoggerInterface
+	metrics       *MLMetrics
+}
+
+// NewMLTradingAlgorithm initializes a new MLTradingAlgorithm over a model trained on the features of config.
+func NewMLTradingAlgorithm(ctx context.Context, mlModel ml.Model, config ml.FeatureConfig, buyThreshold, sellThreshold float64, monitor monitor.Monitoring) (*MLTradingAlgorithm, error) {
+	if mlModel == nil {
+		return nil, errors.New("a trained model is required")
+	}
+	if sellThreshold >= buyThreshold || sellThreshold < 0 || buyThreshold > 1 {
+		return nil, fmt.Errorf("invalid thresholds: 0 <= sell %.2f < buy %.2f <= 1 is required", sellThreshold, buyThreshold)
+	}
+
+	algo := &MLTradingAlgorithm{
+		model:         mlModel,
+		features:      ml.NewFeatureBuilder(config),
+		BuyThreshold:  buyThreshold,
+		SellThreshold: sellThreshold,
+		logger:        logger.GetLogger(),
+	}
+	algo.registerMetrics(ctx, monitor)
+	return algo, nil
+}
+
+func init() {
+	registry.RegisterAlgorithm(registry.AlgorithmEntry{
+		Name:        "ml",
+		Description: "Acts on the predictions of a model saved with ml.SaveModel",
+		Params: []registry.Param{
+			{Name: "model", Type: registry.StringParam, Description: "Path of the model file", Required: true},
+			{Name: "buy_threshold", Type: registry.FloatParam, Default: 0.6, Min: 0, Max: 1},
+			{Name: "sell_threshold", Type: registry.FloatParam, Default: 0.4, Min: 0, Max: 1},
+		},
+		Validate: func(p registry.Params) error {
+			if p.Float("sell_threshold") >= p.Float("buy_threshold") {
+				return errors.New("sell_threshold must be lower than buy_threshold")
+			}
+			return nil
+		},
+		New: func(ctx context.Context, p registry.Params, adaptors []indicator_adaptor.IndicatorAdaptor, m monitor.Monitoring) (TradingAlgorithm, error) {
+			return LoadMLTradingAlgorithm(ctx, p.String("model"), p.Float("buy_threshold"), p.Float("sell_threshold"), m)
+		},
+	})
+}
+
+// LoadMLTradingAlgorithm initializes a new MLTradingAlgorithm from a model file written by ml.SaveModel.
+func LoadMLTradingAlgorithm(ctx context.Context, path string, buyThreshold, sellThreshold float64, monitor monitor.Monitoring) (*MLTradingAlgorithm, error) {
+	mlModel, saved, err := ml.LoadModel(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewMLTradingAlgorithm(ctx, mlModel, saved.Features, buyThreshold, sellThreshold, monitor)
+}
+
+func (ta *MLTradingAlgorithm) registerMetrics(ctx context.Context, m monitor.Monitoring) {
+	ctx = ta.getUpdateContext(ctx)
+	ta.metrics = &MLMetrics{
+		monitor:       m,
+		SignalCounter: m.RegisterCounter(ctx, "ml_signals_generated", "Total number of machine learning signals generated", monitor.Labels{constraint.SIGNAL_TYPE_LABEL}),
+	}
+}
+
+// Name returns the name of the trading algorithm
+func (ta *MLTradingAlgorithm) Name() string {
+	return fmt.Sprintf("ML_%s_%.2f_%.2f", ta.model.Type(), ta.BuyThreshold, ta.SellThreshold)
+}
+
+// Evaluate builds the features of the data point and acts on the predicted probability of a rise.
+// The stop loss is placed on the SuperTrend line when it lies on the protective side of the price.
+func (ta *MLTradingAlgorithm) Evaluate(ctx context.Context, data model.DataPoint) (result model.TradingSignal) {
+	ctx = ta.getUpdateContext(ctx)
+	reason := "features warming up"
+	var votes func() []audit.Vote
+	defer func() {
+		ta.metrics.SignalCounter.IncrementCounter(ctx, monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, result.Action))
+		recordDecision(ctx, ta.logger, ta.Name(), data, result, reason, votes)
+	}()
+
+	result = model.TradingSignal{Time: data.Time, Action: model.Wait}
+	if err := ta.features.AddDataPoint(ctx, data); err != nil {
+		ta.logger.Error(ctx, "Failed to add data point to feature builder", zap.Error(err))
+		reason = "failed to build features"
+		return result
+	}
+	features, ok := ta.features.Features()
+	if !ok {
+		return result
+	}
+
+	probability := ta.model.Predict(features)
+	reason = fmt.Sprintf("rise probability %.2f, buy at %.2f, sell at %.2f", probability, ta.BuyThreshold, ta.SellThreshold)
+	votes = func() []audit.Vote {
+		return []audit.Vote{audit.NewVote(ta.model.Type(), result, map[string]float64{"probability": probability})}
+	}
+	switch {
+	case probability >= ta.BuyThreshold:
+		result.Action = model.Buy
+		result.Strength = probability
+	case probability <= ta.SellThreshold:
+		result.Action = model.Sell
+		result.Strength = 1 - probability
+	default:
+		return result
+	}
+
+	line, _ := ta.features.SuperTrend()
+	if (result.Action == model.Buy && line < data.Close) || (result.Action == model.Sell && line > data.Close) {
+		result.StopLoss = line
+		result.Target = data.Close + 2*(data.Close-line)
+	}
+	result.Rationale = []model.Rationale{{
+		Source:    ta.Name(),
+		Indicator: ta.model.Type(),
+		Event:     "predicted rise probability",
+		Values:    []float64{probability},
+	}}
+	ta.logger.Debug(ctx, "Model prediction", zap.Float64("probability", probability), zap.Int64("time", data.Time))
+	return result
+}
+
+// Function to retrieve and update the slice from context
+func (ta *MLTradingAlgorithm) getUpdateContext(ctx context.Context) context.Context {
+	return getUpdatedCommonLabelsContext(ctx, ta.Name())
+}
@@ -0,0 +1,103 @@
+package algorithm_test
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/algorithm"
+	"github.com/vd09/trading-algorithm-backtesting-system/audit"
+	"github.com/vd09/trading-algorithm-backtesting-system/ml"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+// MomentumModel predicts a rise when the last return was positive.
+type MomentumModel struct{}
+
+func (MomentumModel) Type() string {
+	return "momentum"
+}
+
+func (MomentumModel) Predict(features []float64) float64 {
+	if features[len(features)-1] > 0 {
+		return 0.8
+	}
+	return 0.1
+}
+
+func wave(bars int) []model.DataPoint {
+	data := make([]model.DataPoint, bars)
+	for i := range data {
+		close := 100 + 10*math.Sin(float64(i)/4)
+		data[i] = model.DataPoint{Time: int64(i + 1), Open: close, High: close + 1, Low: close - 1, Close: close}
+	}
+	return data
+}
+
+func TestMLTradingAlgorithm(t *testing.T) {
+	config := ml.DefaultFeatureConfig()
+	algo, err := algorithm.NewMLTradingAlgorithm(ctx, MomentumModel{}, config, 0.7, 0.2, test_utils.NewMockMetricsCollector(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	test_utils.AssertEqual(t, "ML_momentum_0.70_0.20", algo.Name(), "Name does not match")
+
+	data := wave(80)
+	var log DecisionLog
+	for i, dp := range data {
+		signal := algo.Evaluate(audit.WithRecorder(ctx, &log), dp)
+		switch {
+		case i+1 < config.WarmUp():
+			test_utils.AssertEqual(t, model.StockAction(model.Wait), signal.Action, "Expected no signal while warming up")
+		case dp.Close > data[i-1].Close:
+			test_utils.AssertEqual(t, model.StockAction(model.Buy), signal.Action, "Expected a buy on a rise")
+			test_utils.AssertEqual(t, 0.8, signal.Strength, "Buy strength does not match")
+			test_utils.AssertEqual(t, "momentum predicted rise probability at 0.8", signal.Rationale[0].String(), "Rationale does not match")
+		default:
+			test_utils.AssertEqual(t, model.StockAction(model.Sell), signal.Action, "Expected a sell on a fall")
+			test_utils.AssertEqual(t, 0.9, signal.Strength, "Sell strength does not match")
+		}
+		if signal.StopLoss != 0 {
+			test_utils.AssertTrue(t, (signal.Target-dp.Close)*(dp.Close-signal.StopLoss) > 0, "Stop loss and target are not on opposite sides of the price")
+		}
+	}
+
+	test_utils.AssertEqual(t, len(data), len(log), "Expected a decision for every bar")
+	test_utils.AssertEqual(t, "features warming up", log[0].Reason, "Reason does not match")
+	ready := log[config.WarmUp()-1]
+	test_utils.AssertTrue(t, strings.HasPrefix(ready.Reason, "rise probability "), "Expected the probability to explain the decision")
+	test_utils.AssertTrue(t, ready.Votes[0].Values["probability"] > 0, "Expected the probability as the vote's value")
+
+	if _, err := algorithm.NewMLTradingAlgorithm(ctx, MomentumModel{}, config, 0.4, 0.6, test_utils.NewMockMetricsCollector(t)); err == nil {
+		t.Fatalf("expected error for crossed thresholds")
+	}
+}
+
+func TestLoadMLTradingAlgorithm(t *testing.T) {
+	config := ml.DefaultFeatureConfig()
+	ds, err := ml.BuildDataset(context.Background(), config, wave(200), 3, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	trained, err := ml.TrainLogisticRegression(ds, ml.DefaultLogisticConfig())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "model.json")
+	if err := ml.SaveModel(path, trained, config, 3, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	algo, err := algorithm.LoadMLTradingAlgorithm(ctx, path, 0.6, 0.4, test_utils.NewMockMetricsCollector(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	actions := map[model.StockAction]int{}
+	for _, dp := range wave(200) {
+		actions[algo.Evaluate(ctx, dp).Action]++
+	}
+	test_utils.AssertTrue(t, actions[model.Buy] > 0 && actions[model.Sell] > 0, "Expected the loaded model to both buy and sell")
+}
@@ -0,0 +1,354 @@
+package algorithm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/audit"
+	"github.com/vd09/trading-algorithm-backtesting-system/constraint"
+	"github.com/vd09/trading-algorithm-backtesting-system/logger"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
+	"github.com/vd09/trading-algorithm-backtesting-system/statistics"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils"
+	"go.uber.org/zap"
+)
+
+const PAIR_STATISTIC_LABEL = "pair_statistic"
+
+type PairsMetrics struct {
+	SignalCounter monitor.CounterMetric
+	StatGauge     monitor.GaugeMetric
+	monitor       monitor.Monitoring
+}
+
+// PairsConfig configures a PairsTradingAlgorithm.
+type PairsConfig struct {
+	TickerA string
+	TickerB string
+	// Window is the number of bars the hedge ratio, the spread statistics and the cointegration test are estimated over.
+	Window int
+	// EntryZ is the spread z-score beyond which a position is opened, ExitZ the one within which it is closed
+	// and StopZ the one beyond which it is abandoned.
+	EntryZ float64
+	ExitZ  float64
+	StopZ  float64
+	// Significance is the level of the Engle-Granger test, 0.01, 0.05 or 0.10.
+	Significance float64
+	// Lags is the number of lagged differences in the ADF test.
+	Lags int
+	// RetestEvery is the number of bars between cointegration tests.
+	RetestEvery int
+}
+
+// DefaultPairsConfig returns the usual settings for trading the spread of a against b.
+func DefaultPairsConfig(tickerA, tickerB string) PairsConfig {
+	return PairsConfig{
+		TickerA:      tickerA,
+		TickerB:      tickerB,
+		Window:       60,
+		EntryZ:       2,
+		ExitZ:        0.5,
+		StopZ:        4,
+		Significance: 0.05,
+		Lags:         1,
+		RetestEvery:  20,
+	}
+}
+
+func (c PairsConfig) validate() error {
+	if c.TickerA == "" || c.TickerB == "" || c.TickerA == c.TickerB {
+		return errors.New("two different tickers are required")
+	}
+	if c.Window < 20 {
+		return fmt.Errorf("window %d must be at least 20 bars", c.Window)
+	}
+	if c.ExitZ < 0 || c.ExitZ >= c.EntryZ || c.EntryZ >= c.StopZ {
+		return fmt.Errorf("invalid bands: 0 <= exit %.2f < entry %.2f < stop %.2f is required", c.ExitZ, c.EntryZ, c.StopZ)
+	}
+	if _, err := statistics.EngleGrangerCritical.At(c.Significance); err != nil {
+		return err
+	}
+	if c.Lags < 0 || c.Lags > c.Window/4 {
+		return fmt.Errorf("lags %d must be between 0 and a quarter of the window", c.Lags)
+	}
+	if c.RetestEvery < 1 {
+		return errors.New("retest_every must be at least 1")
+	}
+	return nil
+}
+
+// PairsTradingAlgorithm trades the spread between the log prices of two cointegrated instruments.
+// Over a rolling window it regresses log(A) on log(B) for the hedge ratio and measures the z-score of the latest
+// residual. When the pair passes the Engle-Granger test and the spread stretches past the entry band, it sells the
+// rich leg and buys the cheap one, and it unwinds both legs once the spread reverts inside the exit band, diverges
+// past the stop band or the cointegration breaks down. After a position is closed, the spread has to return
+// inside the entry band before it can be opened again.
+//
+// Signals describe the spread: Buy is long A and short B, Sell is short A and long B, and the closing signal of a
+// position carries the opposite action. Their legs list A first.
+type PairsTradingAlgorithm struct {
+	config       PairsConfig
+	logA         []float64
+	logB         []float64
+	lastTime     int64
+	sinceTest    int
+	cointegrated bool
+	statistic    float64
+	zScore       float64
+	// position is the open spread position, Wait when flat, with the legs it was opened with.
+	position model.StockAction
+	legs     []model.Leg
+	logger   logger.LoggerInterface
+	metrics  *PairsMetrics
+}
+
+func init() {
+	defaults := DefaultPairsConfig("", "")
+	registry.RegisterPairAlgorithm(registry.PairAlgorithmEntry{
+		Name:        "pairs",
+		Description: "Trades the spread of two cointegrated instruments when its z-score stretches past the entry band",
+		Params: []registry.Param{
+			{Name: "ticker_a", Type: registry.StringParam, Required: true},
+			{Name: "ticker_b", Type: registry.StringParam, Description: "Ticker the first one is hedged with", Required: true},
+			{Name: "window", Type: registry.IntParam, Description: "Bars the hedge ratio, spread and cointegration are estimated over", Default: defaults.Window, Min: 20, Max: 100000},
+			{Name: "entry_z", Type: registry.FloatParam, Default: defaults.EntryZ, Min: 0, Max: 100},
+			{Name: "exit_z", Type: registry.FloatParam, Default: defaults.ExitZ, Min: 0, Max: 100},
+			{Name: "stop_z", Type: registry.FloatParam, Default: defaults.StopZ, Min: 0, Max: 100},
+			{Name: "significance", Type: registry.FloatParam, Description: "Level of the Engle-Granger test: 0.01, 0.05 or 0.10", Default: defaults.Significance, Min: 0.01, Max: 0.1},
+			{Name: "lags", Type: registry.IntParam, Description: "Lagged differences in the ADF test", Default: defaults.Lags, Min: 0, Max: 100},
+			{Name: "retest_every", Type: registry.IntParam, Description: "Bars between cointegration tests", Default: defaults.RetestEvery, Min: 1, Max: 100000},
+		},
+		Validate: func(p registry.Params) error {
+			if p.String("ticker_a") == p.String("ticker_b") {
+				return errors.New("ticker_a and ticker_b must differ")
+			}
+			if p.Float("exit_z") >= p.Float("entry_z") || p.Float("entry_z") >= p.Float("stop_z") {
+				return errors.New("exit_z, entry_z and stop_z must be increasing")
+			}
+			if _, err := statistics.EngleGrangerCritical.At(p.Float("significance")); err != nil {
+				return &registry.ParamError{Param: "significance", Msg: err.Error()}
+			}
+			return nil
+		},
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (PairTradingAlgorithm, error) {
+			config := DefaultPairsConfig(p.String("ticker_a"), p.String("ticker_b"))
+			config.Window = p.Int("window")
+			config.EntryZ = p.Float("entry_z")
+			config.ExitZ = p.Float("exit_z")
+			config.StopZ = p.Float("stop_z")
+			config.Significance = p.Float("significance")
+			config.Lags = p.Int("lags")
+			config.RetestEvery = p.Int("retest_every")
+			return NewPairsTradingAlgorithm(ctx, config, m)
+		},
+	})
+}
+
+// NewPairsTradingAlgorithm initializes a new PairsTradingAlgorithm.
+func NewPairsTradingAlgorithm(ctx context.Context, config PairsConfig, monitor monitor.Monitoring) (*PairsTradingAlgorithm, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	algo := &PairsTradingAlgorithm{
+		config:   config,
+		position: model.Wait,
+		logger:   logger.GetLogger(),
+	}
+	algo.registerMetrics(ctx, monitor)
+	return algo, nil
+}
+
+func (ta *PairsTradingAlgorithm) registerMetrics(ctx context.Context, m monitor.Monitoring) {
+	ctx = ta.getUpdateContext(ctx)
+	ta.metrics = &PairsMetrics{
+		monitor:       m,
+		SignalCounter: m.RegisterCounter(ctx, "pairs_signals_generated", "Total number of pairs trading signals generated", monitor.Labels{constraint.SIGNAL_TYPE_LABEL}),
+		StatGauge:     m.RegisterGauge(ctx, "pairs_statistics", "Current z-score, hedge ratio and cointegration statistic of the pair", monitor.Labels{PAIR_STATISTIC_LABEL}),
+	}
+}
+
+// Name returns the name of the trading algorithm
+func (ta *PairsTradingAlgorithm) Name() string {
+	c := ta.config
+	return fmt.Sprintf("Pairs_%s_%s_%d_%.2f_%.2f", c.TickerA, c.TickerB, c.Window, c.EntryZ, c.ExitZ)
+}
+
+// Cointegrated reports whether the pair passed its latest cointegration test, and the test statistic.
+func (ta *PairsTradingAlgorithm) Cointegrated() (bool, float64) {
+	return ta.cointegrated, ta.statistic
+}
+
+// EvaluatePair takes the bars of A and B for the same time and returns the signal for the spread.
+func (ta *PairsTradingAlgorithm) EvaluatePair(ctx context.Context, a, b model.DataPoint) (result model.TradingSignal) {
+	ctx = ta.getUpdateContext(ctx)
+	// reason explains the waits the rationale of the result does not, such as skipped bars.
+	var reason string
+	defer func() {
+		ta.metrics.SignalCounter.IncrementCounter(ctx, monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, result.Action))
+		if reason == "" {
+			reason = rationaleReason(result, fmt.Sprintf("z-score %.2f, no entry or exit", ta.zScore))
+		}
+		recordDecision(ctx, ta.logger, ta.Name(), a, result, reason, func() []audit.Vote {
+			values := map[string]float64{"z_score": ta.zScore, "adf_statistic": ta.statistic}
+			return []audit.Vote{audit.NewVote("Spread z-score", result, values)}
+		})
+	}()
+
+	wait := model.TradingSignal{Time: a.Time, Action: model.Wait}
+	if a.Time != b.Time || a.Time <= ta.lastTime || a.Close <= 0 || b.Close <= 0 {
+		ta.logger.Warn(ctx, "Skipping unaligned or invalid pair of bars", zap.Int64("time_a", a.Time), zap.Int64("time_b", b.Time))
+		reason = "unaligned or invalid pair of bars"
+		return wait
+	}
+	ta.lastTime = a.Time
+	ta.logA = utils.AppendWindow(ta.logA, math.Log(a.Close), ta.config.Window)
+	ta.logB = utils.AppendWindow(ta.logB, math.Log(b.Close), ta.config.Window)
+	if len(ta.logA) < ta.config.Window {
+		reason = fmt.Sprintf("filling the window of %d bars", ta.config.Window)
+		return wait
+	}
+
+	if ta.sinceTest == 0 {
+		ta.retest(ctx)
+	}
+	ta.sinceTest = (ta.sinceTest + 1) % ta.config.RetestEvery
+
+	intercept, hedge, residuals, err := statistics.LinearFit(ta.logB, ta.logA)
+	if err != nil {
+		ta.logger.Warn(ctx, "Failed to estimate the hedge ratio", zap.Error(err))
+		reason = "failed to estimate the hedge ratio"
+		return wait
+	}
+	_, std := statistics.MeanStd(residuals)
+	if std == 0 {
+		reason = "spread does not vary"
+		return wait
+	}
+	previous := ta.zScore
+	ta.zScore = residuals[len(residuals)-1] / std
+	ta.metrics.StatGauge.SetGauge(ctx, ta.zScore, monitor.NewTagsKV(PAIR_STATISTIC_LABEL, "z_score"))
+	ta.metrics.StatGauge.SetGauge(ctx, hedge, monitor.NewTagsKV(PAIR_STATISTIC_LABEL, "hedge_ratio"))
+
+	z, c := ta.zScore, ta.config
+	zRationale := func(event string, band float64) model.Rationale {
+		return model.Rationale{
+			Source:    ta.Name(),
+			Indicator: "Spread z-score",
+			Event:     event,
+			Reference: fmt.Sprintf("%.2f", band),
+			Values:    []float64{previous, z},
+		}
+	}
+
+	if ta.position == model.Wait {
+		// Only a cross from inside the entry band opens a position, so a spread still stretched after a stop or a
+		// cointegration exit has to revert first.
+		crossed := math.Abs(previous) < c.EntryZ && math.Abs(z) >= c.EntryZ
+		if !ta.cointegrated || !crossed || math.Abs(z) >= c.StopZ {
+			reason = fmt.Sprintf("z-score %.2f, no entry", z)
+			if !ta.cointegrated {
+				reason += ", not cointegrated"
+			}
+			return wait
+		}
+		action, rationale := model.StockAction(model.Buy), zRationale("crossed below entry band", -c.EntryZ)
+		if z > 0 {
+			action, rationale = model.Sell, zRationale("crossed above entry band", c.EntryZ)
+		}
+		ta.position = action
+		ta.legs = ta.entryLegs(action, hedge)
+		ta.logger.Info(ctx, "Opening spread position", zap.String("action", string(action)), zap.Float64("z_score", z), zap.Float64("hedge_ratio", hedge), zap.Float64("intercept", intercept))
+		return model.TradingSignal{
+			Time:     a.Time,
+			Action:   action,
+			Strength: math.Min(1, math.Abs(z)/c.StopZ),
+			Legs:     ta.legs,
+			Rationale: []model.Rationale{
+				rationale,
+				{Source: ta.Name(), Indicator: "Engle-Granger ADF", Event: "below critical value", Reference: fmt.Sprintf("%.2f", ta.critical()), Values: []float64{ta.statistic}},
+				{Source: ta.Name(), Indicator: "Hedge ratio", Event: "estimated", Values: []float64{hedge}},
+			},
+		}
+	}
+
+	// The spread moves against a long position when the z-score falls and against a short one when it rises.
+	against, exitBand := z, c.ExitZ
+	if ta.position == model.Buy {
+		against, exitBand = -z, -c.ExitZ
+	}
+	var rationale model.Rationale
+	switch {
+	case against <= c.ExitZ:
+		rationale = zRationale("reverted inside exit band", exitBand)
+	case against >= c.StopZ:
+		rationale = zRationale("diverged past stop band", math.Copysign(c.StopZ, z))
+	case !ta.cointegrated:
+		rationale = model.Rationale{Source: ta.Name(), Indicator: "Engle-Granger ADF", Event: "rose above critical value", Reference: fmt.Sprintf("%.2f", ta.critical()), Values: []float64{ta.statistic}}
+	default:
+		return wait
+	}
+	return ta.close(ctx, a.Time, rationale)
+}
+
+// retest runs the Engle-Granger test over the window.
+func (ta *PairsTradingAlgorithm) retest(ctx context.Context) {
+	result, err := statistics.EngleGranger(ta.logA, ta.logB, ta.config.Lags)
+	if err != nil {
+		ta.logger.Warn(ctx, "Failed to test the pair for cointegration", zap.Error(err))
+		ta.cointegrated = false
+		return
+	}
+	ta.statistic = result.Statistic
+	ta.cointegrated = result.Cointegrated(ta.config.Significance)
+	ta.metrics.StatGauge.SetGauge(ctx, result.Statistic, monitor.NewTagsKV(PAIR_STATISTIC_LABEL, "adf_statistic"))
+}
+
+// close unwinds the legs the position was opened with.
+func (ta *PairsTradingAlgorithm) close(ctx context.Context, time int64, rationale model.Rationale) model.TradingSignal {
+	legs := make([]model.Leg, len(ta.legs))
+	for i, leg := range ta.legs {
+		legs[i] = model.Leg{Ticker: leg.Ticker, Action: opposite(leg.Action), Weight: leg.Weight}
+	}
+	action := opposite(ta.position)
+	ta.logger.Info(ctx, "Closing spread position", zap.String("reason", rationale.Event), zap.Float64("z_score", ta.zScore))
+	ta.position, ta.legs = model.Wait, nil
+	return model.TradingSignal{Time: time, Action: action, Strength: 1, Legs: legs, Rationale: []model.Rationale{rationale}}
+}
+
+// entryLegs splits the notional so that the B leg is worth hedge times the A leg; with a negative hedge ratio
+// both legs go the same way.
+func (ta *PairsTradingAlgorithm) entryLegs(action model.StockAction, hedge float64) []model.Leg {
+	actionB := opposite(action)
+	if hedge < 0 {
+		actionB = action
+	}
+	total := 1 + math.Abs(hedge)
+	return []model.Leg{
+		{Ticker: ta.config.TickerA, Action: action, Weight: 1 / total},
+		{Ticker: ta.config.TickerB, Action: actionB, Weight: math.Abs(hedge) / total},
+	}
+}
+
+func (ta *PairsTradingAlgorithm) critical() float64 {
+	critical, _ := statistics.EngleGrangerCritical.At(ta.config.Significance)
+	return critical
+}
+
+func opposite(action model.StockAction) model.StockAction {
+	switch action {
+	case model.Buy:
+		return model.Sell
+	case model.Sell:
+		return model.Buy
+	}
+	return model.Wait
+}
+
+// Function to retrieve and update the slice from context
+func (ta *PairsTradingAlgorithm) getUpdateContext(ctx context.Context) context.Context {
+	return getUpdatedCommonLabelsContext(ctx, ta.Name())
+}
@@ -0,0 +1,103 @@
+package algorithm_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/algorithm"
+	"github.com/vd09/trading-algorithm-backtesting-system/audit"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+// cointegratedPair returns bars of A and B where log(A) follows 1.2·log(B) up to a small bounded spread,
+// which jumps up by the shocks at their bars.
+func cointegratedPair(bars int, shocks map[int]float64) ([]model.DataPoint, []model.DataPoint) {
+	random := rand.New(rand.NewSource(11))
+	logB := math.Log(50)
+	a, b := make([]model.DataPoint, bars), make([]model.DataPoint, bars)
+	for i := 0; i < bars; i++ {
+		logB += 0.01 * random.NormFloat64()
+		spread := 0.01*(random.Float64()-0.5) + shocks[i]
+		time := int64(i+1) * 60
+		b[i] = model.DataPoint{Time: time, Close: math.Exp(logB)}
+		a[i] = model.DataPoint{Time: time, Close: math.Exp(0.3 + 1.2*logB + spread)}
+	}
+	return a, b
+}
+
+func TestPairsTradingAlgorithm(t *testing.T) {
+	algo, err := algorithm.NewPairsTradingAlgorithm(ctx, algorithm.DefaultPairsConfig("A", "B"), test_utils.NewMockMetricsCollector(t))
+	test_utils.AssertEqual(t, nil, err, "Default config should be valid")
+
+	a, b := cointegratedPair(150, map[int]float64{100: 0.01})
+	var signals []model.TradingSignal
+	var log DecisionLog
+	for i := range a {
+		if signal := algo.EvaluatePair(audit.WithRecorder(ctx, &log), a[i], b[i]); signal.Action != model.Wait {
+			signals = append(signals, signal)
+		}
+	}
+	cointegrated, _ := algo.Cointegrated()
+	test_utils.AssertTrue(t, cointegrated, "The pair should be cointegrated")
+	test_utils.AssertTrue(t, len(signals) >= 2, "The shock should open and close a position")
+
+	entry, exit := signals[0], signals[1]
+	test_utils.AssertEqual(t, int64(101*60), entry.Time, "The position should open on the shock")
+	test_utils.AssertEqual(t, model.StockAction(model.Sell), entry.Action, "A rich spread should be sold")
+	test_utils.AssertEqual(t, "crossed above entry band", entry.Rationale[0].Event, "The entry should be explained by the z-score")
+	test_utils.AssertEqual(t, []model.Leg{{Ticker: "A", Action: model.Sell, Weight: entry.Legs[0].Weight}, {Ticker: "B", Action: model.Buy, Weight: entry.Legs[1].Weight}}, entry.Legs, "A should be sold against B")
+	test_utils.AssertTrue(t, math.Abs(entry.Legs[1].Weight/entry.Legs[0].Weight-1.2) < 0.15, "The legs should follow the hedge ratio")
+
+	test_utils.AssertEqual(t, model.StockAction(model.Buy), exit.Action, "The position should be closed by buying the spread back")
+	test_utils.AssertEqual(t, "reverted inside exit band", exit.Rationale[0].Event, "The exit should be explained by the reversion")
+	test_utils.AssertEqual(t, model.StockAction(model.Buy), exit.Legs[0].Action, "A should be bought back")
+	test_utils.AssertEqual(t, model.StockAction(model.Sell), exit.Legs[1].Action, "B should be sold back")
+	test_utils.AssertEqual(t, entry.Legs[1].Weight, exit.Legs[1].Weight, "The exit should unwind the entry weights")
+
+	test_utils.AssertEqual(t, len(a), len(log), "Expected a decision for every pair of bars")
+	test_utils.AssertEqual(t, "filling the window of 60 bars", log[0].Reason, "Reason does not match")
+	opened := log[100]
+	test_utils.AssertEqual(t, entry.Rationale[0].String(), opened.Reason, "Expected the entry to be explained by its rationale")
+	test_utils.AssertTrue(t, opened.Votes[0].Values["z_score"] > 0, "Expected the z-score as the vote's value")
+
+	unaligned := algo.EvaluatePair(ctx, model.DataPoint{Time: 1e6, Close: 1}, model.DataPoint{Time: 1e6 + 1, Close: 1})
+	test_utils.AssertEqual(t, model.StockAction(model.Wait), unaligned.Action, "Unaligned bars should be skipped")
+}
+
+func TestPairsTradingAlgorithmReentersOnCross(t *testing.T) {
+	algo, err := algorithm.NewPairsTradingAlgorithm(ctx, algorithm.DefaultPairsConfig("A", "B"), test_utils.NewMockMetricsCollector(t))
+	test_utils.AssertEqual(t, nil, err, "Default config should be valid")
+
+	// The spread opens a position, blows through the stop band and then stays stretched past the entry band.
+	a, b := cointegratedPair(150, map[int]float64{100: 0.01, 101: 0.04, 102: 0.026, 103: 0.026, 104: 0.026, 105: 0.026})
+	var signals []model.TradingSignal
+	for i := range a {
+		if signal := algo.EvaluatePair(ctx, a[i], b[i]); signal.Action != model.Wait {
+			signals = append(signals, signal)
+		}
+	}
+	test_utils.AssertTrue(t, len(signals) >= 2, "The shock should open and stop out a position")
+	test_utils.AssertEqual(t, int64(101*60), signals[0].Time, "The position should open on the shock")
+	test_utils.AssertEqual(t, "diverged past stop band", signals[1].Rationale[0].Event, "The position should be stopped out")
+	for _, signal := range signals[2:] {
+		test_utils.AssertTrue(t, signal.Time > 106*60, "No position should open while the spread stays stretched after the stop")
+	}
+}
+
+func TestPairsConfigValidation(t *testing.T) {
+	config := algorithm.DefaultPairsConfig("A", "A")
+	_, err := algorithm.NewPairsTradingAlgorithm(ctx, config, test_utils.NewMockMetricsCollector(t))
+	test_utils.AssertTrue(t, err != nil, "The same ticker twice should be rejected")
+
+	config = algorithm.DefaultPairsConfig("A", "B")
+	config.ExitZ = 3
+	_, err = algorithm.NewPairsTradingAlgorithm(ctx, config, test_utils.NewMockMetricsCollector(t))
+	test_utils.AssertTrue(t, err != nil, "An exit band outside the entry band should be rejected")
+
+	config = algorithm.DefaultPairsConfig("A", "B")
+	config.Significance = 0.2
+	_, err = algorithm.NewPairsTradingAlgorithm(ctx, config, test_utils.NewMockMetricsCollector(t))
+	test_utils.AssertTrue(t, err != nil, "An unsupported significance should be rejected")
+}
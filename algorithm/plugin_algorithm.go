@@ -0,0 +1,114 @@
+package algorithm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/constraint"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
+	"github.com/vd09/trading-algorithm-backtesting-system/logger"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/plugin"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
+	"go.uber.org/zap"
+)
+
+type PluginMetrics struct {
+	SignalCounter  monitor.CounterMetric
+	RestartCounter monitor.CounterMetric
+	monitor        monitor.Monitoring
+}
+
+// PluginTradingAlgorithm is a trading algorithm running as an external plugin process.
+// A plugin which fails for good only waits from then on.
+type PluginTradingAlgorithm struct {
+	client  *plugin.Client
+	failed  bool
+	logger  logger.LoggerInterface
+	metrics *PluginMetrics
+}
+
+func init() {
+	registry.RegisterAlgorithm(registry.AlgorithmEntry{
+		Name:        "plugin",
+		Description: "Runs an external executable speaking the JSON lines plugin protocol",
+		Params:      registry.PluginParams(),
+		New: func(ctx context.Context, p registry.Params, adaptors []indicator_adaptor.IndicatorAdaptor, m monitor.Monitoring) (TradingAlgorithm, error) {
+			return NewPluginTradingAlgorithm(ctx, registry.PluginConfig(p), m)
+		},
+	})
+}
+
+// NewPluginTradingAlgorithm starts the plugin and initializes a new PluginTradingAlgorithm instance.
+func NewPluginTradingAlgorithm(ctx context.Context, config plugin.Config, monitor monitor.Monitoring) (*PluginTradingAlgorithm, error) {
+	client, err := plugin.Start(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	algo := &PluginTradingAlgorithm{
+		client: client,
+		logger: logger.GetLogger(),
+	}
+	algo.registerMetrics(ctx, monitor)
+	return algo, nil
+}
+
+func (ta *PluginTradingAlgorithm) registerMetrics(ctx context.Context, m monitor.Monitoring) {
+	ctx = ta.getUpdateContext(ctx)
+	ta.metrics = &PluginMetrics{
+		monitor:        m,
+		SignalCounter:  m.RegisterCounter(ctx, "plugin_algorithm_signals_generated", "Total number of plugin algorithm signals generated", monitor.Labels{constraint.SIGNAL_TYPE_LABEL}),
+		RestartCounter: m.RegisterCounter(ctx, "plugin_algorithm_restarts", "Total number of plugin algorithm restarts", nil),
+	}
+}
+
+// Name returns the name of the trading algorithm
+func (ta *PluginTradingAlgorithm) Name() string {
+	return fmt.Sprintf("Plugin_%s", ta.client.Name())
+}
+
+// Evaluate sends the data point to the plugin and returns its signal.
+func (ta *PluginTradingAlgorithm) Evaluate(ctx context.Context, data model.DataPoint) model.TradingSignal {
+	return ta.EvaluateBatch(ctx, []model.DataPoint{data})[0]
+}
+
+// EvaluateBatch sends the data points to the plugin in batches of the configured size.
+func (ta *PluginTradingAlgorithm) EvaluateBatch(ctx context.Context, data []model.DataPoint) []model.TradingSignal {
+	ctx = ta.getUpdateContext(ctx)
+	signals := make([]model.TradingSignal, len(data))
+	for i, dp := range data {
+		signals[i] = model.TradingSignal{Time: dp.Time, Action: model.Wait}
+	}
+
+	answered := 0
+	if !ta.failed {
+		received, err := ta.client.Send(ctx, data)
+		answered = len(received)
+		ta.metrics.RestartCounter.SetValue(ctx, float64(ta.client.Restarts()), nil)
+		if err != nil {
+			ta.logger.Error(ctx, "Plugin failed, waiting from now on", zap.Int("answered", len(received)), zap.Error(err))
+			ta.failed = true
+		}
+		copy(signals, received)
+	}
+	for i, signal := range signals {
+		ta.metrics.SignalCounter.IncrementCounter(ctx, monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, signal.Action))
+		reason := rationaleReason(signal, "plugin signal")
+		if ta.failed && i >= answered {
+			reason = "plugin failed"
+		}
+		recordDecision(ctx, ta.logger, ta.Name(), data[i], signal, reason, nil)
+	}
+	return signals
+}
+
+// Close shuts the plugin process down.
+func (ta *PluginTradingAlgorithm) Close() error {
+	return ta.client.Close()
+}
+
+// Function to retrieve and update the slice from context
+func (ta *PluginTradingAlgorithm) getUpdateContext(ctx context.Context) context.Context {
+	return getUpdatedCommonLabelsContext(ctx, ta.Name())
+}
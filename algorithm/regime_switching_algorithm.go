@@ -0,0 +1,191 @@
+package algorithm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/audit"
+	"github.com/vd09/trading-algorithm-backtesting-system/constraint"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
+	"github.com/vd09/trading-algorithm-backtesting-system/logger"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/regime"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
+	"go.uber.org/zap"
+)
+
+const (
+	REGIME_LABEL = "regime"
+)
+
+// RegimeRoute assigns an algorithm to the bars of a regime.
+type RegimeRoute struct {
+	// Regime selects the bars the algorithm is listened to on, empty fields match any value
+	// so a route with an empty regime also matches Unknown.
+	Regime    regime.Regime
+	Algorithm TradingAlgorithm
+}
+
+type RegimeSwitchingMetrics struct {
+	SignalCounter monitor.CounterMetric
+	monitor       monitor.Monitoring
+}
+
+// RegimeSwitchingTradingAlgorithm classifies every bar and returns the signal of the first route matching its regime.
+// Every routed algorithm is evaluated on every bar, so their indicators stay warm when the regime changes.
+type RegimeSwitchingTradingAlgorithm struct {
+	classifier regime.Classifier
+	routes     []RegimeRoute
+	current    regime.Regime
+	logger     logger.LoggerInterface
+	metrics    *RegimeSwitchingMetrics
+}
+
+func init() {
+	registry.RegisterAlgorithm(registry.AlgorithmEntry{
+		Name:         "regime_switching",
+		Description:  "Follows the first adaptor whose regime matches the market regime of the bar",
+		UsesAdaptors: true,
+		Params: []registry.Param{
+			{Name: "regimes", Type: registry.StringListParam, Description: "Regime of every adaptor in order, e.g. trend/low_volatility, range/any or any", Required: true},
+			{Name: "classifier", Type: registry.StringParam, Default: "indicator", Choices: []string{"indicator", "hmm"}},
+			{Name: "adx_period", Type: registry.IntParam, Description: "ADX period of the indicator classifier", Default: 14, Min: 1, Max: 1000},
+			{Name: "trend_threshold", Type: registry.FloatParam, Description: "ADX from which the indicator classifier sees a trend", Default: 25.0, Min: 0, Max: 99},
+			{Name: "volatility_period", Type: registry.IntParam, Description: "Log returns the realised volatility of the indicator classifier is taken over", Default: 20, Min: 2, Max: 1000},
+			{Name: "percentile_window", Type: registry.IntParam, Description: "Past volatilities the current one is ranked against", Default: 100, Min: 2, Max: 100000},
+			{Name: "high_percentile", Type: registry.FloatParam, Description: "Volatility percentile rank, from 0 to 1, from which volatility is high", Default: 0.8, Min: 0.01, Max: 0.99},
+			{Name: "states", Type: registry.IntParam, Description: "Hidden states of the hmm classifier", Default: 2, Min: 2, Max: 10},
+			{Name: "train_size", Type: registry.IntParam, Description: "Log returns the hmm classifier is fitted on", Default: 250, Min: 10, Max: 100000},
+			{Name: "refit_every", Type: registry.IntParam, Description: "Bars between refits of the hmm classifier, 0 fits once", Default: 50, Min: 0, Max: 100000},
+			{Name: "trend_ratio", Type: registry.FloatParam, Description: "Mean to standard deviation ratio from which a hidden state is trending", Default: 0.5, Min: 0, Max: 100},
+		},
+		Validate: func(p registry.Params) error {
+			for _, text := range p.Strings("regimes") {
+				if _, err := regime.ParseRegime(text); err != nil {
+					return &registry.ParamError{Param: "regimes", Msg: err.Error()}
+				}
+			}
+			return nil
+		},
+		New: func(ctx context.Context, p registry.Params, adaptors []indicator_adaptor.IndicatorAdaptor, m monitor.Monitoring) (TradingAlgorithm, error) {
+			regimes := p.Strings("regimes")
+			if len(regimes) != len(adaptors) {
+				return nil, fmt.Errorf("%d regimes given for %d adaptors, every adaptor needs one", len(regimes), len(adaptors))
+			}
+			var classifier regime.Classifier = regime.NewIndicatorClassifier(p.Int("adx_period"), p.Float("trend_threshold"), p.Int("volatility_period"), p.Int("percentile_window"), p.Float("high_percentile"))
+			if p.String("classifier") == "hmm" {
+				classifier = regime.NewHMMClassifier(p.Int("states"), p.Int("train_size"), p.Int("refit_every"), p.Float("trend_ratio"))
+			}
+			routes := make([]RegimeRoute, len(adaptors))
+			for i, adaptor := range adaptors {
+				pattern, err := regime.ParseRegime(regimes[i])
+				if err != nil {
+					return nil, err
+				}
+				routes[i] = RegimeRoute{
+					Regime:    pattern,
+					Algorithm: NewCombinationTradingAlgorithm(ctx, []indicator_adaptor.IndicatorAdaptor{adaptor}, m),
+				}
+			}
+			return NewRegimeSwitchingTradingAlgorithm(ctx, classifier, routes, m)
+		},
+	})
+}
+
+// NewRegimeSwitchingTradingAlgorithm initializes a new RegimeSwitchingTradingAlgorithm, routes are matched in order.
+func NewRegimeSwitchingTradingAlgorithm(ctx context.Context, classifier regime.Classifier, routes []RegimeRoute, monitor monitor.Monitoring) (*RegimeSwitchingTradingAlgorithm, error) {
+	if classifier == nil {
+		return nil, errors.New("a regime classifier is required")
+	}
+	if len(routes) == 0 {
+		return nil, errors.New("at least one regime route is required")
+	}
+	for i, route := range routes {
+		if route.Algorithm == nil {
+			return nil, fmt.Errorf("route %d has no algorithm", i+1)
+		}
+	}
+
+	algo := &RegimeSwitchingTradingAlgorithm{
+		classifier: classifier,
+		routes:     routes,
+		logger:     logger.GetLogger(),
+	}
+	algo.registerMetrics(ctx, monitor)
+	return algo, nil
+}
+
+func (ta *RegimeSwitchingTradingAlgorithm) registerMetrics(ctx context.Context, m monitor.Monitoring) {
+	ctx = ta.getUpdateContext(ctx)
+	ta.metrics = &RegimeSwitchingMetrics{
+		monitor:       m,
+		SignalCounter: m.RegisterCounter(ctx, "regime_switching_signals_generated", "Total number of regime switching signals generated", monitor.Labels{constraint.SIGNAL_TYPE_LABEL, REGIME_LABEL}),
+	}
+}
+
+// Name returns the name of the trading algorithm
+func (ta *RegimeSwitchingTradingAlgorithm) Name() string {
+	parts := make([]string, len(ta.routes))
+	for i, route := range ta.routes {
+		parts[i] = route.Regime.String() + ":" + route.Algorithm.Name()
+	}
+	return fmt.Sprintf("Regime_%s(%s)", ta.classifier.Name(), strings.Join(parts, ";"))
+}
+
+// Regime returns the regime of the latest bar.
+func (ta *RegimeSwitchingTradingAlgorithm) Regime() regime.Regime {
+	return ta.current
+}
+
+// Evaluate classifies the data point and returns the signal of the matching route, recording the regime on it.
+func (ta *RegimeSwitchingTradingAlgorithm) Evaluate(ctx context.Context, data model.DataPoint) (result model.TradingSignal) {
+	ctx = ta.getUpdateContext(ctx)
+	defer func() {
+		tags := monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, result.Action)
+		tags.Add(REGIME_LABEL, result.Regime)
+		ta.metrics.SignalCounter.IncrementCounter(ctx, tags)
+	}()
+
+	if err := ta.classifier.AddDataPoint(ctx, data); err != nil {
+		ta.logger.Error(ctx, "Failed to add data point to regime classifier", zap.Error(err))
+	}
+	previous := ta.current
+	ta.current = ta.classifier.Regime()
+	if ta.current != previous {
+		ta.logger.Info(ctx, "Regime changed", zap.String("from", previous.String()), zap.String("to", ta.current.String()), zap.Int64("time", data.Time))
+	}
+
+	result = model.TradingSignal{Time: data.Time, Action: model.Wait}
+	reason := fmt.Sprintf("no route for regime %s", ta.current)
+	votes := make([]audit.Vote, len(ta.routes))
+	matched := false
+	for i, route := range ta.routes {
+		signal := route.Algorithm.Evaluate(ctx, data)
+		votes[i] = audit.NewVote(route.Regime.String()+":"+route.Algorithm.Name(), signal, nil)
+		if !matched && ta.current.Matches(route.Regime) {
+			result = signal
+			reason = fmt.Sprintf("regime %s routed to %s", ta.current, route.Algorithm.Name())
+			matched = true
+		}
+	}
+	result.Regime = ta.current.String()
+	recordDecision(ctx, ta.logger, ta.Name(), data, result, reason, func() []audit.Vote { return votes })
+	return result
+}
+
+// Close releases the routed algorithms holding resources, such as plugin processes.
+func (ta *RegimeSwitchingTradingAlgorithm) Close() error {
+	algorithms := make([]TradingAlgorithm, len(ta.routes))
+	for i, route := range ta.routes {
+		algorithms[i] = route.Algorithm
+	}
+	return closeAll(algorithms)
+}
+
+// Function to retrieve and update the slice from context
+func (ta *RegimeSwitchingTradingAlgorithm) getUpdateContext(ctx context.Context) context.Context {
+	return getUpdatedCommonLabelsContext(ctx, ta.Name())
+}
@@ -0,0 +1,90 @@
+package algorithm_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/algorithm"
+	"github.com/vd09/trading-algorithm-backtesting-system/audit"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/regime"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+// ScriptedClassifier returns one scripted regime per data point.
+type ScriptedClassifier struct {
+	regimes []regime.Regime
+	bar     int
+}
+
+func (s *ScriptedClassifier) Name() string {
+	return "Scripted"
+}
+
+func (s *ScriptedClassifier) Clone() regime.Classifier {
+	return &ScriptedClassifier{regimes: s.regimes}
+}
+
+func (s *ScriptedClassifier) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	s.bar++
+	return nil
+}
+
+func (s *ScriptedClassifier) Regime() regime.Regime {
+	return s.regimes[s.bar-1]
+}
+
+// CountingAlgorithm always returns the same action and counts its evaluations.
+type CountingAlgorithm struct {
+	name        string
+	action      model.StockAction
+	evaluations int
+}
+
+func (c *CountingAlgorithm) Name() string {
+	return c.name
+}
+
+func (c *CountingAlgorithm) Evaluate(ctx context.Context, data model.DataPoint) model.TradingSignal {
+	c.evaluations++
+	return model.TradingSignal{Time: data.Time, Action: c.action}
+}
+
+func TestRegimeSwitchingTradingAlgorithm(t *testing.T) {
+	trend := regime.Regime{Trend: regime.Trending, Volatility: regime.LowVolatility}
+	rangeHigh := regime.Regime{Trend: regime.Ranging, Volatility: regime.HighVolatility}
+	classifier := &ScriptedClassifier{regimes: []regime.Regime{regime.Unknown, trend, rangeHigh}}
+
+	trendFollower := &CountingAlgorithm{name: "EMA", action: model.Buy}
+	meanReverter := &CountingAlgorithm{name: "RSI", action: model.Sell}
+	routes := []algorithm.RegimeRoute{
+		{Regime: regime.Regime{Trend: regime.Trending}, Algorithm: trendFollower},
+		{Regime: regime.Regime{Trend: regime.Ranging}, Algorithm: meanReverter},
+	}
+	algo, err := algorithm.NewRegimeSwitchingTradingAlgorithm(context.Background(), classifier, routes, test_utils.NewMockMetricsCollector(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var log DecisionLog
+	signal := algo.Evaluate(audit.WithRecorder(ctx, &log), model.DataPoint{Time: 1})
+	test_utils.AssertEqual(t, model.Wait, signal.Action, "Expected no route to match an unknown regime")
+	test_utils.AssertEqual(t, "unknown", signal.Regime, "Regime does not match")
+
+	signal = algo.Evaluate(audit.WithRecorder(ctx, &log), model.DataPoint{Time: 2})
+	test_utils.AssertEqual(t, model.StockAction(model.Buy), signal.Action, "Expected the trend route")
+	test_utils.AssertEqual(t, "trend/low_volatility", signal.Regime, "Regime does not match")
+
+	signal = algo.Evaluate(ctx, model.DataPoint{Time: 3})
+	test_utils.AssertEqual(t, model.StockAction(model.Sell), signal.Action, "Expected the range route")
+	test_utils.AssertEqual(t, "range/high_volatility", signal.Regime, "Regime does not match")
+	test_utils.AssertEqual(t, rangeHigh, algo.Regime(), "Current regime does not match")
+
+	test_utils.AssertEqual(t, 3, trendFollower.evaluations, "Expected every route to see every bar")
+	test_utils.AssertEqual(t, 3, meanReverter.evaluations, "Expected every route to see every bar")
+
+	test_utils.AssertEqual(t, 2, len(log), "Expected a decision for every evaluation under the recorder")
+	test_utils.AssertEqual(t, "no route for regime unknown", log[0].Reason, "Reason does not match")
+	test_utils.AssertEqual(t, "regime trend/low_volatility routed to EMA", log[1].Reason, "Reason does not match")
+	test_utils.AssertEqual(t, 2, len(log[1].Votes), "Expected a vote for every route")
+}
@@ -0,0 +1,205 @@
+package algorithm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/constraint"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
+	"github.com/vd09/trading-algorithm-backtesting-system/logger"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
+	"go.uber.org/zap"
+)
+
+const (
+	SETUP_EVENT_LABEL = "setup_event"
+)
+
+// SequenceStep is one adaptor of a sequence.
+type SequenceStep struct {
+	Adaptor indicator_adaptor.IndicatorAdaptor
+	// Within is the number of bars after the previous step this step has to fire in, 0 means on the same bar.
+	// It is ignored for the first step.
+	Within int
+}
+
+type SequentialMetrics struct {
+	SignalCounter monitor.CounterMetric
+	SetupCounter  monitor.CounterMetric
+	monitor       monitor.Monitoring
+}
+
+// SequentialTradingAlgorithm acts when its adaptors fire one after the other in the same direction.
+// The first step arms a setup which every following step has to confirm within its number of bars.
+// A setup is dropped when a step expires or when any adaptor of the sequence signals the opposite direction.
+type SequentialTradingAlgorithm struct {
+	steps []SequenceStep
+	// armed is the direction of the current setup, Wait when no setup is armed.
+	armed         model.StockAction
+	next          int
+	barsSinceStep int
+	confirmed     []model.TradingSignal
+	logger        logger.LoggerInterface
+	metrics       *SequentialMetrics
+}
+
+func init() {
+	registry.RegisterAlgorithm(registry.AlgorithmEntry{
+		Name:         "sequential",
+		Description:  "Acts when the adaptors fire one after the other, in order",
+		UsesAdaptors: true,
+		Params: []registry.Param{
+			{Name: "within", Type: registry.IntParam, Description: "Bars every step has to follow the previous one within", Default: 3, Min: 0, Max: 10000},
+		},
+		New: func(ctx context.Context, p registry.Params, adaptors []indicator_adaptor.IndicatorAdaptor, m monitor.Monitoring) (TradingAlgorithm, error) {
+			steps := make([]SequenceStep, len(adaptors))
+			for i, adaptor := range adaptors {
+				steps[i] = SequenceStep{Adaptor: adaptor, Within: p.Int("within")}
+			}
+			return NewSequentialTradingAlgorithm(ctx, steps, m)
+		},
+	})
+}
+
+// NewSequentialTradingAlgorithm initializes a new SequentialTradingAlgorithm over the steps, in order.
+func NewSequentialTradingAlgorithm(ctx context.Context, steps []SequenceStep, monitor monitor.Monitoring) (*SequentialTradingAlgorithm, error) {
+	if len(steps) == 0 {
+		return nil, errors.New("a sequence needs at least one step")
+	}
+	for i, step := range steps {
+		if step.Adaptor == nil {
+			return nil, fmt.Errorf("step %d has no adaptor", i+1)
+		}
+		if step.Within < 0 {
+			return nil, fmt.Errorf("step %d: within must not be negative", i+1)
+		}
+	}
+
+	algo := &SequentialTradingAlgorithm{
+		steps:  steps,
+		armed:  model.Wait,
+		logger: logger.GetLogger(),
+	}
+	algo.registerMetrics(ctx, monitor)
+	return algo, nil
+}
+
+func (ta *SequentialTradingAlgorithm) registerMetrics(ctx context.Context, m monitor.Monitoring) {
+	ctx = ta.getUpdateContext(ctx)
+	ta.metrics = &SequentialMetrics{
+		monitor:       m,
+		SignalCounter: m.RegisterCounter(ctx, "sequence_signals_generated", "Total number of sequence signals generated", monitor.Labels{constraint.SIGNAL_TYPE_LABEL}),
+		SetupCounter:  m.RegisterCounter(ctx, "sequence_setup_events", "Total number of sequence setup events", monitor.Labels{SETUP_EVENT_LABEL}),
+	}
+}
+
+// Name returns the name of the trading algorithm
+func (ta *SequentialTradingAlgorithm) Name() string {
+	parts := make([]string, len(ta.steps))
+	for i, step := range ta.steps {
+		parts[i] = step.Adaptor.Name()
+		if i > 0 {
+			parts[i] += fmt.Sprintf(" within %d", step.Within)
+		}
+	}
+	return fmt.Sprintf("Sequence(%s)", strings.Join(parts, ", "))
+}
+
+// Evaluate feeds the data point to every adaptor and advances the armed setup.
+// It returns the combined signal of all steps on the bar the last step confirms.
+func (ta *SequentialTradingAlgorithm) Evaluate(ctx context.Context, data model.DataPoint) (result model.TradingSignal) {
+	ctx = ta.getUpdateContext(ctx)
+	defer func() {
+		ta.metrics.SignalCounter.IncrementCounter(ctx, monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, result.Action))
+	}()
+
+	signals := make([]model.TradingSignal, len(ta.steps))
+	for i, step := range ta.steps {
+		step.Adaptor.AddDataPoint(ctx, data)
+		signals[i] = step.Adaptor.GetSignal(ctx)
+	}
+
+	if ta.armed != model.Wait {
+		ta.barsSinceStep++
+		if ta.hasContrarySignal(signals) {
+			ta.reset(ctx, "reset", data)
+		} else if ta.barsSinceStep > ta.steps[ta.next].Within {
+			ta.reset(ctx, "expired", data)
+		}
+	}
+
+	if ta.armed == model.Wait {
+		if first := signals[0]; first.Action == model.Buy || first.Action == model.Sell {
+			ta.armed = first.Action
+			ta.next = 0
+			ta.recordEvent(ctx, "armed", data)
+		}
+	}
+	for ta.armed != model.Wait && ta.next < len(ta.steps) && signals[ta.next].Action == ta.armed {
+		ta.confirmed = append(ta.confirmed, signals[ta.next])
+		ta.next++
+		ta.barsSinceStep = 0
+	}
+
+	if ta.armed != model.Wait && ta.next == len(ta.steps) {
+		result = combineSignals(data.Time, ta.armed, ta.confirmed)
+		ta.logger.Info(ctx, "Sequence completed", zap.String("action", string(result.Action)), zap.Int64("time", data.Time))
+		ta.reset(ctx, "completed", data)
+		recordDecision(ctx, ta.logger, ta.Name(), data, result, fmt.Sprintf("all %d steps confirmed %s", len(ta.steps), result.Action), adaptorVotes(ta.adaptors(), signals))
+		return result
+	}
+
+	result = model.TradingSignal{Time: data.Time, Action: model.Wait}
+	reason := "no setup armed"
+	if ta.armed != model.Wait {
+		reason = fmt.Sprintf("%s setup waiting for step %d of %d", ta.armed, ta.next+1, len(ta.steps))
+	}
+	recordDecision(ctx, ta.logger, ta.Name(), data, result, reason, adaptorVotes(ta.adaptors(), signals))
+	return result
+}
+
+// hasContrarySignal reports whether any adaptor of the sequence signals against the armed setup.
+func (ta *SequentialTradingAlgorithm) hasContrarySignal(signals []model.TradingSignal) bool {
+	for _, signal := range signals {
+		if signal.Action != model.Wait && signal.Action != ta.armed {
+			return true
+		}
+	}
+	return false
+}
+
+func (ta *SequentialTradingAlgorithm) reset(ctx context.Context, event string, data model.DataPoint) {
+	ta.recordEvent(ctx, event, data)
+	ta.armed = model.Wait
+	ta.next = 0
+	ta.barsSinceStep = 0
+	ta.confirmed = nil
+}
+
+func (ta *SequentialTradingAlgorithm) recordEvent(ctx context.Context, event string, data model.DataPoint) {
+	ta.logger.Debug(ctx, "Sequence setup event", zap.String("event", event), zap.String("direction", string(ta.armed)), zap.Int("step", ta.next), zap.Int64("time", data.Time))
+	ta.metrics.SetupCounter.IncrementCounter(ctx, monitor.NewTagsKV(SETUP_EVENT_LABEL, event))
+}
+
+// adaptors returns the adaptors of the steps, in order.
+func (ta *SequentialTradingAlgorithm) adaptors() []indicator_adaptor.IndicatorAdaptor {
+	adaptors := make([]indicator_adaptor.IndicatorAdaptor, len(ta.steps))
+	for i, step := range ta.steps {
+		adaptors[i] = step.Adaptor
+	}
+	return adaptors
+}
+
+// Close releases the adaptors of the steps holding resources, such as plugin processes.
+func (ta *SequentialTradingAlgorithm) Close() error {
+	return closeAll(ta.adaptors())
+}
+
+// Function to retrieve and update the slice from context
+func (ta *SequentialTradingAlgorithm) getUpdateContext(ctx context.Context) context.Context {
+	return getUpdatedCommonLabelsContext(ctx, ta.Name())
+}
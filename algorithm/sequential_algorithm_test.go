@@ -0,0 +1,175 @@
+package algorithm_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/algorithm"
+	"github.com/vd09/trading-algorithm-backtesting-system/audit"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+// ScriptedIndicatorAdaptor returns one scripted action per data point, "." for Wait.
+type ScriptedIndicatorAdaptor struct {
+	name   string
+	script string
+	bar    int
+}
+
+func (s *ScriptedIndicatorAdaptor) Name() string {
+	return s.name
+}
+
+func (s *ScriptedIndicatorAdaptor) Clone(ctx context.Context) indicator_adaptor.IndicatorAdaptor {
+	return &ScriptedIndicatorAdaptor{name: s.name, script: s.script}
+}
+
+func (s *ScriptedIndicatorAdaptor) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	s.bar++
+	return nil
+}
+
+func (s *ScriptedIndicatorAdaptor) GetSignal(ctx context.Context) model.TradingSignal {
+	action := model.Wait
+	if s.bar > 0 && s.bar <= len(s.script) {
+		switch s.script[s.bar-1] {
+		case 'B':
+			action = model.Buy
+		case 'S':
+			action = model.Sell
+		}
+	}
+	return model.TradingSignal{Action: action, Strength: 0.5}
+}
+
+// runSequence evaluates the sequence over as many bars as the scripts are long and returns the actions as a script.
+func runSequence(t *testing.T, bars int, steps ...algorithm.SequenceStep) string {
+	algo, err := algorithm.NewSequentialTradingAlgorithm(context.Background(), steps, test_utils.NewMockMetricsCollector(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	actions := make([]byte, bars)
+	for i := range actions {
+		switch algo.Evaluate(ctx, model.DataPoint{Time: int64(i + 1)}).Action {
+		case model.Buy:
+			actions[i] = 'B'
+		case model.Sell:
+			actions[i] = 'S'
+		default:
+			actions[i] = '.'
+		}
+	}
+	return string(actions)
+}
+
+func step(name, script string, within int) algorithm.SequenceStep {
+	return algorithm.SequenceStep{Adaptor: &ScriptedIndicatorAdaptor{name: name, script: script}, Within: within}
+}
+
+func TestSequentialTradingAlgorithm(t *testing.T) {
+	tests := []struct {
+		name     string
+		steps    []algorithm.SequenceStep
+		expected string
+	}{
+		{
+			name:     "confirmed within window",
+			steps:    []algorithm.SequenceStep{step("MACD", "B.......", 0), step("SuperTrend", "...B....", 3)},
+			expected: "...B....",
+		},
+		{
+			name:     "confirmed on the same bar",
+			steps:    []algorithm.SequenceStep{step("MACD", ".S......", 0), step("SuperTrend", ".S......", 0)},
+			expected: ".S......",
+		},
+		{
+			name:     "expired setup",
+			steps:    []algorithm.SequenceStep{step("MACD", "B.......", 0), step("SuperTrend", "....B...", 3)},
+			expected: "........",
+		},
+		{
+			name:     "re-armed after expiry",
+			steps:    []algorithm.SequenceStep{step("MACD", "B...B...", 0), step("SuperTrend", ".....B..", 3)},
+			expected: ".....B..",
+		},
+		{
+			name:     "reset on contrary signal",
+			steps:    []algorithm.SequenceStep{step("MACD", "B.......", 0), step("SuperTrend", ".S.B....", 3)},
+			expected: "........",
+		},
+		{
+			name:     "contrary first step arms the other direction",
+			steps:    []algorithm.SequenceStep{step("MACD", "B.S.....", 0), step("SuperTrend", "...S....", 3)},
+			expected: "...S....",
+		},
+		{
+			name: "three step chain",
+			steps: []algorithm.SequenceStep{
+				step("MACD", "B.......", 0),
+				step("SuperTrend", "..B.....", 2),
+				step("RSI", ".....B..", 3),
+			},
+			expected: ".....B..",
+		},
+		{
+			name: "three step chain expiring on the last step",
+			steps: []algorithm.SequenceStep{
+				step("MACD", "B.......", 0),
+				step("SuperTrend", "..B.....", 2),
+				step("RSI", "......B.", 3),
+			},
+			expected: "........",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test_utils.AssertEqual(t, tt.expected, runSequence(t, 8, tt.steps...), "Sequence actions do not match")
+		})
+	}
+}
+
+func TestSequentialTradingAlgorithmSignal(t *testing.T) {
+	steps := []algorithm.SequenceStep{step("MACD", "B..", 0), step("SuperTrend", "..B", 2)}
+	algo, err := algorithm.NewSequentialTradingAlgorithm(context.Background(), steps, test_utils.NewMockMetricsCollector(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	test_utils.AssertEqual(t, "Sequence(MACD, SuperTrend within 2)", algo.Name(), "Name does not match")
+
+	var signal model.TradingSignal
+	for i := 1; i <= 3; i++ {
+		signal = algo.Evaluate(ctx, model.DataPoint{Time: int64(i)})
+	}
+	test_utils.AssertEqual(t, int64(3), signal.Time, "Signal time does not match")
+	test_utils.AssertEqual(t, 0.5, signal.Strength, "Signal strength does not match")
+
+	if _, err := algorithm.NewSequentialTradingAlgorithm(context.Background(), nil, test_utils.NewMockMetricsCollector(t)); err == nil {
+		t.Fatalf("expected error for an empty sequence")
+	}
+}
+
+func TestSequentialTradingAlgorithmRecordsDecisions(t *testing.T) {
+	steps := []algorithm.SequenceStep{step("MACD", "B...", 0), step("SuperTrend", "..B.", 3)}
+	algo, err := algorithm.NewSequentialTradingAlgorithm(ctx, steps, test_utils.NewMockMetricsCollector(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var log DecisionLog
+	auditCtx := audit.WithRecorder(ctx, &log)
+	for i := 0; i < 4; i++ {
+		algo.Evaluate(auditCtx, model.DataPoint{Time: int64(i + 1)})
+	}
+
+	reasons := make([]string, len(log))
+	for i, decision := range log {
+		reasons[i] = decision.Reason
+	}
+	test_utils.AssertEqual(t, []string{"buy setup waiting for step 2 of 2", "buy setup waiting for step 2 of 2", "all 2 steps confirmed buy", "no setup armed"}, reasons, "Reasons do not match")
+	test_utils.AssertEqual(t, 2, len(log[2].Votes), "Expected a vote for every step")
+	test_utils.AssertEqual(t, model.StockAction(model.Buy), log[2].Votes[1].Action, "Expected the confirming vote")
+}
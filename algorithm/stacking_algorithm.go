@@ -0,0 +1,200 @@
+package algorithm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/constraint"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
+	"github.com/vd09/trading-algorithm-backtesting-system/logger"
+	"github.com/vd09/trading-algorithm-backtesting-system/ml"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
+	"go.uber.org/zap"
+)
+
+type StackingMetrics struct {
+	SignalCounter monitor.CounterMetric
+	ExpertWeight  monitor.CounterMetric
+	monitor       monitor.Monitoring
+}
+
+// StackingTradingAlgorithm learns online how much to trust each adaptor. Every adaptor votes with the
+// direction of its latest signal and, once the next bar closes, the learner is updated with the direction
+// the price actually moved in. It acts when the combined vote crosses the threshold in a new direction.
+type StackingTradingAlgorithm struct {
+	adaptors  []indicator_adaptor.IndicatorAdaptor
+	learner   ml.OnlineLearner
+	threshold float64
+	// last holds the latest non-Wait signal of every adaptor, its vote until the adaptor signals again.
+	last      []model.TradingSignal
+	votes     []float64
+	lastClose float64
+	stance    model.StockAction
+	logger    logger.LoggerInterface
+	metrics   *StackingMetrics
+}
+
+func init() {
+	registry.RegisterAlgorithm(registry.AlgorithmEntry{
+		Name:         "stacking",
+		Description:  "Learns online how much to trust every adaptor",
+		UsesAdaptors: true,
+		Params: []registry.Param{
+			{Name: "learner", Type: registry.StringParam, Default: "hedge", Choices: []string{"hedge", "perceptron"}},
+			{Name: "learning_rate", Type: registry.FloatParam, Default: 0.3, Min: 0.0001, Max: 10},
+			{Name: "threshold", Type: registry.FloatParam, Description: "Combined vote needed to act", Default: 0.5, Min: 0.01, Max: 1},
+		},
+		New: func(ctx context.Context, p registry.Params, adaptors []indicator_adaptor.IndicatorAdaptor, m monitor.Monitoring) (TradingAlgorithm, error) {
+			var learner ml.OnlineLearner = ml.NewHedge(len(adaptors), p.Float("learning_rate"))
+			if p.String("learner") == "perceptron" {
+				learner = ml.NewPerceptron(len(adaptors), p.Float("learning_rate"))
+			}
+			return NewStackingTradingAlgorithm(ctx, adaptors, learner, p.Float("threshold"), m)
+		},
+	})
+}
+
+// NewStackingTradingAlgorithm initializes a new StackingTradingAlgorithm, the learner needs one weight per adaptor.
+func NewStackingTradingAlgorithm(ctx context.Context, adaptors []indicator_adaptor.IndicatorAdaptor, learner ml.OnlineLearner, threshold float64, monitor monitor.Monitoring) (*StackingTradingAlgorithm, error) {
+	if len(adaptors) == 0 {
+		return nil, errors.New("at least one adaptor is required")
+	}
+	if learner == nil || len(learner.Weights()) != len(adaptors) {
+		return nil, fmt.Errorf("the learner needs one weight for each of the %d adaptors", len(adaptors))
+	}
+	if threshold <= 0 || threshold > 1 {
+		return nil, fmt.Errorf("threshold %.2f must be in (0, 1]", threshold)
+	}
+
+	algo := &StackingTradingAlgorithm{
+		adaptors:  adaptors,
+		learner:   learner,
+		threshold: threshold,
+		last:      make([]model.TradingSignal, len(adaptors)),
+		stance:    model.Wait,
+		logger:    logger.GetLogger(),
+	}
+	algo.registerMetrics(ctx, monitor)
+	return algo, nil
+}
+
+func (ta *StackingTradingAlgorithm) registerMetrics(ctx context.Context, m monitor.Monitoring) {
+	ctx = ta.getUpdateContext(ctx)
+	ta.metrics = &StackingMetrics{
+		monitor:       m,
+		SignalCounter: m.RegisterCounter(ctx, "stacking_signals_generated", "Total number of stacking signals generated", monitor.Labels{constraint.SIGNAL_TYPE_LABEL}),
+		ExpertWeight:  m.RegisterCounter(ctx, "stacking_expert_weight", "Current weight of every adaptor in the stack", monitor.Labels{indicator_adaptor.ADAPTOR_NAME_LABEL}),
+	}
+}
+
+// Name returns the name of the trading algorithm
+func (ta *StackingTradingAlgorithm) Name() string {
+	names := make([]string, len(ta.adaptors))
+	for i, adaptor := range ta.adaptors {
+		names[i] = adaptor.Name()
+	}
+	return fmt.Sprintf("Stacking_%s(%s)", ta.learner.Name(), strings.Join(names, ", "))
+}
+
+// Weights returns the current trust in every adaptor, in the order of the adaptors.
+func (ta *StackingTradingAlgorithm) Weights() []float64 {
+	return ta.learner.Weights()
+}
+
+// Evaluate scores the previous votes against the latest close, then collects the new votes and acts on their combination.
+func (ta *StackingTradingAlgorithm) Evaluate(ctx context.Context, data model.DataPoint) (result model.TradingSignal) {
+	ctx = ta.getUpdateContext(ctx)
+	defer func() {
+		ta.metrics.SignalCounter.IncrementCounter(ctx, monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, result.Action))
+	}()
+
+	if ta.votes != nil {
+		ta.learner.Update(ta.votes, data.Close-ta.lastClose)
+		for i, weight := range ta.learner.Weights() {
+			ta.metrics.ExpertWeight.SetValue(ctx, weight, monitor.NewTagsKV(indicator_adaptor.ADAPTOR_NAME_LABEL, ta.adaptors[i].Name()))
+		}
+	}
+
+	ta.votes = make([]float64, len(ta.adaptors))
+	signals := make([]model.TradingSignal, len(ta.adaptors))
+	for i, adaptor := range ta.adaptors {
+		adaptor.AddDataPoint(ctx, data)
+		if signals[i] = adaptor.GetSignal(ctx); signals[i].Action == model.Buy || signals[i].Action == model.Sell {
+			ta.last[i] = signals[i]
+		}
+		ta.votes[i] = vote(ta.last[i].Action)
+	}
+	ta.lastClose = data.Close
+
+	prediction := ta.learner.Predict(ta.votes)
+	action := model.StockAction(model.Wait)
+	if prediction >= ta.threshold {
+		action = model.Buy
+	} else if prediction <= -ta.threshold {
+		action = model.Sell
+	}
+	reason := fmt.Sprintf("weighted vote %.2f against threshold %.2f", prediction, ta.threshold)
+	if action == model.Wait || action == ta.stance {
+		if action != model.Wait {
+			reason += fmt.Sprintf(", already %s", action)
+		}
+		result = model.TradingSignal{Time: data.Time, Action: model.Wait}
+		recordDecision(ctx, ta.logger, ta.Name(), data, result, reason, adaptorVotes(ta.adaptors, signals))
+		return result
+	}
+	ta.stance = action
+	ta.logger.Info(ctx, "Stack changed direction", zap.String("action", string(action)), zap.Float64("prediction", prediction), zap.Int64("time", data.Time))
+	result = ta.signal(data, action, prediction)
+	recordDecision(ctx, ta.logger, ta.Name(), data, result, reason, adaptorVotes(ta.adaptors, signals))
+	return result
+}
+
+// signal takes the exits of the agreeing adaptors and explains the vote by their weights.
+func (ta *StackingTradingAlgorithm) signal(data model.DataPoint, action model.StockAction, prediction float64) model.TradingSignal {
+	var agreeing []model.TradingSignal
+	var rationale []model.Rationale
+	weights := ta.learner.Weights()
+	for i, last := range ta.last {
+		if last.Action != action {
+			continue
+		}
+		agreeing = append(agreeing, model.TradingSignal{StopLoss: last.StopLoss, Target: last.Target})
+		rationale = append(rationale, model.Rationale{
+			Source:    ta.Name(),
+			Indicator: ta.adaptors[i].Name(),
+			Event:     fmt.Sprintf("voted %s", action),
+			Reference: "with weight",
+			Values:    []float64{weights[i]},
+		})
+	}
+
+	result := combineSignals(data.Time, action, agreeing)
+	result.Strength = math.Min(1, math.Abs(prediction))
+	result.Rationale = rationale
+	return result
+}
+
+func vote(action model.StockAction) float64 {
+	switch action {
+	case model.Buy:
+		return 1
+	case model.Sell:
+		return -1
+	}
+	return 0
+}
+
+// Close releases the adaptors holding resources, such as plugin processes.
+func (ta *StackingTradingAlgorithm) Close() error {
+	return closeAll(ta.adaptors)
+}
+
+// Function to retrieve and update the slice from context
+func (ta *StackingTradingAlgorithm) getUpdateContext(ctx context.Context) context.Context {
+	return getUpdatedCommonLabelsContext(ctx, ta.Name())
+}
@@ -0,0 +1,66 @@
+package algorithm_test
+
+import (
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/algorithm"
+	"github.com/vd09/trading-algorithm-backtesting-system/audit"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
+	"github.com/vd09/trading-algorithm-backtesting-system/ml"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+func TestStackingTradingAlgorithm(t *testing.T) {
+	// The price alternates up and down every two bars. "Good" calls every turn a bar ahead
+	// of it and "Bad" calls the opposite, so the stack should learn to follow Good.
+	closes := []float64{}
+	good, bad := "", ""
+	for i := 0; i < 40; i++ {
+		if i%4 < 2 {
+			closes = append(closes, 100+float64(i%4))
+		} else {
+			closes = append(closes, 102-float64(i%4-1))
+		}
+	}
+	for i := range closes {
+		switch {
+		case i+1 < len(closes) && closes[i+1] > closes[i] && (i == 0 || closes[i] <= closes[i-1]):
+			good, bad = good+"B", bad+"S"
+		case i+1 < len(closes) && closes[i+1] < closes[i] && closes[i] >= closes[i-1]:
+			good, bad = good+"S", bad+"B"
+		default:
+			good, bad = good+".", bad+"."
+		}
+	}
+
+	adaptors := []indicator_adaptor.IndicatorAdaptor{
+		&ScriptedIndicatorAdaptor{name: "Bad", script: bad},
+		&ScriptedIndicatorAdaptor{name: "Good", script: good},
+	}
+	algo, err := algorithm.NewStackingTradingAlgorithm(ctx, adaptors, ml.NewHedge(2, 0.5), 0.5, test_utils.NewMockMetricsCollector(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	test_utils.AssertEqual(t, "Stacking_Hedge(Bad, Good)", algo.Name(), "Name does not match")
+
+	var last model.TradingSignal
+	var log DecisionLog
+	for i, close := range closes {
+		signal := algo.Evaluate(audit.WithRecorder(ctx, &log), model.DataPoint{Time: int64(i + 1), Close: close})
+		if signal.Action != model.Wait {
+			last = signal
+		}
+	}
+	weights := algo.Weights()
+	test_utils.AssertTrue(t, weights[1] > 0.9, "Expected the stack to trust the good adaptor")
+	test_utils.AssertEqual(t, 1, len(last.Rationale), "Expected only the agreeing adaptor in the rationale")
+	test_utils.AssertEqual(t, "Good", last.Rationale[0].Indicator, "Expected the stack to follow the good adaptor")
+	test_utils.AssertEqual(t, len(closes), len(log), "Expected a decision for every bar")
+	test_utils.AssertEqual(t, "weighted vote 0.00 against threshold 0.50", log[0].Reason, "Expected the vote to explain the wait")
+	test_utils.AssertEqual(t, 2, len(log[0].Votes), "Expected a vote for every adaptor")
+
+	if _, err := algorithm.NewStackingTradingAlgorithm(ctx, adaptors, ml.NewHedge(3, 0.5), 0.5, test_utils.NewMockMetricsCollector(t)); err == nil {
+		t.Fatalf("expected error for a learner of the wrong size")
+	}
+}
@@ -2,11 +2,34 @@ package algorithm
 
 import (
 	"context"
+	"errors"
+	"io"
 
 	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
 )
 
-type TradingAlgorithm interface {
-	Name() string
-	Evaluate(ctx context.Context, data model.DataPoint) model.TradingSignal
+// TradingAlgorithm decides on every bar. It is declared by the registry, which the algorithms register with.
+type TradingAlgorithm = registry.TradingAlgorithm
+
+// BatchTradingAlgorithm can evaluate a run of data points at once, returning one signal per point.
+// Its signals must only depend on the data points, as the engine evaluates it ahead of the other algorithms.
+type BatchTradingAlgorithm interface {
+	TradingAlgorithm
+	EvaluateBatch(ctx context.Context, data []model.DataPoint) []model.TradingSignal
+}
+
+// PairTradingAlgorithm trades the relationship between two instruments, taking their bars for the same time.
+// Its signals list the legs of the first instrument first.
+type PairTradingAlgorithm = registry.PairTradingAlgorithm
+
+// closeAll closes the components implementing io.Closer, such as plugins, and joins their errors.
+func closeAll[T any](components []T) error {
+	var errs []error
+	for _, component := range components {
+		if closer, ok := any(component).(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+	return errors.Join(errs...)
 }
@@ -0,0 +1,159 @@
+// Package audit records why algorithms decided what they did, as a JSON lines journal per run that can be queried afterwards.
+package audit
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// Vote is what a single adaptor said on a bar.
+type Vote struct {
+	Adaptor  string            `json:"adaptor"`
+	Action   model.StockAction `json:"action"`
+	Strength float64           `json:"strength,omitempty"`
+	// Values are the adaptor's key indicator values on the bar.
+	Values    map[string]float64 `json:"values,omitempty"`
+	Rationale []string           `json:"rationale,omitempty"`
+}
+
+// Decision records the votes behind a single evaluation and what the algorithm made of them.
+type Decision struct {
+	RunID string `json:"run_id"`
+	// Phase and Generation tell apart the backtests of a search, such as its train and holdout runs per generation.
+	Phase      string            `json:"phase,omitempty"`
+	Generation *int              `json:"generation,omitempty"`
+	Algorithm  string            `json:"algorithm"`
+	Time       int64             `json:"time"`
+	Close      float64           `json:"close"`
+	Votes      []Vote            `json:"votes"`
+	Action     model.StockAction `json:"action"`
+	Strength   float64           `json:"strength,omitempty"`
+	StopLoss   float64           `json:"stop_loss,omitempty"`
+	Target     float64           `json:"target,omitempty"`
+	Reason     string            `json:"reason"`
+}
+
+// NewVote records the signal of an adaptor with its indicator values. Non-finite values are left out.
+func NewVote(adaptor string, signal model.TradingSignal, values map[string]float64) Vote {
+	vote := Vote{Adaptor: adaptor, Action: signal.Action, Strength: signal.Strength}
+	for name, value := range values {
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			continue
+		}
+		if vote.Values == nil {
+			vote.Values = make(map[string]float64, len(values))
+		}
+		vote.Values[name] = value
+	}
+	for _, rationale := range signal.Rationale {
+		vote.Rationale = append(vote.Rationale, rationale.String())
+	}
+	return vote
+}
+
+// Recorder receives the decisions of algorithms.
+type Recorder interface {
+	Record(decision Decision) error
+}
+
+type recorderKey struct{}
+
+// WithRecorder returns a context under which algorithms record their decisions to recorder.
+func WithRecorder(ctx context.Context, recorder Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, recorder)
+}
+
+// RecorderFromContext returns the recorder set by WithRecorder, or nil when decisions are not recorded.
+func RecorderFromContext(ctx context.Context) Recorder {
+	recorder, _ := ctx.Value(recorderKey{}).(Recorder)
+	return recorder
+}
+
+// Tagged returns a recorder passing decisions on to recorder, tagged with the phase and generation of a search.
+func Tagged(recorder Recorder, phase string, generation int) Recorder {
+	return taggedRecorder{recorder: recorder, phase: phase, generation: generation}
+}
+
+type taggedRecorder struct {
+	recorder   Recorder
+	phase      string
+	generation int
+}
+
+func (r taggedRecorder) Record(decision Decision) error {
+	generation := r.generation
+	decision.Phase, decision.Generation = r.phase, &generation
+	return r.recorder.Record(decision)
+}
+
+// NewRunID returns a new identifier for a run, sortable by start time.
+func NewRunID() string {
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return time.Now().UTC().Format("20060102T150405") + "-" + hex.EncodeToString(suffix)
+}
+
+// Journal appends the decisions of a run to a JSON lines file. It is safe for concurrent use.
+type Journal struct {
+	runID  string
+	file   *os.File
+	writer *bufio.Writer
+	mu     sync.Mutex
+}
+
+// OpenJournal opens the journal file for appending, creating it and its directory if needed.
+// Every decision recorded through it is tagged with runID.
+func OpenJournal(path, runID string) (*Journal, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &Journal{runID: runID, file: file, writer: bufio.NewWriter(file)}, nil
+}
+
+// RunID returns the run the journal records.
+func (j *Journal) RunID() string {
+	return j.runID
+}
+
+// Record appends the decision to the journal.
+func (j *Journal) Record(decision Decision) error {
+	decision.RunID = j.runID
+	line, err := json.Marshal(decision)
+	if err != nil {
+		return err
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	_, err = j.writer.Write(append(line, '\n'))
+	return err
+}
+
+// Flush writes the buffered decisions to the file.
+func (j *Journal) Flush() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.writer.Flush()
+}
+
+// Close flushes and closes the journal.
+func (j *Journal) Close() error {
+	if err := j.Flush(); err != nil {
+		j.file.Close()
+		return err
+	}
+	return j.file.Close()
+}
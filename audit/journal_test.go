@@ -0,0 +1,75 @@
+package audit_test
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/audit"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+func TestJournalQuery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "journal.jsonl")
+	for _, run := range []string{"run-1", "run-2"} {
+		journal, err := audit.OpenJournal(path, run)
+		test_utils.AssertEqual(t, nil, err, "Expected the journal to open")
+		for time := int64(1); time <= 4; time++ {
+			for _, algorithm := range []string{"RSI_MACD", "EMA_Pivot"} {
+				action := model.StockAction(model.Wait)
+				if time == 3 {
+					action = model.Buy
+				}
+				test_utils.AssertEqual(t, nil, journal.Record(audit.Decision{Algorithm: algorithm, Time: time, Action: action}), "Expected the decision to be recorded")
+			}
+		}
+		test_utils.AssertEqual(t, nil, journal.Close(), "Expected the journal to close")
+	}
+
+	all, err := audit.Query(path, audit.Filter{})
+	test_utils.AssertEqual(t, nil, err, "Expected the journal to be readable")
+	test_utils.AssertEqual(t, 16, len(all), "Expected both runs to be appended")
+
+	filtered, _ := audit.Query(path, audit.Filter{RunID: "run-2", Algorithm: "MACD", From: 2, To: 3})
+	test_utils.AssertEqual(t, 2, len(filtered), "Expected the filters to combine")
+	test_utils.AssertEqual(t, "run-2", filtered[0].RunID, "Expected the run ID to be recorded")
+	test_utils.AssertEqual(t, int64(2), filtered[0].Time, "Expected the time range to be inclusive")
+
+	buys, _ := audit.Query(path, audit.Filter{Actions: []string{"buy", "sell"}})
+	test_utils.AssertEqual(t, 4, len(buys), "Expected the action filter")
+}
+
+func TestNewVote(t *testing.T) {
+	signal := model.TradingSignal{Action: model.Buy, Strength: 0.7, Rationale: []model.Rationale{{Indicator: "RSI", Event: "crossed up through", Reference: "30", Values: []float64{28.4, 31.2}}}}
+	vote := audit.NewVote("RSI_14", signal, map[string]float64{"rsi": 31.2, "bad": math.NaN()})
+	test_utils.AssertEqual(t, map[string]float64{"rsi": 31.2}, vote.Values, "Expected non-finite values to be left out")
+	test_utils.AssertEqual(t, []string{"RSI crossed up through 30 at 28.4→31.2"}, vote.Rationale, "Expected the rationale as text")
+
+	test_utils.AssertTrue(t, audit.RecorderFromContext(context.Background()) == nil, "Expected no recorder by default")
+	test_utils.AssertTrue(t, audit.NewRunID() != audit.NewRunID(), "Expected unique run IDs")
+}
+
+func TestTaggedQuery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	journal, err := audit.OpenJournal(path, "run-1")
+	test_utils.AssertEqual(t, nil, err, "Expected the journal to open")
+	for generation := 0; generation < 2; generation++ {
+		for _, phase := range []string{"train", "holdout"} {
+			recorder := audit.Tagged(journal, phase, generation)
+			test_utils.AssertEqual(t, nil, recorder.Record(audit.Decision{Algorithm: "RSI_MACD", Time: 1}), "Expected the decision to be recorded")
+		}
+	}
+	test_utils.AssertEqual(t, nil, journal.Record(audit.Decision{Algorithm: "RSI_MACD", Time: 1}), "Expected the decision to be recorded")
+	test_utils.AssertEqual(t, nil, journal.Close(), "Expected the journal to close")
+
+	holdout, _ := audit.Query(path, audit.Filter{Phase: "holdout"})
+	test_utils.AssertEqual(t, 2, len(holdout), "Expected the phase filter")
+
+	first := 0
+	generation, _ := audit.Query(path, audit.Filter{Generation: &first})
+	test_utils.AssertEqual(t, 2, len(generation), "Expected the generation filter to skip untagged decisions")
+	test_utils.AssertEqual(t, "run-1", generation[0].RunID, "Expected the journal to set the run ID of tagged decisions")
+	test_utils.AssertEqual(t, 0, *generation[0].Generation, "Expected the generation to be recorded")
+}
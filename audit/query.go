@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Filter selects decisions from a journal, zero fields match everything.
+type Filter struct {
+	RunID string
+	Phase string
+	// Generation, when set, keeps only decisions of that generation.
+	Generation *int
+	// Algorithm matches the algorithms whose name contains it.
+	Algorithm string
+	// From and To bound the bar time, both inclusive.
+	From int64
+	To   int64
+	// Actions keeps only decisions with one of the actions.
+	Actions []string
+}
+
+// Matches reports whether the decision passes the filter.
+func (f Filter) Matches(decision Decision) bool {
+	if f.RunID != "" && decision.RunID != f.RunID {
+		return false
+	}
+	if f.Phase != "" && decision.Phase != f.Phase {
+		return false
+	}
+	if f.Generation != nil && (decision.Generation == nil || *decision.Generation != *f.Generation) {
+		return false
+	}
+	if f.Algorithm != "" && !strings.Contains(decision.Algorithm, f.Algorithm) {
+		return false
+	}
+	if (f.From != 0 && decision.Time < f.From) || (f.To != 0 && decision.Time > f.To) {
+		return false
+	}
+	if len(f.Actions) == 0 {
+		return true
+	}
+	for _, action := range f.Actions {
+		if string(decision.Action) == action {
+			return true
+		}
+	}
+	return false
+}
+
+// Scan reads a journal and calls fn with every decision passing the filter, stopping at the first error.
+func Scan(r io.Reader, filter Filter, fn func(Decision) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var decision Decision
+		if err := json.Unmarshal(scanner.Bytes(), &decision); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		if !filter.Matches(decision) {
+			continue
+		}
+		if err := fn(decision); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// Query returns the decisions of the journal file passing the filter, in the order they were recorded.
+func Query(path string, filter Filter) ([]Decision, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var decisions []Decision
+	err = Scan(file, filter, func(decision Decision) error {
+		decisions = append(decisions, decision)
+		return nil
+	})
+	return decisions, err
+}
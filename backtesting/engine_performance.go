@@ -2,18 +2,38 @@ package backtesting
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 
 	"github.com/vd09/trading-algorithm-backtesting-system/algorithm"
 	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/strategy"
 	"github.com/vd09/trading-algorithm-backtesting-system/utils"
 )
 
+// DefaultCapital is the capital a new engine sizes fixed fraction positions against.
+const DefaultCapital = 100000
+
 type BacktestEngine struct {
 	Algorithms      []algorithm.TradingAlgorithm
 	Performance     map[string]PerformanceMetrics
 	HistoricalData  []model.DataPoint
 	TrackIterations int
+	// Capital is what fixed fraction sizing invests a share of.
+	Capital float64
+	// strategies holds the strategy of every algorithm added with AddStrategy, by algorithm name.
+	strategies map[string]*strategy.Strategy
+}
+
+// NewBacktestEngine initializes a new BacktestEngine over the given historical data.
+func NewBacktestEngine(historicalData []model.DataPoint, trackIterations int) *BacktestEngine {
+	return &BacktestEngine{
+		Performance:     make(map[string]PerformanceMetrics),
+		HistoricalData:  historicalData,
+		TrackIterations: trackIterations,
+		Capital:         DefaultCapital,
+	}
 }
 
 func (be *BacktestEngine) AddAlgorithm(algo algorithm.TradingAlgorithm) {
@@ -24,27 +44,78 @@ func (be *BacktestEngine) AddAllAlgorithm(algos []algorithm.TradingAlgorithm) {
 	be.Algorithms = append(be.Algorithms, algos...)
 }
 
+// AddStrategy adds the algorithms of a strategy. Their positions are sized by the strategy's sizing
+// and closed by its exit rules, or after the tracked iterations when no rule fires first.
+func (be *BacktestEngine) AddStrategy(s *strategy.Strategy) {
+	if be.strategies == nil {
+		be.strategies = make(map[string]*strategy.Strategy)
+	}
+	for _, algo := range s.Algorithms {
+		be.strategies[algo.Name()] = s
+	}
+	be.AddAllAlgorithm(s.Algorithms)
+}
+
+// Run simulates every algorithm, prints the performance and closes the algorithms.
 func (be *BacktestEngine) Run(ctx context.Context) {
-	for _, dataPoint := range be.HistoricalData {
-		for _, algo := range be.Algorithms {
-			signal := algo.Evaluate(ctx, dataPoint)
+	be.Simulate(ctx)
+	be.printIterationPerformance()
+	if err := be.Close(); err != nil {
+		fmt.Printf("Error closing algorithms: %v\n", err)
+	}
+}
+
+// Close releases the algorithms holding resources, such as plugin processes. Call it once the
+// engine is done simulating, Run does so itself.
+func (be *BacktestEngine) Close() error {
+	var errs []error
+	for _, algo := range be.Algorithms {
+		if closer, ok := algo.(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Simulate evaluates every algorithm over the historical data and records the performance without printing it.
+func (be *BacktestEngine) Simulate(ctx context.Context) {
+	if be.Performance == nil {
+		be.Performance = make(map[string]PerformanceMetrics)
+	}
+	// Batch algorithms, such as plugins, are evaluated over the whole data up front.
+	batchSignals := make(map[int][]model.TradingSignal)
+	for i, algo := range be.Algorithms {
+		if batchAlgo, ok := algo.(algorithm.BatchTradingAlgorithm); ok {
+			batchSignals[i] = batchAlgo.EvaluateBatch(ctx, be.HistoricalData)
+		}
+	}
+
+	for d, dataPoint := range be.HistoricalData {
+		for i, algo := range be.Algorithms {
+			var signal model.TradingSignal
+			if signals, ok := batchSignals[i]; ok {
+				signal = signals[d]
+			} else {
+				signal = algo.Evaluate(ctx, dataPoint)
+			}
 			be.recordPerformance(algo.Name(), signal, dataPoint)
 		}
 	}
-	be.printIterationPerformance()
 }
 
 func (be *BacktestEngine) recordPerformance(algoName string, signal model.TradingSignal, dataPoint model.DataPoint) {
 	metrics := be.getPerformanceMetrics(algoName)
-	be.handleNewPosition(metrics, signal, dataPoint)
+	strat := be.strategies[algoName]
+	be.handleNewPosition(metrics, strat, signal, dataPoint)
 
 	// Update existing open positions
-	completedPositions := be.updateOpenPositions(metrics, dataPoint)
+	completedPositions := be.updateOpenPositions(metrics, strat, dataPoint)
 
 	// Remove completed positions from active positions
 	be.filterActivePositions(metrics)
 	// Move completed positions to the completed list
 	metrics.CompletedPositions = append(metrics.CompletedPositions, completedPositions...)
+	be.Performance[algoName] = *metrics
 }
 
 func (be *BacktestEngine) getPerformanceMetrics(algoName string) *PerformanceMetrics {
@@ -59,11 +130,21 @@ func (be *BacktestEngine) getPerformanceMetrics(algoName string) *PerformanceMet
 	return &metrics
 }
 
-func (be *BacktestEngine) handleNewPosition(metrics *PerformanceMetrics, signal model.TradingSignal, dataPoint model.DataPoint) {
+// handleNewPosition opens a position on a Buy or Sell signal. Positions of algorithms without a strategy
+// have a quantity of 1, a signal the strategy sizes to nothing opens no position.
+func (be *BacktestEngine) handleNewPosition(metrics *PerformanceMetrics, strat *strategy.Strategy, signal model.TradingSignal, dataPoint model.DataPoint) {
 	if signal.Action == model.Buy || signal.Action == model.Sell {
+		quantity := 1.0
+		if strat != nil {
+			quantity = strat.Sizing.Quantity(be.Capital, dataPoint.Close, signal)
+		}
+		if quantity <= 0 {
+			return
+		}
 		newPosition := OpenPosition{
 			EntryPoint:      dataPoint,
 			Signal:          signal,
+			Quantity:        quantity,
 			CurrentProfit:   0,
 			IterationCount:  0,
 			TotalPeEarnings: 0,
@@ -73,7 +154,7 @@ func (be *BacktestEngine) handleNewPosition(metrics *PerformanceMetrics, signal
 	}
 }
 
-func (be *BacktestEngine) updateOpenPositions(metrics *PerformanceMetrics, dataPoint model.DataPoint) []OpenPosition {
+func (be *BacktestEngine) updateOpenPositions(metrics *PerformanceMetrics, strat *strategy.Strategy, dataPoint model.DataPoint) []OpenPosition {
 	var completedPositions []OpenPosition
 
 	for i := 0; i < len(metrics.ActivePositions); i++ {
@@ -99,9 +180,13 @@ func (be *BacktestEngine) updateOpenPositions(metrics *PerformanceMetrics, dataP
 		// Update performance metrics
 		be.updatePerformanceMetrics(metrics, profit)
 
-		// Check if the position has reached the tracking limit
-		if position.IterationCount >= be.TrackIterations {
+		if strat != nil {
+			position.ExitReason = exitReason(strat.Exit, position)
+		}
+		// Check if the position has reached the tracking limit or an exit rule closed it
+		if position.IterationCount >= be.TrackIterations || position.ExitReason != "" {
 			metrics.Trades++
+			metrics.NetProfit += profit * position.Quantity
 			// Move the position to completed positions
 			completedPositions = append(completedPositions, *position)
 		}
@@ -109,6 +194,20 @@ func (be *BacktestEngine) updateOpenPositions(metrics *PerformanceMetrics, dataP
 	return completedPositions
 }
 
+// exitReason returns the exit rule closing the position on its latest close, or "" when none does.
+func exitReason(exit strategy.ExitRules, position *OpenPosition) string {
+	profitPercentage := position.CurrentProfit / position.EntryPoint.Close * 100
+	switch {
+	case exit.StopLossPercent > 0 && profitPercentage <= -exit.StopLossPercent:
+		return ExitStopLoss
+	case exit.TakeProfitPercent > 0 && profitPercentage >= exit.TakeProfitPercent:
+		return ExitTakeProfit
+	case exit.MaxBars > 0 && position.IterationCount >= exit.MaxBars:
+		return ExitMaxBars
+	}
+	return ""
+}
+
 func (be *BacktestEngine) calculateProfit(position *OpenPosition, dataPoint model.DataPoint) float64 {
 	if position.Signal.Action == model.Buy {
 		return dataPoint.Close - position.EntryPoint.Close
@@ -126,7 +225,7 @@ func (be *BacktestEngine) updatePerformanceMetrics(metrics *PerformanceMetrics,
 func (be *BacktestEngine) filterActivePositions(metrics *PerformanceMetrics) {
 	newActivePositions := []OpenPosition{}
 	for _, position := range metrics.ActivePositions {
-		if position.IterationCount < be.TrackIterations {
+		if position.IterationCount < be.TrackIterations && position.ExitReason == "" {
 			newActivePositions = append(newActivePositions, position)
 		}
 	}
@@ -178,9 +277,10 @@ func (be *BacktestEngine) aggregateIterationData(position OpenPosition, iteratio
 
 func (be *BacktestEngine) printIterationMetrics(algoName string, metrics PerformanceMetrics, iterationSummaryData map[int]*IterationSummaryMetrics) {
 	fmt.Printf("Algorithm: %s\n", algoName)
+	fmt.Printf("Net Profit: %.2f\n", metrics.NetProfit)
 	fmt.Println("Performance by Iteration:")
 
-	fmt.Printf("|%-13s | %-5s | ", "Position Time", "Signal")
+	fmt.Printf("|%-13s | %-6s | %-8s | ", "Position Time", "Signal", "Strength")
 	for i := 1; i <= len(iterationSummaryData); i++ {
 		fmt.Printf("%9d | ", i)
 	}
@@ -188,7 +288,7 @@ func (be *BacktestEngine) printIterationMetrics(algoName string, metrics Perform
 	fmt.Println("|---------------------------------------------------------------------------------------------------")
 
 	for _, position := range metrics.ActivePositions {
-		fmt.Printf("|%-13d | %-5s | ", position.Signal.Time, position.Signal.Action)
+		fmt.Printf("|%-13d | %-6s | %-8.2f | ", position.Signal.Time, position.Signal.Action, position.Signal.Strength)
 		for _, iterData := range position.IterationData {
 			profitPercentage := (iterData.Profit / position.EntryPoint.Close) * 100 // Profit in percentage
 			fmt.Printf("%-9.2f | ", profitPercentage)
@@ -196,7 +296,7 @@ func (be *BacktestEngine) printIterationMetrics(algoName string, metrics Perform
 		fmt.Println()
 	}
 	for _, position := range metrics.CompletedPositions {
-		fmt.Printf("|%-13d | %-5s | ", position.Signal.Time, position.Signal.Action)
+		fmt.Printf("|%-13d | %-6s | %-8.2f | ", position.Signal.Time, position.Signal.Action, position.Signal.Strength)
 		for _, iterData := range position.IterationData {
 			profitPercentage := (iterData.Profit / position.EntryPoint.Close) * 100 // Profit in percentage
 			fmt.Printf("%-9.2f | ", profitPercentage)
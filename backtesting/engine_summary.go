@@ -0,0 +1,67 @@
+package backtesting
+
+import (
+	"math"
+)
+
+// PerformanceSummary condenses the completed positions of an algorithm into comparable figures.
+// Percentages are relative to the entry price of each position.
+type PerformanceSummary struct {
+	Trades                  int
+	Wins                    int
+	WinRate                 float64
+	TotalProfitPercentage   float64
+	AverageProfitPercentage float64
+	MaxDrawdownPercentage   float64
+	SharpeRatio             float64
+}
+
+// Summary returns the performance summary of the algorithm with the given name.
+func (be *BacktestEngine) Summary(algoName string) PerformanceSummary {
+	return SummarizePositions(be.Performance[algoName].CompletedPositions)
+}
+
+// SummarizePositions calculates the summary over positions in the order they were opened.
+func SummarizePositions(positions []OpenPosition) PerformanceSummary {
+	returns := make([]float64, 0, len(positions))
+	for _, position := range positions {
+		if len(position.IterationData) == 0 || position.EntryPoint.Close == 0 {
+			continue
+		}
+		finalProfit := position.IterationData[len(position.IterationData)-1].Profit
+		returns = append(returns, (finalProfit/position.EntryPoint.Close)*100)
+	}
+	return SummarizeReturns(returns)
+}
+
+// SummarizeReturns calculates the summary over the percentage returns of trades in the order they were opened.
+func SummarizeReturns(returns []float64) PerformanceSummary {
+	summary := PerformanceSummary{}
+	if len(returns) == 0 {
+		return summary
+	}
+
+	equity, peak := 0.0, 0.0
+	for _, r := range returns {
+		summary.Trades++
+		if r > 0 {
+			summary.Wins++
+		}
+		summary.TotalProfitPercentage += r
+
+		equity += r
+		peak = math.Max(peak, equity)
+		summary.MaxDrawdownPercentage = math.Max(summary.MaxDrawdownPercentage, peak-equity)
+	}
+	summary.WinRate = (float64(summary.Wins) / float64(summary.Trades)) * 100
+	summary.AverageProfitPercentage = summary.TotalProfitPercentage / float64(summary.Trades)
+
+	variance := 0.0
+	for _, r := range returns {
+		variance += math.Pow(r-summary.AverageProfitPercentage, 2)
+	}
+	if stdDev := math.Sqrt(variance / float64(len(returns))); stdDev > 0 {
+		summary.SharpeRatio = summary.AverageProfitPercentage / stdDev
+	}
+	return summary
+}
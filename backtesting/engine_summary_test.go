@@ -0,0 +1,191 @@
+package backtesting_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/algorithm"
+	"github.com/vd09/trading-algorithm-backtesting-system/backtesting"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/strategy"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+// alternatingAlgorithm buys on the first bar and then alternates between selling and buying.
+type alternatingAlgorithm struct {
+	bars int
+}
+
+func (a *alternatingAlgorithm) Name() string {
+	return "alternating"
+}
+
+func (a *alternatingAlgorithm) Evaluate(ctx context.Context, data model.DataPoint) model.TradingSignal {
+	a.bars++
+	if a.bars%2 == 1 {
+		return model.TradingSignal{Time: data.Time, Action: model.Buy}
+	}
+	return model.TradingSignal{Time: data.Time, Action: model.Sell}
+}
+
+// batchAlternatingAlgorithm alternates like alternatingAlgorithm but only through EvaluateBatch.
+type batchAlternatingAlgorithm struct {
+	alternatingAlgorithm
+	batches int
+}
+
+func (a *batchAlternatingAlgorithm) Name() string {
+	return "batch"
+}
+
+func (a *batchAlternatingAlgorithm) Evaluate(ctx context.Context, data model.DataPoint) model.TradingSignal {
+	panic("batch algorithms are evaluated in batches")
+}
+
+func (a *batchAlternatingAlgorithm) EvaluateBatch(ctx context.Context, data []model.DataPoint) []model.TradingSignal {
+	a.batches++
+	signals := make([]model.TradingSignal, len(data))
+	for i, dp := range data {
+		signals[i] = a.alternatingAlgorithm.Evaluate(ctx, dp)
+	}
+	return signals
+}
+
+func TestSimulateBatchAlgorithm(t *testing.T) {
+	data := []model.DataPoint{{Time: 1, Close: 100}, {Time: 2, Close: 110}, {Time: 3, Close: 99}}
+	batch := &batchAlternatingAlgorithm{}
+	engine := backtesting.NewBacktestEngine(data, 2)
+	engine.AddAllAlgorithm([]algorithm.TradingAlgorithm{&alternatingAlgorithm{}, batch})
+	engine.Simulate(context.Background())
+
+	test_utils.AssertEqual(t, 1, batch.batches, "Expected a single batch over the whole data")
+	test_utils.AssertEqual(t, engine.Summary("alternating"), engine.Summary("batch"), "Batch and per bar evaluation differ")
+}
+
+// closingAdaptor always waits and counts how often it is closed, like a plugin holding a process.
+type closingAdaptor struct {
+	closed int
+}
+
+func (a *closingAdaptor) Name() string {
+	return "closing"
+}
+
+func (a *closingAdaptor) Clone(ctx context.Context) indicator_adaptor.IndicatorAdaptor {
+	return &closingAdaptor{}
+}
+
+func (a *closingAdaptor) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	return nil
+}
+
+func (a *closingAdaptor) GetSignal(ctx context.Context) model.TradingSignal {
+	return model.TradingSignal{Action: model.Wait}
+}
+
+func (a *closingAdaptor) Close() error {
+	a.closed++
+	return nil
+}
+
+func TestRunClosesAlgorithms(t *testing.T) {
+	adaptor := &closingAdaptor{}
+	combination := algorithm.NewCombinationTradingAlgorithm(context.Background(), []indicator_adaptor.IndicatorAdaptor{adaptor}, test_utils.NewMockMetricsCollector(t))
+	engine := backtesting.NewBacktestEngine([]model.DataPoint{{Time: 1, Close: 100}}, 1)
+	engine.AddAllAlgorithm([]algorithm.TradingAlgorithm{&alternatingAlgorithm{}, combination})
+	engine.Run(context.Background())
+
+	test_utils.AssertEqual(t, 1, adaptor.closed, "Expected the run to close the adaptor of the combination")
+}
+
+func TestSimulateRecordsPerformance(t *testing.T) {
+	data := []model.DataPoint{
+		{Time: 1, Close: 100},
+		{Time: 2, Close: 110},
+		{Time: 3, Close: 99},
+		{Time: 4, Close: 99},
+		{Time: 5, Close: 99.99},
+		{Time: 6, Close: 99.99},
+	}
+	engine := backtesting.NewBacktestEngine(data, 2)
+	engine.AddAlgorithm(&alternatingAlgorithm{})
+	engine.Simulate(context.Background())
+
+	metrics := engine.Performance["alternating"]
+	test_utils.AssertEqual(t, 5, metrics.Trades, "Number of trades does not match")
+	test_utils.AssertEqual(t, 5, len(metrics.CompletedPositions), "Number of completed positions does not match")
+	test_utils.AssertEqual(t, 1, len(metrics.ActivePositions), "Number of active positions does not match")
+
+	summary := engine.Summary("alternating")
+	test_utils.AssertEqual(t, 5, summary.Trades, "Summary trades do not match")
+	test_utils.AssertEqual(t, 2, summary.Wins, "Summary wins do not match")
+	test_utils.AssertEqual(t, 40.0, summary.WinRate, "Win rate does not match")
+	// Returns are 10%, 10%, 0%, -1% and 0% with a drawdown of one percent after the peak.
+	test_utils.AssertEqual(t, 19.0, round(summary.TotalProfitPercentage), "Total profit does not match")
+	test_utils.AssertEqual(t, 1.0, round(summary.MaxDrawdownPercentage), "Max drawdown does not match")
+}
+
+// scriptedAlgorithm buys at the given times and waits otherwise.
+type scriptedAlgorithm struct {
+	buys map[int64]bool
+}
+
+func (a *scriptedAlgorithm) Name() string {
+	return "scripted"
+}
+
+func (a *scriptedAlgorithm) Evaluate(ctx context.Context, data model.DataPoint) model.TradingSignal {
+	if a.buys[data.Time] {
+		return model.TradingSignal{Time: data.Time, Action: model.Buy, Strength: 1}
+	}
+	return model.TradingSignal{Time: data.Time, Action: model.Wait}
+}
+
+func TestSimulateAppliesStrategyExitsAndSizing(t *testing.T) {
+	data := []model.DataPoint{
+		{Time: 1, Close: 100},
+		{Time: 2, Close: 94},
+		{Time: 3, Close: 100},
+		{Time: 4, Close: 111},
+		{Time: 5, Close: 100},
+		{Time: 6, Close: 101},
+		{Time: 7, Close: 102},
+	}
+	engine := backtesting.NewBacktestEngine(data, 10)
+	engine.AddStrategy(&strategy.Strategy{
+		Name:       "scripted",
+		Exit:       strategy.ExitRules{StopLossPercent: 5, TakeProfitPercent: 10, MaxBars: 3},
+		Sizing:     strategy.Sizing{Method: strategy.FixedQuantity, Value: 2},
+		Algorithms: []algorithm.TradingAlgorithm{&scriptedAlgorithm{buys: map[int64]bool{1: true, 3: true, 5: true}}},
+	})
+	engine.Simulate(context.Background())
+
+	metrics := engine.Performance["scripted"]
+	test_utils.AssertEqual(t, 0, len(metrics.ActivePositions), "Expected the exit rules to close every position")
+	var reasons []string
+	for _, position := range metrics.CompletedPositions {
+		reasons = append(reasons, position.ExitReason)
+		test_utils.AssertEqual(t, 2.0, position.Quantity, "Quantity does not match the sizing")
+	}
+	test_utils.AssertEqual(t, []string{backtesting.ExitStopLoss, backtesting.ExitTakeProfit, backtesting.ExitMaxBars}, reasons, "Exit reasons do not match")
+	// Profits are -6, 11 and 2 per unit on two units each.
+	test_utils.AssertEqual(t, 14.0, metrics.NetProfit, "Net profit does not match")
+
+	fraction := backtesting.NewBacktestEngine(data[:1], 10)
+	fraction.AddStrategy(&strategy.Strategy{
+		Sizing:     strategy.Sizing{Method: strategy.FixedFraction, Value: 0.1},
+		Algorithms: []algorithm.TradingAlgorithm{&scriptedAlgorithm{buys: map[int64]bool{1: true}}},
+	})
+	fraction.Simulate(context.Background())
+	test_utils.AssertEqual(t, 100.0, fraction.Performance["scripted"].ActivePositions[0].Quantity, "Expected a tenth of the capital at 100")
+}
+
+func round(value float64) float64 {
+	return math.Round(value*1e6) / 1e6
+}
+
+func TestSummarizePositionsEmpty(t *testing.T) {
+	test_utils.AssertEqual(t, backtesting.PerformanceSummary{}, backtesting.SummarizePositions(nil), "Empty summary does not match")
+}
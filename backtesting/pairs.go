@@ -0,0 +1,87 @@
+package backtesting
+
+import (
+	"context"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/algorithm"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// PairTrade is a completed position of a pair trading algorithm.
+type PairTrade struct {
+	Entry model.TradingSignal
+	Exit  model.TradingSignal
+	// EntryA, EntryB, ExitA and ExitB are the bars of both instruments when the position was opened and closed.
+	EntryA model.DataPoint
+	EntryB model.DataPoint
+	ExitA  model.DataPoint
+	ExitB  model.DataPoint
+	// ReturnPercentage is the return on the notional of both legs.
+	ReturnPercentage float64
+}
+
+// AlignPairs keeps the bars present in both series, matched by time.
+func AlignPairs(a, b []model.DataPoint) ([]model.DataPoint, []model.DataPoint) {
+	byTime := make(map[int64]model.DataPoint, len(b))
+	for _, point := range b {
+		byTime[point.Time] = point
+	}
+	var alignedA, alignedB []model.DataPoint
+	for _, point := range a {
+		if other, ok := byTime[point.Time]; ok {
+			alignedA = append(alignedA, point)
+			alignedB = append(alignedB, other)
+		}
+	}
+	return alignedA, alignedB
+}
+
+// SimulatePair runs the algorithm over the bars both series have in common. A signal opposite to the open position
+// closes it, and a position still open after the last bar is closed on it.
+func SimulatePair(ctx context.Context, algo algorithm.PairTradingAlgorithm, a, b []model.DataPoint) ([]PairTrade, PerformanceSummary) {
+	a, b = AlignPairs(a, b)
+	var trades []PairTrade
+	var open *PairTrade
+	for i := range a {
+		signal := algo.EvaluatePair(ctx, a[i], b[i])
+		if signal.Action == model.Wait || len(signal.Legs) != 2 {
+			continue
+		}
+		if open == nil {
+			open = &PairTrade{Entry: signal, EntryA: a[i], EntryB: b[i]}
+			continue
+		}
+		if signal.Action != open.Entry.Action {
+			trades = append(trades, closePairTrade(*open, signal, a[i], b[i]))
+			open = nil
+		}
+	}
+	if open != nil && len(a) > 0 {
+		last := model.TradingSignal{Time: a[len(a)-1].Time, Action: model.Wait}
+		trades = append(trades, closePairTrade(*open, last, a[len(a)-1], b[len(b)-1]))
+	}
+
+	returns := make([]float64, len(trades))
+	for i, trade := range trades {
+		returns[i] = trade.ReturnPercentage
+	}
+	return trades, SummarizeReturns(returns)
+}
+
+func closePairTrade(trade PairTrade, exit model.TradingSignal, exitA, exitB model.DataPoint) PairTrade {
+	trade.Exit, trade.ExitA, trade.ExitB = exit, exitA, exitB
+	trade.ReturnPercentage = (legReturn(trade.Entry.Legs[0], trade.EntryA, exitA) + legReturn(trade.Entry.Legs[1], trade.EntryB, exitB)) * 100
+	return trade
+}
+
+// legReturn is the return of the leg weighted by its share of the notional.
+func legReturn(leg model.Leg, entry, exit model.DataPoint) float64 {
+	if entry.Close == 0 {
+		return 0
+	}
+	change := (exit.Close - entry.Close) / entry.Close
+	if leg.Action == model.Sell {
+		change = -change
+	}
+	return leg.Weight * change
+}
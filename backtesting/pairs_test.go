@@ -0,0 +1,46 @@
+package backtesting_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/backtesting"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+// scriptedPairAlgorithm sells the spread on the second bar it sees and buys it back on the fourth.
+type scriptedPairAlgorithm struct {
+	bars int
+}
+
+func (a *scriptedPairAlgorithm) Name() string {
+	return "scripted_pair"
+}
+
+func (a *scriptedPairAlgorithm) EvaluatePair(ctx context.Context, x, y model.DataPoint) model.TradingSignal {
+	a.bars++
+	legs := []model.Leg{{Ticker: "A", Action: model.Sell, Weight: 0.5}, {Ticker: "B", Action: model.Buy, Weight: 0.5}}
+	switch a.bars {
+	case 2:
+		return model.TradingSignal{Time: x.Time, Action: model.Sell, Legs: legs}
+	case 4:
+		return model.TradingSignal{Time: x.Time, Action: model.Buy, Legs: []model.Leg{{Ticker: "A", Action: model.Buy, Weight: 0.5}, {Ticker: "B", Action: model.Sell, Weight: 0.5}}}
+	}
+	return model.TradingSignal{Time: x.Time, Action: model.Wait}
+}
+
+func TestSimulatePair(t *testing.T) {
+	a := []model.DataPoint{{Time: 1, Close: 100}, {Time: 2, Close: 100}, {Time: 3, Close: 105}, {Time: 4, Close: 95}, {Time: 5, Close: 90}}
+	// B misses time 3, so the algorithm only sees times 1, 2, 4 and 5.
+	b := []model.DataPoint{{Time: 1, Close: 50}, {Time: 2, Close: 50}, {Time: 4, Close: 55}, {Time: 5, Close: 60}}
+
+	trades, summary := backtesting.SimulatePair(context.Background(), &scriptedPairAlgorithm{}, a, b)
+	test_utils.AssertEqual(t, 1, len(trades), "The scripted position should be closed once")
+	test_utils.AssertEqual(t, int64(2), trades[0].Entry.Time, "The position should open on the second common bar")
+	test_utils.AssertEqual(t, int64(5), trades[0].Exit.Time, "The position should close on the fourth common bar")
+	// Short A from 100 to 90 earns 10%, long B from 50 to 60 earns 20%, half the notional each.
+	test_utils.AssertTrue(t, math.Abs(trades[0].ReturnPercentage-15) < 1e-9, "The return should weigh both legs")
+	test_utils.AssertEqual(t, 1, summary.Wins, "The trade should be a win")
+}
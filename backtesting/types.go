@@ -2,17 +2,30 @@ package backtesting
 
 import "github.com/vd09/trading-algorithm-backtesting-system/model"
 
+// Exit reasons of positions closed by the exit rules of their strategy.
+const (
+	ExitStopLoss   = "stop_loss"
+	ExitTakeProfit = "take_profit"
+	ExitMaxBars    = "max_bars"
+)
+
 type PerformanceMetrics struct {
-	Trades             int
-	MaxProfit          float64
-	MinProfit          float64
+	Trades    int
+	MaxProfit float64
+	MinProfit float64
+	// NetProfit is the profit of the completed positions weighted by their quantity.
+	NetProfit          float64
 	ActivePositions    []OpenPosition
 	CompletedPositions []OpenPosition
 }
 
 type OpenPosition struct {
-	EntryPoint      model.DataPoint
-	Signal          model.TradingSignal
+	EntryPoint model.DataPoint
+	Signal     model.TradingSignal
+	// Quantity is the size of the position, 1 unless the strategy of the algorithm sizes it.
+	Quantity float64
+	// ExitReason is the exit rule which closed the position, empty when it ran for the tracked iterations.
+	ExitReason      string
 	TotalPeEarnings int
 	CurrentProfit   float64
 	IterationCount  int
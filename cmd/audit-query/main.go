@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/audit"
+)
+
+func main() {
+	journal := flag.String("journal", "", "journal file to query")
+	run := flag.String("run", "", "only decisions of this run ID")
+	phase := flag.String("phase", "", "only decisions of this search phase, e.g. train or holdout")
+	generation := flag.Int("generation", -1, "only decisions of this search generation, all when negative")
+	algorithm := flag.String("algorithm", "", "only algorithms whose name contains this text")
+	from := flag.Int64("from", 0, "only bars at or after this time")
+	to := flag.Int64("to", 0, "only bars at or before this time")
+	actions := flag.String("actions", "", "comma separated actions to keep, e.g. buy,sell")
+	flag.Parse()
+	if *journal == "" {
+		log.Fatal("-journal is required")
+	}
+
+	filter := audit.Filter{RunID: *run, Phase: *phase, Algorithm: *algorithm, From: *from, To: *to}
+	if *generation >= 0 {
+		filter.Generation = generation
+	}
+	if *actions != "" {
+		filter.Actions = strings.Split(*actions, ",")
+	}
+	file, err := os.Open(*journal)
+	if err != nil {
+		log.Fatalf("Failed to open journal: %v", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(os.Stdout)
+	if err := audit.Scan(file, filter, func(decision audit.Decision) error {
+		return encoder.Encode(decision)
+	}); err != nil {
+		log.Fatalf("Failed to query journal: %v", err)
+	}
+}
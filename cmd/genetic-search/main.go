@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/audit"
+	"github.com/vd09/trading-algorithm-backtesting-system/config"
+	"github.com/vd09/trading-algorithm-backtesting-system/datafetcher"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/optimizer"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils"
+)
+
+func main() {
+	adaptors := flag.String("adaptors", "", "comma separated adaptor specs to search, defaults to all")
+	objectiveName := flag.String("objective", optimizer.TotalProfit.Name, "objective to maximise")
+	population := flag.Int("population", 30, "population size")
+	generations := flag.Int("generations", 20, "total number of generations")
+	elitism := flag.Int("elitism", 2, "individuals copied unchanged into the next generation")
+	crossover := flag.Float64("crossover", 0.7, "crossover rate")
+	mutation := flag.Float64("mutation", 0.2, "mutation rate")
+	maxGenes := flag.Int("max-genes", 3, "maximum number of adaptors per strategy")
+	holdout := flag.Float64("holdout", 0.2, "trailing fraction of the data held out to detect overfitting")
+	penalty := flag.Float64("overfit-penalty", 1, "weight of the train/holdout score gap in the fitness")
+	seed := flag.Int64("seed", 1, "random seed")
+	workers := flag.Int("workers", 0, "parallel backtests, defaults to the number of CPUs")
+	trackIterations := flag.Int("track", 10, "bars tracked after every position entry")
+	checkpoint := flag.String("checkpoint", "genetic-search.json", "checkpoint file written after every generation")
+	resume := flag.Bool("resume", false, "resume from the checkpoint file")
+	journalPath := flag.String("journal", "", "JSON lines journal recording every combination decision, off when empty")
+	flag.Parse()
+
+	config.InitConfig()
+	objective, err := optimizer.ObjectiveByName(*objectiveName)
+	if err != nil {
+		log.Fatalf("Invalid objective: %v", err)
+	}
+	specs, err := selectSpecs(*adaptors)
+	if err != nil {
+		log.Fatalf("Invalid adaptors: %v", err)
+	}
+
+	request := &model.HistoricalDataRequest{
+		Ticker:    config.AppConfig.Ticker,
+		Interval:  config.AppConfig.Interval,
+		Timespan:  model.Timespan(config.AppConfig.Timespan),
+		StartDate: timeutil(config.AppConfig.StartDate),
+		EndDate:   timeutil(config.AppConfig.EndDate),
+	}
+	response, err := datafetcher.GetHistoricalData(request)
+	if err != nil {
+		log.Fatalf("Error fetching data: %v", err)
+	}
+
+	search := &optimizer.GeneticSearch{
+		Specs:           specs,
+		Objective:       objective,
+		TrackIterations: *trackIterations,
+		Workers:         *workers,
+		Monitor:         monitor.NewPrometheusMonitoring(),
+		PopulationSize:  *population,
+		Generations:     *generations,
+		Elitism:         *elitism,
+		CrossoverRate:   *crossover,
+		MutationRate:    *mutation,
+		MaxGenes:        *maxGenes,
+		HoldoutFraction: *holdout,
+		OverfitPenalty:  *penalty,
+		Seed:            *seed,
+		OnGeneration: func(cp optimizer.Checkpoint) error {
+			stats := cp.History[len(cp.History)-1]
+			fmt.Printf("generation %d: best %.2f mean %.2f %s\n", stats.Generation, stats.BestFitness, stats.MeanFitness, stats.BestGenome)
+			return optimizer.SaveCheckpoint(*checkpoint, cp)
+		},
+	}
+
+	var cp optimizer.Checkpoint
+	if *resume {
+		if cp, err = optimizer.LoadCheckpoint(*checkpoint); err != nil {
+			log.Fatalf("Error loading checkpoint: %v", err)
+		}
+	}
+
+	ctx, closeJournal := withJournal(context.Background(), *journalPath)
+	var result *optimizer.GeneticResult
+	if *resume {
+		result, err = search.Resume(ctx, response.Results, cp)
+	} else {
+		result, err = search.Run(ctx, response.Results)
+	}
+	closeJournal()
+	if err != nil {
+		log.Fatalf("Error running genetic search: %v", err)
+	}
+	printBest(result)
+}
+
+// withJournal records the decisions of the run under ctx to the journal at path, when one is given.
+// The returned function flushes and closes the journal.
+func withJournal(ctx context.Context, path string) (context.Context, func()) {
+	if path == "" {
+		return ctx, func() {}
+	}
+	journal, err := audit.OpenJournal(path, audit.NewRunID())
+	if err != nil {
+		log.Fatalf("Error opening journal: %v", err)
+	}
+	fmt.Printf("Recording the decisions of run %s to %s\n", journal.RunID(), path)
+	return audit.WithRecorder(ctx, journal), func() {
+		if err := journal.Close(); err != nil {
+			log.Printf("Error closing journal: %v", err)
+		}
+	}
+}
+
+func selectSpecs(names string) ([]optimizer.AdaptorSpec, error) {
+	specs := optimizer.DefaultAdaptorSpecs()
+	if names == "" {
+		return specs, nil
+	}
+	var selected []optimizer.AdaptorSpec
+	for _, name := range strings.Split(names, ",") {
+		found := false
+		for _, spec := range specs {
+			if spec.Name == strings.TrimSpace(name) {
+				selected = append(selected, spec)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown adaptor spec %q", name)
+		}
+	}
+	return selected, nil
+}
+
+func printBest(result *optimizer.GeneticResult) {
+	if result.Best == nil {
+		fmt.Println("No generation evaluated")
+		return
+	}
+	best := result.Best
+	fmt.Printf("Best strategy (%s): %s\n", result.Objective, best.Genome.Key())
+	fmt.Printf("Fitness: %.2f, train score: %.2f\n", best.Fitness, best.Train.Score)
+	fmt.Printf("Train: %d trades, %.2f%% win rate, %.2f%% total profit\n", best.Train.Summary.Trades, best.Train.Summary.WinRate, best.Train.Summary.TotalProfitPercentage)
+	if best.Holdout != nil {
+		fmt.Printf("Holdout: %d trades, %.2f%% win rate, %.2f%% total profit, score %.2f\n", best.Holdout.Summary.Trades, best.Holdout.Summary.WinRate, best.Holdout.Summary.TotalProfitPercentage, best.HoldoutScore)
+	}
+}
+
+func timeutil(t string) utils.TimeUtil {
+	format, err := utils.NewTimeUtilFromFormat(t)
+	if err != nil {
+		log.Fatalf("Invalid date %q: %v", t, err)
+	}
+	return format
+}
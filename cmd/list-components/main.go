@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	// The algorithm package registers the built-in algorithms, and through indicator_adaptor the built-in adaptors.
+	_ "github.com/vd09/trading-algorithm-backtesting-system/algorithm"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
+)
+
+func main() {
+	kind := flag.String("kind", "all", "components to list: adaptors, algorithms, pairs or all")
+	flag.Parse()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if *kind == "all" || *kind == "adaptors" {
+		fmt.Fprintln(w, "ADAPTORS")
+		for _, entry := range registry.Adaptors() {
+			printComponent(w, entry.Name, entry.Description, entry.Params)
+		}
+	}
+	if *kind == "all" || *kind == "algorithms" {
+		fmt.Fprintln(w, "ALGORITHMS")
+		for _, entry := range registry.Algorithms() {
+			description := entry.Description
+			if entry.UsesAdaptors {
+				description += " (takes adaptors)"
+			}
+			printComponent(w, entry.Name, description, entry.Params)
+		}
+	}
+	if *kind == "all" || *kind == "pairs" {
+		fmt.Fprintln(w, "PAIR ALGORITHMS")
+		for _, entry := range registry.PairAlgorithms() {
+			printComponent(w, entry.Name, entry.Description, entry.Params)
+		}
+	}
+	w.Flush()
+}
+
+func printComponent(w *tabwriter.Writer, name, description string, params []registry.Param) {
+	fmt.Fprintf(w, "  %s: %s\n", name, description)
+	for _, param := range params {
+		value := fmt.Sprintf("default %v", param.Default)
+		if param.Required {
+			value = "required"
+		}
+		fmt.Fprintf(w, "    %s\t%s\t%s\t%s\t%s\n", param.Name, param.Type, value, param.Range(), param.Description)
+	}
+}
@@ -0,0 +1,238 @@
+package expression
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils"
+)
+
+// calculator adapts an indicator from the indicator package to a set of named output fields.
+type calculator interface {
+	update(ctx context.Context, data model.DataPoint) error
+	ready() bool
+	values() []float64
+	save() CalculatorState
+	restore(state CalculatorState) bool
+}
+
+// CalculatorState holds the indicator behind an expression indicator call, only the field of its type is set.
+type CalculatorState struct {
+	RSI        *indicator.RSI            `json:",omitempty"`
+	EMA        *indicator.EMA            `json:",omitempty"`
+	MACD       *indicator.MACD           `json:",omitempty"`
+	SuperTrend *indicator.SuperTrend     `json:",omitempty"`
+	Bollinger  *indicator.BollingerBands `json:",omitempty"`
+	Pivot      *indicator.PivotPoint     `json:",omitempty"`
+	ADX        *indicator.ADX            `json:",omitempty"`
+}
+
+// indicatorSpec describes an indicator function usable inside expressions.
+type indicatorSpec struct {
+	defaults []float64
+	// periods is the number of leading parameters that count bars, they must be whole numbers.
+	periods int
+	// validate optionally checks the parameters against each other.
+	validate func(args []float64) error
+	// fields lists the outputs of the indicator, the first one is used when no field is selected.
+	fields []string
+	build  func(args []float64) calculator
+}
+
+var indicatorSpecs = map[string]indicatorSpec{
+	"rsi": {
+		defaults: []float64{14},
+		periods:  1,
+		fields:   []string{"value"},
+		build: func(args []float64) calculator {
+			return &indicatorCalculator[float64, *indicator.RSI]{
+				indicator: indicator.NewRSI(int(args[0])),
+				slot:      func(state *CalculatorState) **indicator.RSI { return &state.RSI },
+				fields:    func(value float64) []float64 { return []float64{value} },
+			}
+		},
+	},
+	"ema": {
+		defaults: []float64{20},
+		periods:  1,
+		fields:   []string{"value"},
+		build: func(args []float64) calculator {
+			return &indicatorCalculator[float64, *indicator.EMA]{
+				indicator: indicator.NewEMA(int(args[0])),
+				slot:      func(state *CalculatorState) **indicator.EMA { return &state.EMA },
+				fields:    func(value float64) []float64 { return []float64{value} },
+			}
+		},
+	},
+	"macd": {
+		defaults: []float64{12, 26, 9},
+		periods:  3,
+		fields:   []string{"line", "signal", "histogram"},
+		validate: func(args []float64) error {
+			if args[0] >= args[1] {
+				return fmt.Errorf("short period %g must be lower than long period %g", args[0], args[1])
+			}
+			return nil
+		},
+		build: func(args []float64) calculator {
+			return &indicatorCalculator[indicator.MACDResult, *indicator.MACD]{
+				indicator: indicator.NewMACD(int(args[0]), int(args[1]), int(args[2])),
+				slot:      func(state *CalculatorState) **indicator.MACD { return &state.MACD },
+				fields: func(result indicator.MACDResult) []float64 {
+					return []float64{result.MACDLine, result.MACDSignal, result.MACDHistogram}
+				},
+			}
+		},
+	},
+	"supertrend": {
+		defaults: []float64{10, 3},
+		periods:  1,
+		fields:   []string{"line", "uptrend"},
+		build: func(args []float64) calculator {
+			return &indicatorCalculator[indicator.SuperTrendValues, *indicator.SuperTrend]{
+				indicator: indicator.NewSuperTrend(int(args[0]), args[1]),
+				slot:      func(state *CalculatorState) **indicator.SuperTrend { return &state.SuperTrend },
+				fields: func(values indicator.SuperTrendValues) []float64 {
+					return []float64{values.Line, utils.B2F(values.UpTrend)}
+				},
+			}
+		},
+	},
+	"bollinger": {
+		defaults: []float64{20},
+		periods:  1,
+		fields:   []string{"middle", "upper", "lower"},
+		build: func(args []float64) calculator {
+			return &indicatorCalculator[indicator.BollingerBandsValues, *indicator.BollingerBands]{
+				indicator: indicator.NewBollingerBands(int(args[0])),
+				slot:      func(state *CalculatorState) **indicator.BollingerBands { return &state.Bollinger },
+				fields: func(bands indicator.BollingerBandsValues) []float64 {
+					return []float64{bands.MovingAverage, bands.UpperBand, bands.LowerBand}
+				},
+			}
+		},
+	},
+	"adx": {
+		defaults: []float64{14},
+		periods:  1,
+		fields:   []string{"value", "plus_di", "minus_di"},
+		build: func(args []float64) calculator {
+			return &indicatorCalculator[indicator.ADXValues, *indicator.ADX]{
+				indicator: indicator.NewADX(int(args[0])),
+				slot:      func(state *CalculatorState) **indicator.ADX { return &state.ADX },
+				fields: func(values indicator.ADXValues) []float64 {
+					return []float64{values.ADX, values.PlusDI, values.MinusDI}
+				},
+			}
+		},
+	},
+	"pivot": {
+		defaults: []float64{},
+		fields:   []string{"pivot", "r1", "r2", "r3", "s1", "s2", "s3"},
+		build: func(args []float64) calculator {
+			return &indicatorCalculator[indicator.PivotLevels, *indicator.PivotPoint]{
+				indicator: indicator.NewPivotPoint(),
+				slot:      func(state *CalculatorState) **indicator.PivotPoint { return &state.Pivot },
+				fields: func(levels indicator.PivotLevels) []float64 {
+					return []float64{levels.Pivot, levels.Resistance1, levels.Resistance2, levels.Resistance3, levels.Support1, levels.Support2, levels.Support3}
+				},
+				// A pivot point without any data has only zero fields, which the binary format leaves out.
+				empty: indicator.NewPivotPoint,
+			}
+		},
+	},
+}
+
+// savedIndicator is an indicator held by pointer, so a CalculatorState can tell whether it holds one.
+type savedIndicator[T any] interface {
+	comparable
+	indicator.Indicator[T]
+}
+
+// indicatorCalculator is the calculator of any indicator whose outputs are of type T.
+type indicatorCalculator[T any, I savedIndicator[T]] struct {
+	indicator I
+	// slot returns the field of a CalculatorState holding the indicator.
+	slot func(state *CalculatorState) *I
+	// fields flattens the outputs of the indicator into the fields of its spec.
+	fields func(value T) []float64
+	// empty, when set, builds the indicator restored from a state without one.
+	empty func() I
+}
+
+func (c *indicatorCalculator[T, I]) update(ctx context.Context, data model.DataPoint) error {
+	return c.indicator.Update(ctx, data)
+}
+
+func (c *indicatorCalculator[T, I]) ready() bool {
+	return c.indicator.Ready()
+}
+
+func (c *indicatorCalculator[T, I]) values() []float64 {
+	return c.fields(c.indicator.Value())
+}
+
+func (c *indicatorCalculator[T, I]) save() CalculatorState {
+	var state CalculatorState
+	*c.slot(&state) = c.indicator
+	return state
+}
+
+func (c *indicatorCalculator[T, I]) restore(state CalculatorState) bool {
+	var missing I
+	saved := *c.slot(&state)
+	if saved == missing && c.empty != nil {
+		saved = c.empty()
+	}
+	if saved == missing {
+		return false
+	}
+	c.indicator = saved
+	return true
+}
+
+// indicatorKey returns the canonical name of an indicator call, used to share instances.
+func indicatorKey(name string, args []float64) string {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = fmt.Sprintf("%g", arg)
+	}
+	return fmt.Sprintf("%s(%s)", name, strings.Join(parts, ","))
+}
+
+// indicatorSeries keeps the recent outputs of a single indicator instance.
+type indicatorSeries struct {
+	key     string
+	fields  []string
+	calc    calculator
+	history [][]float64
+	size    int
+}
+
+func (s *indicatorSeries) update(ctx context.Context, data model.DataPoint) error {
+	if err := s.calc.update(ctx, data); err != nil {
+		return err
+	}
+	var values []float64
+	if s.calc.ready() {
+		values = s.calc.values()
+	}
+	s.history = append(s.history, values)
+	if len(s.history) > s.size {
+		s.history = s.history[1:]
+	}
+	return nil
+}
+
+// at returns the value of a field offset bars ago, or NaN when it is not available.
+func (s *indicatorSeries) at(field, offset int) float64 {
+	index := len(s.history) - 1 - offset
+	if index < 0 || s.history[index] == nil {
+		return math.NaN()
+	}
+	return s.history[index][field]
+}
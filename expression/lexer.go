@@ -0,0 +1,98 @@
+package expression
+
+import (
+	"fmt"
+	"strconv"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenIdent
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenLBracket
+	tokenRBracket
+	tokenComma
+	tokenDot
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value float64
+	pos   int
+}
+
+// SyntaxError reports an invalid expression together with the column where parsing failed.
+type SyntaxError struct {
+	Source string
+	Column int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("expression %q: column %d: %s", e.Source, e.Column, e.Msg)
+}
+
+// lex splits an expression into tokens.
+func lex(source string) ([]token, error) {
+	var tokens []token
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			text := string(runes[start:i])
+			value, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, &SyntaxError{Source: source, Column: start + 1, Msg: fmt.Sprintf("invalid number %q", text)}
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: text, value: value, pos: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[start:i]), pos: start})
+		default:
+			kind, text := tokenOperator, string(r)
+			switch r {
+			case '(':
+				kind = tokenLParen
+			case ')':
+				kind = tokenRParen
+			case '[':
+				kind = tokenLBracket
+			case ']':
+				kind = tokenRBracket
+			case ',':
+				kind = tokenComma
+			case '.':
+				kind = tokenDot
+			case '+', '-', '*', '/':
+			case '<', '>', '=', '!':
+				if i+1 < len(runes) && runes[i+1] == '=' {
+					text += "="
+				} else if r == '=' || r == '!' {
+					return nil, &SyntaxError{Source: source, Column: i + 1, Msg: fmt.Sprintf("unexpected character %q", r)}
+				}
+			default:
+				return nil, &SyntaxError{Source: source, Column: i + 1, Msg: fmt.Sprintf("unexpected character %q", r)}
+			}
+			tokens = append(tokens, token{kind: kind, text: text, pos: i})
+			i += len([]rune(text))
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
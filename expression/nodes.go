@@ -0,0 +1,205 @@
+package expression
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// node is a compiled part of an expression evaluated against the bars seen so far.
+// Booleans are represented as 1 and 0, NaN marks a value that is not available yet.
+type node interface {
+	// eval returns the value of the node offset bars before the current one.
+	eval(p *Program, offset int) float64
+	// depth returns how many bars before the current one the node looks back.
+	depth() int
+	String() string
+}
+
+type numberNode struct {
+	value float64
+}
+
+func (n *numberNode) eval(p *Program, offset int) float64 { return n.value }
+func (n *numberNode) depth() int                          { return 0 }
+func (n *numberNode) String() string                      { return fmt.Sprintf("%g", n.value) }
+
+var priceFields = map[string]func(model.DataPoint) float64{
+	"open":   func(data model.DataPoint) float64 { return data.Open },
+	"high":   func(data model.DataPoint) float64 { return data.High },
+	"low":    func(data model.DataPoint) float64 { return data.Low },
+	"close":  func(data model.DataPoint) float64 { return data.Close },
+	"volume": func(data model.DataPoint) float64 { return data.Volume },
+}
+
+type priceNode struct {
+	name  string
+	value func(model.DataPoint) float64
+}
+
+func (n *priceNode) eval(p *Program, offset int) float64 {
+	index := len(p.bars) - 1 - offset
+	if index < 0 {
+		return math.NaN()
+	}
+	return n.value(p.bars[index])
+}
+func (n *priceNode) depth() int     { return 0 }
+func (n *priceNode) String() string { return n.name }
+
+type seriesNode struct {
+	series    *indicatorSeries
+	field     int
+	fieldName string
+}
+
+func (n *seriesNode) eval(p *Program, offset int) float64 { return n.series.at(n.field, offset) }
+func (n *seriesNode) depth() int                          { return 0 }
+func (n *seriesNode) String() string                      { return n.series.key + "." + n.fieldName }
+
+type lookbackNode struct {
+	inner node
+	bars  int
+}
+
+func (n *lookbackNode) eval(p *Program, offset int) float64 { return n.inner.eval(p, offset+n.bars) }
+func (n *lookbackNode) depth() int                          { return n.inner.depth() + n.bars }
+func (n *lookbackNode) String() string                      { return fmt.Sprintf("%s[%d]", n.inner, n.bars) }
+
+type unaryNode struct {
+	op      string
+	operand node
+}
+
+func (n *unaryNode) eval(p *Program, offset int) float64 {
+	value := n.operand.eval(p, offset)
+	switch n.op {
+	case "-":
+		return -value
+	case "not":
+		if math.IsNaN(value) {
+			return value
+		}
+		return boolValue(value == 0)
+	}
+	return math.NaN()
+}
+func (n *unaryNode) depth() int     { return n.operand.depth() }
+func (n *unaryNode) String() string { return fmt.Sprintf("(%s %s)", n.op, n.operand) }
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n *binaryNode) eval(p *Program, offset int) float64 {
+	left, right := n.left.eval(p, offset), n.right.eval(p, offset)
+	if math.IsNaN(left) || math.IsNaN(right) {
+		return math.NaN()
+	}
+	switch n.op {
+	case "and":
+		return boolValue(left != 0 && right != 0)
+	case "or":
+		return boolValue(left != 0 || right != 0)
+	case "+":
+		return left + right
+	case "-":
+		return left - right
+	case "*":
+		return left * right
+	case "/":
+		if right == 0 {
+			return math.NaN()
+		}
+		return left / right
+	case "<":
+		return boolValue(left < right)
+	case "<=":
+		return boolValue(left <= right)
+	case ">":
+		return boolValue(left > right)
+	case ">=":
+		return boolValue(left >= right)
+	case "==":
+		return boolValue(left == right)
+	case "!=":
+		return boolValue(left != right)
+	}
+	return math.NaN()
+}
+func (n *binaryNode) depth() int     { return max(n.left.depth(), n.right.depth()) }
+func (n *binaryNode) String() string { return fmt.Sprintf("(%s %s %s)", n.left, n.op, n.right) }
+
+// functionSpec describes a helper function usable inside expressions.
+type functionSpec struct {
+	arity int
+	// lookback is the number of extra bars the function reads from its arguments.
+	lookback int
+	eval     func(p *Program, offset int, args []node) float64
+}
+
+var functionSpecs = map[string]functionSpec{
+	"crosses_above": {arity: 2, lookback: 1, eval: func(p *Program, offset int, args []node) float64 {
+		return crosses(p, offset, args[0], args[1])
+	}},
+	"crosses_below": {arity: 2, lookback: 1, eval: func(p *Program, offset int, args []node) float64 {
+		return crosses(p, offset, args[1], args[0])
+	}},
+	"abs": {arity: 1, eval: func(p *Program, offset int, args []node) float64 {
+		return math.Abs(args[0].eval(p, offset))
+	}},
+	"min": {arity: 2, eval: func(p *Program, offset int, args []node) float64 {
+		return math.Min(args[0].eval(p, offset), args[1].eval(p, offset))
+	}},
+	"max": {arity: 2, eval: func(p *Program, offset int, args []node) float64 {
+		return math.Max(args[0].eval(p, offset), args[1].eval(p, offset))
+	}},
+}
+
+// crosses reports whether a moved from at or below b on the previous bar to above b on the current one.
+func crosses(p *Program, offset int, a, b node) float64 {
+	current, currentRef := a.eval(p, offset), b.eval(p, offset)
+	previous, previousRef := a.eval(p, offset+1), b.eval(p, offset+1)
+	if math.IsNaN(current) || math.IsNaN(currentRef) || math.IsNaN(previous) || math.IsNaN(previousRef) {
+		return 0
+	}
+	return boolValue(previous <= previousRef && current > currentRef)
+}
+
+type callNode struct {
+	name string
+	spec functionSpec
+	args []node
+}
+
+func (n *callNode) eval(p *Program, offset int) float64 { return n.spec.eval(p, offset, n.args) }
+
+func (n *callNode) depth() int {
+	depth := 0
+	for _, arg := range n.args {
+		depth = max(depth, arg.depth())
+	}
+	return depth + n.spec.lookback
+}
+
+func (n *callNode) String() string {
+	args := make([]string, len(n.args))
+	for i, arg := range n.args {
+		args[i] = arg.String()
+	}
+	return fmt.Sprintf("%s(%s)", n.name, strings.Join(args, ", "))
+}
+
+func truthy(value float64) bool {
+	return !math.IsNaN(value) && value != 0
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,336 @@
+package expression
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+var comparisonOperators = map[string]bool{"<": true, "<=": true, ">": true, ">=": true, "==": true, "!=": true}
+
+// parser is a recursive descent parser producing a node tree. Indicator calls with the
+// same name and arguments share one indicatorSeries.
+type parser struct {
+	source string
+	tokens []token
+	pos    int
+	series map[string]*indicatorSeries
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) errorf(tok token, format string, args ...interface{}) error {
+	return &SyntaxError{Source: p.source, Column: tok.pos + 1, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) expect(kind tokenKind, what string) (token, error) {
+	tok := p.next()
+	if tok.kind != kind {
+		return tok, p.errorf(tok, "expected %s, found %s", what, describe(tok))
+	}
+	return tok, nil
+}
+
+func (p *parser) isKeyword(word string) bool {
+	tok := p.peek()
+	return tok.kind == tokenIdent && tok.text == word
+}
+
+func (p *parser) parse() (node, error) {
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.errorf(tok, "unexpected %s", describe(tok))
+	}
+	return root, nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "or", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("and") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "and", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.isKeyword("not") {
+		p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: "not", operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind == tokenOperator && comparisonOperators[tok.text] {
+		p.next()
+		right, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		return &binaryNode{op: tok.text, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parseSum() (node, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for tok := p.peek(); tok.kind == tokenOperator && (tok.text == "+" || tok.text == "-"); tok = p.peek() {
+		p.next()
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: tok.text, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseProduct() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for tok := p.peek(); tok.kind == tokenOperator && (tok.text == "*" || tok.text == "/"); tok = p.peek() {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: tok.text, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if tok := p.peek(); tok.kind == tokenOperator && tok.text == "-" {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: "-", operand: operand}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (node, error) {
+	primary, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch tok := p.peek(); tok.kind {
+		case tokenDot:
+			p.next()
+			field, err := p.expect(tokenIdent, "field name")
+			if err != nil {
+				return nil, err
+			}
+			if primary, err = p.selectField(primary, field); err != nil {
+				return nil, err
+			}
+		case tokenLBracket:
+			p.next()
+			bars, err := p.expect(tokenNumber, "number of bars")
+			if err != nil {
+				return nil, err
+			}
+			if bars.value != math.Trunc(bars.value) {
+				return nil, p.errorf(bars, "lookback must be a whole number of bars, found %s", bars.text)
+			}
+			if _, err := p.expect(tokenRBracket, "']'"); err != nil {
+				return nil, err
+			}
+			primary = &lookbackNode{inner: primary, bars: int(bars.value)}
+		default:
+			return primary, nil
+		}
+	}
+}
+
+func (p *parser) selectField(target node, field token) (node, error) {
+	series, ok := target.(*seriesNode)
+	if !ok {
+		return nil, p.errorf(field, "field %q can only be selected from an indicator", field.text)
+	}
+	fields := series.series.fields
+	for i, name := range fields {
+		if name == field.text {
+			return &seriesNode{series: series.series, field: i, fieldName: name}, nil
+		}
+	}
+	return nil, p.errorf(field, "unknown field %q, expected one of: %s", field.text, strings.Join(fields, ", "))
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenNumber:
+		return &numberNode{value: tok.value}, nil
+	case tokenLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokenRParen, "')'"); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	case tokenIdent:
+		if value, ok := priceFields[tok.text]; ok {
+			return &priceNode{name: tok.text, value: value}, nil
+		}
+		if spec, ok := indicatorSpecs[tok.text]; ok {
+			return p.parseIndicator(tok, spec)
+		}
+		if spec, ok := functionSpecs[tok.text]; ok {
+			return p.parseFunction(tok, spec)
+		}
+		return nil, p.errorf(tok, "unknown identifier %q", tok.text)
+	}
+	return nil, p.errorf(tok, "unexpected %s", describe(tok))
+}
+
+func (p *parser) parseIndicator(name token, spec indicatorSpec) (node, error) {
+	args := spec.defaults
+	if p.peek().kind == tokenLParen {
+		p.next()
+		args = nil
+		for p.peek().kind != tokenRParen {
+			if len(args) > 0 {
+				if _, err := p.expect(tokenComma, "','"); err != nil {
+					return nil, err
+				}
+			}
+			negative := false
+			if tok := p.peek(); tok.kind == tokenOperator && tok.text == "-" {
+				p.next()
+				negative = true
+			}
+			arg, err := p.expect(tokenNumber, "numeric indicator parameter")
+			if err != nil {
+				return nil, err
+			}
+			if negative || arg.value <= 0 {
+				return nil, p.errorf(arg, "indicator parameters must be positive")
+			}
+			if len(args) < spec.periods && (arg.value < 1 || arg.value != math.Trunc(arg.value)) {
+				return nil, p.errorf(arg, "period must be a whole number of bars, found %s", arg.text)
+			}
+			args = append(args, arg.value)
+		}
+		closing := p.next()
+		if len(args) != len(spec.defaults) {
+			return nil, p.errorf(closing, "%s expects %d parameters, found %d", name.text, len(spec.defaults), len(args))
+		}
+		if spec.validate != nil {
+			if err := spec.validate(args); err != nil {
+				return nil, p.errorf(name, "%s: %v", name.text, err)
+			}
+		}
+	}
+
+	key := indicatorKey(name.text, args)
+	series, ok := p.series[key]
+	if !ok {
+		series = &indicatorSeries{key: key, fields: spec.fields, calc: spec.build(args)}
+		p.series[key] = series
+	}
+	return &seriesNode{series: series, field: 0, fieldName: spec.fields[0]}, nil
+}
+
+func (p *parser) parseFunction(name token, spec functionSpec) (node, error) {
+	if _, err := p.expect(tokenLParen, "'('"); err != nil {
+		return nil, err
+	}
+	var args []node
+	for p.peek().kind != tokenRParen {
+		if len(args) > 0 {
+			if _, err := p.expect(tokenComma, "','"); err != nil {
+				return nil, err
+			}
+		}
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	closing := p.next()
+	if len(args) != spec.arity {
+		return nil, p.errorf(closing, "%s expects %d arguments, found %d", name.text, spec.arity, len(args))
+	}
+	return &callNode{name: name.text, spec: spec, args: args}, nil
+}
+
+func describe(tok token) string {
+	if tok.kind == tokenEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q", tok.text)
+}
+
+// Identifiers returns the names of every price field, indicator and function usable in expressions.
+func Identifiers() []string {
+	var names []string
+	for name := range priceFields {
+		names = append(names, name)
+	}
+	for name := range indicatorSpecs {
+		names = append(names, name)
+	}
+	for name := range functionSpecs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,189 @@
+// Package expression implements a small rule language for entry and exit conditions, such as
+//
+//	rsi(14) < 30 and close > ema(200) and crosses_above(macd(12,26,9).line, macd.signal)
+//
+// Expressions combine price fields (open, high, low, close, volume), indicators from the
+// indicator package (rsi, ema, macd, supertrend, bollinger, pivot) with optional field
+// selection, lookback (x[n] is the value of x n bars ago), arithmetic, comparisons,
+// and/or/not and the helpers crosses_above, crosses_below, abs, min and max.
+// Indicators called without parameters use their conventional defaults.
+package expression
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// Program is a compiled expression that is evaluated bar by bar.
+type Program struct {
+	source string
+	root   node
+	series []*indicatorSeries
+	bars   []model.DataPoint
+	size   int
+}
+
+// Compile parses an expression and prepares the indicators it uses.
+func Compile(source string) (*Program, error) {
+	tokens, err := lex(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{source: source, tokens: tokens, series: make(map[string]*indicatorSeries)}
+	root, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	program := &Program{
+		source: source,
+		root:   root,
+		size:   root.depth() + 1,
+	}
+	for _, series := range p.series {
+		series.size = program.size
+		program.series = append(program.series, series)
+	}
+	sort.Slice(program.series, func(i, j int) bool {
+		return program.series[i].key < program.series[j].key
+	})
+	return program, nil
+}
+
+// MustCompile is like Compile but panics if the expression cannot be parsed.
+func MustCompile(source string) *Program {
+	program, err := Compile(source)
+	if err != nil {
+		panic(err)
+	}
+	return program
+}
+
+// Clone returns a fresh program for the same expression without any bar history.
+func (p *Program) Clone() *Program {
+	return MustCompile(p.source)
+}
+
+// Source returns the expression the program was compiled from.
+func (p *Program) Source() string {
+	return p.source
+}
+
+// String returns the fully parenthesised form of the compiled expression.
+func (p *Program) String() string {
+	return p.root.String()
+}
+
+// Lookback returns how many bars before the current one the expression reads.
+func (p *Program) Lookback() int {
+	return p.size - 1
+}
+
+// Indicators returns the canonical names of the indicator instances used by the expression.
+func (p *Program) Indicators() []string {
+	keys := make([]string, len(p.series))
+	for i, series := range p.series {
+		keys[i] = series.key
+	}
+	return keys
+}
+
+// AddDataPoint feeds a new bar to every indicator used by the expression.
+func (p *Program) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	for _, series := range p.series {
+		if err := series.update(ctx, data); err != nil {
+			return err
+		}
+	}
+	p.bars = append(p.bars, data)
+	if len(p.bars) > p.size {
+		p.bars = p.bars[1:]
+	}
+	return nil
+}
+
+// Value evaluates the expression on the latest bar. It returns NaN while the
+// indicators it depends on are still warming up.
+func (p *Program) Value() float64 {
+	return p.root.eval(p, 0)
+}
+
+// Ready reports whether the expression has a value on the latest bar.
+func (p *Program) Ready() bool {
+	return !math.IsNaN(p.Value())
+}
+
+// True reports whether the expression holds on the latest bar.
+func (p *Program) True() bool {
+	return truthy(p.Value())
+}
+
+// ProgramState is the full state of a program: the recent bars and, for every indicator call,
+// its indicator and recent outputs.
+type ProgramState struct {
+	Source string
+	Bars   []model.DataPoint
+	Series []SeriesState
+}
+
+// SeriesState is the state of one indicator instance, History holds no values for bars it was not ready on.
+type SeriesState struct {
+	Key        string
+	Calculator CalculatorState
+	History    [][]float64
+}
+
+// State returns the full state of the program.
+func (p *Program) State() ProgramState {
+	state := ProgramState{Source: p.source, Bars: p.bars, Series: make([]SeriesState, len(p.series))}
+	for i, series := range p.series {
+		state.Series[i] = SeriesState{Key: series.key, Calculator: series.calc.save(), History: series.history}
+	}
+	return state
+}
+
+// Restore replaces the state of the program with one taken from a program compiled from the same source.
+func (p *Program) Restore(state ProgramState) error {
+	if state.Source != p.source {
+		return fmt.Errorf("state of %q cannot be restored into %q", state.Source, p.source)
+	}
+	if len(state.Series) != len(p.series) {
+		return fmt.Errorf("state has %d indicators, expected %d", len(state.Series), len(p.series))
+	}
+	for i, series := range p.series {
+		saved := state.Series[i]
+		if saved.Key != series.key || !series.calc.restore(saved.Calculator) {
+			return fmt.Errorf("state of indicator %s cannot be restored into %s", saved.Key, series.key)
+		}
+		history := make([][]float64, len(saved.History))
+		for j, values := range saved.History {
+			// The binary format turns missing values into empty ones.
+			if len(values) > 0 {
+				history[j] = values
+			}
+		}
+		series.history = history
+	}
+	p.bars = state.Bars
+	return nil
+}
+
+// MarshalState encodes the full state of the program.
+func (p *Program) MarshalState(format indicator.StateFormat) ([]byte, error) {
+	return indicator.EncodeState(format, p.State())
+}
+
+// UnmarshalState replaces the state of the program with one saved by MarshalState.
+func (p *Program) UnmarshalState(format indicator.StateFormat, data []byte) error {
+	var state ProgramState
+	if err := indicator.DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	return p.Restore(state)
+}
@@ -0,0 +1,146 @@
+package expression
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+func feed(t *testing.T, program *Program, closes ...float64) {
+	ctx := context.Background()
+	start := int64(1)
+	if len(program.bars) > 0 {
+		start = program.bars[len(program.bars)-1].Time + 1
+	}
+	for i, close := range closes {
+		data := model.DataPoint{Time: start + int64(i), Open: close, High: close + 1, Low: close - 1, Close: close}
+		if err := program.AddDataPoint(ctx, data); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestCompileSharesIndicatorInstances(t *testing.T) {
+	program, err := Compile("rsi(14) < 30 and close > ema(200) and crosses_above(macd(12,26,9).line, macd.signal)")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	test_utils.AssertEqual(t, []string{"ema(200)", "macd(12,26,9)", "rsi(14)"}, program.Indicators(), "Indicators do not match")
+	test_utils.AssertEqual(t, 1, program.Lookback(), "Lookback does not match")
+	test_utils.AssertEqual(t,
+		"(((rsi(14).value < 30) and (close > ema(200).value)) and crosses_above(macd(12,26,9).line, macd(12,26,9).signal))",
+		program.String(), "Parsed expression does not match")
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		column int
+	}{
+		{"close >", 8},
+		{"rsi(14", 7},
+		{"rsi(14).upper", 9},
+		{"close.line", 7},
+		{"macd(12, 26)", 12},
+		{"foo(1)", 1},
+		{"close[1.5]", 7},
+		{"close = 1", 7},
+		{"close > 1)", 10},
+		{"supertrend(0.4, 3).line > close", 12},
+		{"rsi(0.5) < 30", 5},
+		{"ema(14.5) > close", 5},
+		{"bollinger(0.5).upper < close", 11},
+		{"macd(12, 26, 0.5).line > 0", 14},
+		{"close > macd(26, 12, 9).line", 9},
+		{"macd(12, 12, 9).line > 0", 1},
+	}
+
+	for _, tt := range tests {
+		_, err := Compile(tt.source)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("%q: expected syntax error, got %v", tt.source, err)
+			continue
+		}
+		test_utils.AssertEqual(t, tt.column, syntaxErr.Column, tt.source+" error column does not match")
+	}
+}
+
+func TestArithmeticAndLookback(t *testing.T) {
+	program := MustCompile("(close - close[2]) * 2 + -1")
+	feed(t, program, 10, 11)
+	test_utils.AssertTrue(t, math.IsNaN(program.Value()), "Expected NaN before enough bars")
+	test_utils.AssertTrue(t, !program.Ready(), "Expected program not to be ready")
+
+	feed(t, program, 15)
+	test_utils.AssertEqual(t, 9.0, program.Value(), "Value does not match")
+}
+
+func TestCrossDetection(t *testing.T) {
+	above := MustCompile("crosses_above(close, 10)")
+	below := MustCompile("crosses_below(close, 10)")
+
+	closes := []float64{8, 9, 11, 12, 9}
+	expectedAbove := []bool{false, false, true, false, false}
+	expectedBelow := []bool{false, false, false, false, true}
+	for i, close := range closes {
+		feed(t, above, close)
+		feed(t, below, close)
+		test_utils.AssertEqual(t, expectedAbove[i], above.True(), "crosses_above does not match")
+		test_utils.AssertEqual(t, expectedBelow[i], below.True(), "crosses_below does not match")
+	}
+}
+
+func TestIndicatorValues(t *testing.T) {
+	program := MustCompile("ema(3) > ema(3)[1] and not (bollinger(3).upper < close)")
+	feed(t, program, 10, 11, 12)
+	test_utils.AssertTrue(t, !program.True(), "Expected false while ema history is incomplete")
+
+	feed(t, program, 13)
+	test_utils.AssertTrue(t, program.True(), "Expected rising ema inside the bands")
+
+	feed(t, program, 5)
+	test_utils.AssertTrue(t, !program.True(), "Expected falling ema")
+}
+
+func TestLogicalOperators(t *testing.T) {
+	program := MustCompile("close > 5 or close < 1 and close != 0")
+	feed(t, program, 0.5)
+	test_utils.AssertTrue(t, program.True(), "Expected and to bind tighter than or")
+
+	feed(t, program, 3)
+	test_utils.AssertTrue(t, !program.True(), "Expected false")
+	test_utils.AssertEqual(t, 1.0, MustCompile("max(2, abs(-3)) == 3").Value(), "Helper functions do not match")
+}
+
+func TestLogicalOperatorsWaitForWarmUp(t *testing.T) {
+	and := MustCompile("close > 100 and rsi(4) < 30")
+	or := MustCompile("close < 100 or rsi(4) < 30")
+	for _, close := range []float64{10, 11, 12} {
+		feed(t, and, close)
+		feed(t, or, close)
+		test_utils.AssertTrue(t, !and.Ready(), "Expected and not to be ready while rsi warms up")
+		test_utils.AssertTrue(t, !or.Ready(), "Expected or not to be ready while rsi warms up")
+		test_utils.AssertTrue(t, math.IsNaN(and.Value()) && math.IsNaN(or.Value()), "Expected NaN while rsi warms up")
+	}
+
+	feed(t, and, 13)
+	feed(t, or, 13)
+	test_utils.AssertEqual(t, 0.0, and.Value(), "Expected and to be false once rsi is ready")
+	test_utils.AssertEqual(t, 1.0, or.Value(), "Expected or to be true once rsi is ready")
+}
+
+func TestADXFilter(t *testing.T) {
+	program := MustCompile("adx(3) > 25 and adx(3).plus_di > adx(3).minus_di")
+	feed(t, program, 10, 11, 12, 13, 14)
+	test_utils.AssertTrue(t, !program.True(), "Expected false before the ADX has two periods of moves")
+
+	feed(t, program, 15)
+	test_utils.AssertTrue(t, program.True(), "Expected a strong uptrend")
+	test_utils.AssertEqual(t, []string{"adx(3)"}, program.Indicators(), "Indicators do not match")
+}
@@ -1,6 +1,9 @@
 package fileutils
 
-import "os"
+import (
+	"os"
+	"path/filepath"
+)
 
 func EnsureDir(path string) error {
 	if _, err := os.Stat(path); os.IsNotExist(err) {
@@ -8,3 +11,21 @@ func EnsureDir(path string) error {
 	}
 	return nil
 }
+
+// WriteFileAtomic writes data to a temporary file next to path and renames it over path,
+// so readers never see a partially written file.
+func WriteFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,76 @@
+package indicator
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// AccumulationDistribution represents the state of the Accumulation/Distribution line, a running total of
+// the volume weighted by where each bar closed within its range.
+type AccumulationDistribution struct {
+	Current     float64
+	LastTime    int64
+	Initialized bool
+}
+
+// NewAccumulationDistribution initializes a new Accumulation/Distribution instance.
+func NewAccumulationDistribution() *AccumulationDistribution {
+	return &AccumulationDistribution{}
+}
+
+// Update adds a new data point and updates the Accumulation/Distribution line.
+func (ad *AccumulationDistribution) Update(ctx context.Context, data model.DataPoint) error {
+	if ad.Initialized && data.Time <= ad.LastTime {
+		return errors.New("data point is not in chronological order")
+	}
+	ad.Current += MoneyFlowMultiplier(data) * data.Volume
+	ad.LastTime = data.Time
+	ad.Initialized = true
+	return nil
+}
+
+// Ready reports whether the A/D line has seen enough bars.
+func (ad *AccumulationDistribution) Ready() bool {
+	return ad.Initialized
+}
+
+// Value returns the current A/D line.
+func (ad *AccumulationDistribution) Value() float64 {
+	return ad.Current
+}
+
+// Lookback returns the number of bars before the A/D line is ready.
+func (ad *AccumulationDistribution) Lookback() int {
+	return 1
+}
+
+// Reset forgets the data seen by the A/D line, keeping its parameters.
+func (ad *AccumulationDistribution) Reset() {
+	*ad = *NewAccumulationDistribution()
+}
+
+// MarshalState encodes the full state of the Accumulation/Distribution line.
+func (ad *AccumulationDistribution) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, ad)
+}
+
+// UnmarshalState replaces the state of the Accumulation/Distribution line with one saved by MarshalState.
+func (ad *AccumulationDistribution) UnmarshalState(format StateFormat, data []byte) error {
+	var state AccumulationDistribution
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*ad = state
+	return nil
+}
+
+// MoneyFlowMultiplier returns where the bar closed within its range, from -1 at the low to 1 at the high.
+// A bar without range gives 0.
+func MoneyFlowMultiplier(data model.DataPoint) float64 {
+	if data.High == data.Low {
+		return 0
+	}
+	return ((data.Close - data.Low) - (data.High - data.Close)) / (data.High - data.Low)
+}
@@ -0,0 +1,137 @@
+package indicator
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// ADXValues represents the calculated values of the Average Directional Index.
+type ADXValues struct {
+	ADX     float64
+	PlusDI  float64
+	MinusDI float64
+}
+
+// ADX represents the state of the Average Directional Index with Wilder smoothing. The directional
+// indicators are set after Period bar to bar moves, the ADX after another Period-1.
+type ADX struct {
+	Period          int
+	LastData        model.DataPoint
+	Moves           int
+	SmoothedTR      float64
+	SmoothedPlusDM  float64
+	SmoothedMinusDM float64
+	DXSum           float64
+	DXCount         int
+	Values          ADXValues
+	// DIReady is set once the directional indicators are available.
+	DIReady     bool
+	Initialized bool
+}
+
+// NewADX initializes a new ADX instance.
+func NewADX(period int) *ADX {
+	return &ADX{
+		Period: period,
+	}
+}
+
+// Update adds a new data point and updates the ADX calculation.
+func (a *ADX) Update(ctx context.Context, data model.DataPoint) error {
+	if a.LastData.Time != 0 && data.Time <= a.LastData.Time {
+		return errors.New("data point is not in chronological order")
+	}
+	if a.LastData.Time == 0 {
+		a.LastData = data
+		return nil
+	}
+	prev := a.LastData
+	a.LastData = data
+
+	trueRange := TrueRange(data, prev.Close)
+	up, down := data.High-prev.High, prev.Low-data.Low
+	plusDM, minusDM := 0.0, 0.0
+	if up > down && up > 0 {
+		plusDM = up
+	}
+	if down > up && down > 0 {
+		minusDM = down
+	}
+
+	a.Moves++
+	period := float64(a.Period)
+	if a.Moves <= a.Period {
+		a.SmoothedTR += trueRange
+		a.SmoothedPlusDM += plusDM
+		a.SmoothedMinusDM += minusDM
+		if a.Moves < a.Period {
+			return nil
+		}
+	} else {
+		a.SmoothedTR = a.SmoothedTR - a.SmoothedTR/period + trueRange
+		a.SmoothedPlusDM = a.SmoothedPlusDM - a.SmoothedPlusDM/period + plusDM
+		a.SmoothedMinusDM = a.SmoothedMinusDM - a.SmoothedMinusDM/period + minusDM
+	}
+
+	dx := 0.0
+	a.Values.PlusDI, a.Values.MinusDI = 0, 0
+	if a.SmoothedTR > 0 {
+		a.Values.PlusDI = 100 * a.SmoothedPlusDM / a.SmoothedTR
+		a.Values.MinusDI = 100 * a.SmoothedMinusDM / a.SmoothedTR
+		if a.Values.PlusDI+a.Values.MinusDI > 0 {
+			dx = 100 * math.Abs(a.Values.PlusDI-a.Values.MinusDI) / (a.Values.PlusDI + a.Values.MinusDI)
+		}
+	}
+	a.DIReady = true
+
+	if a.DXCount < a.Period {
+		a.DXSum += dx
+		a.DXCount++
+		if a.DXCount == a.Period {
+			a.Values.ADX = a.DXSum / period
+			a.Initialized = true
+		}
+		return nil
+	}
+	a.Values.ADX = (a.Values.ADX*(period-1) + dx) / period
+	return nil
+}
+
+// Ready reports whether the ADX has seen enough bars.
+func (a *ADX) Ready() bool {
+	return a.Initialized
+}
+
+// Value returns the current ADX.
+func (a *ADX) Value() ADXValues {
+	return a.Values
+}
+
+// Lookback returns the number of bars before the ADX is ready,
+// the directional indicators need Period+1 bars and the ADX averages Period of their DX.
+func (a *ADX) Lookback() int {
+	return 2 * a.Period
+}
+
+// Reset forgets the data seen by the ADX, keeping its parameters.
+func (a *ADX) Reset() {
+	*a = *NewADX(a.Period)
+}
+
+// MarshalState encodes the full state of the ADX.
+func (a *ADX) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, a)
+}
+
+// UnmarshalState replaces the state of the ADX with one saved by MarshalState.
+func (a *ADX) UnmarshalState(format StateFormat, data []byte) error {
+	var state ADX
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*a = state
+	return nil
+}
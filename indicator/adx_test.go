@@ -0,0 +1,53 @@
+package indicator
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// adxData rises for two bars, then falls for two and rallies on the last one.
+var adxData = []model.DataPoint{
+	{Time: 1, High: 10, Low: 8, Close: 9},
+	{Time: 2, High: 11, Low: 9, Close: 10},
+	{Time: 3, High: 12, Low: 10, Close: 11},
+	{Time: 4, High: 11, Low: 8, Close: 9},
+	{Time: 5, High: 10, Low: 7, Close: 8},
+	{Time: 6, High: 12, Low: 9, Close: 11},
+}
+
+func TestADX(t *testing.T) {
+	tests := []struct {
+		bar         int
+		diReady     bool
+		initialized bool
+		want        ADXValues
+	}{
+		{bar: 2},
+		{bar: 3, diReady: true, want: ADXValues{PlusDI: 50, MinusDI: 0}},
+		{bar: 4, diReady: true, initialized: true, want: ADXValues{ADX: 200.0 / 3, PlusDI: 20, MinusDI: 40}},
+		{bar: 5, diReady: true, initialized: true, want: ADXValues{ADX: 190.0 / 3, PlusDI: 100.0 / 11, MinusDI: 400.0 / 11}},
+	}
+
+	ctx := context.Background()
+	adx := NewADX(2)
+	adx.Update(ctx, adxData[0])
+	for _, tt := range tests {
+		if err := adx.Update(ctx, adxData[tt.bar-1]); err != nil {
+			t.Fatalf("bar %d: unexpected error: %v", tt.bar, err)
+		}
+		if adx.DIReady != tt.diReady || adx.Initialized != tt.initialized {
+			t.Errorf("bar %d: expected DI ready %v and initialized %v, got %v and %v", tt.bar, tt.diReady, tt.initialized, adx.DIReady, adx.Initialized)
+		}
+		got := adx.Value()
+		if math.Abs(got.ADX-tt.want.ADX) > 1e-9 || math.Abs(got.PlusDI-tt.want.PlusDI) > 1e-9 || math.Abs(got.MinusDI-tt.want.MinusDI) > 1e-9 {
+			t.Errorf("bar %d: expected %+v, got %+v", tt.bar, tt.want, got)
+		}
+	}
+
+	if err := adx.Update(ctx, adxData[0]); err == nil {
+		t.Errorf("expected error for out-of-order data point, got nil")
+	}
+}
@@ -0,0 +1,86 @@
+package indicator
+
+import (
+	"context"
+	"math"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils"
+)
+
+// ALMA represents the state of the Arnaud Legoux moving average, the close weighted by a Gaussian curve
+// centred Offset of the way from the oldest to the latest of Period bars, Period/Sigma bars wide.
+type ALMA struct {
+	Period int
+	// Offset is usually 0.85, closer to 1 follows the price more closely.
+	Offset float64
+	// Sigma is usually 6, larger values narrow the curve.
+	Sigma float64
+	// Prices holds the closes of the latest Period bars.
+	Prices      []float64
+	Current     float64
+	Initialized bool
+}
+
+// NewALMA initializes a new ALMA instance.
+func NewALMA(period int, offset, sigma float64) *ALMA {
+	return &ALMA{
+		Period: period,
+		Offset: offset,
+		Sigma:  sigma,
+	}
+}
+
+// Update adds a new data point and updates the ALMA.
+func (a *ALMA) Update(ctx context.Context, data model.DataPoint) error {
+	a.Prices = utils.AppendWindow(a.Prices, data.Close, a.Period)
+	if len(a.Prices) < a.Period {
+		return nil
+	}
+	centre := a.Offset * float64(a.Period-1)
+	width := float64(a.Period) / a.Sigma
+	total, weights := 0.0, 0.0
+	for i, price := range a.Prices {
+		weight := math.Exp(-math.Pow(float64(i)-centre, 2) / (2 * width * width))
+		total += weight * price
+		weights += weight
+	}
+	a.Current = total / weights
+	a.Initialized = true
+	return nil
+}
+
+// Value returns the current ALMA.
+func (a *ALMA) Value() float64 {
+	return a.Current
+}
+
+// Ready reports whether the ALMA has seen a full period.
+func (a *ALMA) Ready() bool {
+	return a.Initialized
+}
+
+// Lookback returns the number of bars before the ALMA is ready.
+func (a *ALMA) Lookback() int {
+	return a.Period
+}
+
+// Reset forgets the data seen by the ALMA, keeping its parameters.
+func (a *ALMA) Reset() {
+	*a = *NewALMA(a.Period, a.Offset, a.Sigma)
+}
+
+// MarshalState encodes the full state of the ALMA.
+func (a *ALMA) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, a)
+}
+
+// UnmarshalState replaces the state of the ALMA with one saved by MarshalState.
+func (a *ALMA) UnmarshalState(format StateFormat, data []byte) error {
+	var state ALMA
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*a = state
+	return nil
+}
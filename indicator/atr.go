@@ -0,0 +1,131 @@
+package indicator
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// ATRSmoothing selects how the true ranges are averaged.
+type ATRSmoothing string
+
+const (
+	// WilderSmoothing is Wilder's running average, each new range weighing 1/Period. It is used when no smoothing is set.
+	WilderSmoothing ATRSmoothing = "wilder"
+	// SMASmoothing is the plain average of the last Period ranges.
+	SMASmoothing ATRSmoothing = "sma"
+	// EMASmoothing is the exponential average, each new range weighing 2/(Period+1).
+	EMASmoothing ATRSmoothing = "ema"
+)
+
+// ATR represents the state of the Average True Range indicator.
+// The first bar has no previous close, so its true range is its high to low range
+// and the average is ready after Period bars.
+type ATR struct {
+	Period    int
+	Smoothing ATRSmoothing
+	Current   float64
+	// TrueRange is the true range of the latest bar.
+	TrueRange float64
+	// Ranges holds the latest Period true ranges.
+	Ranges   []float64
+	LastData model.DataPoint
+	// HasPrevious is set once LastData holds a bar, the previous one of the next update.
+	HasPrevious bool
+	Initialized bool
+}
+
+// NewATR initializes a new ATR instance.
+func NewATR(period int, smoothing ATRSmoothing) *ATR {
+	return &ATR{
+		Period:    period,
+		Smoothing: smoothing,
+	}
+}
+
+// TrueRange returns the largest of the bar's range and its distances to the previous close.
+func TrueRange(data model.DataPoint, previousClose float64) float64 {
+	return math.Max(data.High-data.Low, math.Max(math.Abs(data.High-previousClose), math.Abs(data.Low-previousClose)))
+}
+
+// Update adds a new data point and updates the ATR calculation.
+func (a *ATR) Update(ctx context.Context, data model.DataPoint) error {
+	if a.HasPrevious && data.Time <= a.LastData.Time {
+		return errors.New("data point is not in chronological order")
+	}
+
+	a.TrueRange = data.High - data.Low
+	if a.HasPrevious {
+		a.TrueRange = TrueRange(data, a.LastData.Close)
+	}
+	a.LastData = data
+	a.HasPrevious = true
+	a.Ranges = append(a.Ranges, a.TrueRange)
+	if len(a.Ranges) > a.Period {
+		a.Ranges = a.Ranges[1:]
+	}
+
+	if !a.Initialized {
+		if len(a.Ranges) == a.Period {
+			a.Current = simpleMovingAverage(a.Ranges)
+			a.Initialized = true
+		}
+		return nil
+	}
+
+	switch a.Smoothing {
+	case SMASmoothing:
+		a.Current = simpleMovingAverage(a.Ranges)
+	case EMASmoothing:
+		alpha := 2 / float64(a.Period+1)
+		a.Current += alpha * (a.TrueRange - a.Current)
+	default:
+		a.Current += (a.TrueRange - a.Current) / float64(a.Period)
+	}
+	return nil
+}
+
+// Percent returns the current average true range as a percentage of the latest close.
+func (a *ATR) Percent() float64 {
+	if a.LastData.Close == 0 {
+		return 0
+	}
+	return a.Current / a.LastData.Close * 100
+}
+
+// Ready reports whether the ATR has seen enough bars.
+func (a *ATR) Ready() bool {
+	return a.Initialized
+}
+
+// Value returns the current ATR.
+func (a *ATR) Value() float64 {
+	return a.Current
+}
+
+// Lookback returns the number of bars before the ATR is ready.
+func (a *ATR) Lookback() int {
+	return a.Period
+}
+
+// Reset forgets the data seen by the ATR, keeping its parameters.
+func (a *ATR) Reset() {
+	*a = *NewATR(a.Period, a.Smoothing)
+}
+
+// MarshalState encodes the full state of the ATR.
+func (a *ATR) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, a)
+}
+
+// UnmarshalState replaces the state of the ATR with one saved by MarshalState.
+func (a *ATR) UnmarshalState(format StateFormat, data []byte) error {
+	var state ATR
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*a = state
+	return nil
+}
@@ -0,0 +1,86 @@
+package indicator
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+func TestATR(t *testing.T) {
+	// The true ranges are 4, 6 (gap up from 100 to a high of 106), 4, 8.
+	dataPoints := []model.DataPoint{
+		{Time: 1, High: 102, Low: 98, Close: 100},
+		{Time: 2, High: 106, Low: 102, Close: 105},
+		{Time: 3, High: 107, Low: 103, Close: 104},
+		{Time: 4, High: 106, Low: 98, Close: 99},
+	}
+	tests := []struct {
+		smoothing ATRSmoothing
+		want      float64
+	}{
+		{WilderSmoothing, (14.0/3*2 + 8) / 3},
+		{"", (14.0/3*2 + 8) / 3},
+		{SMASmoothing, 6},
+		{EMASmoothing, 14.0/3 + 0.5*(8-14.0/3)},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		atr := NewATR(3, tt.smoothing)
+		for i, dp := range dataPoints {
+			if err := atr.Update(ctx, dp); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if i == 1 && atr.Initialized {
+				t.Errorf("%s: expected ATR not to be initialized before a full period", tt.smoothing)
+			}
+			if i == 2 && math.Abs(atr.Value()-14.0/3) > 1e-9 {
+				t.Errorf("%s: expected the first ATR to be the average true range %v, got %v", tt.smoothing, 14.0/3, atr.Value())
+			}
+		}
+		if math.Abs(atr.Value()-tt.want) > 1e-9 {
+			t.Errorf("%s: expected ATR %v, got %v", tt.smoothing, tt.want, atr.Value())
+		}
+		if atr.TrueRange != 8 {
+			t.Errorf("%s: expected true range 8, got %v", tt.smoothing, atr.TrueRange)
+		}
+	}
+
+	atr := NewATR(3, WilderSmoothing)
+	atr.Update(ctx, dataPoints[1])
+	if err := atr.Update(ctx, dataPoints[0]); err == nil {
+		t.Errorf("expected error for out-of-order data point, got nil")
+	}
+}
+
+func TestATRFirstBarAtTimeZero(t *testing.T) {
+	// A bar at Unix time 0 still counts as the previous bar of the next one.
+	ctx := context.Background()
+	atr := NewATR(2, WilderSmoothing)
+	if err := atr.Update(ctx, model.DataPoint{Time: 0, High: 102, Low: 98, Close: 100}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := atr.Update(ctx, model.DataPoint{Time: 1, High: 106, Low: 102, Close: 105}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if atr.TrueRange != 6 {
+		t.Errorf("expected the true range to include the gap from the bar at time 0, got %v", atr.TrueRange)
+	}
+	if err := atr.Update(ctx, model.DataPoint{Time: 0, High: 102, Low: 98, Close: 100}); err == nil {
+		t.Errorf("expected error for a bar before the latest one, got nil")
+	}
+
+	restored := NewATR(2, WilderSmoothing)
+	state, err := atr.MarshalState(JSONState)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := restored.UnmarshalState(JSONState, state); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !restored.HasPrevious {
+		t.Errorf("expected the restored ATR to keep its previous bar")
+	}
+}
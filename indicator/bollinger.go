@@ -15,22 +15,34 @@ type BollingerBandsValues struct {
 	LowerBand     float64
 }
 
+// DefaultBollingerMultiplier is the usual distance of the bands from the moving average, in standard deviations.
+const DefaultBollingerMultiplier = 2.0
+
 // BollingerBands represents the state of the Bollinger Bands indicator.
 type BollingerBands struct {
-	Period  int
-	History []model.DataPoint
-	Values  BollingerBandsValues
+	Period int
+	// Multiplier is the distance of the bands from the moving average in standard deviations,
+	// DefaultBollingerMultiplier when zero.
+	Multiplier float64
+	History    []model.DataPoint
+	Values     BollingerBandsValues
 }
 
-// NewBollingerBands initializes a new Bollinger Bands instance.
+// NewBollingerBands initializes a new Bollinger Bands instance with bands two standard deviations wide.
 func NewBollingerBands(period int) *BollingerBands {
+	return NewBollingerBandsWithMultiplier(period, DefaultBollingerMultiplier)
+}
+
+// NewBollingerBandsWithMultiplier initializes a new Bollinger Bands instance with bands multiplier standard deviations wide.
+func NewBollingerBandsWithMultiplier(period int, multiplier float64) *BollingerBands {
 	return &BollingerBands{
-		Period: period,
+		Period:     period,
+		Multiplier: multiplier,
 	}
 }
 
-// AddDataPoint adds a new data point and updates the Bollinger Bands calculation.
-func (bb *BollingerBands) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+// Update adds a new data point and updates the Bollinger Bands calculation.
+func (bb *BollingerBands) Update(ctx context.Context, data model.DataPoint) error {
 	if len(bb.History) > 0 && data.Time <= bb.History[len(bb.History)-1].Time {
 		return errors.New("data point is not in chronological order")
 	}
@@ -64,11 +76,75 @@ func (bb *BollingerBands) calculateBollingerBands() {
 	stdDev := math.Sqrt(sumOfSquares / float64(bb.Period))
 
 	// Calculate the upper and lower bands
-	bb.Values.UpperBand = bb.Values.MovingAverage + (2 * stdDev)
-	bb.Values.LowerBand = bb.Values.MovingAverage - (2 * stdDev)
+	multiplier := bb.Multiplier
+	if multiplier == 0 {
+		multiplier = DefaultBollingerMultiplier
+	}
+	bb.Values.UpperBand = bb.Values.MovingAverage + (multiplier * stdDev)
+	bb.Values.LowerBand = bb.Values.MovingAverage - (multiplier * stdDev)
+}
+
+// Width returns the distance between the bands relative to the moving average.
+func (bb *BollingerBands) Width() float64 {
+	if bb.Values.MovingAverage == 0 {
+		return 0
+	}
+	return (bb.Values.UpperBand - bb.Values.LowerBand) / bb.Values.MovingAverage
+}
+
+// PercentB returns where the price lies relative to the bands, 0 at the lower band and 1 at the upper band.
+func (bb *BollingerBands) PercentB(price float64) float64 {
+	if bb.Values.UpperBand == bb.Values.LowerBand {
+		return 0.5
+	}
+	return (price - bb.Values.LowerBand) / (bb.Values.UpperBand - bb.Values.LowerBand)
 }
 
 // GetBollingerBands returns the current Bollinger Bands levels.
+//
+// Deprecated: Use Value.
 func (bb *BollingerBands) GetBollingerBands() BollingerBandsValues {
+	return bb.Value()
+}
+
+// AddDataPoint adds a new data point.
+//
+// Deprecated: Use Update.
+func (bb *BollingerBands) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	return bb.Update(ctx, data)
+}
+
+// Ready reports whether a full period of data has been seen and the bands are set.
+func (bb *BollingerBands) Ready() bool {
+	return len(bb.History) >= bb.Period
+}
+
+// Value returns the current Bollinger Bands.
+func (bb *BollingerBands) Value() BollingerBandsValues {
 	return bb.Values
 }
+
+// Lookback returns the number of bars before the Bollinger Bands are ready.
+func (bb *BollingerBands) Lookback() int {
+	return bb.Period
+}
+
+// Reset forgets the data seen by the Bollinger Bands, keeping their parameters.
+func (bb *BollingerBands) Reset() {
+	*bb = *NewBollingerBandsWithMultiplier(bb.Period, bb.Multiplier)
+}
+
+// MarshalState encodes the full state of the Bollinger Bands.
+func (bb *BollingerBands) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, bb)
+}
+
+// UnmarshalState replaces the state of the Bollinger Bands with one saved by MarshalState.
+func (bb *BollingerBands) UnmarshalState(format StateFormat, data []byte) error {
+	var state BollingerBands
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*bb = state
+	return nil
+}
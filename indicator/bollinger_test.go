@@ -2,6 +2,7 @@ package indicator
 
 import (
 	"context"
+	"math"
 	"testing"
 	"time"
 
@@ -11,51 +12,71 @@ import (
 func TestBollingerBands(t *testing.T) {
 	bb := NewBollingerBands(20)
 
-	dataPoints := []model.DataPoint{
-		{Time: time.Now().Unix(), Close: 100.0},
-		{Time: time.Now().Add(time.Minute * 1).Unix(), Close: 101.0},
-		{Time: time.Now().Add(time.Minute * 2).Unix(), Close: 102.0},
-		// ... (add more data points to have at least 20 entries)
+	start := time.Now()
+	dataPoints := []model.DataPoint{}
+	for i := 0; i < 25; i++ {
+		dataPoints = append(dataPoints, model.DataPoint{Time: start.Add(time.Minute * time.Duration(i)).Unix(), Close: 100.0 + float64(i%5)})
 	}
 
 	// Adding less than 20 data points should not update the Bollinger Bands values
 	ctx := context.Background()
 	for _, dp := range dataPoints[:19] {
-		err := bb.AddDataPoint(ctx, dp)
+		err := bb.Update(ctx, dp)
 		if err != nil {
 			t.Errorf("unexpected error: %v", err)
 		}
-		values := bb.GetBollingerBands()
+		values := bb.Value()
 		if values.MovingAverage != 0 || values.UpperBand != 0 || values.LowerBand != 0 {
 			t.Errorf("expected initial values to be zero")
 		}
 	}
 
 	// Adding the 20th data point should update the Bollinger Bands values
-	err := bb.AddDataPoint(ctx, dataPoints[19])
+	err := bb.Update(ctx, dataPoints[19])
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
-	values := bb.GetBollingerBands()
+	values := bb.Value()
 	if values.MovingAverage == 0 || values.UpperBand == 0 || values.LowerBand == 0 {
 		t.Errorf("expected Bollinger Bands values to be calculated")
 	}
 
 	// Adding more data points should keep updating the Bollinger Bands values
 	for _, dp := range dataPoints[20:] {
-		err := bb.AddDataPoint(ctx, dp)
+		err := bb.Update(ctx, dp)
 		if err != nil {
 			t.Errorf("unexpected error: %v", err)
 		}
-		values = bb.GetBollingerBands()
+		values = bb.Value()
 		if values.MovingAverage == 0 || values.UpperBand == 0 || values.LowerBand == 0 {
 			t.Errorf("expected Bollinger Bands values to be updated")
 		}
 	}
 
 	// Test adding a data point with a timestamp earlier than the last one
-	err = bb.AddDataPoint(ctx, model.DataPoint{Time: dataPoints[18].Time, Close: 105.0})
+	err = bb.Update(ctx, model.DataPoint{Time: dataPoints[18].Time, Close: 105.0})
 	if err == nil {
 		t.Errorf("expected error for out-of-order data point, got nil")
 	}
 }
+
+func TestBollingerBandsMultiplier(t *testing.T) {
+	ctx := context.Background()
+	standard := NewBollingerBands(5)
+	wide := NewBollingerBandsWithMultiplier(5, 3)
+	for i := 0; i < 5; i++ {
+		dp := model.DataPoint{Time: int64(i + 1), Close: 100.0 + float64(i)}
+		standard.Update(ctx, dp)
+		wide.Update(ctx, dp)
+	}
+
+	standardValues, wideValues := standard.Value(), wide.Value()
+	standardWidth := standardValues.UpperBand - standardValues.MovingAverage
+	wideWidth := wideValues.UpperBand - wideValues.MovingAverage
+	if math.Abs(wideWidth-1.5*standardWidth) > 1e-9 {
+		t.Errorf("expected bands 1.5 times as wide with a multiplier of 3, got %v and %v", wideWidth, standardWidth)
+	}
+	if math.Abs(standard.PercentB(standardValues.UpperBand)-1) > 1e-9 {
+		t.Errorf("expected percent b of 1 at the upper band, got %v", standard.PercentB(standardValues.UpperBand))
+	}
+}
@@ -0,0 +1,91 @@
+package indicator
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils"
+)
+
+// cciConstant scales the Commodity Channel Index so most values fall between -100 and 100.
+const cciConstant = 0.015
+
+// CCI represents the state of the Commodity Channel Index, the distance of the typical price from its
+// Period bar average in mean absolute deviations.
+type CCI struct {
+	Period int
+	// TypicalPrices holds the typical prices of the latest Period bars.
+	TypicalPrices []float64
+	LastTime      int64
+	Current       float64
+	Initialized   bool
+}
+
+// NewCCI initializes a new CCI instance.
+func NewCCI(period int) *CCI {
+	return &CCI{
+		Period: period,
+	}
+}
+
+// Update adds a new data point and updates the CCI.
+func (c *CCI) Update(ctx context.Context, data model.DataPoint) error {
+	if c.LastTime != 0 && data.Time <= c.LastTime {
+		return errors.New("data point is not in chronological order")
+	}
+	c.LastTime = data.Time
+	c.TypicalPrices = utils.AppendWindow(c.TypicalPrices, TypicalPrice(data), c.Period)
+	if len(c.TypicalPrices) < c.Period {
+		return nil
+	}
+
+	average := simpleMovingAverage(c.TypicalPrices)
+	deviation := 0.0
+	for _, price := range c.TypicalPrices {
+		deviation += math.Abs(price - average)
+	}
+	deviation /= float64(c.Period)
+	c.Current = 0
+	if deviation > 0 {
+		c.Current = (c.TypicalPrices[len(c.TypicalPrices)-1] - average) / (cciConstant * deviation)
+	}
+	c.Initialized = true
+	return nil
+}
+
+// Ready reports whether the CCI has seen enough bars.
+func (c *CCI) Ready() bool {
+	return c.Initialized
+}
+
+// Value returns the current CCI.
+func (c *CCI) Value() float64 {
+	return c.Current
+}
+
+// Lookback returns the number of bars before the CCI is ready.
+func (c *CCI) Lookback() int {
+	return c.Period
+}
+
+// Reset forgets the data seen by the CCI, keeping its parameters.
+func (c *CCI) Reset() {
+	*c = *NewCCI(c.Period)
+}
+
+// MarshalState encodes the full state of the CCI.
+func (c *CCI) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, c)
+}
+
+// UnmarshalState replaces the state of the CCI with one saved by MarshalState.
+func (c *CCI) UnmarshalState(format StateFormat, data []byte) error {
+	var state CCI
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*c = state
+	return nil
+}
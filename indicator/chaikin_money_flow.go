@@ -0,0 +1,86 @@
+package indicator
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// ChaikinMoneyFlow represents the state of the Chaikin Money Flow, the money flow volume of Period bars
+// divided by their volume, from -1 to 1.
+type ChaikinMoneyFlow struct {
+	Period      int
+	History     []model.DataPoint
+	Current     float64
+	Initialized bool
+}
+
+// NewChaikinMoneyFlow initializes a new Chaikin Money Flow instance.
+func NewChaikinMoneyFlow(period int) *ChaikinMoneyFlow {
+	return &ChaikinMoneyFlow{
+		Period: period,
+	}
+}
+
+// Update adds a new data point and updates the Chaikin Money Flow.
+func (c *ChaikinMoneyFlow) Update(ctx context.Context, data model.DataPoint) error {
+	if len(c.History) > 0 && data.Time <= c.History[len(c.History)-1].Time {
+		return errors.New("data point is not in chronological order")
+	}
+
+	c.History = append(c.History, data)
+	if len(c.History) > c.Period {
+		c.History = c.History[1:]
+	}
+	if len(c.History) < c.Period {
+		return nil
+	}
+
+	flow, volume := 0.0, 0.0
+	for _, bar := range c.History {
+		flow += MoneyFlowMultiplier(bar) * bar.Volume
+		volume += bar.Volume
+	}
+	c.Current = 0
+	if volume > 0 {
+		c.Current = flow / volume
+	}
+	c.Initialized = true
+	return nil
+}
+
+// Ready reports whether the Chaikin Money Flow has seen enough bars.
+func (c *ChaikinMoneyFlow) Ready() bool {
+	return c.Initialized
+}
+
+// Value returns the current Chaikin Money Flow.
+func (c *ChaikinMoneyFlow) Value() float64 {
+	return c.Current
+}
+
+// Lookback returns the number of bars before the Chaikin Money Flow is ready.
+func (c *ChaikinMoneyFlow) Lookback() int {
+	return c.Period
+}
+
+// Reset forgets the data seen by the Chaikin Money Flow, keeping its parameters.
+func (c *ChaikinMoneyFlow) Reset() {
+	*c = *NewChaikinMoneyFlow(c.Period)
+}
+
+// MarshalState encodes the full state of the Chaikin Money Flow.
+func (c *ChaikinMoneyFlow) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, c)
+}
+
+// UnmarshalState replaces the state of the Chaikin Money Flow with one saved by MarshalState.
+func (c *ChaikinMoneyFlow) UnmarshalState(format StateFormat, data []byte) error {
+	var state ChaikinMoneyFlow
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*c = state
+	return nil
+}
@@ -0,0 +1,118 @@
+package indicator
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+func TestParabolicSAR(t *testing.T) {
+	// Rises with new highs from bar 3 on, then drops through the SAR on bar 5.
+	data := []model.DataPoint{
+		{Time: 1, High: 10, Low: 8, Close: 9},
+		{Time: 2, High: 11, Low: 9, Close: 10.5},
+		{Time: 3, High: 12, Low: 10, Close: 11.5},
+		{Time: 4, High: 13, Low: 11, Close: 12.5},
+		{Time: 5, High: 12, Low: 7, Close: 7.5},
+		{Time: 6, High: 9, Low: 6, Close: 6.5},
+	}
+	tests := []struct {
+		sar          float64
+		long         bool
+		reversed     bool
+		acceleration float64
+	}{
+		{0, false, false, 0},
+		{8, true, false, 0.02},
+		// Bar 3 would move to 8.06, but the SAR stays under the low of bar 1.
+		{8, true, false, 0.04},
+		{8.16, true, false, 0.06},
+		// The reversal restarts at the highest high of the uptrend.
+		{13, false, true, 0.02},
+		// Bar 6 would move to 12.88, but the SAR stays above the high of bar 4.
+		{13, false, false, 0.04},
+	}
+
+	ctx := context.Background()
+	sar := NewParabolicSAR(0.02, 0.2)
+	for i, dp := range data {
+		if err := sar.Update(ctx, dp); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := tests[i]
+		if sar.Initialized != (i > 0) {
+			t.Errorf("bar %d: expected initialized %v", i+1, i > 0)
+		}
+		if math.Abs(sar.Value()-want.sar) > 1e-9 || sar.Long != want.long || sar.Reversed != want.reversed ||
+			math.Abs(sar.Acceleration-want.acceleration) > 1e-9 {
+			t.Errorf("bar %d: expected %+v, got SAR %v long %v reversed %v acceleration %v", i+1, want, sar.SAR, sar.Long, sar.Reversed, sar.Acceleration)
+		}
+	}
+
+	if err := sar.Update(ctx, data[0]); err == nil {
+		t.Errorf("expected an error for an out of order data point")
+	}
+}
+
+func TestParabolicSARMaximum(t *testing.T) {
+	ctx := context.Background()
+	sar := NewParabolicSAR(0.1, 0.25)
+	for i := 1; i <= 10; i++ {
+		price := float64(10 + i)
+		sar.Update(ctx, model.DataPoint{Time: int64(i), High: price + 1, Low: price - 1, Close: price})
+	}
+	if sar.Acceleration != 0.25 {
+		t.Errorf("expected the acceleration to stop at 0.25, got %v", sar.Acceleration)
+	}
+}
+
+// channelData has true ranges of 2 and closes of 9, 10 and 12.
+var channelData = []model.DataPoint{
+	{Time: 1, High: 10, Low: 8, Close: 9},
+	{Time: 2, High: 11, Low: 9, Close: 10},
+	{Time: 3, High: 12, Low: 10, Close: 12},
+}
+
+func TestKeltnerChannels(t *testing.T) {
+	want := []KeltnerValues{
+		{},
+		{Middle: 9.5, Upper: 13.5, Lower: 5.5},
+		{Middle: 67.0 / 6, Upper: 67.0/6 + 4, Lower: 67.0/6 - 4},
+	}
+
+	ctx := context.Background()
+	keltner := NewKeltnerChannels(2, 2, 2)
+	for i, dp := range channelData {
+		if err := keltner.Update(ctx, dp); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got := keltner.Value()
+		if math.Abs(got.Middle-want[i].Middle) > 1e-9 || math.Abs(got.Upper-want[i].Upper) > 1e-9 || math.Abs(got.Lower-want[i].Lower) > 1e-9 {
+			t.Errorf("bar %d: expected %+v, got %+v", i+1, want[i], got)
+		}
+		if keltner.Initialized != (i > 0) {
+			t.Errorf("bar %d: expected initialized %v", i+1, i > 0)
+		}
+	}
+}
+
+func TestDonchianChannels(t *testing.T) {
+	want := []DonchianValues{{}, {Upper: 11, Middle: 9.5, Lower: 8}, {Upper: 12, Middle: 10.5, Lower: 9}}
+
+	ctx := context.Background()
+	donchian := NewDonchianChannels(2)
+	for i, dp := range channelData {
+		if err := donchian.Update(ctx, dp); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := donchian.Value(); got != want[i] {
+			t.Errorf("bar %d: expected %+v, got %+v", i+1, want[i], got)
+		}
+	}
+
+	if err := donchian.Update(ctx, channelData[0]); err == nil {
+		t.Errorf("expected an error for an out of order data point")
+	}
+}
@@ -0,0 +1,138 @@
+package indicator
+
+import (
+	"context"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// DEMA represents the state of the double exponential moving average, twice the EMA less the EMA of the
+// EMA. It is ready after 2*Period-1 bars.
+type DEMA struct {
+	Period int
+	First  *EMA
+	Second *EMA
+}
+
+// NewDEMA initializes a new DEMA instance.
+func NewDEMA(period int) *DEMA {
+	return &DEMA{
+		Period: period,
+		First:  NewEMA(period),
+		Second: NewEMA(period),
+	}
+}
+
+// Update adds a new data point and updates the DEMA.
+func (d *DEMA) Update(ctx context.Context, data model.DataPoint) error {
+	if err := d.First.Update(ctx, data); err != nil || !d.First.Initialized {
+		return err
+	}
+	return d.Second.Update(ctx, model.DataPoint{Time: data.Time, Close: d.First.Current})
+}
+
+// Value returns the current DEMA, zero before it is ready.
+func (d *DEMA) Value() float64 {
+	if !d.Ready() {
+		return 0
+	}
+	return 2*d.First.Current - d.Second.Current
+}
+
+// Ready reports whether the DEMA has seen enough bars.
+func (d *DEMA) Ready() bool {
+	return d.Second.Initialized
+}
+
+// Lookback returns the number of bars before the DEMA is ready.
+func (d *DEMA) Lookback() int {
+	return 2*d.Period - 1
+}
+
+// Reset forgets the data seen by the DEMA, keeping its parameters.
+func (d *DEMA) Reset() {
+	*d = *NewDEMA(d.Period)
+}
+
+// MarshalState encodes the full state of the DEMA.
+func (d *DEMA) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, d)
+}
+
+// UnmarshalState replaces the state of the DEMA with one saved by MarshalState.
+func (d *DEMA) UnmarshalState(format StateFormat, data []byte) error {
+	var state DEMA
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*d = state
+	return nil
+}
+
+// TEMA represents the state of the triple exponential moving average, 3*EMA - 3*EMA(EMA) + EMA(EMA(EMA)).
+// It is ready after 3*Period-2 bars.
+type TEMA struct {
+	Period int
+	First  *EMA
+	Second *EMA
+	Third  *EMA
+}
+
+// NewTEMA initializes a new TEMA instance.
+func NewTEMA(period int) *TEMA {
+	return &TEMA{
+		Period: period,
+		First:  NewEMA(period),
+		Second: NewEMA(period),
+		Third:  NewEMA(period),
+	}
+}
+
+// Update adds a new data point and updates the TEMA.
+func (t *TEMA) Update(ctx context.Context, data model.DataPoint) error {
+	if err := t.First.Update(ctx, data); err != nil || !t.First.Initialized {
+		return err
+	}
+	if err := t.Second.Update(ctx, model.DataPoint{Time: data.Time, Close: t.First.Current}); err != nil || !t.Second.Initialized {
+		return err
+	}
+	return t.Third.Update(ctx, model.DataPoint{Time: data.Time, Close: t.Second.Current})
+}
+
+// Value returns the current TEMA, zero before it is ready.
+func (t *TEMA) Value() float64 {
+	if !t.Ready() {
+		return 0
+	}
+	return 3*t.First.Current - 3*t.Second.Current + t.Third.Current
+}
+
+// Ready reports whether the TEMA has seen enough bars.
+func (t *TEMA) Ready() bool {
+	return t.Third.Initialized
+}
+
+// Lookback returns the number of bars before the TEMA is ready.
+func (t *TEMA) Lookback() int {
+	return 3*t.Period - 2
+}
+
+// Reset forgets the data seen by the TEMA, keeping its parameters.
+func (t *TEMA) Reset() {
+	*t = *NewTEMA(t.Period)
+}
+
+// MarshalState encodes the full state of the TEMA.
+func (t *TEMA) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, t)
+}
+
+// UnmarshalState replaces the state of the TEMA with one saved by MarshalState.
+func (t *TEMA) UnmarshalState(format StateFormat, data []byte) error {
+	var state TEMA
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*t = state
+	return nil
+}
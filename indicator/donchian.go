@@ -0,0 +1,89 @@
+package indicator
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// DonchianValues represents the calculated values of Donchian Channels.
+type DonchianValues struct {
+	Upper  float64
+	Middle float64
+	Lower  float64
+}
+
+// DonchianChannels represents the state of Donchian Channels, the highest high and lowest low of the latest Period bars.
+type DonchianChannels struct {
+	Period      int
+	History     []model.DataPoint
+	Values      DonchianValues
+	Initialized bool
+}
+
+// NewDonchianChannels initializes new Donchian Channels.
+func NewDonchianChannels(period int) *DonchianChannels {
+	return &DonchianChannels{
+		Period: period,
+	}
+}
+
+// Update adds a new data point and updates the channels.
+func (dc *DonchianChannels) Update(ctx context.Context, data model.DataPoint) error {
+	if len(dc.History) > 0 && data.Time <= dc.History[len(dc.History)-1].Time {
+		return errors.New("data point is not in chronological order")
+	}
+
+	dc.History = append(dc.History, data)
+	if len(dc.History) > dc.Period {
+		dc.History = dc.History[1:]
+	}
+	if len(dc.History) < dc.Period {
+		return nil
+	}
+
+	upper, lower := dc.History[0].High, dc.History[0].Low
+	for _, bar := range dc.History[1:] {
+		upper = max(upper, bar.High)
+		lower = min(lower, bar.Low)
+	}
+	dc.Values = DonchianValues{Upper: upper, Middle: (upper + lower) / 2, Lower: lower}
+	dc.Initialized = true
+	return nil
+}
+
+// Ready reports whether the Donchian Channels have seen enough bars.
+func (dc *DonchianChannels) Ready() bool {
+	return dc.Initialized
+}
+
+// Value returns the current Donchian Channels.
+func (dc *DonchianChannels) Value() DonchianValues {
+	return dc.Values
+}
+
+// Lookback returns the number of bars before the Donchian Channels are ready.
+func (dc *DonchianChannels) Lookback() int {
+	return dc.Period
+}
+
+// Reset forgets the data seen by the Donchian Channels, keeping their parameters.
+func (dc *DonchianChannels) Reset() {
+	*dc = *NewDonchianChannels(dc.Period)
+}
+
+// MarshalState encodes the full state of the Donchian Channels.
+func (dc *DonchianChannels) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, dc)
+}
+
+// UnmarshalState replaces the state of the Donchian Channels with one saved by MarshalState.
+func (dc *DonchianChannels) UnmarshalState(format StateFormat, data []byte) error {
+	var state DonchianChannels
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*dc = state
+	return nil
+}
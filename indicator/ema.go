@@ -10,7 +10,7 @@ import (
 type EMA struct {
 	Period           int
 	Multiplier       float64
-	Value            float64
+	Current          float64
 	Initialized      bool
 	InitPricesForSMA []float64
 }
@@ -25,17 +25,28 @@ func NewEMA(period int) *EMA {
 	}
 }
 
-// AddDataPoint updates the EMA with a new data point and recalculates the EMA.
-func (e *EMA) AddDataPoint(ctx context.Context, data model.DataPoint) {
+// Update updates the EMA with a new data point and recalculates the EMA.
+func (e *EMA) Update(ctx context.Context, data model.DataPoint) error {
 	if !e.Initialized {
 		e.InitPricesForSMA = append(e.InitPricesForSMA, data.Close)
 		if len(e.InitPricesForSMA) == e.Period {
-			e.Value = simpleMovingAverage(e.InitPricesForSMA)
+			e.Current = simpleMovingAverage(e.InitPricesForSMA)
 			e.Initialized = true
 		}
 	} else {
-		e.Value = (data.Close-e.Value)*e.Multiplier + e.Value
+		e.Current = (data.Close-e.Current)*e.Multiplier + e.Current
 	}
+	return nil
+}
+
+// Value returns the current EMA.
+func (e *EMA) Value() float64 {
+	return e.Current
+}
+
+// Ready reports whether the EMA has seen a full period.
+func (e *EMA) Ready() bool {
+	return e.Initialized
 }
 
 // Helper function to calculate the simple moving average for initial EMA value.
@@ -46,3 +57,35 @@ func simpleMovingAverage(data []float64) float64 {
 	}
 	return sum / float64(len(data))
 }
+
+// AddDataPoint adds a new data point.
+//
+// Deprecated: Use Update.
+func (e *EMA) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	return e.Update(ctx, data)
+}
+
+// Lookback returns the number of bars before the EMA is ready.
+func (e *EMA) Lookback() int {
+	return e.Period
+}
+
+// Reset forgets the data seen by the EMA, keeping its parameters.
+func (e *EMA) Reset() {
+	*e = *NewEMA(e.Period)
+}
+
+// MarshalState encodes the full state of the EMA.
+func (e *EMA) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, e)
+}
+
+// UnmarshalState replaces the state of the EMA with one saved by MarshalState.
+func (e *EMA) UnmarshalState(format StateFormat, data []byte) error {
+	var state EMA
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*e = state
+	return nil
+}
@@ -41,8 +41,8 @@ func NewFibonacci(size int) *Fibonacci {
 	}
 }
 
-// AddDataPoint adds a new data point and updates the Fibonacci calculation.
-func (f *Fibonacci) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+// Update adds a new data point and updates the Fibonacci calculation.
+func (f *Fibonacci) Update(ctx context.Context, data model.DataPoint) error {
 	if len(f.History) > 0 && data.Time <= f.History[len(f.History)-1].Time {
 		return errors.New("data point is not in chronological order")
 	}
@@ -81,6 +81,53 @@ func (f *Fibonacci) calculateFibonacciLevels() {
 }
 
 // GetFibonacciLevels returns the current Fibonacci retracement levels.
+//
+// Deprecated: Use Value.
 func (f *Fibonacci) GetFibonacciLevels() map[FibonacciLevel]float64 {
+	return f.Value()
+}
+
+// AddDataPoint adds a new data point.
+//
+// Deprecated: Use Update.
+func (f *Fibonacci) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	return f.Update(ctx, data)
+}
+
+// Ready reports whether the Fibonacci indicator has seen enough bars.
+func (f *Fibonacci) Ready() bool {
+	return f.IsInitialized
+}
+
+// Value returns the current Fibonacci levels.
+func (f *Fibonacci) Value() map[FibonacciLevel]float64 {
 	return f.Levels
 }
+
+// Lookback returns the number of bars before the Fibonacci indicator is ready.
+func (f *Fibonacci) Lookback() int {
+	return f.Size
+}
+
+// Reset forgets the data seen by the Fibonacci indicator, keeping its parameters.
+func (f *Fibonacci) Reset() {
+	*f = *NewFibonacci(f.Size)
+}
+
+// MarshalState encodes the full state of the Fibonacci levels.
+func (f *Fibonacci) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, f)
+}
+
+// UnmarshalState replaces the state of the Fibonacci levels with one saved by MarshalState.
+func (f *Fibonacci) UnmarshalState(format StateFormat, data []byte) error {
+	var state Fibonacci
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	if state.Levels == nil {
+		state.Levels = make(map[FibonacciLevel]float64)
+	}
+	*f = state
+	return nil
+}
@@ -21,13 +21,13 @@ func TestFibonacci(t *testing.T) {
 
 	ctx := context.Background()
 	for _, dp := range dataPoints {
-		err := fib.AddDataPoint(ctx, dp)
+		err := fib.Update(ctx, dp)
 		if err != nil {
 			t.Fatalf("Failed to add data point: %v", err)
 		}
 	}
 
-	levels := fib.GetFibonacciLevels()
+	levels := fib.Value()
 	expectedLevels := map[FibonacciLevel]float64{
 		Zero:        140,
 		TwentyThree: 127.2,
@@ -0,0 +1,73 @@
+package indicator
+
+import (
+	"context"
+	"math"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// HMA represents the state of the Hull moving average, the sqrt(Period) bar WMA of twice the Period/2 bar
+// WMA less the Period bar WMA. It is ready after Period+sqrt(Period)-1 bars.
+type HMA struct {
+	Period int
+	Half   *WMA
+	Full   *WMA
+	Smooth *WMA
+}
+
+// NewHMA initializes a new HMA instance.
+func NewHMA(period int) *HMA {
+	return &HMA{
+		Period: period,
+		Half:   NewWMA(max(period/2, 1)),
+		Full:   NewWMA(period),
+		Smooth: NewWMA(max(int(math.Sqrt(float64(period))), 1)),
+	}
+}
+
+// Update adds a new data point and updates the HMA.
+func (h *HMA) Update(ctx context.Context, data model.DataPoint) error {
+	if err := h.Half.Update(ctx, data); err != nil {
+		return err
+	}
+	if err := h.Full.Update(ctx, data); err != nil || !h.Full.Initialized {
+		return err
+	}
+	return h.Smooth.Update(ctx, model.DataPoint{Time: data.Time, Close: 2*h.Half.Current - h.Full.Current})
+}
+
+// Value returns the current HMA.
+func (h *HMA) Value() float64 {
+	return h.Smooth.Current
+}
+
+// Ready reports whether the HMA has seen enough bars.
+func (h *HMA) Ready() bool {
+	return h.Smooth.Initialized
+}
+
+// Lookback returns the number of bars before the HMA is ready.
+func (h *HMA) Lookback() int {
+	return h.Period + h.Smooth.Period - 1
+}
+
+// Reset forgets the data seen by the HMA, keeping its parameters.
+func (h *HMA) Reset() {
+	*h = *NewHMA(h.Period)
+}
+
+// MarshalState encodes the full state of the HMA.
+func (h *HMA) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, h)
+}
+
+// UnmarshalState replaces the state of the HMA with one saved by MarshalState.
+func (h *HMA) UnmarshalState(format StateFormat, data []byte) error {
+	var state HMA
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*h = state
+	return nil
+}
@@ -0,0 +1,154 @@
+package indicator
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils"
+)
+
+// IchimokuValues represents the Ichimoku Kinko Hyo lines as known at the latest bar.
+//
+// The Senkou spans are plotted Displacement bars ahead of the bar they are calculated on, so the cloud
+// under the latest bar, SenkouA and SenkouB, was calculated Displacement bars ago. LeadingSenkouA and
+// LeadingSenkouB are calculated on the latest bar and form the cloud Displacement bars ahead.
+// The Chikou span is the latest close plotted Displacement bars back, it is compared with ChikouPrice,
+// the close of that bar, instead of being written into the past.
+type IchimokuValues struct {
+	Tenkan         float64
+	Kijun          float64
+	SenkouA        float64
+	SenkouB        float64
+	LeadingSenkouA float64
+	LeadingSenkouB float64
+	Chikou         float64
+	ChikouPrice    float64
+}
+
+// Ichimoku represents the state of the Ichimoku Kinko Hyo indicator.
+type Ichimoku struct {
+	TenkanPeriod  int
+	KijunPeriod   int
+	SenkouBPeriod int
+	Displacement  int
+	History       []model.DataPoint
+	// LeadingA and LeadingB hold the Senkou spans of the latest Displacement+1 bars, the oldest one is
+	// the cloud under the latest bar.
+	LeadingA []float64
+	LeadingB []float64
+	Values   IchimokuValues
+	// LinesReady is set once the Tenkan, Kijun and leading spans are calculated.
+	LinesReady bool
+	// Initialized is set once the cloud under the latest bar and the Chikou comparison are known as well.
+	Initialized bool
+}
+
+// NewIchimoku initializes a new Ichimoku instance.
+func NewIchimoku(tenkanPeriod, kijunPeriod, senkouBPeriod, displacement int) *Ichimoku {
+	return &Ichimoku{
+		TenkanPeriod:  tenkanPeriod,
+		KijunPeriod:   kijunPeriod,
+		SenkouBPeriod: senkouBPeriod,
+		Displacement:  displacement,
+	}
+}
+
+// NewStandardIchimoku initializes an Ichimoku with the classic 9, 26, 52 and 26 settings.
+func NewStandardIchimoku() *Ichimoku {
+	return NewIchimoku(9, 26, 52, 26)
+}
+
+// Update adds a new data point and updates the Ichimoku calculation.
+func (ic *Ichimoku) Update(ctx context.Context, data model.DataPoint) error {
+	if len(ic.History) > 0 && data.Time <= ic.History[len(ic.History)-1].Time {
+		return errors.New("data point is not in chronological order")
+	}
+
+	ic.History = append(ic.History, data)
+	if len(ic.History) > ic.historySize() {
+		ic.History = ic.History[1:]
+	}
+	if len(ic.History) < max(ic.TenkanPeriod, ic.KijunPeriod, ic.SenkouBPeriod) {
+		return nil
+	}
+
+	ic.Values.Tenkan = ic.midpoint(ic.TenkanPeriod)
+	ic.Values.Kijun = ic.midpoint(ic.KijunPeriod)
+	ic.Values.LeadingSenkouA = (ic.Values.Tenkan + ic.Values.Kijun) / 2
+	ic.Values.LeadingSenkouB = ic.midpoint(ic.SenkouBPeriod)
+	ic.LinesReady = true
+
+	ic.LeadingA = utils.AppendWindow(ic.LeadingA, ic.Values.LeadingSenkouA, ic.Displacement+1)
+	ic.LeadingB = utils.AppendWindow(ic.LeadingB, ic.Values.LeadingSenkouB, ic.Displacement+1)
+	if len(ic.LeadingA) <= ic.Displacement {
+		return nil
+	}
+	ic.Values.SenkouA, ic.Values.SenkouB = ic.LeadingA[0], ic.LeadingB[0]
+	ic.Values.Chikou = data.Close
+	ic.Values.ChikouPrice = ic.History[len(ic.History)-1-ic.Displacement].Close
+	ic.Initialized = true
+	return nil
+}
+
+// historySize keeps enough bars for the longest midpoint and the close Displacement bars back.
+func (ic *Ichimoku) historySize() int {
+	return max(ic.TenkanPeriod, ic.KijunPeriod, ic.SenkouBPeriod, ic.Displacement+1)
+}
+
+// midpoint returns the middle of the highest high and lowest low of the latest period bars.
+func (ic *Ichimoku) midpoint(period int) float64 {
+	bars := ic.History[len(ic.History)-period:]
+	highest, lowest := bars[0].High, bars[0].Low
+	for _, data := range bars[1:] {
+		highest = max(highest, data.High)
+		lowest = min(lowest, data.Low)
+	}
+	return (highest + lowest) / 2
+}
+
+// CloudTop returns the upper edge of the cloud under the latest bar.
+func (v IchimokuValues) CloudTop() float64 {
+	return max(v.SenkouA, v.SenkouB)
+}
+
+// CloudBottom returns the lower edge of the cloud under the latest bar.
+func (v IchimokuValues) CloudBottom() float64 {
+	return min(v.SenkouA, v.SenkouB)
+}
+
+// Ready reports whether the Ichimoku has seen enough bars.
+func (ic *Ichimoku) Ready() bool {
+	return ic.Initialized
+}
+
+// Value returns the current Ichimoku.
+func (ic *Ichimoku) Value() IchimokuValues {
+	return ic.Values
+}
+
+// Lookback returns the number of bars before the Ichimoku is ready,
+// the cloud is shifted Displacement bars forward from the longest line.
+func (ic *Ichimoku) Lookback() int {
+	return max(ic.TenkanPeriod, ic.KijunPeriod, ic.SenkouBPeriod) + ic.Displacement
+}
+
+// Reset forgets the data seen by the Ichimoku, keeping its parameters.
+func (ic *Ichimoku) Reset() {
+	*ic = *NewIchimoku(ic.TenkanPeriod, ic.KijunPeriod, ic.SenkouBPeriod, ic.Displacement)
+}
+
+// MarshalState encodes the full state of the Ichimoku.
+func (ic *Ichimoku) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, ic)
+}
+
+// UnmarshalState replaces the state of the Ichimoku with one saved by MarshalState.
+func (ic *Ichimoku) UnmarshalState(format StateFormat, data []byte) error {
+	var state Ichimoku
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*ic = state
+	return nil
+}
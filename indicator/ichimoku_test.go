@@ -0,0 +1,68 @@
+package indicator
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+func TestIchimoku(t *testing.T) {
+	ichimoku := NewIchimoku(2, 3, 4, 2)
+	ctx := context.Background()
+
+	// Bar i spans i-1 to i+1 and closes at i.
+	for i := 1; i <= 6; i++ {
+		if err := ichimoku.Update(ctx, model.DataPoint{Time: int64(i), High: float64(i + 1), Low: float64(i - 1), Close: float64(i)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ichimoku.LinesReady != (i >= 4) || ichimoku.Initialized != (i >= 6) {
+			t.Errorf("bar %d: expected lines ready %v and initialized %v", i, i >= 4, i >= 6)
+		}
+	}
+
+	want := IchimokuValues{
+		Tenkan:         5.5,
+		Kijun:          5,
+		SenkouA:        3.25,
+		SenkouB:        2.5,
+		LeadingSenkouA: 5.25,
+		LeadingSenkouB: 4.5,
+		Chikou:         6,
+		ChikouPrice:    4,
+	}
+	if got := ichimoku.Value(); got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	if ichimoku.Value().CloudTop() != 3.25 || ichimoku.Value().CloudBottom() != 2.5 {
+		t.Errorf("expected the cloud between 2.5 and 3.25")
+	}
+}
+
+// TestIchimokuDoesNotLeakFutureData checks that the cloud under every bar is the one calculated
+// Displacement bars before it, and that the Chikou is compared with a past close.
+func TestIchimokuDoesNotLeakFutureData(t *testing.T) {
+	ichimoku := NewIchimoku(3, 5, 8, 4)
+	ctx := context.Background()
+
+	var leading []IchimokuValues
+	var closes []float64
+	for i := 0; i < 60; i++ {
+		price := 100 + 10*math.Sin(float64(i)/4)
+		ichimoku.Update(ctx, model.DataPoint{Time: int64(i + 1), High: price + 1, Low: price - 1, Close: price})
+		closes = append(closes, price)
+		values := ichimoku.Value()
+		leading = append(leading, values)
+		if !ichimoku.Initialized {
+			continue
+		}
+		projected := leading[i-ichimoku.Displacement]
+		if values.SenkouA != projected.LeadingSenkouA || values.SenkouB != projected.LeadingSenkouB {
+			t.Fatalf("bar %d: expected the cloud projected %d bars ago, got %+v", i+1, ichimoku.Displacement, values)
+		}
+		if values.ChikouPrice != closes[i-ichimoku.Displacement] || values.Chikou != price {
+			t.Fatalf("bar %d: expected the Chikou to compare the close with the close %d bars ago", i+1, ichimoku.Displacement)
+		}
+	}
+}
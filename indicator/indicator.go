@@ -0,0 +1,28 @@
+package indicator
+
+import (
+	"context"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// Streaming is the part of the Indicator interface that does not depend on the type of its outputs, so tooling
+// can feed, warm up and reset indicators of any kind alike.
+type Streaming interface {
+	// Update adds the next bar, bars have to arrive in chronological order.
+	Update(ctx context.Context, data model.DataPoint) error
+	// Ready reports whether Value holds a complete result.
+	Ready() bool
+	// Lookback returns the number of bars the indicator needs before it is ready.
+	Lookback() int
+	// Reset forgets every bar seen so far, keeping the parameters.
+	Reset()
+}
+
+// Indicator is a streaming indicator whose outputs are of type T, e.g. float64 for the RSI or MACDResult for
+// the MACD.
+type Indicator[T any] interface {
+	Streaming
+	// Value returns the latest outputs, only meaningful once the indicator is ready.
+	Value() T
+}
@@ -0,0 +1,109 @@
+package indicator
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+var (
+	_ Indicator[float64]                    = (*RSI)(nil)
+	_ Indicator[float64]                    = (*EMA)(nil)
+	_ Indicator[MACDResult]                 = (*MACD)(nil)
+	_ Indicator[SuperTrendValues]           = (*SuperTrend)(nil)
+	_ Indicator[BollingerBandsValues]       = (*BollingerBands)(nil)
+	_ Indicator[PivotLevels]                = (*PivotPoint)(nil)
+	_ Indicator[map[FibonacciLevel]float64] = (*Fibonacci)(nil)
+	_ Indicator[float64]                    = (*ATR)(nil)
+	_ Indicator[StochasticValues]           = (*Stochastic)(nil)
+	_ Indicator[float64]                    = (*WilliamsR)(nil)
+	_ Indicator[ADXValues]                  = (*ADX)(nil)
+	_ Indicator[IchimokuValues]             = (*Ichimoku)(nil)
+	_ Indicator[float64]                    = (*OBV)(nil)
+	_ Indicator[float64]                    = (*AccumulationDistribution)(nil)
+	_ Indicator[VWAPValues]                 = (*VWAP)(nil)
+	_ Indicator[float64]                    = (*MoneyFlowIndex)(nil)
+	_ Indicator[float64]                    = (*ChaikinMoneyFlow)(nil)
+	_ Indicator[float64]                    = (*ParabolicSAR)(nil)
+	_ Indicator[KeltnerValues]              = (*KeltnerChannels)(nil)
+	_ Indicator[DonchianValues]             = (*DonchianChannels)(nil)
+	_ Indicator[float64]                    = (*CCI)(nil)
+	_ Indicator[float64]                    = (*RateOfChange)(nil)
+	_ Indicator[float64]                    = (*Momentum)(nil)
+	_ Indicator[float64]                    = (*TRIX)(nil)
+	_ Indicator[float64]                    = (*UltimateOscillator)(nil)
+	_ MovingAverage                         = (*SMA)(nil)
+	_ MovingAverage                         = (*EMA)(nil)
+	_ MovingAverage                         = (*WMA)(nil)
+	_ MovingAverage                         = (*HMA)(nil)
+	_ MovingAverage                         = (*DEMA)(nil)
+	_ MovingAverage                         = (*TEMA)(nil)
+	_ MovingAverage                         = (*KAMA)(nil)
+	_ MovingAverage                         = (*ALMA)(nil)
+)
+
+func TestStreamingIndicators(t *testing.T) {
+	indicators := map[string]func() Streaming{
+		"rsi":                       func() Streaming { return NewRSI(14) },
+		"macd":                      func() Streaming { return NewMACD(12, 26, 9) },
+		"supertrend":                func() Streaming { return NewSuperTrend(10, 3) },
+		"bollinger":                 func() Streaming { return NewBollingerBands(20) },
+		"pivot":                     func() Streaming { return NewPivotPoint() },
+		"fibonacci":                 func() Streaming { return NewFibonacci(20) },
+		"atr":                       func() Streaming { return NewATR(14, EMASmoothing) },
+		"stochastic":                func() Streaming { return NewStochastic(14, 3, 5) },
+		"williams_r":                func() Streaming { return NewWilliamsR(14) },
+		"adx":                       func() Streaming { return NewADX(14) },
+		"ichimoku":                  func() Streaming { return NewIchimoku(5, 10, 20, 10) },
+		"obv":                       func() Streaming { return NewOBV() },
+		"accumulation_distribution": func() Streaming { return NewAccumulationDistribution() },
+		"vwap":                      func() Streaming { return NewVWAP(2) },
+		"mfi":                       func() Streaming { return NewMoneyFlowIndex(14) },
+		"cmf":                       func() Streaming { return NewChaikinMoneyFlow(20) },
+		"parabolic_sar":             func() Streaming { return NewParabolicSAR(0.02, 0.2) },
+		"keltner":                   func() Streaming { return NewKeltnerChannels(20, 10, 2) },
+		"donchian":                  func() Streaming { return NewDonchianChannels(20) },
+		"cci":                       func() Streaming { return NewCCI(20) },
+		"roc":                       func() Streaming { return NewRateOfChange(12) },
+		"momentum":                  func() Streaming { return NewMomentum(10) },
+		"trix":                      func() Streaming { return NewTRIX(9) },
+		"ultimate_oscillator":       func() Streaming { return NewUltimateOscillator(7, 14, 28) },
+	}
+	for _, kind := range MovingAverageTypes() {
+		kind := kind
+		indicators[string(kind)] = func() Streaming {
+			average, _ := NewMovingAverage(kind, 9)
+			return average
+		}
+	}
+
+	ctx := context.Background()
+	data := waveData(60)
+	for name, build := range indicators {
+		indicator := build()
+		for i, dp := range data {
+			if err := indicator.Update(ctx, dp); err != nil {
+				t.Fatalf("%s: unexpected error: %v", name, err)
+			}
+			if want := i+1 >= indicator.Lookback(); indicator.Ready() != want {
+				t.Errorf("%s: bar %d of a lookback of %d, expected ready %v", name, i+1, indicator.Lookback(), want)
+			}
+		}
+
+		// After a reset the indicator starts over as a new one.
+		indicator.Reset()
+		if indicator.Ready() {
+			t.Errorf("%s: expected not ready after a reset", name)
+		}
+		fresh := build()
+		for _, dp := range data[:30] {
+			indicator.Update(ctx, dp)
+			fresh.Update(ctx, dp)
+		}
+		want, _ := json.Marshal(fresh)
+		got, _ := json.Marshal(indicator)
+		if string(want) != string(got) {
+			t.Errorf("%s: reset indicator diverged from a new one\nwant %s\ngot  %s", name, want, got)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package indicator
+
+import (
+	"context"
+	"math"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils"
+)
+
+// KAMA represents the state of Kaufman's adaptive moving average. Its smoothing moves between the EMAs of
+// FastPeriod and SlowPeriod with the efficiency ratio, the net change of the close over Period bars divided
+// by the sum of the bar to bar changes. It starts from the close of bar Period and is ready on the next bar.
+type KAMA struct {
+	Period     int
+	FastPeriod int
+	SlowPeriod int
+	// Prices holds the closes of the latest Period+1 bars.
+	Prices      []float64
+	Current     float64
+	Initialized bool
+}
+
+// NewKAMA initializes a new KAMA instance, usually with a fast period of 2 and a slow period of 30.
+func NewKAMA(period, fastPeriod, slowPeriod int) *KAMA {
+	return &KAMA{
+		Period:     period,
+		FastPeriod: fastPeriod,
+		SlowPeriod: slowPeriod,
+	}
+}
+
+// Update adds a new data point and updates the KAMA.
+func (k *KAMA) Update(ctx context.Context, data model.DataPoint) error {
+	k.Prices = utils.AppendWindow(k.Prices, data.Close, k.Period+1)
+	if len(k.Prices) <= k.Period {
+		k.Current = data.Close
+		return nil
+	}
+
+	volatility := 0.0
+	for i := 1; i < len(k.Prices); i++ {
+		volatility += math.Abs(k.Prices[i] - k.Prices[i-1])
+	}
+	efficiency := 0.0
+	if volatility > 0 {
+		efficiency = math.Abs(data.Close-k.Prices[0]) / volatility
+	}
+	fast, slow := 2/(float64(k.FastPeriod)+1), 2/(float64(k.SlowPeriod)+1)
+	smoothing := math.Pow(efficiency*(fast-slow)+slow, 2)
+	k.Current += smoothing * (data.Close - k.Current)
+	k.Initialized = true
+	return nil
+}
+
+// Value returns the current KAMA, zero before it is ready.
+func (k *KAMA) Value() float64 {
+	if !k.Initialized {
+		return 0
+	}
+	return k.Current
+}
+
+// Ready reports whether the KAMA has seen enough bars.
+func (k *KAMA) Ready() bool {
+	return k.Initialized
+}
+
+// Lookback returns the number of bars before the KAMA is ready.
+func (k *KAMA) Lookback() int {
+	return k.Period + 1
+}
+
+// Reset forgets the data seen by the KAMA, keeping its parameters.
+func (k *KAMA) Reset() {
+	*k = *NewKAMA(k.Period, k.FastPeriod, k.SlowPeriod)
+}
+
+// MarshalState encodes the full state of the KAMA.
+func (k *KAMA) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, k)
+}
+
+// UnmarshalState replaces the state of the KAMA with one saved by MarshalState.
+func (k *KAMA) UnmarshalState(format StateFormat, data []byte) error {
+	var state KAMA
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*k = state
+	return nil
+}
@@ -0,0 +1,84 @@
+package indicator
+
+import (
+	"context"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// KeltnerValues represents the calculated values of Keltner Channels.
+type KeltnerValues struct {
+	Middle float64
+	Upper  float64
+	Lower  float64
+}
+
+// KeltnerChannels represents the state of Keltner Channels, an EMA of the close with bands
+// Multiplier average true ranges above and below.
+type KeltnerChannels struct {
+	Multiplier       float64
+	MovingAverage    *EMA
+	AverageTrueRange *ATR
+	Values           KeltnerValues
+	Initialized      bool
+}
+
+// NewKeltnerChannels initializes new Keltner Channels around a period EMA, with a Wilder ATR over atrPeriod bars.
+func NewKeltnerChannels(period, atrPeriod int, multiplier float64) *KeltnerChannels {
+	return &KeltnerChannels{
+		Multiplier:       multiplier,
+		MovingAverage:    NewEMA(period),
+		AverageTrueRange: NewATR(atrPeriod, WilderSmoothing),
+	}
+}
+
+// Update adds a new data point and updates the channels.
+func (kc *KeltnerChannels) Update(ctx context.Context, data model.DataPoint) error {
+	if err := kc.AverageTrueRange.Update(ctx, data); err != nil {
+		return err
+	}
+	kc.MovingAverage.Update(ctx, data)
+	if !kc.MovingAverage.Initialized || !kc.AverageTrueRange.Initialized {
+		return nil
+	}
+
+	middle, offset := kc.MovingAverage.Current, kc.Multiplier*kc.AverageTrueRange.Value()
+	kc.Values = KeltnerValues{Middle: middle, Upper: middle + offset, Lower: middle - offset}
+	kc.Initialized = true
+	return nil
+}
+
+// Ready reports whether the Keltner Channels have seen enough bars.
+func (kc *KeltnerChannels) Ready() bool {
+	return kc.Initialized
+}
+
+// Value returns the current Keltner Channels.
+func (kc *KeltnerChannels) Value() KeltnerValues {
+	return kc.Values
+}
+
+// Lookback returns the number of bars before the Keltner Channels are ready.
+func (kc *KeltnerChannels) Lookback() int {
+	return max(kc.MovingAverage.Period, kc.AverageTrueRange.Period)
+}
+
+// Reset forgets the data seen by the Keltner Channels, keeping their parameters.
+func (kc *KeltnerChannels) Reset() {
+	*kc = *NewKeltnerChannels(kc.MovingAverage.Period, kc.AverageTrueRange.Period, kc.Multiplier)
+}
+
+// MarshalState encodes the full state of the Keltner Channels.
+func (kc *KeltnerChannels) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, kc)
+}
+
+// UnmarshalState replaces the state of the Keltner Channels with one saved by MarshalState.
+func (kc *KeltnerChannels) UnmarshalState(format StateFormat, data []byte) error {
+	var state KeltnerChannels
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*kc = state
+	return nil
+}
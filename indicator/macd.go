@@ -36,8 +36,8 @@ func NewMACD(shortPeriod, longPeriod, signalPeriod int) *MACD {
 	}
 }
 
-// AddDataPoint adds a new data point and updates the MACD calculation.
-func (m *MACD) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+// Update adds a new data point and updates the MACD calculation.
+func (m *MACD) Update(ctx context.Context, data model.DataPoint) error {
 	if len(m.History) > 0 && data.Time <= m.History[len(m.History)-1].Time {
 		return errors.New("data point is not in chronological order")
 	}
@@ -85,7 +85,26 @@ func (m *MACD) simpleMovingAverage(period int) float64 {
 }
 
 // CalculateMACD returns the MACD line and the MACD histogram as an object.
+//
+// Deprecated: Use Value.
 func (m *MACD) CalculateMACD() MACDResult {
+	return m.Value()
+}
+
+// AddDataPoint adds a new data point.
+//
+// Deprecated: Use Update.
+func (m *MACD) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	return m.Update(ctx, data)
+}
+
+// Ready reports whether the MACD has seen enough bars.
+func (m *MACD) Ready() bool {
+	return m.Initialized
+}
+
+// Value returns the current MACD.
+func (m *MACD) Value() MACDResult {
 	macdLine := m.ShortEMA - m.LongEMA
 	macdHistogram := macdLine - m.SignalLine
 	return MACDResult{
@@ -94,3 +113,28 @@ func (m *MACD) CalculateMACD() MACDResult {
 		MACDSignal:    m.SignalLine,
 	}
 }
+
+// Lookback returns the number of bars before the MACD is ready.
+func (m *MACD) Lookback() int {
+	return m.LongPeriod
+}
+
+// Reset forgets the data seen by the MACD, keeping its parameters.
+func (m *MACD) Reset() {
+	*m = *NewMACD(m.ShortPeriod, m.LongPeriod, m.SignalPeriod)
+}
+
+// MarshalState encodes the full state of the MACD.
+func (m *MACD) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, m)
+}
+
+// UnmarshalState replaces the state of the MACD with one saved by MarshalState.
+func (m *MACD) UnmarshalState(format StateFormat, data []byte) error {
+	var state MACD
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*m = state
+	return nil
+}
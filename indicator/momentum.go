@@ -0,0 +1,147 @@
+package indicator
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils"
+)
+
+// Momentum represents the state of the raw Momentum indicator, the change of the close over Period bars.
+type Momentum struct {
+	Period int
+	// Closes holds the closes of the latest Period+1 bars.
+	Closes      []float64
+	LastTime    int64
+	Current     float64
+	Initialized bool
+}
+
+// NewMomentum initializes a new Momentum instance.
+func NewMomentum(period int) *Momentum {
+	return &Momentum{
+		Period: period,
+	}
+}
+
+// Update adds a new data point and updates the Momentum.
+func (m *Momentum) Update(ctx context.Context, data model.DataPoint) error {
+	if m.LastTime != 0 && data.Time <= m.LastTime {
+		return errors.New("data point is not in chronological order")
+	}
+	m.LastTime = data.Time
+	m.Closes = utils.AppendWindow(m.Closes, data.Close, m.Period+1)
+	if len(m.Closes) <= m.Period {
+		return nil
+	}
+	m.Current = data.Close - m.Closes[0]
+	m.Initialized = true
+	return nil
+}
+
+// Ready reports whether the Momentum has seen enough bars.
+func (m *Momentum) Ready() bool {
+	return m.Initialized
+}
+
+// Value returns the current Momentum.
+func (m *Momentum) Value() float64 {
+	return m.Current
+}
+
+// Lookback returns the number of bars before the Momentum is ready.
+func (m *Momentum) Lookback() int {
+	return m.Period + 1
+}
+
+// Reset forgets the data seen by the Momentum, keeping its parameters.
+func (m *Momentum) Reset() {
+	*m = *NewMomentum(m.Period)
+}
+
+// MarshalState encodes the full state of the Momentum.
+func (m *Momentum) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, m)
+}
+
+// UnmarshalState replaces the state of the Momentum with one saved by MarshalState.
+func (m *Momentum) UnmarshalState(format StateFormat, data []byte) error {
+	var state Momentum
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*m = state
+	return nil
+}
+
+// RateOfChange represents the state of the Rate of Change indicator, the percentage change of the close
+// over Period bars.
+type RateOfChange struct {
+	Period int
+	// Closes holds the closes of the latest Period+1 bars.
+	Closes      []float64
+	LastTime    int64
+	Current     float64
+	Initialized bool
+}
+
+// NewRateOfChange initializes a new Rate of Change instance.
+func NewRateOfChange(period int) *RateOfChange {
+	return &RateOfChange{
+		Period: period,
+	}
+}
+
+// Update adds a new data point and updates the Rate of Change.
+func (r *RateOfChange) Update(ctx context.Context, data model.DataPoint) error {
+	if r.LastTime != 0 && data.Time <= r.LastTime {
+		return errors.New("data point is not in chronological order")
+	}
+	r.LastTime = data.Time
+	r.Closes = utils.AppendWindow(r.Closes, data.Close, r.Period+1)
+	if len(r.Closes) <= r.Period {
+		return nil
+	}
+	r.Current = 0
+	if r.Closes[0] != 0 {
+		r.Current = 100 * (data.Close - r.Closes[0]) / r.Closes[0]
+	}
+	r.Initialized = true
+	return nil
+}
+
+// Ready reports whether the Rate of Change has seen enough bars.
+func (r *RateOfChange) Ready() bool {
+	return r.Initialized
+}
+
+// Value returns the current Rate of Change.
+func (r *RateOfChange) Value() float64 {
+	return r.Current
+}
+
+// Lookback returns the number of bars before the Rate of Change is ready.
+func (r *RateOfChange) Lookback() int {
+	return r.Period + 1
+}
+
+// Reset forgets the data seen by the Rate of Change, keeping its parameters.
+func (r *RateOfChange) Reset() {
+	*r = *NewRateOfChange(r.Period)
+}
+
+// MarshalState encodes the full state of the Rate of Change.
+func (r *RateOfChange) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, r)
+}
+
+// UnmarshalState replaces the state of the Rate of Change with one saved by MarshalState.
+func (r *RateOfChange) UnmarshalState(format StateFormat, data []byte) error {
+	var state RateOfChange
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*r = state
+	return nil
+}
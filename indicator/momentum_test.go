@@ -0,0 +1,122 @@
+package indicator
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+func TestCCI(t *testing.T) {
+	want := []float64{200.0 / 3, 100, -100}
+
+	ctx := context.Background()
+	cci := NewCCI(3)
+	for i, dp := range volumeData {
+		if err := cci.Update(ctx, dp); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if i < 2 {
+			if cci.Initialized {
+				t.Errorf("expected not initialized after %d bars", i+1)
+			}
+			continue
+		}
+		if math.Abs(cci.Value()-want[i-2]) > 1e-9 {
+			t.Errorf("bar %d: expected CCI %v, got %v", i+1, want[i-2], cci.Value())
+		}
+	}
+
+	if err := cci.Update(ctx, volumeData[0]); err == nil {
+		t.Errorf("expected an error for an out of order data point")
+	}
+}
+
+func TestMomentumAndRateOfChange(t *testing.T) {
+	// Closes of 9, 11, 10, 11 and 10.
+	wantMomentum := []float64{1, 0, 0}
+	wantROC := []float64{100.0 / 9, 0, 0}
+
+	ctx := context.Background()
+	momentum, roc := NewMomentum(2), NewRateOfChange(2)
+	for i, dp := range volumeData {
+		if err := momentum.Update(ctx, dp); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := roc.Update(ctx, dp); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if i < 2 {
+			if momentum.Initialized || roc.Initialized {
+				t.Errorf("expected not initialized after %d bars", i+1)
+			}
+			continue
+		}
+		if momentum.Value() != wantMomentum[i-2] {
+			t.Errorf("bar %d: expected momentum %v, got %v", i+1, wantMomentum[i-2], momentum.Value())
+		}
+		if math.Abs(roc.Value()-wantROC[i-2]) > 1e-9 {
+			t.Errorf("bar %d: expected rate of change %v, got %v", i+1, wantROC[i-2], roc.Value())
+		}
+	}
+
+	if err := momentum.Update(ctx, volumeData[0]); err == nil {
+		t.Errorf("expected an error for an out of order momentum data point")
+	}
+	if err := roc.Update(ctx, volumeData[0]); err == nil {
+		t.Errorf("expected an error for an out of order rate of change data point")
+	}
+}
+
+func TestTRIX(t *testing.T) {
+	// On closes of 1, 2, 3, ... every 3 bar EMA seeded with its SMA lags the close by one bar, so the triple
+	// smoothed EMA of bar n is n-3, first ready on bar 7.
+	ctx := context.Background()
+	trix := NewTRIX(3)
+	for i := 1; i <= 9; i++ {
+		if err := trix.Update(ctx, model.DataPoint{Time: int64(i), Close: float64(i)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if i < 8 {
+			if trix.Initialized {
+				t.Errorf("expected not initialized after %d bars", i)
+			}
+			continue
+		}
+		want := 100 / float64(i-4)
+		if math.Abs(trix.Value()-want) > 1e-9 {
+			t.Errorf("bar %d: expected TRIX %v, got %v", i, want, trix.Value())
+		}
+	}
+
+	if err := trix.Update(ctx, model.DataPoint{Time: 1, Close: 1}); err == nil {
+		t.Errorf("expected an error for an out of order data point")
+	}
+}
+
+func TestUltimateOscillator(t *testing.T) {
+	// Buying pressures of 2, 0, 1 and 1 over true ranges of 2 from the second bar on.
+	want := []float64{300.0 / 7, 1000.0 / 21}
+
+	ctx := context.Background()
+	uo := NewUltimateOscillator(1, 2, 3)
+	for i, dp := range volumeData {
+		if err := uo.Update(ctx, dp); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if i < 3 {
+			if uo.Initialized {
+				t.Errorf("expected not initialized after %d bars", i+1)
+			}
+			continue
+		}
+		if math.Abs(uo.Value()-want[i-3]) > 1e-9 {
+			t.Errorf("bar %d: expected ultimate oscillator %v, got %v", i+1, want[i-3], uo.Value())
+		}
+	}
+
+	if err := uo.Update(ctx, volumeData[0]); err == nil {
+		t.Errorf("expected an error for an out of order data point")
+	}
+}
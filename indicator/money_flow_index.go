@@ -0,0 +1,105 @@
+package indicator
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils"
+)
+
+// MoneyFlowIndex represents the state of the Money Flow Index, a volume weighted RSI of the typical price
+// over Period bar to bar moves.
+type MoneyFlowIndex struct {
+	Period int
+	// LastData is the previous bar, whose typical price the next one is compared with.
+	LastData      model.DataPoint
+	PositiveFlows []float64
+	NegativeFlows []float64
+	Current       float64
+	Initialized   bool
+}
+
+// NewMoneyFlowIndex initializes a new Money Flow Index instance.
+func NewMoneyFlowIndex(period int) *MoneyFlowIndex {
+	return &MoneyFlowIndex{
+		Period: period,
+	}
+}
+
+// TypicalPrice returns the average of the high, low and close of the bar.
+func TypicalPrice(data model.DataPoint) float64 {
+	return (data.High + data.Low + data.Close) / 3
+}
+
+// Update adds a new data point and updates the Money Flow Index.
+func (m *MoneyFlowIndex) Update(ctx context.Context, data model.DataPoint) error {
+	if m.LastData.Time != 0 && data.Time <= m.LastData.Time {
+		return errors.New("data point is not in chronological order")
+	}
+	if m.LastData.Time == 0 {
+		m.LastData = data
+		return nil
+	}
+
+	typical, previous := TypicalPrice(data), TypicalPrice(m.LastData)
+	positive, negative := 0.0, 0.0
+	if typical > previous {
+		positive = typical * data.Volume
+	} else if typical < previous {
+		negative = typical * data.Volume
+	}
+	m.LastData = data
+	m.PositiveFlows = utils.AppendWindow(m.PositiveFlows, positive, m.Period)
+	m.NegativeFlows = utils.AppendWindow(m.NegativeFlows, negative, m.Period)
+	if len(m.PositiveFlows) < m.Period {
+		return nil
+	}
+
+	positiveSum, negativeSum := sum(m.PositiveFlows), sum(m.NegativeFlows)
+	switch {
+	case negativeSum == 0 && positiveSum == 0:
+		m.Current = 50
+	case negativeSum == 0:
+		m.Current = 100
+	default:
+		m.Current = 100 - 100/(1+positiveSum/negativeSum)
+	}
+	m.Initialized = true
+	return nil
+}
+
+// Ready reports whether the Money Flow Index has seen enough bars.
+func (m *MoneyFlowIndex) Ready() bool {
+	return m.Initialized
+}
+
+// Value returns the current Money Flow Index.
+func (m *MoneyFlowIndex) Value() float64 {
+	return m.Current
+}
+
+// Lookback returns the number of bars before the Money Flow Index is ready.
+func (m *MoneyFlowIndex) Lookback() int {
+	return m.Period + 1
+}
+
+// Reset forgets the data seen by the Money Flow Index, keeping its parameters.
+func (m *MoneyFlowIndex) Reset() {
+	*m = *NewMoneyFlowIndex(m.Period)
+}
+
+// MarshalState encodes the full state of the Money Flow Index.
+func (m *MoneyFlowIndex) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, m)
+}
+
+// UnmarshalState replaces the state of the Money Flow Index with one saved by MarshalState.
+func (m *MoneyFlowIndex) UnmarshalState(format StateFormat, data []byte) error {
+	var state MoneyFlowIndex
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*m = state
+	return nil
+}
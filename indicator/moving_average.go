@@ -0,0 +1,54 @@
+package indicator
+
+import "fmt"
+
+// MovingAverageType names a kind of moving average.
+type MovingAverageType string
+
+const (
+	SMAType  MovingAverageType = "sma"
+	EMAType  MovingAverageType = "ema"
+	WMAType  MovingAverageType = "wma"
+	HMAType  MovingAverageType = "hma"
+	DEMAType MovingAverageType = "dema"
+	TEMAType MovingAverageType = "tema"
+	KAMAType MovingAverageType = "kama"
+	ALMAType MovingAverageType = "alma"
+)
+
+// MovingAverageTypes returns every kind of moving average NewMovingAverage builds.
+func MovingAverageTypes() []MovingAverageType {
+	return []MovingAverageType{SMAType, EMAType, WMAType, HMAType, DEMAType, TEMAType, KAMAType, ALMAType}
+}
+
+// MovingAverage is a moving average of the close updated one bar at a time.
+type MovingAverage interface {
+	Stateful
+	Indicator[float64]
+}
+
+// NewMovingAverage builds a moving average of the given kind and period, KAMA and ALMA use their usual defaults.
+func NewMovingAverage(kind MovingAverageType, period int) (MovingAverage, error) {
+	if period < 1 {
+		return nil, fmt.Errorf("moving average period must be positive, got %d", period)
+	}
+	switch kind {
+	case SMAType:
+		return NewSMA(period), nil
+	case EMAType:
+		return NewEMA(period), nil
+	case WMAType:
+		return NewWMA(period), nil
+	case HMAType:
+		return NewHMA(period), nil
+	case DEMAType:
+		return NewDEMA(period), nil
+	case TEMAType:
+		return NewTEMA(period), nil
+	case KAMAType:
+		return NewKAMA(period, 2, 30), nil
+	case ALMAType:
+		return NewALMA(period, 0.85, 6), nil
+	}
+	return nil, fmt.Errorf("unknown moving average type %q", kind)
+}
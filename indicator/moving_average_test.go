@@ -0,0 +1,92 @@
+package indicator
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// linearData has closes of 1, 2, 3, ... up to bars.
+func linearData(bars int) []model.DataPoint {
+	data := make([]model.DataPoint, bars)
+	for i := range data {
+		data[i] = model.DataPoint{Time: int64(i + 1), Close: float64(i + 1)}
+	}
+	return data
+}
+
+func TestMovingAverages(t *testing.T) {
+	tests := []struct {
+		name string
+		kind MovingAverageType
+		// want holds the averages of the last bars of linearData.
+		period int
+		want   []float64
+	}{
+		{"sma", SMAType, 3, []float64{6, 7}},
+		{"ema", EMAType, 3, []float64{6, 7}},
+		{"wma", WMAType, 3, []float64{7 - 2.0/3, 8 - 2.0/3}},
+		// The lags of the inner averages cancel out on a straight line.
+		{"hma", HMAType, 4, []float64{7, 8}},
+		{"dema", DEMAType, 3, []float64{7, 8}},
+		{"tema", TEMAType, 3, []float64{7, 8}},
+		// Fully efficient, the close of bar 3 smoothed by (2/3)^2 towards every later close.
+		{"kama", KAMAType, 3, []float64{5.869074837, 6.816152687}},
+		// Weights of exp(-5.78), exp(-0.98) and exp(-0.18) from the oldest close.
+		{"alma", ALMAType, 3, []float64{7 - 0.3143264, 8 - 0.3143264}},
+	}
+
+	ctx := context.Background()
+	data := linearData(8)
+	for _, tt := range tests {
+		average, err := NewMovingAverage(tt.kind, tt.period)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		var got []float64
+		for _, dp := range data {
+			average.Update(ctx, dp)
+			if average.Ready() {
+				got = append(got, average.Value())
+			} else if len(got) > 0 {
+				t.Errorf("%s: should stay ready", tt.name)
+			}
+		}
+		if len(got) < len(tt.want) {
+			t.Fatalf("%s: expected at least %d ready bars, got %d", tt.name, len(tt.want), len(got))
+		}
+		got = got[len(got)-len(tt.want):]
+		for i := range tt.want {
+			if math.Abs(got[i]-tt.want[i]) > 1e-6 {
+				t.Errorf("%s: bar %d expected %v, got %v", tt.name, len(data)-len(tt.want)+i+1, tt.want[i], got[i])
+			}
+		}
+	}
+}
+
+func TestMovingAverageWarmUp(t *testing.T) {
+	// Bars until the average is first ready.
+	tests := map[MovingAverageType]int{SMAType: 4, EMAType: 4, WMAType: 4, HMAType: 5, DEMAType: 7, TEMAType: 10, KAMAType: 5, ALMAType: 4}
+
+	ctx := context.Background()
+	for kind, want := range tests {
+		average, _ := NewMovingAverage(kind, 4)
+		for i, dp := range linearData(want) {
+			average.Update(ctx, dp)
+			if ready := average.Ready(); ready != (i+1 == want) {
+				t.Errorf("%s: bar %d expected ready %v, got %v", kind, i+1, i+1 == want, ready)
+			}
+		}
+	}
+}
+
+func TestNewMovingAverageErrors(t *testing.T) {
+	if _, err := NewMovingAverage("vwma", 10); err == nil {
+		t.Errorf("expected an error for an unknown type")
+	}
+	if _, err := NewMovingAverage(SMAType, 0); err == nil {
+		t.Errorf("expected an error for a zero period")
+	}
+}
@@ -0,0 +1,73 @@
+package indicator
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// OBV represents the state of the On-Balance Volume indicator, a running total adding the volume of
+// bars closing higher and subtracting the volume of bars closing lower.
+type OBV struct {
+	Current     float64
+	LastData    model.DataPoint
+	Initialized bool
+}
+
+// NewOBV initializes a new OBV instance.
+func NewOBV() *OBV {
+	return &OBV{}
+}
+
+// Update adds a new data point and updates the OBV.
+func (o *OBV) Update(ctx context.Context, data model.DataPoint) error {
+	if o.Initialized && data.Time <= o.LastData.Time {
+		return errors.New("data point is not in chronological order")
+	}
+	if o.Initialized {
+		if data.Close > o.LastData.Close {
+			o.Current += data.Volume
+		} else if data.Close < o.LastData.Close {
+			o.Current -= data.Volume
+		}
+	}
+	o.LastData = data
+	o.Initialized = true
+	return nil
+}
+
+// Ready reports whether the OBV has seen enough bars.
+func (o *OBV) Ready() bool {
+	return o.Initialized
+}
+
+// Value returns the current OBV.
+func (o *OBV) Value() float64 {
+	return o.Current
+}
+
+// Lookback returns the number of bars before the OBV is ready.
+func (o *OBV) Lookback() int {
+	return 1
+}
+
+// Reset forgets the data seen by the OBV, keeping its parameters.
+func (o *OBV) Reset() {
+	*o = *NewOBV()
+}
+
+// MarshalState encodes the full state of the OBV.
+func (o *OBV) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, o)
+}
+
+// UnmarshalState replaces the state of the OBV with one saved by MarshalState.
+func (o *OBV) UnmarshalState(format StateFormat, data []byte) error {
+	var state OBV
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*o = state
+	return nil
+}
@@ -0,0 +1,130 @@
+package indicator
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// ParabolicSAR represents the state of Wilder's Parabolic Stop and Reverse.
+// The trend of the second bar is set by its close against the first one, from then on the SAR
+// accelerates towards the extreme point of the trend and reverses when the price crosses it.
+type ParabolicSAR struct {
+	// Step is the acceleration added on every new extreme point, up to Maximum.
+	Step    float64
+	Maximum float64
+	// SAR is the stop level of the latest bar.
+	SAR          float64
+	ExtremePoint float64
+	Acceleration float64
+	Long         bool
+	// Reversed is set when the trend reversed on the latest bar.
+	Reversed bool
+	// LastData and PreviousData are the latest two bars, the next SAR never moves into their range.
+	LastData     model.DataPoint
+	PreviousData model.DataPoint
+	Initialized  bool
+}
+
+// NewParabolicSAR initializes a new Parabolic SAR instance.
+func NewParabolicSAR(step, maximum float64) *ParabolicSAR {
+	return &ParabolicSAR{
+		Step:    step,
+		Maximum: maximum,
+	}
+}
+
+// Update adds a new data point and updates the SAR.
+func (ps *ParabolicSAR) Update(ctx context.Context, data model.DataPoint) error {
+	if ps.LastData.Time != 0 && data.Time <= ps.LastData.Time {
+		return errors.New("data point is not in chronological order")
+	}
+	last, previous := ps.LastData, ps.PreviousData
+	ps.LastData, ps.PreviousData = data, last
+	ps.Reversed = false
+	if last.Time == 0 {
+		return nil
+	}
+
+	if !ps.Initialized {
+		ps.Long = data.Close >= last.Close
+		if ps.Long {
+			ps.SAR, ps.ExtremePoint = math.Min(last.Low, data.Low), math.Max(last.High, data.High)
+		} else {
+			ps.SAR, ps.ExtremePoint = math.Max(last.High, data.High), math.Min(last.Low, data.Low)
+		}
+		ps.Acceleration = ps.Step
+		ps.Initialized = true
+		return nil
+	}
+
+	sar := ps.SAR + ps.Acceleration*(ps.ExtremePoint-ps.SAR)
+	if ps.Long {
+		sar = math.Min(sar, math.Min(last.Low, previous.Low))
+		if data.Low < sar {
+			ps.reverse(math.Max(ps.ExtremePoint, data.High), data.Low)
+			return nil
+		}
+		if data.High > ps.ExtremePoint {
+			ps.ExtremePoint = data.High
+			ps.Acceleration = math.Min(ps.Acceleration+ps.Step, ps.Maximum)
+		}
+	} else {
+		sar = math.Max(sar, math.Max(last.High, previous.High))
+		if data.High > sar {
+			ps.reverse(math.Min(ps.ExtremePoint, data.Low), data.High)
+			return nil
+		}
+		if data.Low < ps.ExtremePoint {
+			ps.ExtremePoint = data.Low
+			ps.Acceleration = math.Min(ps.Acceleration+ps.Step, ps.Maximum)
+		}
+	}
+	ps.SAR = sar
+	return nil
+}
+
+// reverse flips the trend, the SAR restarts at the extreme point of the old trend.
+func (ps *ParabolicSAR) reverse(sar, extremePoint float64) {
+	ps.Long = !ps.Long
+	ps.SAR, ps.ExtremePoint = sar, extremePoint
+	ps.Acceleration = ps.Step
+	ps.Reversed = true
+}
+
+// Ready reports whether the Parabolic SAR has seen enough bars.
+func (ps *ParabolicSAR) Ready() bool {
+	return ps.Initialized
+}
+
+// Value returns the current Parabolic SAR.
+func (ps *ParabolicSAR) Value() float64 {
+	return ps.SAR
+}
+
+// Lookback returns the number of bars before the Parabolic SAR is ready.
+func (ps *ParabolicSAR) Lookback() int {
+	return 2
+}
+
+// Reset forgets the data seen by the Parabolic SAR, keeping its parameters.
+func (ps *ParabolicSAR) Reset() {
+	*ps = *NewParabolicSAR(ps.Step, ps.Maximum)
+}
+
+// MarshalState encodes the full state of the Parabolic SAR.
+func (ps *ParabolicSAR) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, ps)
+}
+
+// UnmarshalState replaces the state of the Parabolic SAR with one saved by MarshalState.
+func (ps *ParabolicSAR) UnmarshalState(format StateFormat, data []byte) error {
+	var state ParabolicSAR
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*ps = state
+	return nil
+}
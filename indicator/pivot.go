@@ -12,6 +12,8 @@ type PivotPoint struct {
 	PreviousData model.DataPoint
 	Initialized  bool
 	Levels       PivotLevels
+	// LevelsReady is set once Levels hold the levels of a previous bar.
+	LevelsReady bool
 }
 
 // PivotLevels represents the pivot point and its support/resistance levels.
@@ -30,8 +32,8 @@ func NewPivotPoint() *PivotPoint {
 	return &PivotPoint{Initialized: false}
 }
 
-// AddDataPoint adds a new data point and updates the Pivot Point calculation.
-func (pp *PivotPoint) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+// Update adds a new data point and updates the Pivot Point calculation.
+func (pp *PivotPoint) Update(ctx context.Context, data model.DataPoint) error {
 	if pp.PreviousData.Time > 0 && data.Time <= pp.PreviousData.Time {
 		return errors.New("data point is not in chronological order")
 	}
@@ -58,9 +60,55 @@ func (pp *PivotPoint) calculatePivotLevels() {
 	pp.Levels.Resistance1 = 2*pp.Levels.Pivot - low
 	pp.Levels.Resistance2 = pp.Levels.Pivot + (high - low)
 	pp.Levels.Resistance3 = high + 2*(pp.Levels.Pivot-low)
+	pp.LevelsReady = true
 }
 
 // GetPivotLevels returns the current Pivot Point levels.
+//
+// Deprecated: Use Value.
 func (pp *PivotPoint) GetPivotLevels() PivotLevels {
+	return pp.Value()
+}
+
+// AddDataPoint adds a new data point.
+//
+// Deprecated: Use Update.
+func (pp *PivotPoint) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	return pp.Update(ctx, data)
+}
+
+// Ready reports whether the pivot point has seen enough bars.
+func (pp *PivotPoint) Ready() bool {
+	return pp.LevelsReady
+}
+
+// Value returns the current pivot levels.
+func (pp *PivotPoint) Value() PivotLevels {
 	return pp.Levels
 }
+
+// Lookback returns the number of bars before the pivot point is ready,
+// the levels come from the bar before the latest one.
+func (pp *PivotPoint) Lookback() int {
+	return 2
+}
+
+// Reset forgets the data seen by the pivot point, keeping its parameters.
+func (pp *PivotPoint) Reset() {
+	*pp = *NewPivotPoint()
+}
+
+// MarshalState encodes the full state of the pivot points.
+func (pp *PivotPoint) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, pp)
+}
+
+// UnmarshalState replaces the state of the pivot points with one saved by MarshalState.
+func (pp *PivotPoint) UnmarshalState(format StateFormat, data []byte) error {
+	var state PivotPoint
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*pp = state
+	return nil
+}
@@ -18,7 +18,7 @@ func TestPivotPoint(t *testing.T) {
 
 	ctx := context.Background()
 	for _, dp := range data {
-		err := pp.AddDataPoint(ctx, dp)
+		err := pp.Update(ctx, dp)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -32,7 +32,7 @@ func TestPivotPoint(t *testing.T) {
 	expectedResistance2 := expectedPivot + (float64(18) - float64(8))
 	expectedResistance3 := float64(18) + 2*(expectedPivot-float64(8))
 
-	pivotLevels := pp.GetPivotLevels()
+	pivotLevels := pp.Value()
 
 	if pivotLevels.Pivot != expectedPivot {
 		t.Errorf("expected pivot %v, got %v", expectedPivot, pivotLevels.Pivot)
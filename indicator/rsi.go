@@ -29,8 +29,8 @@ func NewRSI(period int) *RSI {
 	}
 }
 
-// AddDataPoint adds a new data point and updates the RSI calculation.
-func (r *RSI) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+// Update adds a new data point and updates the RSI calculation.
+func (r *RSI) Update(ctx context.Context, data model.DataPoint) error {
 	if len(r.History) > 0 && data.Time <= r.History[len(r.History)-1].Time {
 		return errors.New("data point is not in chronological order")
 	}
@@ -85,10 +85,54 @@ func sum(arr []float64) float64 {
 }
 
 // CalculateRSI returns the current RSI value.
+//
+// Deprecated: Use Value.
 func (r *RSI) CalculateRSI() float64 {
+	return r.Value()
+}
+
+// AddDataPoint adds a new data point.
+//
+// Deprecated: Use Update.
+func (r *RSI) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	return r.Update(ctx, data)
+}
+
+// Ready reports whether the RSI has seen enough bars.
+func (r *RSI) Ready() bool {
+	return r.Initialized
+}
+
+// Value returns the current RSI.
+func (r *RSI) Value() float64 {
 	if r.AvgLoss == 0 {
 		return 100
 	}
 	rs := r.AvgGain / r.AvgLoss
 	return 100 - (100 / (1 + rs))
 }
+
+// Lookback returns the number of bars before the RSI is ready.
+func (r *RSI) Lookback() int {
+	return r.Period
+}
+
+// Reset forgets the data seen by the RSI, keeping its parameters.
+func (r *RSI) Reset() {
+	*r = *NewRSI(r.Period)
+}
+
+// MarshalState encodes the full state of the RSI.
+func (r *RSI) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, r)
+}
+
+// UnmarshalState replaces the state of the RSI with one saved by MarshalState.
+func (r *RSI) UnmarshalState(format StateFormat, data []byte) error {
+	var state RSI
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*r = state
+	return nil
+}
@@ -30,14 +30,14 @@ func TestRSI(t *testing.T) {
 
 	ctx := context.Background()
 	for _, dp := range data {
-		err := rsi.AddDataPoint(ctx, dp)
+		err := rsi.Update(ctx, dp)
 		if err != nil {
 			t.Errorf("Error adding data point: %v", err)
 		}
 	}
 
 	expectedRSI := 70.464
-	actualRSI := rsi.CalculateRSI()
+	actualRSI := rsi.Value()
 	if actualRSI != expectedRSI {
 		t.Errorf("Expected RSI: %v, got: %v", expectedRSI, actualRSI)
 	}
@@ -0,0 +1,69 @@
+package indicator
+
+import (
+	"context"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils"
+)
+
+// SMA represents the state of the simple moving average of the close.
+type SMA struct {
+	Period int
+	// Prices holds the closes of the latest Period bars.
+	Prices      []float64
+	Current     float64
+	Initialized bool
+}
+
+// NewSMA initializes a new SMA instance.
+func NewSMA(period int) *SMA {
+	return &SMA{
+		Period: period,
+	}
+}
+
+// Update adds a new data point and updates the SMA.
+func (s *SMA) Update(ctx context.Context, data model.DataPoint) error {
+	s.Prices = utils.AppendWindow(s.Prices, data.Close, s.Period)
+	if len(s.Prices) == s.Period {
+		s.Current = simpleMovingAverage(s.Prices)
+		s.Initialized = true
+	}
+	return nil
+}
+
+// Value returns the current SMA.
+func (s *SMA) Value() float64 {
+	return s.Current
+}
+
+// Ready reports whether the SMA has seen a full period.
+func (s *SMA) Ready() bool {
+	return s.Initialized
+}
+
+// Lookback returns the number of bars before the SMA is ready.
+func (s *SMA) Lookback() int {
+	return s.Period
+}
+
+// Reset forgets the data seen by the SMA, keeping its parameters.
+func (s *SMA) Reset() {
+	*s = *NewSMA(s.Period)
+}
+
+// MarshalState encodes the full state of the SMA.
+func (s *SMA) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, s)
+}
+
+// UnmarshalState replaces the state of the SMA with one saved by MarshalState.
+func (s *SMA) UnmarshalState(format StateFormat, data []byte) error {
+	var state SMA
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*s = state
+	return nil
+}
@@ -0,0 +1,51 @@
+package indicator
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+)
+
+// StateFormat selects how the state of indicators and adaptors is encoded.
+type StateFormat string
+
+const (
+	JSONState StateFormat = "json"
+	// BinaryState is the gob encoding, more compact than JSON and able to hold non-finite values.
+	BinaryState StateFormat = "binary"
+)
+
+// Stateful is implemented by indicators and adaptors whose full state can be saved mid-run
+// and restored later, continuing exactly where the saved instance stopped.
+type Stateful interface {
+	MarshalState(format StateFormat) ([]byte, error)
+	UnmarshalState(format StateFormat, data []byte) error
+}
+
+// EncodeState encodes the exported fields of state.
+func EncodeState(format StateFormat, state interface{}) ([]byte, error) {
+	switch format {
+	case JSONState:
+		return json.Marshal(state)
+	case BinaryState:
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(state); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, fmt.Errorf("unknown state format %q", format)
+}
+
+// DecodeState decodes data written by EncodeState into state, which must point to a zero value
+// as the binary format leaves out zero fields.
+func DecodeState(format StateFormat, data []byte, state interface{}) error {
+	switch format {
+	case JSONState:
+		return json.Unmarshal(data, state)
+	case BinaryState:
+		return gob.NewDecoder(bytes.NewReader(data)).Decode(state)
+	}
+	return fmt.Errorf("unknown state format %q", format)
+}
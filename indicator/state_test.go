@@ -0,0 +1,161 @@
+package indicator
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// statefulIndicator is an indicator that can be fed and saved.
+type statefulIndicator interface {
+	Stateful
+	add(data model.DataPoint)
+}
+
+type rsiFeed struct{ *RSI }
+type emaFeed struct{ *EMA }
+type macdFeed struct{ *MACD }
+type superTrendFeed struct{ *SuperTrend }
+type bollingerFeed struct{ *BollingerBands }
+type pivotFeed struct{ *PivotPoint }
+type fibonacciFeed struct{ *Fibonacci }
+type atrFeed struct{ *ATR }
+type stochasticFeed struct{ *Stochastic }
+type williamsRFeed struct{ *WilliamsR }
+type adxFeed struct{ *ADX }
+type ichimokuFeed struct{ *Ichimoku }
+type obvFeed struct{ *OBV }
+type adLineFeed struct{ *AccumulationDistribution }
+type vwapFeed struct{ *VWAP }
+type moneyFlowIndexFeed struct{ *MoneyFlowIndex }
+type chaikinMoneyFlowFeed struct{ *ChaikinMoneyFlow }
+type parabolicSARFeed struct{ *ParabolicSAR }
+type keltnerFeed struct{ *KeltnerChannels }
+type donchianFeed struct{ *DonchianChannels }
+type cciFeed struct{ *CCI }
+type rateOfChangeFeed struct{ *RateOfChange }
+type momentumFeed struct{ *Momentum }
+type trixFeed struct{ *TRIX }
+type ultimateOscillatorFeed struct{ *UltimateOscillator }
+type smaFeed struct{ *SMA }
+type wmaFeed struct{ *WMA }
+type hmaFeed struct{ *HMA }
+type demaFeed struct{ *DEMA }
+type temaFeed struct{ *TEMA }
+type kamaFeed struct{ *KAMA }
+type almaFeed struct{ *ALMA }
+
+func (f rsiFeed) add(data model.DataPoint)                { f.Update(context.Background(), data) }
+func (f emaFeed) add(data model.DataPoint)                { f.Update(context.Background(), data) }
+func (f macdFeed) add(data model.DataPoint)               { f.Update(context.Background(), data) }
+func (f superTrendFeed) add(data model.DataPoint)         { f.Update(context.Background(), data) }
+func (f bollingerFeed) add(data model.DataPoint)          { f.Update(context.Background(), data) }
+func (f pivotFeed) add(data model.DataPoint)              { f.Update(context.Background(), data) }
+func (f fibonacciFeed) add(data model.DataPoint)          { f.Update(context.Background(), data) }
+func (f atrFeed) add(data model.DataPoint)                { f.Update(context.Background(), data) }
+func (f stochasticFeed) add(data model.DataPoint)         { f.Update(context.Background(), data) }
+func (f williamsRFeed) add(data model.DataPoint)          { f.Update(context.Background(), data) }
+func (f adxFeed) add(data model.DataPoint)                { f.Update(context.Background(), data) }
+func (f ichimokuFeed) add(data model.DataPoint)           { f.Update(context.Background(), data) }
+func (f obvFeed) add(data model.DataPoint)                { f.Update(context.Background(), data) }
+func (f adLineFeed) add(data model.DataPoint)             { f.Update(context.Background(), data) }
+func (f vwapFeed) add(data model.DataPoint)               { f.Update(context.Background(), data) }
+func (f moneyFlowIndexFeed) add(data model.DataPoint)     { f.Update(context.Background(), data) }
+func (f chaikinMoneyFlowFeed) add(data model.DataPoint)   { f.Update(context.Background(), data) }
+func (f parabolicSARFeed) add(data model.DataPoint)       { f.Update(context.Background(), data) }
+func (f keltnerFeed) add(data model.DataPoint)            { f.Update(context.Background(), data) }
+func (f donchianFeed) add(data model.DataPoint)           { f.Update(context.Background(), data) }
+func (f cciFeed) add(data model.DataPoint)                { f.Update(context.Background(), data) }
+func (f rateOfChangeFeed) add(data model.DataPoint)       { f.Update(context.Background(), data) }
+func (f momentumFeed) add(data model.DataPoint)           { f.Update(context.Background(), data) }
+func (f trixFeed) add(data model.DataPoint)               { f.Update(context.Background(), data) }
+func (f ultimateOscillatorFeed) add(data model.DataPoint) { f.Update(context.Background(), data) }
+func (f smaFeed) add(data model.DataPoint)                { f.Update(context.Background(), data) }
+func (f wmaFeed) add(data model.DataPoint)                { f.Update(context.Background(), data) }
+func (f hmaFeed) add(data model.DataPoint)                { f.Update(context.Background(), data) }
+func (f demaFeed) add(data model.DataPoint)               { f.Update(context.Background(), data) }
+func (f temaFeed) add(data model.DataPoint)               { f.Update(context.Background(), data) }
+func (f kamaFeed) add(data model.DataPoint)               { f.Update(context.Background(), data) }
+func (f almaFeed) add(data model.DataPoint)               { f.Update(context.Background(), data) }
+
+func waveData(bars int) []model.DataPoint {
+	data := make([]model.DataPoint, bars)
+	for i := range data {
+		price := 100 + 10*math.Sin(float64(i)/5) + float64(i%3)
+		data[i] = model.DataPoint{Time: int64(i + 1), Open: price - 0.5, High: price + 1, Low: price - 1, Close: price, Volume: 1000}
+	}
+	return data
+}
+
+func TestStateRoundTrip(t *testing.T) {
+	indicators := map[string]func() statefulIndicator{
+		"rsi":                       func() statefulIndicator { return rsiFeed{NewRSI(14)} },
+		"ema":                       func() statefulIndicator { return emaFeed{NewEMA(10)} },
+		"macd":                      func() statefulIndicator { return macdFeed{NewMACD(12, 26, 9)} },
+		"supertrend":                func() statefulIndicator { return superTrendFeed{NewSuperTrend(10, 3)} },
+		"bollinger":                 func() statefulIndicator { return bollingerFeed{NewBollingerBands(20)} },
+		"pivot":                     func() statefulIndicator { return pivotFeed{NewPivotPoint()} },
+		"fibonacci":                 func() statefulIndicator { return fibonacciFeed{NewFibonacci(20)} },
+		"atr":                       func() statefulIndicator { return atrFeed{NewATR(14, EMASmoothing)} },
+		"stochastic":                func() statefulIndicator { return stochasticFeed{NewSlowStochastic(14)} },
+		"williams_r":                func() statefulIndicator { return williamsRFeed{NewWilliamsR(14)} },
+		"adx":                       func() statefulIndicator { return adxFeed{NewADX(14)} },
+		"ichimoku":                  func() statefulIndicator { return ichimokuFeed{NewIchimoku(5, 10, 20, 10)} },
+		"obv":                       func() statefulIndicator { return obvFeed{NewOBV()} },
+		"accumulation_distribution": func() statefulIndicator { return adLineFeed{NewAccumulationDistribution()} },
+		"vwap":                      func() statefulIndicator { return vwapFeed{NewVWAP(2)} },
+		"mfi":                       func() statefulIndicator { return moneyFlowIndexFeed{NewMoneyFlowIndex(14)} },
+		"cmf":                       func() statefulIndicator { return chaikinMoneyFlowFeed{NewChaikinMoneyFlow(20)} },
+		"parabolic_sar":             func() statefulIndicator { return parabolicSARFeed{NewParabolicSAR(0.02, 0.2)} },
+		"keltner":                   func() statefulIndicator { return keltnerFeed{NewKeltnerChannels(20, 10, 2)} },
+		"donchian":                  func() statefulIndicator { return donchianFeed{NewDonchianChannels(20)} },
+		"cci":                       func() statefulIndicator { return cciFeed{NewCCI(20)} },
+		"roc":                       func() statefulIndicator { return rateOfChangeFeed{NewRateOfChange(12)} },
+		"momentum":                  func() statefulIndicator { return momentumFeed{NewMomentum(10)} },
+		"trix":                      func() statefulIndicator { return trixFeed{NewTRIX(9)} },
+		"ultimate_oscillator":       func() statefulIndicator { return ultimateOscillatorFeed{NewUltimateOscillator(7, 14, 28)} },
+		"sma":                       func() statefulIndicator { return smaFeed{NewSMA(10)} },
+		"wma":                       func() statefulIndicator { return wmaFeed{NewWMA(10)} },
+		"hma":                       func() statefulIndicator { return hmaFeed{NewHMA(9)} },
+		"dema":                      func() statefulIndicator { return demaFeed{NewDEMA(10)} },
+		"tema":                      func() statefulIndicator { return temaFeed{NewTEMA(10)} },
+		"kama":                      func() statefulIndicator { return kamaFeed{NewKAMA(10, 2, 30)} },
+		"alma":                      func() statefulIndicator { return almaFeed{NewALMA(9, 0.85, 6)} },
+	}
+	data := waveData(80)
+
+	for name, build := range indicators {
+		for _, format := range []StateFormat{JSONState, BinaryState} {
+			original := build()
+			for _, dp := range data[:40] {
+				original.add(dp)
+			}
+			saved, err := original.MarshalState(format)
+			if err != nil {
+				t.Fatalf("%s %s: unexpected error saving: %v", name, format, err)
+			}
+			restored := build()
+			if err := restored.UnmarshalState(format, saved); err != nil {
+				t.Fatalf("%s %s: unexpected error restoring: %v", name, format, err)
+			}
+
+			// Both continue identically after the snapshot.
+			for _, dp := range data[40:] {
+				original.add(dp)
+				restored.add(dp)
+			}
+			want, _ := json.Marshal(original)
+			got, _ := json.Marshal(restored)
+			if string(want) != string(got) {
+				t.Errorf("%s %s: restored state diverged\nwant %s\ngot  %s", name, format, want, got)
+			}
+		}
+	}
+
+	if err := NewRSI(14).UnmarshalState("xml", nil); err == nil {
+		t.Errorf("expected an error for an unknown format")
+	}
+}
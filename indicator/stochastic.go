@@ -0,0 +1,194 @@
+package indicator
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils"
+)
+
+// StochasticValues represents the calculated %K and %D lines of the Stochastic oscillator.
+type StochasticValues struct {
+	K float64
+	D float64
+}
+
+// Stochastic represents the state of the full Stochastic oscillator. The raw %K places the close within the
+// highest high and lowest low of Period bars, %K averages KSmoothing raw values and %D averages DPeriod %K values.
+type Stochastic struct {
+	Period     int
+	KSmoothing int
+	DPeriod    int
+	History    []model.DataPoint
+	RawK       []float64
+	K          []float64
+	Values     StochasticValues
+	// Initialized is set once %D has a full period of %K values.
+	Initialized bool
+}
+
+// NewStochastic initializes a new full Stochastic instance.
+func NewStochastic(period, kSmoothing, dPeriod int) *Stochastic {
+	return &Stochastic{
+		Period:     period,
+		KSmoothing: kSmoothing,
+		DPeriod:    dPeriod,
+	}
+}
+
+// NewFastStochastic initializes a fast Stochastic, whose %K is the raw %K.
+func NewFastStochastic(period, dPeriod int) *Stochastic {
+	return NewStochastic(period, 1, dPeriod)
+}
+
+// NewSlowStochastic initializes a slow Stochastic, whose %K is the 3 bar average of the raw %K and %D its 3 bar average.
+func NewSlowStochastic(period int) *Stochastic {
+	return NewStochastic(period, 3, 3)
+}
+
+// Update adds a new data point and updates the Stochastic calculation.
+func (s *Stochastic) Update(ctx context.Context, data model.DataPoint) error {
+	if len(s.History) > 0 && data.Time <= s.History[len(s.History)-1].Time {
+		return errors.New("data point is not in chronological order")
+	}
+
+	s.History = append(s.History, data)
+	if len(s.History) > s.Period {
+		s.History = s.History[1:]
+	}
+	if len(s.History) < s.Period {
+		return nil
+	}
+
+	s.RawK = utils.AppendWindow(s.RawK, rangePosition(s.History, data.Close), s.KSmoothing)
+	if len(s.RawK) < s.KSmoothing {
+		return nil
+	}
+	s.Values.K = simpleMovingAverage(s.RawK)
+	s.K = utils.AppendWindow(s.K, s.Values.K, s.DPeriod)
+	if len(s.K) < s.DPeriod {
+		return nil
+	}
+	s.Values.D = simpleMovingAverage(s.K)
+	s.Initialized = true
+	return nil
+}
+
+// Ready reports whether the Stochastic has seen enough bars.
+func (s *Stochastic) Ready() bool {
+	return s.Initialized
+}
+
+// Value returns the current Stochastic.
+func (s *Stochastic) Value() StochasticValues {
+	return s.Values
+}
+
+// Lookback returns the number of bars before the Stochastic is ready.
+func (s *Stochastic) Lookback() int {
+	return s.Period + s.KSmoothing + s.DPeriod - 2
+}
+
+// Reset forgets the data seen by the Stochastic, keeping its parameters.
+func (s *Stochastic) Reset() {
+	*s = *NewStochastic(s.Period, s.KSmoothing, s.DPeriod)
+}
+
+// MarshalState encodes the full state of the Stochastic.
+func (s *Stochastic) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, s)
+}
+
+// UnmarshalState replaces the state of the Stochastic with one saved by MarshalState.
+func (s *Stochastic) UnmarshalState(format StateFormat, data []byte) error {
+	var state Stochastic
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*s = state
+	return nil
+}
+
+// WilliamsR represents the state of the Williams %R oscillator, running from -100 at the lowest low
+// of Period bars to 0 at their highest high.
+type WilliamsR struct {
+	Period      int
+	History     []model.DataPoint
+	Current     float64
+	Initialized bool
+}
+
+// NewWilliamsR initializes a new Williams %R instance.
+func NewWilliamsR(period int) *WilliamsR {
+	return &WilliamsR{
+		Period: period,
+	}
+}
+
+// Update adds a new data point and updates the Williams %R calculation.
+func (w *WilliamsR) Update(ctx context.Context, data model.DataPoint) error {
+	if len(w.History) > 0 && data.Time <= w.History[len(w.History)-1].Time {
+		return errors.New("data point is not in chronological order")
+	}
+
+	w.History = append(w.History, data)
+	if len(w.History) > w.Period {
+		w.History = w.History[1:]
+	}
+	if len(w.History) < w.Period {
+		return nil
+	}
+	w.Current = rangePosition(w.History, data.Close) - 100
+	w.Initialized = true
+	return nil
+}
+
+// Ready reports whether the Williams %R has seen enough bars.
+func (w *WilliamsR) Ready() bool {
+	return w.Initialized
+}
+
+// Value returns the current Williams %R.
+func (w *WilliamsR) Value() float64 {
+	return w.Current
+}
+
+// Lookback returns the number of bars before the Williams %R is ready.
+func (w *WilliamsR) Lookback() int {
+	return w.Period
+}
+
+// Reset forgets the data seen by the Williams %R, keeping its parameters.
+func (w *WilliamsR) Reset() {
+	*w = *NewWilliamsR(w.Period)
+}
+
+// MarshalState encodes the full state of the Williams %R.
+func (w *WilliamsR) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, w)
+}
+
+// UnmarshalState replaces the state of the Williams %R with one saved by MarshalState.
+func (w *WilliamsR) UnmarshalState(format StateFormat, data []byte) error {
+	var state WilliamsR
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*w = state
+	return nil
+}
+
+// rangePosition returns where price lies between the lowest low and highest high of the bars, from 0 to 100.
+// A flat range places it in the middle.
+func rangePosition(history []model.DataPoint, price float64) float64 {
+	highest, lowest := history[0].High, history[0].Low
+	for _, data := range history[1:] {
+		highest = max(highest, data.High)
+		lowest = min(lowest, data.Low)
+	}
+	if highest == lowest {
+		return 50
+	}
+	return (price - lowest) / (highest - lowest) * 100
+}
@@ -0,0 +1,74 @@
+package indicator
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// oscillatorData has raw %K values of 75, 33.33, 100, 33.33 and 0 over 3 bars from the third bar on.
+var oscillatorData = []model.DataPoint{
+	{Time: 1, High: 10, Low: 8, Close: 9},
+	{Time: 2, High: 11, Low: 9, Close: 10},
+	{Time: 3, High: 12, Low: 9, Close: 11},
+	{Time: 4, High: 12, Low: 10, Close: 10},
+	{Time: 5, High: 13, Low: 11, Close: 13},
+	{Time: 6, High: 13, Low: 10, Close: 11},
+	{Time: 7, High: 12, Low: 10, Close: 10},
+}
+
+func TestStochastic(t *testing.T) {
+	tests := []struct {
+		name        string
+		stochastic  *Stochastic
+		initialized int
+		want        StochasticValues
+	}{
+		{"fast", NewFastStochastic(3, 3), 5, StochasticValues{K: 0, D: 400.0 / 9}},
+		{"slow", NewSlowStochastic(3), 7, StochasticValues{K: 400.0 / 9, D: 1525.0 / 27}},
+		{"full", NewStochastic(3, 2, 2), 5, StochasticValues{K: 50.0 / 3, D: 125.0 / 3}},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		for i, dp := range oscillatorData {
+			if err := tt.stochastic.Update(ctx, dp); err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if tt.stochastic.Initialized != (i+1 >= tt.initialized) {
+				t.Errorf("%s: expected initialized %v after %d bars", tt.name, i+1 >= tt.initialized, i+1)
+			}
+		}
+		got := tt.stochastic.Value()
+		if math.Abs(got.K-tt.want.K) > 1e-9 || math.Abs(got.D-tt.want.D) > 1e-9 {
+			t.Errorf("%s: expected %+v, got %+v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestWilliamsR(t *testing.T) {
+	want := []float64{-25, -200.0 / 3, 0, -200.0 / 3, -100}
+
+	ctx := context.Background()
+	williamsR := NewWilliamsR(3)
+	for i, dp := range oscillatorData {
+		if err := williamsR.Update(ctx, dp); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if i < 2 {
+			if williamsR.Initialized {
+				t.Errorf("expected Williams %%R not to be initialized after %d bars", i+1)
+			}
+			continue
+		}
+		if math.Abs(williamsR.Value()-want[i-2]) > 1e-9 {
+			t.Errorf("bar %d: expected %v, got %v", i+1, want[i-2], williamsR.Value())
+		}
+	}
+
+	if err := williamsR.Update(ctx, oscillatorData[0]); err == nil {
+		t.Errorf("expected error for out-of-order data point, got nil")
+	}
+}
@@ -3,33 +3,41 @@ package indicator
 import (
 	"context"
 	"errors"
-	"math"
 
 	"github.com/vd09/trading-algorithm-backtesting-system/model"
 )
 
 // SuperTrend represents the state of the Super Trend indicator.
 type SuperTrend struct {
-	Period         int
-	Multiplier     float64
-	ATR            float64
-	SuperTrendLine float64
-	History        []model.DataPoint
-	IsUpTrend      bool
-	Initialized    bool
+	Period     int
+	Multiplier float64
+	// ATR is the current Wilder average true range of AverageTrueRange.
+	ATR              float64
+	AverageTrueRange *ATR
+	SuperTrendLine   float64
+	History          []model.DataPoint
+	IsUpTrend        bool
+	Initialized      bool
+}
+
+// SuperTrendValues holds the Super Trend line and the direction of the trend.
+type SuperTrendValues struct {
+	Line    float64
+	UpTrend bool
 }
 
 // NewSuperTrend initializes a new Super Trend instance.
 func NewSuperTrend(period int, multiplier float64) *SuperTrend {
 	return &SuperTrend{
-		Period:      period,
-		Multiplier:  multiplier,
-		Initialized: false,
+		Period:           period,
+		Multiplier:       multiplier,
+		AverageTrueRange: NewATR(period, WilderSmoothing),
+		Initialized:      false,
 	}
 }
 
-// AddDataPoint adds a new data point and updates the Super Trend calculation.
-func (st *SuperTrend) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+// Update adds a new data point and updates the Super Trend calculation.
+func (st *SuperTrend) Update(ctx context.Context, data model.DataPoint) error {
 	if len(st.History) > 0 && data.Time <= st.History[len(st.History)-1].Time {
 		return errors.New("data point is not in chronological order")
 	}
@@ -39,6 +47,11 @@ func (st *SuperTrend) AddDataPoint(ctx context.Context, data model.DataPoint) er
 		return errors.New("data point contains negative or zero prices")
 	}
 
+	if err := st.AverageTrueRange.Update(ctx, data); err != nil {
+		return err
+	}
+	st.ATR = st.AverageTrueRange.Value()
+
 	st.History = append(st.History, data)
 	if len(st.History) > st.Period {
 		st.History = st.History[1:]
@@ -48,27 +61,10 @@ func (st *SuperTrend) AddDataPoint(ctx context.Context, data model.DataPoint) er
 		return nil
 	}
 
-	st.calculateATR()
 	st.calculateSuperTrend()
 	return nil
 }
 
-func (st *SuperTrend) calculateATR() {
-	if len(st.History) < st.Period {
-		return
-	}
-
-	trSum := 0.0
-	for i := 1; i < len(st.History); i++ {
-		highLow := st.History[i].High - st.History[i].Low
-		highClose := math.Abs(st.History[i].High - st.History[i-1].Close)
-		lowClose := math.Abs(st.History[i].Low - st.History[i-1].Close)
-		tr := math.Max(highLow, math.Max(highClose, lowClose))
-		trSum += tr
-	}
-	st.ATR = trSum / float64(st.Period)
-}
-
 func (st *SuperTrend) calculateSuperTrend() {
 	if len(st.History) < st.Period {
 		return
@@ -89,6 +85,54 @@ func (st *SuperTrend) calculateSuperTrend() {
 }
 
 // CalculateSuperTrend returns the current Super Trend value and the trend direction.
+//
+// Deprecated: Use Value.
 func (st *SuperTrend) CalculateSuperTrend() (float64, bool) {
-	return st.SuperTrendLine, st.IsUpTrend
+	value := st.Value()
+	return value.Line, value.UpTrend
+}
+
+// AddDataPoint adds a new data point.
+//
+// Deprecated: Use Update.
+func (st *SuperTrend) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	return st.Update(ctx, data)
+}
+
+// Ready reports whether the SuperTrend has seen enough bars.
+func (st *SuperTrend) Ready() bool {
+	return st.Initialized
+}
+
+// Value returns the current SuperTrend.
+func (st *SuperTrend) Value() SuperTrendValues {
+	return SuperTrendValues{Line: st.SuperTrendLine, UpTrend: st.IsUpTrend}
+}
+
+// Lookback returns the number of bars before the SuperTrend is ready.
+func (st *SuperTrend) Lookback() int {
+	return st.Period
+}
+
+// Reset forgets the data seen by the SuperTrend, keeping its parameters.
+func (st *SuperTrend) Reset() {
+	*st = *NewSuperTrend(st.Period, st.Multiplier)
+}
+
+// MarshalState encodes the full state of the Super Trend.
+func (st *SuperTrend) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, st)
+}
+
+// UnmarshalState replaces the state of the Super Trend with one saved by MarshalState.
+func (st *SuperTrend) UnmarshalState(format StateFormat, data []byte) error {
+	var state SuperTrend
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	if state.AverageTrueRange == nil {
+		return errors.New("super trend state is missing its average true range")
+	}
+	*st = state
+	return nil
 }
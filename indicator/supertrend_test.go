@@ -26,14 +26,14 @@ func TestSuperTrend(t *testing.T) {
 
 	ctx := context.Background()
 	for _, dp := range dataPoints {
-		err := st.AddDataPoint(ctx, dp)
+		err := st.Update(ctx, dp)
 		if err != nil {
 			t.Errorf("error adding data point: %v", err)
 		}
 
 		if len(st.History) >= st.Period {
-			superTrendLine, isUpTrend := st.CalculateSuperTrend()
-			t.Logf("SuperTrend: %v, IsUpTrend: %v", superTrendLine, isUpTrend)
+			value := st.Value()
+			t.Logf("SuperTrend: %v, IsUpTrend: %v", value.Line, value.UpTrend)
 		}
 	}
 }
@@ -0,0 +1,97 @@
+package indicator
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// TRIX represents the state of the TRIX indicator, the one bar percentage change of a triple smoothed
+// EMA of the close. It is ready after 3*Period-1 bars.
+type TRIX struct {
+	Period int
+	First  *EMA
+	Second *EMA
+	Third  *EMA
+	// PreviousTriple is the triple smoothed EMA of the bar before the latest one.
+	PreviousTriple float64
+	LastTime       int64
+	Current        float64
+	Initialized    bool
+}
+
+// NewTRIX initializes a new TRIX instance.
+func NewTRIX(period int) *TRIX {
+	return &TRIX{
+		Period: period,
+		First:  NewEMA(period),
+		Second: NewEMA(period),
+		Third:  NewEMA(period),
+	}
+}
+
+// Update adds a new data point and updates the TRIX.
+func (t *TRIX) Update(ctx context.Context, data model.DataPoint) error {
+	if t.LastTime != 0 && data.Time <= t.LastTime {
+		return errors.New("data point is not in chronological order")
+	}
+	t.LastTime = data.Time
+
+	t.First.Update(ctx, data)
+	if !t.First.Initialized {
+		return nil
+	}
+	t.Second.Update(ctx, model.DataPoint{Time: data.Time, Close: t.First.Current})
+	if !t.Second.Initialized {
+		return nil
+	}
+	wasReady, previous := t.Third.Initialized, t.Third.Current
+	t.Third.Update(ctx, model.DataPoint{Time: data.Time, Close: t.Second.Current})
+	if !wasReady {
+		return nil
+	}
+
+	t.PreviousTriple = previous
+	t.Current = 0
+	if previous != 0 {
+		t.Current = 100 * (t.Third.Current - previous) / previous
+	}
+	t.Initialized = true
+	return nil
+}
+
+// Ready reports whether the TRIX has seen enough bars.
+func (t *TRIX) Ready() bool {
+	return t.Initialized
+}
+
+// Value returns the current TRIX.
+func (t *TRIX) Value() float64 {
+	return t.Current
+}
+
+// Lookback returns the number of bars before the TRIX is ready.
+func (t *TRIX) Lookback() int {
+	return 3*t.Period - 1
+}
+
+// Reset forgets the data seen by the TRIX, keeping its parameters.
+func (t *TRIX) Reset() {
+	*t = *NewTRIX(t.Period)
+}
+
+// MarshalState encodes the full state of the TRIX.
+func (t *TRIX) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, t)
+}
+
+// UnmarshalState replaces the state of the TRIX with one saved by MarshalState.
+func (t *TRIX) UnmarshalState(format StateFormat, data []byte) error {
+	var state TRIX
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*t = state
+	return nil
+}
@@ -0,0 +1,98 @@
+package indicator
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils"
+)
+
+// UltimateOscillator represents the state of Larry Williams' Ultimate Oscillator, a 4:2:1 weighted average
+// of the buying pressure relative to the true range over a short, medium and long period, from 0 to 100.
+type UltimateOscillator struct {
+	ShortPeriod  int
+	MediumPeriod int
+	LongPeriod   int
+	LastData     model.DataPoint
+	// BuyingPressures and TrueRanges hold the values of the latest LongPeriod bar to bar moves.
+	BuyingPressures []float64
+	TrueRanges      []float64
+	Current         float64
+	Initialized     bool
+}
+
+// NewUltimateOscillator initializes a new Ultimate Oscillator instance.
+func NewUltimateOscillator(shortPeriod, mediumPeriod, longPeriod int) *UltimateOscillator {
+	return &UltimateOscillator{
+		ShortPeriod:  shortPeriod,
+		MediumPeriod: mediumPeriod,
+		LongPeriod:   longPeriod,
+	}
+}
+
+// Update adds a new data point and updates the Ultimate Oscillator.
+func (u *UltimateOscillator) Update(ctx context.Context, data model.DataPoint) error {
+	if u.LastData.Time != 0 && data.Time <= u.LastData.Time {
+		return errors.New("data point is not in chronological order")
+	}
+	previous := u.LastData
+	u.LastData = data
+	if previous.Time == 0 {
+		return nil
+	}
+
+	low := math.Min(data.Low, previous.Close)
+	u.BuyingPressures = utils.AppendWindow(u.BuyingPressures, data.Close-low, u.LongPeriod)
+	u.TrueRanges = utils.AppendWindow(u.TrueRanges, TrueRange(data, previous.Close), u.LongPeriod)
+	if len(u.TrueRanges) < u.LongPeriod {
+		return nil
+	}
+
+	average := func(period int) float64 {
+		ranges := sum(u.TrueRanges[len(u.TrueRanges)-period:])
+		if ranges == 0 {
+			return 0.5
+		}
+		return sum(u.BuyingPressures[len(u.BuyingPressures)-period:]) / ranges
+	}
+	u.Current = 100 * (4*average(u.ShortPeriod) + 2*average(u.MediumPeriod) + average(u.LongPeriod)) / 7
+	u.Initialized = true
+	return nil
+}
+
+// Ready reports whether the Ultimate Oscillator has seen enough bars.
+func (u *UltimateOscillator) Ready() bool {
+	return u.Initialized
+}
+
+// Value returns the current Ultimate Oscillator.
+func (u *UltimateOscillator) Value() float64 {
+	return u.Current
+}
+
+// Lookback returns the number of bars before the Ultimate Oscillator is ready.
+func (u *UltimateOscillator) Lookback() int {
+	return u.LongPeriod + 1
+}
+
+// Reset forgets the data seen by the Ultimate Oscillator, keeping its parameters.
+func (u *UltimateOscillator) Reset() {
+	*u = *NewUltimateOscillator(u.ShortPeriod, u.MediumPeriod, u.LongPeriod)
+}
+
+// MarshalState encodes the full state of the Ultimate Oscillator.
+func (u *UltimateOscillator) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, u)
+}
+
+// UnmarshalState replaces the state of the Ultimate Oscillator with one saved by MarshalState.
+func (u *UltimateOscillator) UnmarshalState(format StateFormat, data []byte) error {
+	var state UltimateOscillator
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*u = state
+	return nil
+}
@@ -0,0 +1,151 @@
+package indicator
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// volumeData has money flow multipliers of 0, 1, -1, 0 and 0 and typical prices of 9, 31/3, 32/3, 11 and 10.
+var volumeData = []model.DataPoint{
+	{Time: 1, High: 10, Low: 8, Close: 9, Volume: 100},
+	{Time: 2, High: 11, Low: 9, Close: 11, Volume: 200},
+	{Time: 3, High: 12, Low: 10, Close: 10, Volume: 300},
+	{Time: 4, High: 12, Low: 10, Close: 11, Volume: 100},
+	{Time: 5, High: 11, Low: 9, Close: 10, Volume: 400},
+}
+
+func TestVolumeLines(t *testing.T) {
+	wantOBV := []float64{0, 200, -100, 0, -400}
+	wantAD := []float64{0, 200, -100, -100, -100}
+
+	ctx := context.Background()
+	obv, ad := NewOBV(), NewAccumulationDistribution()
+	for i, dp := range volumeData {
+		if err := obv.Update(ctx, dp); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := ad.Update(ctx, dp); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if obv.Value() != wantOBV[i] {
+			t.Errorf("bar %d: expected OBV %v, got %v", i+1, wantOBV[i], obv.Value())
+		}
+		if ad.Value() != wantAD[i] {
+			t.Errorf("bar %d: expected A/D %v, got %v", i+1, wantAD[i], ad.Value())
+		}
+	}
+
+	if err := obv.Update(ctx, volumeData[0]); err == nil {
+		t.Errorf("expected an error for an out of order OBV data point")
+	}
+	if err := ad.Update(ctx, volumeData[0]); err == nil {
+		t.Errorf("expected an error for an out of order A/D data point")
+	}
+}
+
+func TestChaikinMoneyFlow(t *testing.T) {
+	want := []float64{-1.0 / 6, -1.0 / 6, -3.0 / 8}
+
+	ctx := context.Background()
+	cmf := NewChaikinMoneyFlow(3)
+	for i, dp := range volumeData {
+		if err := cmf.Update(ctx, dp); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if i < 2 {
+			if cmf.Initialized {
+				t.Errorf("expected not initialized after %d bars", i+1)
+			}
+			continue
+		}
+		if got := cmf.Value(); math.Abs(got-want[i-2]) > 1e-9 {
+			t.Errorf("bar %d: expected %v, got %v", i+1, want[i-2], got)
+		}
+	}
+}
+
+func TestMoneyFlowIndex(t *testing.T) {
+	// Positive flows of 6200/3, 3200 and 1100 and a negative flow of 4000.
+	want := []float64{100, 100, 1100.0 / 51}
+
+	ctx := context.Background()
+	mfi := NewMoneyFlowIndex(2)
+	for i, dp := range volumeData {
+		if err := mfi.Update(ctx, dp); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if i < 2 {
+			if mfi.Initialized {
+				t.Errorf("expected not initialized after %d bars", i+1)
+			}
+			continue
+		}
+		if got := mfi.Value(); math.Abs(got-want[i-2]) > 1e-9 {
+			t.Errorf("bar %d: expected %v, got %v", i+1, want[i-2], got)
+		}
+	}
+}
+
+func TestVWAP(t *testing.T) {
+	ctx := context.Background()
+	vwap := NewVWAP(2)
+	for _, dp := range volumeData[:2] {
+		if err := vwap.Update(ctx, dp); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// A price volume of 8900/3 over 300 shares, the squared deviations average 32/81.
+	stdDev := math.Sqrt(32) / 9
+	want := VWAPValues{VWAP: 89.0 / 9, UpperBand: 89.0/9 + 2*stdDev, LowerBand: 89.0/9 - 2*stdDev, StdDev: stdDev}
+	got := vwap.Value()
+	if math.Abs(got.VWAP-want.VWAP) > 1e-9 || math.Abs(got.UpperBand-want.UpperBand) > 1e-9 ||
+		math.Abs(got.LowerBand-want.LowerBand) > 1e-9 || math.Abs(got.StdDev-want.StdDev) > 1e-9 {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	// The first bar of the next day starts a new session.
+	nextDay := model.DataPoint{Time: 24*60*60*1000 + 1, High: 20, Low: 18, Close: 19, Volume: 50}
+	if err := vwap.Update(ctx, nextDay); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := vwap.Value(); got != (VWAPValues{VWAP: 19, UpperBand: 19, LowerBand: 19}) {
+		t.Errorf("expected the VWAP to restart at 19, got %+v", got)
+	}
+	if vwap.SessionBars != 1 {
+		t.Errorf("expected 1 bar in the new session, got %d", vwap.SessionBars)
+	}
+}
+
+func TestVWAPSessionsFollowUTC(t *testing.T) {
+	// Sessions must not depend on the local time zone, which here puts midnight at 19:00 UTC.
+	local := time.Local
+	time.Local = time.FixedZone("UTC+5", 5*60*60)
+	defer func() { time.Local = local }()
+
+	ctx := context.Background()
+	vwap := NewVWAP(2)
+	bars := []struct {
+		time time.Time
+		bars int
+	}{
+		{time.Date(2024, 3, 4, 18, 30, 0, 0, time.UTC), 1},
+		{time.Date(2024, 3, 4, 19, 30, 0, 0, time.UTC), 2},
+		{time.Date(2024, 3, 4, 23, 30, 0, 0, time.UTC), 3},
+		{time.Date(2024, 3, 5, 0, 30, 0, 0, time.UTC), 1},
+	}
+	for _, bar := range bars {
+		if err := vwap.Update(ctx, model.DataPoint{Time: bar.time.UnixMilli(), High: 11, Low: 9, Close: 10, Volume: 100}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if vwap.SessionBars != bar.bars {
+			t.Errorf("expected %d bars in the session at %v, got %d", bar.bars, bar.time, vwap.SessionBars)
+		}
+	}
+	if vwap.Session != "2024-03-05" {
+		t.Errorf("expected the 2024-03-05 session, got %s", vwap.Session)
+	}
+}
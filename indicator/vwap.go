@@ -0,0 +1,113 @@
+package indicator
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// VWAPValues represents the session VWAP and its standard deviation bands.
+type VWAPValues struct {
+	VWAP      float64
+	UpperBand float64
+	LowerBand float64
+	// StdDev is the volume weighted standard deviation of the typical price around the VWAP.
+	StdDev float64
+}
+
+// VWAP represents the state of the Volume Weighted Average Price, anchored to the start of every session.
+// A session is a UTC calendar day of the millisecond bar times.
+type VWAP struct {
+	// BandMultiplier is the distance of the bands from the VWAP in standard deviations.
+	BandMultiplier float64
+	Session        string
+	LastTime       int64
+	// PriceVolume, SquaredPriceVolume and Volume are the session sums of the typical price times
+	// the volume, its square times the volume and the volume.
+	PriceVolume        float64
+	SquaredPriceVolume float64
+	Volume             float64
+	// SessionBars counts the bars of the current session.
+	SessionBars int
+	Values      VWAPValues
+	Initialized bool
+}
+
+// NewVWAP initializes a new session anchored VWAP instance.
+func NewVWAP(bandMultiplier float64) *VWAP {
+	return &VWAP{
+		BandMultiplier: bandMultiplier,
+	}
+}
+
+// Update adds a new data point, starting a new session on the first bar of a day, and updates the VWAP.
+func (v *VWAP) Update(ctx context.Context, data model.DataPoint) error {
+	if v.LastTime != 0 && data.Time <= v.LastTime {
+		return errors.New("data point is not in chronological order")
+	}
+	v.LastTime = data.Time
+
+	if session := time.UnixMilli(data.Time).UTC().Format(time.DateOnly); session != v.Session {
+		v.Session = session
+		v.PriceVolume, v.SquaredPriceVolume, v.Volume, v.SessionBars = 0, 0, 0, 0
+	}
+	typical := TypicalPrice(data)
+	v.PriceVolume += typical * data.Volume
+	v.SquaredPriceVolume += typical * typical * data.Volume
+	v.Volume += data.Volume
+	v.SessionBars++
+
+	if v.Volume == 0 {
+		// Without any volume yet the VWAP falls back to the typical price.
+		v.Values = VWAPValues{VWAP: typical, UpperBand: typical, LowerBand: typical}
+	} else {
+		vwap := v.PriceVolume / v.Volume
+		stdDev := math.Sqrt(math.Max(0, v.SquaredPriceVolume/v.Volume-vwap*vwap))
+		v.Values = VWAPValues{
+			VWAP:      vwap,
+			UpperBand: vwap + v.BandMultiplier*stdDev,
+			LowerBand: vwap - v.BandMultiplier*stdDev,
+			StdDev:    stdDev,
+		}
+	}
+	v.Initialized = true
+	return nil
+}
+
+// Ready reports whether the VWAP has seen enough bars.
+func (v *VWAP) Ready() bool {
+	return v.Initialized
+}
+
+// Value returns the current VWAP.
+func (v *VWAP) Value() VWAPValues {
+	return v.Values
+}
+
+// Lookback returns the number of bars before the VWAP is ready.
+func (v *VWAP) Lookback() int {
+	return 1
+}
+
+// Reset forgets the data seen by the VWAP, keeping its parameters.
+func (v *VWAP) Reset() {
+	*v = *NewVWAP(v.BandMultiplier)
+}
+
+// MarshalState encodes the full state of the VWAP.
+func (v *VWAP) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, v)
+}
+
+// UnmarshalState replaces the state of the VWAP with one saved by MarshalState.
+func (v *VWAP) UnmarshalState(format StateFormat, data []byte) error {
+	var state VWAP
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*v = state
+	return nil
+}
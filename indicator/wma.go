@@ -0,0 +1,75 @@
+package indicator
+
+import (
+	"context"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils"
+)
+
+// WMA represents the state of the linearly weighted moving average of the close, the latest bar weighs
+// Period and the oldest 1.
+type WMA struct {
+	Period int
+	// Prices holds the closes of the latest Period bars.
+	Prices      []float64
+	Current     float64
+	Initialized bool
+}
+
+// NewWMA initializes a new WMA instance.
+func NewWMA(period int) *WMA {
+	return &WMA{
+		Period: period,
+	}
+}
+
+// Update adds a new data point and updates the WMA.
+func (w *WMA) Update(ctx context.Context, data model.DataPoint) error {
+	w.Prices = utils.AppendWindow(w.Prices, data.Close, w.Period)
+	if len(w.Prices) < w.Period {
+		return nil
+	}
+	total := 0.0
+	for i, price := range w.Prices {
+		total += float64(i+1) * price
+	}
+	w.Current = total / float64(w.Period*(w.Period+1)/2)
+	w.Initialized = true
+	return nil
+}
+
+// Value returns the current WMA.
+func (w *WMA) Value() float64 {
+	return w.Current
+}
+
+// Ready reports whether the WMA has seen a full period.
+func (w *WMA) Ready() bool {
+	return w.Initialized
+}
+
+// Lookback returns the number of bars before the WMA is ready.
+func (w *WMA) Lookback() int {
+	return w.Period
+}
+
+// Reset forgets the data seen by the WMA, keeping its parameters.
+func (w *WMA) Reset() {
+	*w = *NewWMA(w.Period)
+}
+
+// MarshalState encodes the full state of the WMA.
+func (w *WMA) MarshalState(format StateFormat) ([]byte, error) {
+	return EncodeState(format, w)
+}
+
+// UnmarshalState replaces the state of the WMA with one saved by MarshalState.
+func (w *WMA) UnmarshalState(format StateFormat, data []byte) error {
+	var state WMA
+	if err := DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	*w = state
+	return nil
+}
@@ -0,0 +1,212 @@
+package indicator_adaptor
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/constraint"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator"
+	"github.com/vd09/trading-algorithm-backtesting-system/logger"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
+	"go.uber.org/zap"
+)
+
+const (
+	ADX_LINE_LABEL = "adx_line_type"
+)
+
+type ADXMetrics struct {
+	SignalCounter monitor.CounterMetric
+	LineGauge     monitor.GaugeMetric
+	monitor       monitor.Monitoring
+}
+
+// ADXAdapter buys when +DI crosses above -DI and sells when it crosses below, only while the ADX
+// is at or above the trend threshold.
+type ADXAdapter struct {
+	ADX            *indicator.ADX
+	TrendThreshold float64
+	PreviousValues indicator.ADXValues
+	// HasPrevious is set when PreviousValues hold the directional indicators of the bar before the current one.
+	HasPrevious bool
+	CurrentData model.DataPoint
+	swing       priceSwing
+	logger      logger.LoggerInterface
+	metrics     *ADXMetrics
+}
+
+func init() {
+	registry.RegisterAdaptor(registry.AdaptorEntry{
+		Name:        "adx",
+		Description: "Signals when the directional indicators cross while the ADX shows a trend",
+		Params: []registry.Param{
+			{Name: "period", Type: registry.IntParam, Default: 14, Min: 1, Max: 1000},
+			{Name: "trend_threshold", Type: registry.FloatParam, Description: "ADX from which the market is trending", Default: 25.0, Min: 0, Max: 99},
+		},
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (IndicatorAdaptor, error) {
+			return NewADXAdapter(ctx, p.Int("period"), p.Float("trend_threshold"), m), nil
+		},
+	})
+}
+
+func NewADXAdapter(ctx context.Context, period int, trendThreshold float64, monitor monitor.Monitoring) *ADXAdapter {
+	adapter := &ADXAdapter{
+		ADX:            indicator.NewADX(period),
+		TrendThreshold: trendThreshold,
+		logger:         logger.GetLogger(),
+	}
+	adapter.registerMetrics(ctx, monitor)
+	return adapter
+}
+
+func (aa *ADXAdapter) registerMetrics(ctx context.Context, m monitor.Monitoring) {
+	ctx = aa.getUpdateContext(ctx)
+	aa.metrics = &ADXMetrics{
+		monitor:       m,
+		SignalCounter: m.RegisterCounter(ctx, "adx_signals_generated", "Total number of ADX signals generated", monitor.Labels{constraint.SIGNAL_TYPE_LABEL}),
+		LineGauge:     m.RegisterGauge(ctx, "adx_lines", "Current ADX and directional indicators", monitor.Labels{ADX_LINE_LABEL}),
+	}
+}
+
+func (aa *ADXAdapter) Clone(ctx context.Context) IndicatorAdaptor {
+	return NewADXAdapter(ctx, aa.ADX.Period, aa.TrendThreshold, aa.metrics.monitor)
+}
+
+func (aa *ADXAdapter) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	ctx = aa.getUpdateContext(ctx)
+	aa.logger.Debug(ctx, "Adding data point to ADXAdapter", zap.Int64("timestamp", data.Time))
+
+	previous, wasReady := aa.ADX.Value(), aa.ADX.DIReady
+	if err := aa.ADX.Update(ctx, data); err != nil {
+		aa.logger.Error(ctx, "Failed to add data point to ADX", zap.Error(err))
+		return err
+	}
+	aa.CurrentData = data
+	aa.swing.add(data)
+	aa.PreviousValues, aa.HasPrevious = previous, wasReady
+
+	if aa.ADX.Ready() {
+		values := aa.ADX.Value()
+		aa.metrics.LineGauge.SetGauge(ctx, values.ADX, monitor.NewTagsKV(ADX_LINE_LABEL, "adx"))
+		aa.metrics.LineGauge.SetGauge(ctx, values.PlusDI, monitor.NewTagsKV(ADX_LINE_LABEL, "plus_di"))
+		aa.metrics.LineGauge.SetGauge(ctx, values.MinusDI, monitor.NewTagsKV(ADX_LINE_LABEL, "minus_di"))
+	}
+	return nil
+}
+
+func (aa *ADXAdapter) Name() string {
+	return fmt.Sprintf("ADX_%d_%.0f", aa.ADX.Period, aa.TrendThreshold)
+}
+
+// Value returns the latest ADX, zero until it is initialized, for components using the trend strength as a filter.
+func (aa *ADXAdapter) Value() float64 {
+	return aa.ADX.Value().ADX
+}
+
+func (aa *ADXAdapter) GetSignal(ctx context.Context) (result model.TradingSignal) {
+	ctx = aa.getUpdateContext(ctx)
+	defer func() {
+		tags := monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result.Action))
+		tags.Add(ADAPTOR_NAME_LABEL, aa.Name())
+		aa.metrics.SignalCounter.IncrementCounter(ctx, tags)
+	}()
+	result = model.TradingSignal{Time: aa.CurrentData.Time, Action: model.Wait}
+	zaps := []zap.Field{zap.String("adapter", aa.Name()), zap.Any("time", aa.CurrentData.Time)}
+
+	if !aa.HasPrevious || !aa.ADX.Ready() {
+		aa.logger.Debug(ctx, "ADX not initialized", zaps...)
+		return result
+	}
+	current := aa.ADX.Value()
+	if current.ADX < aa.TrendThreshold {
+		aa.logger.Debug(ctx, "No trend", append(zaps, zap.Float64("adx", current.ADX))...)
+		return result
+	}
+
+	previous := aa.PreviousValues
+	if previous.PlusDI <= previous.MinusDI && current.PlusDI > current.MinusDI {
+		aa.logger.Info(ctx, "Buy signal detected", zaps...)
+		return aa.signal(model.Buy, "+DI crossed above -DI")
+	}
+	if previous.PlusDI >= previous.MinusDI && current.PlusDI < current.MinusDI {
+		aa.logger.Info(ctx, "Sell signal detected", zaps...)
+		return aa.signal(model.Sell, "+DI crossed below -DI")
+	}
+
+	aa.logger.Debug(ctx, "No trading signal detected", zaps...)
+	return result
+}
+
+// signal builds a directional indicator cross signal, the stronger the trend the stronger the signal.
+func (aa *ADXAdapter) signal(action model.StockAction, event string) model.TradingSignal {
+	stopLoss, target := aa.swing.exits(action, aa.CurrentData.Close)
+	current := aa.ADX.Value()
+	return model.TradingSignal{
+		Time:     aa.CurrentData.Time,
+		Action:   action,
+		Strength: clampStrength(0.5 + (current.ADX-aa.TrendThreshold)/(100-aa.TrendThreshold)),
+		StopLoss: stopLoss,
+		Target:   target,
+		Rationale: []model.Rationale{{
+			Source:    aa.Name(),
+			Indicator: "ADX",
+			Event:     event + " with ADX above",
+			Reference: strconv.FormatFloat(aa.TrendThreshold, 'g', -1, 64),
+			Values:    []float64{current.PlusDI, current.MinusDI, current.ADX},
+		}},
+	}
+}
+
+// IndicatorValues returns the latest ADX and directional indicators.
+func (aa *ADXAdapter) IndicatorValues() map[string]float64 {
+	if !aa.ADX.Ready() {
+		return nil
+	}
+	values := aa.ADX.Value()
+	return map[string]float64{"adx": values.ADX, "plus_di": values.PlusDI, "minus_di": values.MinusDI}
+}
+
+// Function to retrieve and update the slice from context
+func (aa *ADXAdapter) getUpdateContext(ctx context.Context) context.Context {
+	ctx = getUpdatedCommonLabelsContext(ctx)
+	return context.WithValue(ctx, ADAPTOR_NAME_LABEL, aa.Name())
+}
+
+// adxAdapterState is the saved state of an ADXAdapter.
+type adxAdapterState struct {
+	Adaptor        string
+	ADX            *indicator.ADX
+	PreviousValues indicator.ADXValues
+	HasPrevious    bool
+	CurrentData    model.DataPoint
+	Swing          priceSwing
+}
+
+// MarshalState encodes the full state of the adapter.
+func (aa *ADXAdapter) MarshalState(format indicator.StateFormat) ([]byte, error) {
+	return indicator.EncodeState(format, adxAdapterState{
+		Adaptor:        aa.Name(),
+		ADX:            aa.ADX,
+		PreviousValues: aa.PreviousValues,
+		HasPrevious:    aa.HasPrevious,
+		CurrentData:    aa.CurrentData,
+		Swing:          aa.swing,
+	})
+}
+
+// UnmarshalState replaces the state of the adapter with one saved by an adapter with the same parameters.
+func (aa *ADXAdapter) UnmarshalState(format indicator.StateFormat, data []byte) error {
+	var state adxAdapterState
+	if err := indicator.DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	if err := checkSavedState(state.Adaptor, aa.Name(), state.ADX != nil); err != nil {
+		return err
+	}
+	aa.ADX, aa.PreviousValues, aa.HasPrevious = state.ADX, state.PreviousValues, state.HasPrevious
+	aa.CurrentData, aa.swing = state.CurrentData, state.Swing
+	return nil
+}
@@ -0,0 +1,43 @@
+package indicator_adaptor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+// diCrossData has +DI cross below -DI on the fourth bar and back above it on the sixth, with the ADX of 2 bars above 50.
+var diCrossData = []model.DataPoint{
+	{Time: 1, High: 10, Low: 8, Close: 9},
+	{Time: 2, High: 11, Low: 9, Close: 10},
+	{Time: 3, High: 12, Low: 10, Close: 11},
+	{Time: 4, High: 11, Low: 8, Close: 9},
+	{Time: 5, High: 10, Low: 7, Close: 8},
+	{Time: 6, High: 12, Low: 9, Close: 11},
+}
+
+func TestADXAdapterSignals(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold float64
+		want      []model.StockAction
+	}{
+		{"trending", 25, []model.StockAction{model.Wait, model.Wait, model.Wait, model.Sell, model.Wait, model.Buy}},
+		{"filtered by the threshold", 70, []model.StockAction{model.Wait, model.Wait, model.Wait, model.Wait, model.Wait, model.Wait}},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		adapter := indicator_adaptor.NewADXAdapter(ctx, 2, tt.threshold, test_utils.NewMockMetricsCollector(t))
+		for i, dp := range diCrossData {
+			if err := adapter.AddDataPoint(ctx, dp); err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			test_utils.AssertEqual(t, tt.want[i], adapter.GetSignal(ctx).Action, tt.name+": signal does not match")
+		}
+		test_utils.AssertTrue(t, adapter.Value() > 50, tt.name+": ADX should be exposed")
+	}
+}
@@ -0,0 +1,219 @@
+package indicator_adaptor
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/constraint"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator"
+	"github.com/vd09/trading-algorithm-backtesting-system/logger"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
+	"go.uber.org/zap"
+)
+
+const (
+	ATR_VALUE_LABEL = "atr_value_type"
+)
+
+// atrStopMultiple places the stop loss of a volatility expansion signal this many average true ranges from the close.
+const atrStopMultiple = 1.5
+
+type ATRMetrics struct {
+	SignalCounter monitor.CounterMetric
+	ValueGauge    monitor.GaugeMetric
+	monitor       monitor.Monitoring
+}
+
+// ATRAdapter signals on volatility expansion: a bar whose true range is at least Expansion times the
+// average true range before it, in the direction the bar closed.
+type ATRAdapter struct {
+	ATR       *indicator.ATR
+	Expansion float64
+	// PreviousATR is the average true range before the current bar.
+	PreviousATR  float64
+	PreviousData model.DataPoint
+	CurrentData  model.DataPoint
+	logger       logger.LoggerInterface
+	metrics      *ATRMetrics
+}
+
+func init() {
+	registry.RegisterAdaptor(registry.AdaptorEntry{
+		Name:        "atr",
+		Description: "Signals in the direction of bars whose true range expands well past the average true range",
+		Params: []registry.Param{
+			{Name: "period", Type: registry.IntParam, Default: 14, Min: 1, Max: 1000},
+			{Name: "smoothing", Type: registry.StringParam, Default: string(indicator.WilderSmoothing), Choices: []string{string(indicator.WilderSmoothing), string(indicator.SMASmoothing), string(indicator.EMASmoothing)}},
+			{Name: "expansion", Type: registry.FloatParam, Description: "Multiple of the average true range a bar's true range has to reach", Default: 2.0, Min: 1, Max: 100},
+		},
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (IndicatorAdaptor, error) {
+			return NewATRAdapter(ctx, p.Int("period"), indicator.ATRSmoothing(p.String("smoothing")), p.Float("expansion"), m), nil
+		},
+	})
+}
+
+func NewATRAdapter(ctx context.Context, period int, smoothing indicator.ATRSmoothing, expansion float64, monitor monitor.Monitoring) *ATRAdapter {
+	adapter := &ATRAdapter{
+		ATR:       indicator.NewATR(period, smoothing),
+		Expansion: expansion,
+		logger:    logger.GetLogger(),
+	}
+	adapter.registerMetrics(ctx, monitor)
+	return adapter
+}
+
+func (aa *ATRAdapter) registerMetrics(ctx context.Context, m monitor.Monitoring) {
+	ctx = aa.getUpdateContext(ctx)
+	aa.metrics = &ATRMetrics{
+		monitor:       m,
+		SignalCounter: m.RegisterCounter(ctx, "atr_signals_generated", "Total number of ATR volatility expansion signals generated", monitor.Labels{constraint.SIGNAL_TYPE_LABEL}),
+		ValueGauge:    m.RegisterGauge(ctx, "atr_values", "Current average true range and true range", monitor.Labels{ATR_VALUE_LABEL}),
+	}
+}
+
+func (aa *ATRAdapter) Clone(ctx context.Context) IndicatorAdaptor {
+	return NewATRAdapter(ctx, aa.ATR.Period, aa.ATR.Smoothing, aa.Expansion, aa.metrics.monitor)
+}
+
+func (aa *ATRAdapter) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	ctx = aa.getUpdateContext(ctx)
+	aa.logger.Debug(ctx, "Adding data point to ATRAdapter", zap.Int64("timestamp", data.Time))
+
+	previousATR, wasInitialized := aa.ATR.Value(), aa.ATR.Ready()
+	if err := aa.ATR.Update(ctx, data); err != nil {
+		aa.logger.Error(ctx, "Failed to add data point to ATR", zap.Error(err))
+		return err
+	}
+	aa.PreviousATR = 0
+	if wasInitialized {
+		aa.PreviousATR = previousATR
+	}
+	aa.PreviousData, aa.CurrentData = aa.CurrentData, data
+
+	if aa.ATR.Ready() {
+		aa.metrics.ValueGauge.SetGauge(ctx, aa.ATR.Value(), monitor.NewTagsKV(ATR_VALUE_LABEL, "atr"))
+		aa.metrics.ValueGauge.SetGauge(ctx, aa.ATR.TrueRange, monitor.NewTagsKV(ATR_VALUE_LABEL, "true_range"))
+	}
+	return nil
+}
+
+func (aa *ATRAdapter) Name() string {
+	return fmt.Sprintf("ATR_%d_%s_%.2f", aa.ATR.Period, aa.smoothing(), aa.Expansion)
+}
+
+func (aa *ATRAdapter) smoothing() indicator.ATRSmoothing {
+	if aa.ATR.Smoothing == "" {
+		return indicator.WilderSmoothing
+	}
+	return aa.ATR.Smoothing
+}
+
+func (aa *ATRAdapter) GetSignal(ctx context.Context) (result model.TradingSignal) {
+	ctx = aa.getUpdateContext(ctx)
+	defer func() {
+		tags := monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result.Action))
+		tags.Add(ADAPTOR_NAME_LABEL, aa.Name())
+		aa.metrics.SignalCounter.IncrementCounter(ctx, tags)
+	}()
+	result = model.TradingSignal{Time: aa.CurrentData.Time, Action: model.Wait}
+	zaps := []zap.Field{zap.String("adapter", aa.Name()), zap.Any("time", aa.CurrentData.Time)}
+
+	if aa.PreviousATR == 0 {
+		aa.logger.Debug(ctx, "ATR not initialized", zaps...)
+		return result
+	}
+	ratio := aa.ATR.TrueRange / aa.PreviousATR
+	if ratio < aa.Expansion {
+		aa.logger.Debug(ctx, "No volatility expansion", append(zaps, zap.Float64("ratio", ratio))...)
+		return result
+	}
+
+	switch {
+	case aa.CurrentData.Close > aa.PreviousData.Close:
+		aa.logger.Info(ctx, "Buy signal detected", zaps...)
+		return aa.signal(model.Buy, ratio)
+	case aa.CurrentData.Close < aa.PreviousData.Close:
+		aa.logger.Info(ctx, "Sell signal detected", zaps...)
+		return aa.signal(model.Sell, ratio)
+	}
+	aa.logger.Debug(ctx, "Volatility expansion without direction", zaps...)
+	return result
+}
+
+// signal builds a volatility expansion signal with the stop loss atrStopMultiple average true ranges away,
+// the more the range expanded past the threshold the stronger the signal.
+func (aa *ATRAdapter) signal(action model.StockAction, ratio float64) model.TradingSignal {
+	price := aa.CurrentData.Close
+	stopLoss := price - atrStopMultiple*aa.PreviousATR
+	if action == model.Sell {
+		stopLoss = price + atrStopMultiple*aa.PreviousATR
+	}
+	return model.TradingSignal{
+		Time:     aa.CurrentData.Time,
+		Action:   action,
+		Strength: clampStrength(0.5 + (ratio-aa.Expansion)/aa.Expansion),
+		StopLoss: stopLoss,
+		Target:   rewardTarget(price, stopLoss),
+		Rationale: []model.Rationale{{
+			Source:    aa.Name(),
+			Indicator: "ATR",
+			Event:     "true range expanded past",
+			Reference: strconv.FormatFloat(aa.Expansion, 'g', -1, 64) + "x ATR",
+			Values:    []float64{aa.PreviousATR, aa.ATR.TrueRange},
+		}},
+	}
+}
+
+// IndicatorValues returns the latest average true range, also as a percentage of the close, and true range.
+func (aa *ATRAdapter) IndicatorValues() map[string]float64 {
+	if !aa.ATR.Ready() {
+		return nil
+	}
+	return map[string]float64{
+		"atr":         aa.ATR.Value(),
+		"atr_percent": aa.ATR.Percent(),
+		"true_range":  aa.ATR.TrueRange,
+	}
+}
+
+// Function to retrieve and update the slice from context
+func (aa *ATRAdapter) getUpdateContext(ctx context.Context) context.Context {
+	ctx = getUpdatedCommonLabelsContext(ctx)
+	return context.WithValue(ctx, ADAPTOR_NAME_LABEL, aa.Name())
+}
+
+// atrAdapterState is the saved state of an ATRAdapter.
+type atrAdapterState struct {
+	Adaptor      string
+	ATR          *indicator.ATR
+	PreviousATR  float64
+	PreviousData model.DataPoint
+	CurrentData  model.DataPoint
+}
+
+// MarshalState encodes the full state of the adapter.
+func (aa *ATRAdapter) MarshalState(format indicator.StateFormat) ([]byte, error) {
+	return indicator.EncodeState(format, atrAdapterState{
+		Adaptor:      aa.Name(),
+		ATR:          aa.ATR,
+		PreviousATR:  aa.PreviousATR,
+		PreviousData: aa.PreviousData,
+		CurrentData:  aa.CurrentData,
+	})
+}
+
+// UnmarshalState replaces the state of the adapter with one saved by an adapter with the same parameters.
+func (aa *ATRAdapter) UnmarshalState(format indicator.StateFormat, data []byte) error {
+	var state atrAdapterState
+	if err := indicator.DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	if err := checkSavedState(state.Adaptor, aa.Name(), state.ATR != nil); err != nil {
+		return err
+	}
+	aa.ATR, aa.PreviousATR, aa.PreviousData, aa.CurrentData = state.ATR, state.PreviousATR, state.PreviousData, state.CurrentData
+	return nil
+}
@@ -0,0 +1,38 @@
+package indicator_adaptor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+func TestATRAdapterVolatilityExpansion(t *testing.T) {
+	ctx := context.Background()
+	mock := test_utils.NewMockMetricsCollector(t)
+	adapter := indicator_adaptor.NewATRAdapter(ctx, 5, indicator.WilderSmoothing, 2, mock)
+	test_utils.AssertEqual(t, "ATR_5_wilder_2.00", adapter.Name(), "Name does not match")
+
+	// Quiet bars with a range of 2, then a wide bar closing higher and one closing lower.
+	for i := 1; i <= 10; i++ {
+		adapter.AddDataPoint(ctx, model.DataPoint{Time: int64(i), Open: 100, High: 101, Low: 99, Close: 100})
+		test_utils.AssertEqual(t, model.StockAction(model.Wait), adapter.GetSignal(ctx).Action, "Quiet bars should not signal")
+	}
+
+	adapter.AddDataPoint(ctx, model.DataPoint{Time: 11, Open: 100, High: 106, Low: 100, Close: 105})
+	buy := adapter.GetSignal(ctx)
+	test_utils.AssertEqual(t, model.StockAction(model.Buy), buy.Action, "Wide bar closing higher should buy")
+	test_utils.AssertEqual(t, 105-1.5*2.0, buy.StopLoss, "Stop loss should be 1.5 ATR under the close")
+	test_utils.AssertEqual(t, 105+2*1.5*2.0, buy.Target, "Target should be twice the risk above the close")
+	test_utils.AssertEqual(t, 1.0, buy.Strength, "True range of three times the ATR should give full strength")
+
+	adapter.AddDataPoint(ctx, model.DataPoint{Time: 12, Open: 105, High: 105, Low: 96, Close: 97})
+	test_utils.AssertEqual(t, model.StockAction(model.Sell), adapter.GetSignal(ctx).Action, "Wide bar closing lower should sell")
+
+	adapter.AddDataPoint(ctx, model.DataPoint{Time: 13, Open: 97, High: 98, Low: 96, Close: 97})
+	test_utils.AssertEqual(t, model.StockAction(model.Wait), adapter.GetSignal(ctx).Action, "Narrow bar should not signal")
+	test_utils.AssertTrue(t, adapter.IndicatorValues()["atr_percent"] > 0, "ATR percent should be reported")
+}
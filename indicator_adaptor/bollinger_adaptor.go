@@ -0,0 +1,334 @@
+package indicator_adaptor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/constraint"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator"
+	"github.com/vd09/trading-algorithm-backtesting-system/logger"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
+	"go.uber.org/zap"
+)
+
+const (
+	BOLLINGER_BAND_LABEL = "bollinger_band_type"
+)
+
+// BollingerMode selects which price action around the bands the BollingerAdapter signals on.
+type BollingerMode string
+
+const (
+	// BollingerMeanReversion buys when the close comes back inside the bands from below the lower band,
+	// and sells when it comes back from above the upper band.
+	BollingerMeanReversion BollingerMode = "mean_reversion"
+	// BollingerBreakout buys when the close breaks above the upper band and sells when it breaks below the lower band.
+	BollingerBreakout BollingerMode = "breakout"
+	// BollingerSqueeze signals a band break only shortly after a squeeze, when the band width was
+	// among the narrowest of the lookback.
+	BollingerSqueeze BollingerMode = "squeeze"
+)
+
+// squeezeMemory is the number of bars a squeeze keeps arming a breakout after the bands start widening.
+const squeezeMemory = 5
+
+type BollingerMetrics struct {
+	SignalCounter monitor.CounterMetric
+	BandGauge     monitor.GaugeMetric
+	monitor       monitor.Monitoring
+}
+
+type BollingerAdapter struct {
+	Bollinger *indicator.BollingerBands
+	Mode      BollingerMode
+	// SqueezeLookback is the number of band widths the current width is ranked against.
+	SqueezeLookback int
+	// SqueezePercentile is the percentile of the lookback widths at or under which the bands are in a squeeze.
+	SqueezePercentile float64
+	Widths            []float64
+	// SinceSqueeze counts the bars since the bands were last in a squeeze, -1 before the first one.
+	SinceSqueeze  int
+	PreviousBands indicator.BollingerBandsValues
+	PreviousData  model.DataPoint
+	CurrentData   model.DataPoint
+	swing         priceSwing
+	logger        logger.LoggerInterface
+	metrics       *BollingerMetrics
+}
+
+func init() {
+	registry.RegisterAdaptor(registry.AdaptorEntry{
+		Name:        "bollinger",
+		Description: "Signals on Bollinger Bands re-entries, band breakouts or breakouts after a squeeze",
+		Params: []registry.Param{
+			{Name: "period", Type: registry.IntParam, Default: 20, Min: 2, Max: 1000},
+			{Name: "multiplier", Type: registry.FloatParam, Description: "Standard deviations between the average and the bands", Default: 2.0, Min: 0.1, Max: 10},
+			{Name: "mode", Type: registry.StringParam, Default: string(BollingerMeanReversion), Choices: []string{string(BollingerMeanReversion), string(BollingerBreakout), string(BollingerSqueeze)}},
+			{Name: "squeeze_lookback", Type: registry.IntParam, Description: "Band widths a squeeze is ranked against", Default: 120, Min: 2, Max: 100000},
+			{Name: "squeeze_percentile", Type: registry.FloatParam, Description: "Width percentile at or under which the bands are in a squeeze", Default: 20.0, Min: 1, Max: 99},
+		},
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (IndicatorAdaptor, error) {
+			return NewBollingerAdapter(ctx, p.Int("period"), p.Float("multiplier"), BollingerMode(p.String("mode")), p.Int("squeeze_lookback"), p.Float("squeeze_percentile"), m)
+		},
+	})
+}
+
+// NewBollingerAdapter creates a Bollinger Bands adapter signalling in the given mode.
+// The squeeze settings are only used by BollingerSqueeze.
+func NewBollingerAdapter(ctx context.Context, period int, multiplier float64, mode BollingerMode, squeezeLookback int, squeezePercentile float64, monitor monitor.Monitoring) (*BollingerAdapter, error) {
+	switch mode {
+	case BollingerMeanReversion, BollingerBreakout, BollingerSqueeze:
+	default:
+		return nil, fmt.Errorf("unknown bollinger mode %q", mode)
+	}
+	if period < 2 || multiplier <= 0 {
+		return nil, fmt.Errorf("bollinger bands need a period of at least 2 and a positive multiplier, got %d and %v", period, multiplier)
+	}
+	if mode == BollingerSqueeze && (squeezeLookback < 2 || squeezePercentile <= 0 || squeezePercentile >= 100) {
+		return nil, fmt.Errorf("squeeze needs a lookback of at least 2 and a percentile between 0 and 100, got %d and %v", squeezeLookback, squeezePercentile)
+	}
+	adapter := &BollingerAdapter{
+		Bollinger:         indicator.NewBollingerBandsWithMultiplier(period, multiplier),
+		Mode:              mode,
+		SqueezeLookback:   squeezeLookback,
+		SqueezePercentile: squeezePercentile,
+		SinceSqueeze:      -1,
+		logger:            logger.GetLogger(),
+	}
+	adapter.registerMetrics(ctx, monitor)
+	return adapter, nil
+}
+
+func (ba *BollingerAdapter) registerMetrics(ctx context.Context, m monitor.Monitoring) {
+	ctx = ba.getUpdateContext(ctx)
+	ba.metrics = &BollingerMetrics{
+		monitor:       m,
+		SignalCounter: m.RegisterCounter(ctx, "bollinger_signals_generated", "Total number of Bollinger Bands signals generated", monitor.Labels{constraint.SIGNAL_TYPE_LABEL}),
+		BandGauge:     m.RegisterGauge(ctx, "bollinger_bands", "Current levels and width of the Bollinger Bands", monitor.Labels{BOLLINGER_BAND_LABEL}),
+	}
+}
+
+func (ba *BollingerAdapter) Clone(ctx context.Context) IndicatorAdaptor {
+	adapter, _ := NewBollingerAdapter(ctx, ba.Bollinger.Period, ba.Bollinger.Multiplier, ba.Mode, ba.SqueezeLookback, ba.SqueezePercentile, ba.metrics.monitor)
+	return adapter
+}
+
+func (ba *BollingerAdapter) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	ctx = ba.getUpdateContext(ctx)
+	ba.logger.Debug(ctx, "Adding data point to BollingerAdapter", zap.Int64("timestamp", data.Time))
+
+	previousBands, wasInitialized := ba.Bollinger.Value(), ba.Bollinger.Ready()
+	if err := ba.Bollinger.Update(ctx, data); err != nil {
+		ba.logger.Error(ctx, "Failed to add data point to Bollinger Bands", zap.Error(err))
+		return err
+	}
+	if wasInitialized {
+		ba.PreviousBands, ba.PreviousData = previousBands, ba.CurrentData
+	}
+	ba.CurrentData = data
+	ba.swing.add(data)
+	if !ba.Bollinger.Ready() {
+		return nil
+	}
+
+	width := ba.Bollinger.Width()
+	if ba.Mode == BollingerSqueeze {
+		ba.trackSqueeze(width)
+	}
+	bands := ba.Bollinger.Value()
+	ba.metrics.BandGauge.SetGauge(ctx, bands.UpperBand, monitor.NewTagsKV(BOLLINGER_BAND_LABEL, "upper"))
+	ba.metrics.BandGauge.SetGauge(ctx, bands.MovingAverage, monitor.NewTagsKV(BOLLINGER_BAND_LABEL, "middle"))
+	ba.metrics.BandGauge.SetGauge(ctx, bands.LowerBand, monitor.NewTagsKV(BOLLINGER_BAND_LABEL, "lower"))
+	ba.metrics.BandGauge.SetGauge(ctx, width, monitor.NewTagsKV(BOLLINGER_BAND_LABEL, "width"))
+	return nil
+}
+
+// trackSqueeze ranks the width against the lookback and counts the bars since the last squeeze.
+func (ba *BollingerAdapter) trackSqueeze(width float64) {
+	ba.Widths = append(ba.Widths, width)
+	if len(ba.Widths) > ba.SqueezeLookback {
+		ba.Widths = ba.Widths[1:]
+	}
+	if len(ba.Widths) == ba.SqueezeLookback && width <= ba.squeezeWidth() {
+		ba.SinceSqueeze = 0
+	} else if ba.SinceSqueeze >= 0 {
+		ba.SinceSqueeze++
+	}
+}
+
+// squeezeWidth returns the widest band width still counted as a squeeze.
+func (ba *BollingerAdapter) squeezeWidth() float64 {
+	sorted := append([]float64(nil), ba.Widths...)
+	sort.Float64s(sorted)
+	index := int(ba.SqueezePercentile / 100 * float64(len(sorted)-1))
+	return sorted[index]
+}
+
+func (ba *BollingerAdapter) Name() string {
+	name := fmt.Sprintf("Bollinger_%d_%.2f_%s", ba.Bollinger.Period, ba.Bollinger.Multiplier, ba.Mode)
+	if ba.Mode == BollingerSqueeze {
+		name += fmt.Sprintf("_%d_%.0f", ba.SqueezeLookback, ba.SqueezePercentile)
+	}
+	return name
+}
+
+func (ba *BollingerAdapter) GetSignal(ctx context.Context) (result model.TradingSignal) {
+	ctx = ba.getUpdateContext(ctx)
+	defer func() {
+		tags := monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result.Action))
+		tags.Add(ADAPTOR_NAME_LABEL, ba.Name())
+		ba.metrics.SignalCounter.IncrementCounter(ctx, tags)
+	}()
+	result = model.TradingSignal{Time: ba.CurrentData.Time, Action: model.Wait}
+	zaps := []zap.Field{zap.String("adapter", ba.Name()), zap.Any("time", ba.CurrentData.Time)}
+
+	if ba.PreviousData.Time == 0 || !ba.Bollinger.Ready() {
+		ba.logger.Debug(ctx, "Bollinger Bands not initialized", zaps...)
+		return result
+	}
+
+	previous, current := ba.PreviousData.Close, ba.CurrentData.Close
+	bands := ba.Bollinger.Value()
+	switch ba.Mode {
+	case BollingerMeanReversion:
+		if previous < ba.PreviousBands.LowerBand && current >= bands.LowerBand {
+			ba.logger.Info(ctx, "Buy signal detected", zaps...)
+			return ba.reversionSignal(model.Buy, "closed back above", "lower band")
+		}
+		if previous > ba.PreviousBands.UpperBand && current <= bands.UpperBand {
+			ba.logger.Info(ctx, "Sell signal detected", zaps...)
+			return ba.reversionSignal(model.Sell, "closed back below", "upper band")
+		}
+	case BollingerBreakout, BollingerSqueeze:
+		if ba.Mode == BollingerSqueeze && (ba.SinceSqueeze < 0 || ba.SinceSqueeze > squeezeMemory) {
+			ba.logger.Debug(ctx, "No recent squeeze", zaps...)
+			return result
+		}
+		if previous <= ba.PreviousBands.UpperBand && current > bands.UpperBand {
+			ba.logger.Info(ctx, "Buy signal detected", zaps...)
+			return ba.breakoutSignal(model.Buy, "closed above", "upper band", current-bands.UpperBand)
+		}
+		if previous >= ba.PreviousBands.LowerBand && current < bands.LowerBand {
+			ba.logger.Info(ctx, "Sell signal detected", zaps...)
+			return ba.breakoutSignal(model.Sell, "closed below", "lower band", bands.LowerBand-current)
+		}
+	}
+
+	ba.logger.Debug(ctx, "No trading signal detected", zaps...)
+	return result
+}
+
+// reversionSignal builds a band re-entry signal targeting the moving average,
+// the further the price is from the average the stronger the signal.
+func (ba *BollingerAdapter) reversionSignal(action model.StockAction, event, reference string) model.TradingSignal {
+	bands := ba.Bollinger.Value()
+	stopLoss, _ := ba.swing.exits(action, ba.CurrentData.Close)
+	return model.TradingSignal{
+		Time:     ba.CurrentData.Time,
+		Action:   action,
+		Strength: relativeStrength(bands.MovingAverage-ba.CurrentData.Close, ba.CurrentData.Close),
+		StopLoss: stopLoss,
+		Target:   bands.MovingAverage,
+		Rationale: []model.Rationale{{
+			Source:    ba.Name(),
+			Indicator: "Bollinger Bands",
+			Event:     event,
+			Reference: reference,
+			Values:    []float64{ba.PreviousData.Close, ba.CurrentData.Close},
+		}},
+	}
+}
+
+// breakoutSignal builds a band break signal with the stop loss at the moving average,
+// the further the close went past the band the stronger the signal.
+func (ba *BollingerAdapter) breakoutSignal(action model.StockAction, event, reference string, gap float64) model.TradingSignal {
+	bands := ba.Bollinger.Value()
+	signal := model.TradingSignal{
+		Time:     ba.CurrentData.Time,
+		Action:   action,
+		Strength: relativeStrength(gap, ba.CurrentData.Close),
+		StopLoss: bands.MovingAverage,
+		Target:   rewardTarget(ba.CurrentData.Close, bands.MovingAverage),
+		Rationale: []model.Rationale{{
+			Source:    ba.Name(),
+			Indicator: "Bollinger Bands",
+			Event:     event,
+			Reference: reference,
+			Values:    []float64{ba.PreviousData.Close, ba.CurrentData.Close},
+		}},
+	}
+	if ba.Mode == BollingerSqueeze {
+		signal.Rationale = append(signal.Rationale, model.Rationale{
+			Source:    ba.Name(),
+			Indicator: "Bollinger Bands",
+			Event:     fmt.Sprintf("broke out %d bars after a squeeze", ba.SinceSqueeze),
+			Reference: "band width",
+			Values:    []float64{ba.squeezeWidth(), ba.Bollinger.Width()},
+		})
+	}
+	return signal
+}
+
+// IndicatorValues returns the latest bands, their width and where the close lies within them.
+func (ba *BollingerAdapter) IndicatorValues() map[string]float64 {
+	if !ba.Bollinger.Ready() {
+		return nil
+	}
+	bands := ba.Bollinger.Value()
+	return map[string]float64{
+		"upper":     bands.UpperBand,
+		"middle":    bands.MovingAverage,
+		"lower":     bands.LowerBand,
+		"width":     ba.Bollinger.Width(),
+		"percent_b": ba.Bollinger.PercentB(ba.CurrentData.Close),
+	}
+}
+
+// Function to retrieve and update the slice from context
+func (ba *BollingerAdapter) getUpdateContext(ctx context.Context) context.Context {
+	ctx = getUpdatedCommonLabelsContext(ctx)
+	return context.WithValue(ctx, ADAPTOR_NAME_LABEL, ba.Name())
+}
+
+// bollingerAdapterState is the saved state of a BollingerAdapter.
+type bollingerAdapterState struct {
+	Adaptor       string
+	Bollinger     *indicator.BollingerBands
+	Widths        []float64
+	SinceSqueeze  int
+	PreviousBands indicator.BollingerBandsValues
+	PreviousData  model.DataPoint
+	CurrentData   model.DataPoint
+	Swing         priceSwing
+}
+
+// MarshalState encodes the full state of the adapter.
+func (ba *BollingerAdapter) MarshalState(format indicator.StateFormat) ([]byte, error) {
+	return indicator.EncodeState(format, bollingerAdapterState{
+		Adaptor:       ba.Name(),
+		Bollinger:     ba.Bollinger,
+		Widths:        ba.Widths,
+		SinceSqueeze:  ba.SinceSqueeze,
+		PreviousBands: ba.PreviousBands,
+		PreviousData:  ba.PreviousData,
+		CurrentData:   ba.CurrentData,
+		Swing:         ba.swing,
+	})
+}
+
+// UnmarshalState replaces the state of the adapter with one saved by an adapter with the same parameters.
+func (ba *BollingerAdapter) UnmarshalState(format indicator.StateFormat, data []byte) error {
+	var state bollingerAdapterState
+	if err := indicator.DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	if err := checkSavedState(state.Adaptor, ba.Name(), state.Bollinger != nil); err != nil {
+		return err
+	}
+	ba.Bollinger, ba.Widths, ba.SinceSqueeze = state.Bollinger, state.Widths, state.SinceSqueeze
+	ba.PreviousBands, ba.PreviousData, ba.CurrentData, ba.swing = state.PreviousBands, state.PreviousData, state.CurrentData, state.Swing
+	return nil
+}
@@ -0,0 +1,112 @@
+package indicator_adaptor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+// bollingerSignals feeds the closes to the adapter and returns the signal of every bar.
+func bollingerSignals(t *testing.T, adapter *indicator_adaptor.BollingerAdapter, closes []float64) []model.TradingSignal {
+	ctx := context.Background()
+	signals := make([]model.TradingSignal, len(closes))
+	for i, price := range closes {
+		dp := model.DataPoint{Time: int64(i + 1), Open: price, High: price + 0.5, Low: price - 0.5, Close: price, Volume: 1000}
+		if err := adapter.AddDataPoint(ctx, dp); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		signals[i] = adapter.GetSignal(ctx)
+	}
+	return signals
+}
+
+// rangeBound alternates around 100 so the bands settle about one point either side.
+func rangeBound(n int) []float64 {
+	closes := make([]float64, n)
+	for i := range closes {
+		closes[i] = 100 + float64(i%2*2-1)
+	}
+	return closes
+}
+
+func TestNewBollingerAdapter(t *testing.T) {
+	ctx := context.Background()
+	mock := test_utils.NewMockMetricsCollector(t)
+
+	adapter, err := indicator_adaptor.NewBollingerAdapter(ctx, 20, 2.5, indicator_adaptor.BollingerBreakout, 0, 0, mock)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	test_utils.AssertEqual(t, 2.5, adapter.Bollinger.Multiplier, "Multiplier does not match")
+	test_utils.AssertEqual(t, "Bollinger_20_2.50_breakout", adapter.Name(), "Name does not match")
+
+	_, err = indicator_adaptor.NewBollingerAdapter(ctx, 20, 2, "trend", 0, 0, mock)
+	test_utils.AssertTrue(t, err != nil, "Unknown mode should be rejected")
+	_, err = indicator_adaptor.NewBollingerAdapter(ctx, 20, 2, indicator_adaptor.BollingerSqueeze, 1, 20, mock)
+	test_utils.AssertTrue(t, err != nil, "Squeeze lookback shorter than two should be rejected")
+}
+
+func TestBollingerAdapterMeanReversion(t *testing.T) {
+	mock := test_utils.NewMockMetricsCollector(t)
+	adapter, _ := indicator_adaptor.NewBollingerAdapter(context.Background(), 20, 2, indicator_adaptor.BollingerMeanReversion, 0, 0, mock)
+
+	closes := append(rangeBound(30), 95, 99)
+	signals := bollingerSignals(t, adapter, closes)
+
+	test_utils.AssertEqual(t, model.StockAction(model.Wait), signals[30].Action, "Closing below the lower band should wait for the re-entry")
+	buy := signals[31]
+	test_utils.AssertEqual(t, model.StockAction(model.Buy), buy.Action, "Closing back inside from below the lower band should buy")
+	test_utils.AssertEqual(t, adapter.Bollinger.Value().MovingAverage, buy.Target, "Target should be the moving average")
+	test_utils.AssertTrue(t, buy.StopLoss < 99, "Stop loss should be under the price")
+	test_utils.AssertEqual(t, "lower band", buy.Rationale[0].Reference, "Rationale reference does not match")
+}
+
+func TestBollingerAdapterBreakout(t *testing.T) {
+	mock := test_utils.NewMockMetricsCollector(t)
+	adapter, _ := indicator_adaptor.NewBollingerAdapter(context.Background(), 20, 2, indicator_adaptor.BollingerBreakout, 0, 0, mock)
+
+	closes := append(rangeBound(30), 104)
+	signals := bollingerSignals(t, adapter, closes)
+	for _, signal := range signals[:30] {
+		test_utils.AssertEqual(t, model.StockAction(model.Wait), signal.Action, "Range bound bars should not signal")
+	}
+	buy := signals[30]
+	test_utils.AssertEqual(t, model.StockAction(model.Buy), buy.Action, "Closing above the upper band should buy")
+	bands := adapter.Bollinger.Value()
+	test_utils.AssertEqual(t, bands.MovingAverage, buy.StopLoss, "Stop loss should be the moving average")
+	test_utils.AssertTrue(t, buy.Target > 104, "Target should be above the price")
+
+	values := adapter.IndicatorValues()
+	test_utils.AssertTrue(t, values["percent_b"] > 1, "Close above the upper band should have a percent b over one")
+}
+
+func TestBollingerAdapterSqueeze(t *testing.T) {
+	ctx := context.Background()
+	mock := test_utils.NewMockMetricsCollector(t)
+	breakout, _ := indicator_adaptor.NewBollingerAdapter(ctx, 10, 2, indicator_adaptor.BollingerSqueeze, 30, 20, mock)
+
+	// Wide swings, then a narrowing range, then a break out of it.
+	var closes []float64
+	for i := 0; i < 40; i++ {
+		closes = append(closes, 100+float64(i%2*10-5))
+	}
+	for i := 0; i < 15; i++ {
+		closes = append(closes, 100+float64(i%2*2-1)*0.2)
+	}
+	closes = append(closes, 101)
+	signals := bollingerSignals(t, breakout, closes)
+
+	buy := signals[len(signals)-1]
+	test_utils.AssertEqual(t, model.StockAction(model.Buy), buy.Action, "Breaking out of a squeeze should buy")
+	test_utils.AssertEqual(t, 2, len(buy.Rationale), "Squeeze breakout should explain the squeeze")
+
+	// The same break out of the wide range is no squeeze breakout.
+	wide, _ := indicator_adaptor.NewBollingerAdapter(ctx, 10, 2, indicator_adaptor.BollingerSqueeze, 30, 20, mock)
+	closes = closes[:40]
+	closes = append(closes, 112)
+	signals = bollingerSignals(t, wide, closes)
+	test_utils.AssertEqual(t, model.StockAction(model.Wait), signals[len(signals)-1].Action, "Breakout without a squeeze should wait")
+}
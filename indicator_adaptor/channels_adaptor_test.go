@@ -0,0 +1,126 @@
+package indicator_adaptor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+// flatThenRallyData holds at 100 for ten bars, then rises by 2 a bar.
+func flatThenRallyData(bars int) []model.DataPoint {
+	var data []model.DataPoint
+	for i := 1; i <= bars; i++ {
+		price := 100.0
+		if i > 10 {
+			price += float64(i-10) * 2
+		}
+		data = append(data, model.DataPoint{Time: int64(i), Open: price, High: price + 1, Low: price - 1, Close: price})
+	}
+	return data
+}
+
+// mirror reflects the bars around 100.
+func mirror(data []model.DataPoint) []model.DataPoint {
+	mirrored := append([]model.DataPoint(nil), data...)
+	for i := range mirrored {
+		mirrored[i].Open, mirrored[i].High, mirrored[i].Low, mirrored[i].Close = 200-data[i].Open, 200-data[i].Low, 200-data[i].High, 200-data[i].Close
+	}
+	return mirrored
+}
+
+// signalBars feeds the adapter and returns the signals by bar time, leaving out the waits.
+func signalBars(t *testing.T, adapter indicator_adaptor.IndicatorAdaptor, data []model.DataPoint) map[int64]model.TradingSignal {
+	ctx := context.Background()
+	signals := map[int64]model.TradingSignal{}
+	for _, dp := range data {
+		if err := adapter.AddDataPoint(ctx, dp); err != nil {
+			t.Fatalf("%s: unexpected error: %v", adapter.Name(), err)
+		}
+		if signal := adapter.GetSignal(ctx); signal.Action != model.Wait {
+			signals[dp.Time] = signal
+		}
+	}
+	return signals
+}
+
+func TestParabolicSARAdapterFlip(t *testing.T) {
+	// Falls by 1 a bar from 119 to 110, then rises by 2, the SAR flips under the price on bar 12.
+	var data []model.DataPoint
+	for i := 1; i <= 16; i++ {
+		price := 120 - float64(i)
+		if i > 10 {
+			price = 110 + float64(i-10)*2
+		}
+		data = append(data, model.DataPoint{Time: int64(i), High: price + 1, Low: price - 1, Close: price})
+	}
+
+	ctx := context.Background()
+	signals := signalBars(t, indicator_adaptor.NewParabolicSARAdapter(ctx, 0.02, 0.2, test_utils.NewMockMetricsCollector(t)), data)
+	test_utils.AssertEqual(t, 1, len(signals), "SAR should flip once")
+	test_utils.AssertEqual(t, model.StockAction(model.Buy), signals[12].Action, "SAR flip under the price should buy")
+	test_utils.AssertEqual(t, 109.0, signals[12].StopLoss, "stop loss should be at the lowest low of the downtrend")
+
+	signals = signalBars(t, indicator_adaptor.NewParabolicSARAdapter(ctx, 0.02, 0.2, test_utils.NewMockMetricsCollector(t)), mirror(data))
+	test_utils.AssertEqual(t, model.StockAction(model.Sell), signals[12].Action, "SAR flip above the price should sell")
+}
+
+func TestKeltnerAdapterSignals(t *testing.T) {
+	tests := []struct {
+		name string
+		mode indicator_adaptor.KeltnerMode
+		bar  int64
+	}{
+		// The channels are 1.5 true ranges of 2 away, the third bar of the rally closes 6 above the flat line.
+		{"breakout", indicator_adaptor.KeltnerBreakout, 13},
+		// The flat closes squeeze the Bollinger Bands inside the channels until the rally widens them.
+		{"squeeze", indicator_adaptor.KeltnerSqueeze, 13},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		for _, direction := range []struct {
+			data []model.DataPoint
+			want model.StockAction
+		}{{flatThenRallyData(15), model.Buy}, {mirror(flatThenRallyData(15)), model.Sell}} {
+			adapter, err := indicator_adaptor.NewKeltnerAdapter(ctx, 10, 10, 1.5, tt.mode, 2, test_utils.NewMockMetricsCollector(t))
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			signals := signalBars(t, adapter, direction.data)
+			test_utils.AssertEqual(t, 1, len(signals), tt.name+": should signal once")
+			test_utils.AssertEqual(t, direction.want, signals[tt.bar].Action, tt.name+": signal does not match")
+		}
+	}
+
+	_, err := indicator_adaptor.NewKeltnerAdapter(ctx, 10, 10, 1.5, "reversal", 2, test_utils.NewMockMetricsCollector(t))
+	test_utils.AssertTrue(t, err != nil, "unknown keltner mode should be rejected")
+}
+
+func TestDonchianAdapterTurtle(t *testing.T) {
+	data := []model.DataPoint{
+		{Time: 1, High: 101, Low: 99, Close: 100},
+		{Time: 2, High: 101, Low: 99, Close: 100},
+		{Time: 3, High: 101, Low: 99, Close: 100},
+		{Time: 4, High: 101, Low: 99, Close: 100},
+		{Time: 5, High: 101, Low: 99, Close: 100},
+		// Breaks above the 4 bar high of 101.
+		{Time: 6, High: 103.5, Low: 100, Close: 103},
+		// Another new high while already long.
+		{Time: 7, High: 104.5, Low: 102, Close: 104},
+		{Time: 8, High: 104, Low: 101, Close: 101.5},
+		// Breaks below the 2 bar low of 101, ending the long, and below the 4 bar low of 99.
+		{Time: 9, High: 101.5, Low: 98, Close: 98.5},
+	}
+
+	adapter := indicator_adaptor.NewDonchianAdapter(context.Background(), 4, 2, test_utils.NewMockMetricsCollector(t))
+	signals := signalBars(t, adapter, data)
+	test_utils.AssertEqual(t, 2, len(signals), "should only signal on entries")
+	test_utils.AssertEqual(t, model.StockAction(model.Buy), signals[6].Action, "breakout above the entry channel should buy")
+	test_utils.AssertEqual(t, 99.0, signals[6].StopLoss, "stop loss should be at the exit channel")
+	test_utils.AssertEqual(t, model.StockAction(model.Sell), signals[9].Action, "breakdown below the entry channel should sell")
+	test_utils.AssertEqual(t, 104.5, signals[9].StopLoss, "stop loss should be at the exit channel")
+	test_utils.AssertEqual(t, model.StockAction(model.Sell), adapter.Position, "should be short after the breakdown")
+}
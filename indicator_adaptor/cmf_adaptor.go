@@ -0,0 +1,195 @@
+package indicator_adaptor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/constraint"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator"
+	"github.com/vd09/trading-algorithm-backtesting-system/logger"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
+	"go.uber.org/zap"
+)
+
+type CMFMetrics struct {
+	SignalCounter monitor.CounterMetric
+	ValueGauge    monitor.GaugeMetric
+	monitor       monitor.Monitoring
+}
+
+// CMFAdapter buys when the Chaikin Money Flow rises above Threshold, money flowing in, and sells when it
+// falls below -Threshold.
+type CMFAdapter struct {
+	ChaikinMoneyFlow *indicator.ChaikinMoneyFlow
+	Threshold        float64
+	PreviousValue    float64
+	// HasPrevious is set when PreviousValue holds the money flow of the bar before the current one.
+	HasPrevious bool
+	CurrentData model.DataPoint
+	swing       priceSwing
+	logger      logger.LoggerInterface
+	metrics     *CMFMetrics
+}
+
+func init() {
+	registry.RegisterAdaptor(registry.AdaptorEntry{
+		Name:        "cmf",
+		Description: "Buys when the Chaikin Money Flow rises above the threshold and sells when it falls below its negative",
+		Params: []registry.Param{
+			{Name: "period", Type: registry.IntParam, Default: 20, Min: 1, Max: 1000},
+			{Name: "threshold", Type: registry.FloatParam, Description: "Money flow, from 0 to 1, that has to be crossed either way", Default: 0.05, Min: 0, Max: 0.99},
+		},
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (IndicatorAdaptor, error) {
+			return NewCMFAdapter(ctx, p.Int("period"), p.Float("threshold"), m), nil
+		},
+	})
+}
+
+func NewCMFAdapter(ctx context.Context, period int, threshold float64, monitor monitor.Monitoring) *CMFAdapter {
+	adapter := &CMFAdapter{
+		ChaikinMoneyFlow: indicator.NewChaikinMoneyFlow(period),
+		Threshold:        threshold,
+		logger:           logger.GetLogger(),
+	}
+	adapter.registerMetrics(ctx, monitor)
+	return adapter
+}
+
+func (ca *CMFAdapter) registerMetrics(ctx context.Context, m monitor.Monitoring) {
+	ctx = ca.getUpdateContext(ctx)
+	ca.metrics = &CMFMetrics{
+		monitor:       m,
+		SignalCounter: m.RegisterCounter(ctx, "cmf_signals_generated", "Total number of Chaikin Money Flow signals generated", monitor.Labels{constraint.SIGNAL_TYPE_LABEL}),
+		ValueGauge:    m.RegisterGauge(ctx, "cmf_value", "Current value of the Chaikin Money Flow", nil),
+	}
+}
+
+func (ca *CMFAdapter) Clone(ctx context.Context) IndicatorAdaptor {
+	return NewCMFAdapter(ctx, ca.ChaikinMoneyFlow.Period, ca.Threshold, ca.metrics.monitor)
+}
+
+func (ca *CMFAdapter) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	ctx = ca.getUpdateContext(ctx)
+	ca.logger.Debug(ctx, "Adding data point to CMFAdapter", zap.Int64("timestamp", data.Time))
+
+	previous, wasInitialized := ca.ChaikinMoneyFlow.Value(), ca.ChaikinMoneyFlow.Ready()
+	if err := ca.ChaikinMoneyFlow.Update(ctx, data); err != nil {
+		ca.logger.Error(ctx, "Failed to add data point to Chaikin Money Flow", zap.Error(err))
+		return err
+	}
+	ca.CurrentData = data
+	ca.swing.add(data)
+	ca.PreviousValue, ca.HasPrevious = previous, wasInitialized
+
+	if ca.ChaikinMoneyFlow.Ready() {
+		ca.metrics.ValueGauge.SetGauge(ctx, ca.ChaikinMoneyFlow.Value(), nil)
+	}
+	return nil
+}
+
+func (ca *CMFAdapter) Name() string {
+	return fmt.Sprintf("CMF_%d_%.2f", ca.ChaikinMoneyFlow.Period, ca.Threshold)
+}
+
+func (ca *CMFAdapter) GetSignal(ctx context.Context) (result model.TradingSignal) {
+	ctx = ca.getUpdateContext(ctx)
+	defer func() {
+		tags := monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result.Action))
+		tags.Add(ADAPTOR_NAME_LABEL, ca.Name())
+		ca.metrics.SignalCounter.IncrementCounter(ctx, tags)
+	}()
+	result = model.TradingSignal{Time: ca.CurrentData.Time, Action: model.Wait}
+	zaps := []zap.Field{zap.String("adapter", ca.Name()), zap.Any("time", ca.CurrentData.Time)}
+
+	if !ca.HasPrevious {
+		ca.logger.Debug(ctx, "Not enough Chaikin Money Flow values for signal generation", zaps...)
+		return result
+	}
+
+	previous, current := ca.PreviousValue, ca.ChaikinMoneyFlow.Value()
+	if previous <= ca.Threshold && current > ca.Threshold {
+		ca.logger.Info(ctx, "Buy signal detected", zaps...)
+		return ca.signal(model.Buy, "crossed up through", ca.Threshold)
+	}
+	if previous >= -ca.Threshold && current < -ca.Threshold {
+		ca.logger.Info(ctx, "Sell signal detected", zaps...)
+		return ca.signal(model.Sell, "crossed down through", -ca.Threshold)
+	}
+
+	ca.logger.Debug(ctx, "No trading signal detected", zaps...)
+	return result
+}
+
+// signal builds a threshold cross signal, a money flow of 0.5 or more either way gives full strength.
+func (ca *CMFAdapter) signal(action model.StockAction, event string, threshold float64) model.TradingSignal {
+	stopLoss, target := ca.swing.exits(action, ca.CurrentData.Close)
+	current := ca.ChaikinMoneyFlow.Value()
+	return model.TradingSignal{
+		Time:     ca.CurrentData.Time,
+		Action:   action,
+		Strength: clampStrength(0.5 + math.Abs(current)),
+		StopLoss: stopLoss,
+		Target:   target,
+		Rationale: []model.Rationale{{
+			Source:    ca.Name(),
+			Indicator: "CMF",
+			Event:     event,
+			Reference: strconv.FormatFloat(threshold, 'g', -1, 64),
+			Values:    []float64{ca.PreviousValue, current},
+		}},
+	}
+}
+
+// IndicatorValues returns the latest Chaikin Money Flow.
+func (ca *CMFAdapter) IndicatorValues() map[string]float64 {
+	if !ca.ChaikinMoneyFlow.Ready() {
+		return nil
+	}
+	return map[string]float64{"cmf": ca.ChaikinMoneyFlow.Value()}
+}
+
+// Function to retrieve and update the slice from context
+func (ca *CMFAdapter) getUpdateContext(ctx context.Context) context.Context {
+	ctx = getUpdatedCommonLabelsContext(ctx)
+	return context.WithValue(ctx, ADAPTOR_NAME_LABEL, ca.Name())
+}
+
+// cmfAdapterState is the saved state of a CMFAdapter.
+type cmfAdapterState struct {
+	Adaptor          string
+	ChaikinMoneyFlow *indicator.ChaikinMoneyFlow
+	PreviousValue    float64
+	HasPrevious      bool
+	CurrentData      model.DataPoint
+	Swing            priceSwing
+}
+
+// MarshalState encodes the full state of the adapter.
+func (ca *CMFAdapter) MarshalState(format indicator.StateFormat) ([]byte, error) {
+	return indicator.EncodeState(format, cmfAdapterState{
+		Adaptor:          ca.Name(),
+		ChaikinMoneyFlow: ca.ChaikinMoneyFlow,
+		PreviousValue:    ca.PreviousValue,
+		HasPrevious:      ca.HasPrevious,
+		CurrentData:      ca.CurrentData,
+		Swing:            ca.swing,
+	})
+}
+
+// UnmarshalState replaces the state of the adapter with one saved by an adapter with the same parameters.
+func (ca *CMFAdapter) UnmarshalState(format indicator.StateFormat, data []byte) error {
+	var state cmfAdapterState
+	if err := indicator.DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	if err := checkSavedState(state.Adaptor, ca.Name(), state.ChaikinMoneyFlow != nil); err != nil {
+		return err
+	}
+	ca.ChaikinMoneyFlow, ca.PreviousValue, ca.HasPrevious = state.ChaikinMoneyFlow, state.PreviousValue, state.HasPrevious
+	ca.CurrentData, ca.swing = state.CurrentData, state.Swing
+	return nil
+}
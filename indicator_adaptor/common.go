@@ -3,9 +3,12 @@ package indicator_adaptor
 
 import (
 	"context"
+	"math"
 
 	"github.com/vd09/trading-algorithm-backtesting-system/constraint"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
 	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils"
 )
 
 const (
@@ -21,3 +24,90 @@ func getUpdatedCommonLabelsContext(ctx context.Context) context.Context {
 	slice = append(slice, ADAPTOR_NAME_LABEL)
 	return context.WithValue(ctx, constraint.COMMON_LABELS_CTX, slice)
 }
+
+const (
+	// swingWindow is the number of recent bars the stop loss of a signal is placed behind.
+	swingWindow = 10
+	// rewardRiskRatio places the target of a signal at this multiple of its distance to the stop loss.
+	rewardRiskRatio = 2
+)
+
+// priceSwing keeps the highs and lows of the latest bars.
+type priceSwing struct {
+	Highs []float64
+	Lows  []float64
+}
+
+func (ps *priceSwing) add(data model.DataPoint) {
+	ps.Highs = append(ps.Highs, data.High)
+	ps.Lows = append(ps.Lows, data.Low)
+	if len(ps.Highs) > swingWindow {
+		ps.Highs = ps.Highs[1:]
+		ps.Lows = ps.Lows[1:]
+	}
+}
+
+// exits places the stop loss behind the recent swing low for buys, or swing high for sells.
+func (ps *priceSwing) exits(action model.StockAction, price float64) (stopLoss, target float64) {
+	if len(ps.Lows) == 0 {
+		return 0, 0
+	}
+	switch action {
+	case model.Buy:
+		stopLoss = utils.Min(price, ps.Lows[0])
+		for _, low := range ps.Lows {
+			stopLoss = utils.Min(stopLoss, low)
+		}
+	case model.Sell:
+		stopLoss = utils.Max(price, ps.Highs[0])
+		for _, high := range ps.Highs {
+			stopLoss = utils.Max(stopLoss, high)
+		}
+	default:
+		return 0, 0
+	}
+	return stopLoss, rewardTarget(price, stopLoss)
+}
+
+// crossedLines reports whether the fast line crossed every other line within their histories, upwards for buys and
+// downwards for sells.
+func crossedLines(action model.StockAction, fast []float64, others ...[]float64) bool {
+	for _, other := range others {
+		if action == model.Buy && fast[0] >= other[0] || action == model.Sell && fast[0] <= other[0] {
+			return false
+		}
+		if !utils.IsLineIntersect(fast, other) {
+			return false
+		}
+	}
+	return true
+}
+
+// rewardTarget returns the target on the other side of the price, rewardRiskRatio times as far as the stop loss.
+func rewardTarget(price, stopLoss float64) float64 {
+	return price + rewardRiskRatio*(price-stopLoss)
+}
+
+// relativeStrength converts the gap between two lines into a signal strength,
+// a gap of 1% of the price or more gives full strength.
+func relativeStrength(gap, price float64) float64 {
+	if price == 0 {
+		return 0.5
+	}
+	return clampStrength(0.5 + 50*math.Abs(gap)/math.Abs(price))
+}
+
+func clampStrength(strength float64) float64 {
+	return math.Max(0, math.Min(1, strength))
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, value := range values {
+		total += value
+	}
+	return total / float64(len(values))
+}
@@ -0,0 +1,246 @@
+package indicator_adaptor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/constraint"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator"
+	"github.com/vd09/trading-algorithm-backtesting-system/logger"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
+	"go.uber.org/zap"
+)
+
+const (
+	DONCHIAN_CHANNEL_LABEL = "donchian_channel_type"
+)
+
+type DonchianMetrics struct {
+	SignalCounter monitor.CounterMetric
+	ChannelGauge  monitor.GaugeMetric
+	monitor       monitor.Monitoring
+}
+
+// DonchianAdapter trades turtle-style breakouts. It buys when the close breaks above the highest high of
+// the entry lookback before the bar and sells when it breaks below the lowest low. The position is kept
+// until the close breaks the exit lookback channel against it, and the stop loss is placed at that channel.
+type DonchianAdapter struct {
+	Entry *indicator.DonchianChannels
+	Exit  *indicator.DonchianChannels
+	// PreviousEntry and PreviousExit are the channels up to the bar before the current one.
+	PreviousEntry indicator.DonchianValues
+	PreviousExit  indicator.DonchianValues
+	HasPrevious   bool
+	// Position is the side of the latest breakout, Wait once it has been exited.
+	Position model.StockAction
+	// Breakout is the side the current bar broke out to, Wait without a new breakout.
+	Breakout    model.StockAction
+	CurrentData model.DataPoint
+	logger      logger.LoggerInterface
+	metrics     *DonchianMetrics
+}
+
+func init() {
+	registry.RegisterAdaptor(registry.AdaptorEntry{
+		Name:        "donchian",
+		Description: "Trades turtle-style Donchian Channel breakouts with a shorter channel for the exit",
+		Params: []registry.Param{
+			{Name: "entry_period", Type: registry.IntParam, Description: "Bars whose high or low the close has to break to enter", Default: 20, Min: 1, Max: 1000},
+			{Name: "exit_period", Type: registry.IntParam, Description: "Bars whose low or high against the position ends it", Default: 10, Min: 1, Max: 1000},
+		},
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (IndicatorAdaptor, error) {
+			return NewDonchianAdapter(ctx, p.Int("entry_period"), p.Int("exit_period"), m), nil
+		},
+	})
+}
+
+func NewDonchianAdapter(ctx context.Context, entryPeriod, exitPeriod int, monitor monitor.Monitoring) *DonchianAdapter {
+	adapter := &DonchianAdapter{
+		Entry:    indicator.NewDonchianChannels(entryPeriod),
+		Exit:     indicator.NewDonchianChannels(exitPeriod),
+		Position: model.Wait,
+		Breakout: model.Wait,
+		logger:   logger.GetLogger(),
+	}
+	adapter.registerMetrics(ctx, monitor)
+	return adapter
+}
+
+func (da *DonchianAdapter) registerMetrics(ctx context.Context, m monitor.Monitoring) {
+	ctx = da.getUpdateContext(ctx)
+	da.metrics = &DonchianMetrics{
+		monitor:       m,
+		SignalCounter: m.RegisterCounter(ctx, "donchian_signals_generated", "Total number of Donchian Channels signals generated", monitor.Labels{constraint.SIGNAL_TYPE_LABEL}),
+		ChannelGauge:  m.RegisterGauge(ctx, "donchian_channels", "Current entry and exit Donchian Channels", monitor.Labels{DONCHIAN_CHANNEL_LABEL}),
+	}
+}
+
+func (da *DonchianAdapter) Clone(ctx context.Context) IndicatorAdaptor {
+	return NewDonchianAdapter(ctx, da.Entry.Period, da.Exit.Period, da.metrics.monitor)
+}
+
+func (da *DonchianAdapter) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	ctx = da.getUpdateContext(ctx)
+	da.logger.Debug(ctx, "Adding data point to DonchianAdapter", zap.Int64("timestamp", data.Time))
+
+	previousEntry, previousExit := da.Entry.Value(), da.Exit.Value()
+	wasInitialized := da.Entry.Ready() && da.Exit.Ready()
+	if err := da.Entry.Update(ctx, data); err != nil {
+		da.logger.Error(ctx, "Failed to add data point to Donchian Channels", zap.Error(err))
+		return err
+	}
+	if err := da.Exit.Update(ctx, data); err != nil {
+		da.logger.Error(ctx, "Failed to add data point to Donchian Channels", zap.Error(err))
+		return err
+	}
+	da.PreviousEntry, da.PreviousExit, da.HasPrevious = previousEntry, previousExit, wasInitialized
+	da.CurrentData = data
+	if da.HasPrevious {
+		da.trackBreakout(data.Close)
+	}
+
+	if da.Entry.Ready() {
+		entry := da.Entry.Value()
+		da.metrics.ChannelGauge.SetGauge(ctx, entry.Upper, monitor.NewTagsKV(DONCHIAN_CHANNEL_LABEL, "entry_upper"))
+		da.metrics.ChannelGauge.SetGauge(ctx, entry.Lower, monitor.NewTagsKV(DONCHIAN_CHANNEL_LABEL, "entry_lower"))
+	}
+	if da.Exit.Ready() {
+		exit := da.Exit.Value()
+		da.metrics.ChannelGauge.SetGauge(ctx, exit.Upper, monitor.NewTagsKV(DONCHIAN_CHANNEL_LABEL, "exit_upper"))
+		da.metrics.ChannelGauge.SetGauge(ctx, exit.Lower, monitor.NewTagsKV(DONCHIAN_CHANNEL_LABEL, "exit_lower"))
+	}
+	return nil
+}
+
+// trackBreakout exits the position when the close breaks the exit channel against it, and enters a new one
+// when the close breaks the entry channel.
+func (da *DonchianAdapter) trackBreakout(price float64) {
+	if (da.Position == model.Buy && price < da.PreviousExit.Lower) || (da.Position == model.Sell && price > da.PreviousExit.Upper) {
+		da.Position = model.Wait
+	}
+	da.Breakout = model.Wait
+	if da.Position != model.Buy && price > da.PreviousEntry.Upper {
+		da.Breakout = model.Buy
+	} else if da.Position != model.Sell && price < da.PreviousEntry.Lower {
+		da.Breakout = model.Sell
+	}
+	if da.Breakout != model.Wait {
+		da.Position = da.Breakout
+	}
+}
+
+func (da *DonchianAdapter) Name() string {
+	return fmt.Sprintf("Donchian_%d_%d", da.Entry.Period, da.Exit.Period)
+}
+
+func (da *DonchianAdapter) GetSignal(ctx context.Context) (result model.TradingSignal) {
+	ctx = da.getUpdateContext(ctx)
+	defer func() {
+		tags := monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result.Action))
+		tags.Add(ADAPTOR_NAME_LABEL, da.Name())
+		da.metrics.SignalCounter.IncrementCounter(ctx, tags)
+	}()
+	result = model.TradingSignal{Time: da.CurrentData.Time, Action: model.Wait}
+	zaps := []zap.Field{zap.String("adapter", da.Name()), zap.Any("time", da.CurrentData.Time)}
+
+	if !da.HasPrevious {
+		da.logger.Debug(ctx, "Donchian Channels not initialized", zaps...)
+		return result
+	}
+
+	switch da.Breakout {
+	case model.Buy:
+		da.logger.Info(ctx, "Buy signal detected", zaps...)
+		return da.signal(model.Buy, "closed above", da.PreviousEntry.Upper, da.PreviousExit.Lower)
+	case model.Sell:
+		da.logger.Info(ctx, "Sell signal detected", zaps...)
+		return da.signal(model.Sell, "closed below", da.PreviousEntry.Lower, da.PreviousExit.Upper)
+	}
+
+	da.logger.Debug(ctx, "No trading signal detected", zaps...)
+	return result
+}
+
+// signal builds a breakout signal with the stop loss at the exit channel,
+// the further the close went past the entry channel the stronger the signal.
+func (da *DonchianAdapter) signal(action model.StockAction, event string, entry, exit float64) model.TradingSignal {
+	price := da.CurrentData.Close
+	return model.TradingSignal{
+		Time:     da.CurrentData.Time,
+		Action:   action,
+		Strength: relativeStrength(price-entry, price),
+		StopLoss: exit,
+		Target:   rewardTarget(price, exit),
+		Rationale: []model.Rationale{{
+			Source:    da.Name(),
+			Indicator: "Donchian Channels",
+			Event:     event,
+			Reference: fmt.Sprintf("%d bar channel", da.Entry.Period),
+			Values:    []float64{entry, price},
+		}},
+	}
+}
+
+// IndicatorValues returns the latest entry and exit channels.
+func (da *DonchianAdapter) IndicatorValues() map[string]float64 {
+	if !da.Entry.Ready() || !da.Exit.Ready() {
+		return nil
+	}
+	entry, exit := da.Entry.Value(), da.Exit.Value()
+	return map[string]float64{
+		"donchian_upper":      entry.Upper,
+		"donchian_lower":      entry.Lower,
+		"donchian_exit_upper": exit.Upper,
+		"donchian_exit_lower": exit.Lower,
+	}
+}
+
+// Function to retrieve and update the slice from context
+func (da *DonchianAdapter) getUpdateContext(ctx context.Context) context.Context {
+	ctx = getUpdatedCommonLabelsContext(ctx)
+	return context.WithValue(ctx, ADAPTOR_NAME_LABEL, da.Name())
+}
+
+// donchianAdapterState is the saved state of a DonchianAdapter.
+type donchianAdapterState struct {
+	Adaptor       string
+	Entry         *indicator.DonchianChannels
+	Exit          *indicator.DonchianChannels
+	PreviousEntry indicator.DonchianValues
+	PreviousExit  indicator.DonchianValues
+	HasPrevious   bool
+	Position      model.StockAction
+	Breakout      model.StockAction
+	CurrentData   model.DataPoint
+}
+
+// MarshalState encodes the full state of the adapter.
+func (da *DonchianAdapter) MarshalState(format indicator.StateFormat) ([]byte, error) {
+	return indicator.EncodeState(format, donchianAdapterState{
+		Adaptor:       da.Name(),
+		Entry:         da.Entry,
+		Exit:          da.Exit,
+		PreviousEntry: da.PreviousEntry,
+		PreviousExit:  da.PreviousExit,
+		HasPrevious:   da.HasPrevious,
+		Position:      da.Position,
+		Breakout:      da.Breakout,
+		CurrentData:   da.CurrentData,
+	})
+}
+
+// UnmarshalState replaces the state of the adapter with one saved by an adapter with the same parameters.
+func (da *DonchianAdapter) UnmarshalState(format indicator.StateFormat, data []byte) error {
+	var state donchianAdapterState
+	if err := indicator.DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	if err := checkSavedState(state.Adaptor, da.Name(), state.Entry != nil && state.Exit != nil); err != nil {
+		return err
+	}
+	da.Entry, da.Exit, da.PreviousEntry, da.PreviousExit = state.Entry, state.Exit, state.PreviousEntry, state.PreviousExit
+	da.HasPrevious, da.Position, da.Breakout, da.CurrentData = state.HasPrevious, state.Position, state.Breakout, state.CurrentData
+	return nil
+}
@@ -2,6 +2,7 @@ package indicator_adaptor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -11,7 +12,7 @@ import (
 	"github.com/vd09/trading-algorithm-backtesting-system/logger"
 	"github.com/vd09/trading-algorithm-backtesting-system/model"
 	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
-	"github.com/vd09/trading-algorithm-backtesting-system/utils"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
 	"go.uber.org/zap"
 )
 
@@ -28,10 +29,31 @@ type EMAAdapter struct {
 	HistoricalValues       map[int][]float64
 	periods                []int
 	periodsString          string // Store periods as string
+	swing                  priceSwing
 	logger                 logger.LoggerInterface
 	metrics                *EMAMetrics
 }
 
+func init() {
+	registry.RegisterAdaptor(registry.AdaptorEntry{
+		Name:        "ema",
+		Description: "Signals when the EMAs of the given periods cross",
+		Params: []registry.Param{
+			{Name: "periods", Type: registry.IntListParam, Required: true, Min: 1, Max: 1000},
+			{Name: "max_history", Type: registry.IntParam, Default: 10, Min: 2, Max: 100000},
+		},
+		Validate: func(p registry.Params) error {
+			if len(p.Ints("periods")) < 2 {
+				return errors.New("at least two periods are needed")
+			}
+			return nil
+		},
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (IndicatorAdaptor, error) {
+			return NewEMAAdapter(ctx, p.Ints("periods"), p.Int("max_history"), m), nil
+		},
+	})
+}
+
 func NewEMAAdapter(ctx context.Context, periods []int, maxTotalHistoricalData int, monitor monitor.Monitoring) *EMAAdapter {
 	emas := make(map[int]*indicator.EMA)
 	historicalValues := make(map[int][]float64)
@@ -70,15 +92,21 @@ func (ea *EMAAdapter) AddDataPoint(ctx context.Context, data model.DataPoint) er
 	ctx = ea.getUpdateContext(ctx)
 	ea.logger.Debug(ctx, "Adding data point to EMAAdapter", zap.Int64("timestamp", data.Time))
 
+	for _, ema := range ea.EMAs {
+		if err := ema.Update(ctx, data); err != nil {
+			ea.logger.Error(ctx, "Failed to add data point to EMA", zap.Error(err))
+			return err
+		}
+	}
 	ea.CurrentData = data
+	ea.swing.add(data)
 	for period, ema := range ea.EMAs {
-		ema.AddDataPoint(ctx, data)
-		if ema.Initialized {
+		if ema.Ready() {
 			tags := monitor.NewTagsKV(PERIOD_LABEL, period)
 			tags.Add(ADAPTOR_NAME_LABEL, ea.Name())
-			ea.metrics.ValueCounter.SetValue(ctx, ema.Value, tags)
+			ea.metrics.ValueCounter.SetValue(ctx, ema.Value(), tags)
 
-			ea.HistoricalValues[period] = append(ea.HistoricalValues[period], ema.Value)
+			ea.HistoricalValues[period] = append(ea.HistoricalValues[period], ema.Value())
 		}
 		if len(ea.HistoricalValues[period]) > ea.MaxTotalHistoricalData {
 			ea.HistoricalValues[period] = ea.HistoricalValues[period][1:]
@@ -92,38 +120,64 @@ func (ea *EMAAdapter) Name() string {
 	return fmt.Sprintf("EMA_%s", ea.periodsString) // Use the stored periods string
 }
 
-func (ea *EMAAdapter) GetSignal(ctx context.Context) (result model.StockAction) {
+func (ea *EMAAdapter) GetSignal(ctx context.Context) (result model.TradingSignal) {
 	ctx = ea.getUpdateContext(ctx)
 	defer func() {
-		tags := monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result))
+		tags := monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result.Action))
 		tags.Add(ADAPTOR_NAME_LABEL, ea.Name())
 		ea.metrics.SignalCounter.IncrementCounter(ctx, tags)
 	}()
+	result = model.TradingSignal{Time: ea.CurrentData.Time, Action: model.Wait}
 
 	zaps := []zap.Field{zap.String("adapter", ea.Name()), zap.Any("time", ea.CurrentData.Time)}
 	for _, ema := range ea.EMAs {
-		if !ema.Initialized {
+		if !ema.Ready() {
 			ea.logger.Debug(ctx, "EMA not initialized", zaps...)
-			return model.Wait
+			return result
 		}
 	}
 
 	highestPeriod := ea.periods[len(ea.periods)-1]
 	if len(ea.HistoricalValues[highestPeriod]) < 2 {
 		ea.logger.Debug(ctx, "Not enough historical data for signal generation", zaps...)
-		return model.Wait
+		return result
 	}
 
 	if ea.checkForBuySignal() {
 		ea.logger.Info(ctx, "Buy signal detected", zaps...)
-		return model.Buy
+		return ea.signal(model.Buy, "crossed above")
 	} else if ea.checkForSellSignal() {
 		ea.logger.Info(ctx, "Sell signal detected", zaps...)
-		return model.Sell
+		return ea.signal(model.Sell, "crossed below")
 	}
 
 	ea.logger.Debug(ctx, "No trading signal detected", zaps...)
-	return model.Wait
+	return result
+}
+
+// signal builds a crossover signal, its strength grows with the gap between the fastest and slowest EMA.
+func (ea *EMAAdapter) signal(action model.StockAction, event string) model.TradingSignal {
+	fastest := ea.HistoricalValues[ea.periods[0]]
+	slowest := ea.HistoricalValues[ea.periods[len(ea.periods)-1]]
+	stopLoss, target := ea.swing.exits(action, ea.CurrentData.Close)
+
+	signal := model.TradingSignal{
+		Time:     ea.CurrentData.Time,
+		Action:   action,
+		Strength: relativeStrength(fastest[len(fastest)-1]-slowest[len(slowest)-1], ea.CurrentData.Close),
+		StopLoss: stopLoss,
+		Target:   target,
+	}
+	for _, period := range ea.periods[1:] {
+		signal.Rationale = append(signal.Rationale, model.Rationale{
+			Source:    ea.Name(),
+			Indicator: fmt.Sprintf("EMA(%d)", ea.periods[0]),
+			Event:     event,
+			Reference: fmt.Sprintf("EMA(%d)", period),
+			Values:    []float64{fastest[0], fastest[len(fastest)-1]},
+		})
+	}
+	return signal
 }
 
 func getPeriodsString(periods []int) string {
@@ -131,29 +185,31 @@ func getPeriodsString(periods []int) string {
 }
 
 func (ea *EMAAdapter) checkForBuySignal() bool {
-	smallestPeriod := ea.periods[0]
-	for _, period := range ea.periods[1:] {
-		if ea.HistoricalValues[smallestPeriod][0] >= ea.HistoricalValues[period][0] {
-			return false
-		}
-		if !utils.IsLineIntersect(ea.HistoricalValues[smallestPeriod], ea.HistoricalValues[period]) {
-			return false
-		}
-	}
-	return true
+	return crossedLines(model.Buy, ea.HistoricalValues[ea.periods[0]], ea.slowerLines()...)
 }
 
 func (ea *EMAAdapter) checkForSellSignal() bool {
-	smallestPeriod := ea.periods[0]
+	return crossedLines(model.Sell, ea.HistoricalValues[ea.periods[0]], ea.slowerLines()...)
+}
+
+// slowerLines returns the histories of every EMA but the fastest.
+func (ea *EMAAdapter) slowerLines() [][]float64 {
+	lines := make([][]float64, 0, len(ea.periods)-1)
 	for _, period := range ea.periods[1:] {
-		if ea.HistoricalValues[smallestPeriod][0] <= ea.HistoricalValues[period][0] {
-			return false
-		}
-		if !utils.IsLineIntersect(ea.HistoricalValues[smallestPeriod], ea.HistoricalValues[period]) {
-			return false
+		lines = append(lines, ea.HistoricalValues[period])
+	}
+	return lines
+}
+
+// IndicatorValues returns the latest value of every initialized EMA, e.g. "ema_20".
+func (ea *EMAAdapter) IndicatorValues() map[string]float64 {
+	values := make(map[string]float64, len(ea.EMAs))
+	for period, ema := range ea.EMAs {
+		if ema.Ready() {
+			values[fmt.Sprintf("ema_%d", period)] = ema.Value()
 		}
 	}
-	return true
+	return values
 }
 
 // Function to retrieve and update the slice from context
@@ -161,3 +217,39 @@ func (ea *EMAAdapter) getUpdateContext(ctx context.Context) context.Context {
 	ctx = getUpdatedCommonLabelsContext(ctx)
 	return context.WithValue(ctx, ADAPTOR_NAME_LABEL, ea.Name())
 }
+
+// emaAdapterState is the saved state of an EMAAdapter.
+type emaAdapterState struct {
+	Adaptor          string
+	EMAs             map[int]*indicator.EMA
+	HistoricalValues map[int][]float64
+	CurrentData      model.DataPoint
+	Swing            priceSwing
+}
+
+// MarshalState encodes the full state of the adapter.
+func (ea *EMAAdapter) MarshalState(format indicator.StateFormat) ([]byte, error) {
+	return indicator.EncodeState(format, emaAdapterState{
+		Adaptor:          ea.Name(),
+		EMAs:             ea.EMAs,
+		HistoricalValues: ea.HistoricalValues,
+		CurrentData:      ea.CurrentData,
+		Swing:            ea.swing,
+	})
+}
+
+// UnmarshalState replaces the state of the adapter with one saved by an adapter with the same parameters.
+func (ea *EMAAdapter) UnmarshalState(format indicator.StateFormat, data []byte) error {
+	var state emaAdapterState
+	if err := indicator.DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	if err := checkSavedState(state.Adaptor, ea.Name(), len(state.EMAs) == len(ea.periods)); err != nil {
+		return err
+	}
+	if state.HistoricalValues == nil {
+		state.HistoricalValues = make(map[int][]float64)
+	}
+	ea.EMAs, ea.HistoricalValues, ea.CurrentData, ea.swing = state.EMAs, state.HistoricalValues, state.CurrentData, state.Swing
+	return nil
+}
@@ -94,7 +94,7 @@ func TestGetSignal(t *testing.T) {
 	}
 
 	// Check the initial signal, should be Wait
-	signal := adapter.GetSignal(ctx)
+	signal := adapter.GetSignal(ctx).Action
 	if signal != model.Wait {
 		t.Errorf("expected signal %v, got %v", model.Wait, signal)
 	}
@@ -105,7 +105,7 @@ func TestGetSignal(t *testing.T) {
 	adapter.HistoricalValues[3] = []float64{20.0, 30.0, 40.0, 50.0, 60.0}
 	adapter.HistoricalValues[5] = test_utils.GiveCrossingLine(adapter.HistoricalValues[3], 5, test_utils.Above)
 
-	signal = adapter.GetSignal(ctx)
+	signal = adapter.GetSignal(ctx).Action
 	if signal != model.Buy {
 		t.Errorf("expected signal %v, got %v", model.Buy, signal)
 	}
@@ -114,7 +114,7 @@ func TestGetSignal(t *testing.T) {
 	adapter.HistoricalValues[3] = []float64{80.0, 70.0, 55.0, 35.0, 25.0}
 	adapter.HistoricalValues[5] = test_utils.GiveCrossingLine(adapter.HistoricalValues[3], 5, test_utils.Below)
 
-	signal = adapter.GetSignal(ctx)
+	signal = adapter.GetSignal(ctx).Action
 	if signal != model.Sell {
 		t.Errorf("expected signal %v, got %v", model.Sell, signal)
 	}
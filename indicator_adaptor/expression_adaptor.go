@@ -0,0 +1,241 @@
+package indicator_adaptor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/constraint"
+	"github.com/vd09/trading-algorithm-backtesting-system/expression"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator"
+	"github.com/vd09/trading-algorithm-backtesting-system/logger"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
+	"go.uber.org/zap"
+)
+
+type ExpressionMetrics struct {
+	SignalCounter monitor.CounterMetric
+	monitor       monitor.Monitoring
+}
+
+// ExpressionAdapter emits signals from rule expressions, see the expression package for the syntax.
+type ExpressionAdapter struct {
+	BuyRule     *expression.Program
+	SellRule    *expression.Program
+	CurrentData model.DataPoint
+	swing       priceSwing
+	logger      logger.LoggerInterface
+	metrics     *ExpressionMetrics
+}
+
+func init() {
+	registry.RegisterAdaptor(registry.AdaptorEntry{
+		Name:        "expression",
+		Description: "Signals when rule expressions over indicators hold, e.g. \"crosses_above(ema(9), ema(21)) and rsi(14) < 70\"",
+		Params: []registry.Param{
+			{Name: "buy", Type: registry.StringParam, Description: "Rule to buy on, empty never buys", Default: ""},
+			{Name: "sell", Type: registry.StringParam, Description: "Rule to sell on, empty never sells", Default: ""},
+		},
+		Validate: func(p registry.Params) error {
+			for _, name := range []string{"buy", "sell"} {
+				if rule := p.String(name); rule != "" {
+					if _, err := expression.Compile(rule); err != nil {
+						return &registry.ParamError{Param: name, Msg: err.Error()}
+					}
+				}
+			}
+			return nil
+		},
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (IndicatorAdaptor, error) {
+			return NewExpressionAdapter(ctx, p.String("buy"), p.String("sell"), m)
+		},
+	})
+}
+
+// NewExpressionAdapter compiles the buy and sell rules and initializes a new ExpressionAdapter instance.
+// An empty rule never fires.
+func NewExpressionAdapter(ctx context.Context, buyRule, sellRule string, monitor monitor.Monitoring) (*ExpressionAdapter, error) {
+	adapter := &ExpressionAdapter{
+		logger: logger.GetLogger(),
+	}
+
+	var err error
+	if buyRule != "" {
+		if adapter.BuyRule, err = expression.Compile(buyRule); err != nil {
+			return nil, fmt.Errorf("invalid buy rule: %w", err)
+		}
+	}
+	if sellRule != "" {
+		if adapter.SellRule, err = expression.Compile(sellRule); err != nil {
+			return nil, fmt.Errorf("invalid sell rule: %w", err)
+		}
+	}
+	adapter.registerMetrics(ctx, monitor)
+	return adapter, nil
+}
+
+func (ea *ExpressionAdapter) registerMetrics(ctx context.Context, m monitor.Monitoring) {
+	ctx = ea.getUpdateContext(ctx)
+	ea.metrics = &ExpressionMetrics{
+		monitor:       m,
+		SignalCounter: m.RegisterCounter(ctx, "expression_signals_generated", "Total number of expression signals generated", monitor.Labels{constraint.SIGNAL_TYPE_LABEL}),
+	}
+}
+
+// Clone creates a new instance of ExpressionAdapter with the same rules.
+func (ea *ExpressionAdapter) Clone(ctx context.Context) IndicatorAdaptor {
+	clone := &ExpressionAdapter{
+		logger: logger.GetLogger(),
+	}
+	if ea.BuyRule != nil {
+		clone.BuyRule = ea.BuyRule.Clone()
+	}
+	if ea.SellRule != nil {
+		clone.SellRule = ea.SellRule.Clone()
+	}
+	clone.registerMetrics(ctx, ea.metrics.monitor)
+	return clone
+}
+
+// AddDataPoint feeds a new data point to both rules.
+func (ea *ExpressionAdapter) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	ctx = ea.getUpdateContext(ctx)
+	ea.logger.Debug(ctx, "Adding data point to ExpressionAdapter", zap.Int64("timestamp", data.Time))
+
+	ea.CurrentData = data
+	ea.swing.add(data)
+	for _, rule := range []*expression.Program{ea.BuyRule, ea.SellRule} {
+		if rule == nil {
+			continue
+		}
+		if err := rule.AddDataPoint(ctx, data); err != nil {
+			ea.logger.Error(ctx, "Failed to add data point to expression", zap.String("rule", rule.Source()), zap.Error(err))
+			return err
+		}
+	}
+	return nil
+}
+
+// Name returns the name of the expression adapter.
+func (ea *ExpressionAdapter) Name() string {
+	return fmt.Sprintf("Expression_B(%s)_S(%s)", ruleSource(ea.BuyRule), ruleSource(ea.SellRule))
+}
+
+// GetSignal returns Buy when the buy rule holds, Sell when the sell rule holds and Wait otherwise.
+func (ea *ExpressionAdapter) GetSignal(ctx context.Context) (result model.TradingSignal) {
+	ctx = ea.getUpdateContext(ctx)
+	defer func() {
+		ea.metrics.SignalCounter.IncrementCounter(ctx, monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result.Action)))
+	}()
+	result = model.TradingSignal{Time: ea.CurrentData.Time, Action: model.Wait}
+	zaps := []zap.Field{zap.String("adapter", ea.Name()), zap.Any("time", ea.CurrentData.Time)}
+
+	buy := ea.BuyRule != nil && ea.BuyRule.True()
+	sell := ea.SellRule != nil && ea.SellRule.True()
+	if buy && sell {
+		ea.logger.Debug(ctx, "Buy and sell rules both hold, ignoring conflicting signal", zaps...)
+		return result
+	}
+	if buy {
+		ea.logger.Info(ctx, "Buy signal detected", zaps...)
+		return ea.signal(model.Buy, ea.BuyRule)
+	}
+	if sell {
+		ea.logger.Info(ctx, "Sell signal detected", zaps...)
+		return ea.signal(model.Sell, ea.SellRule)
+	}
+
+	ea.logger.Debug(ctx, "No trading signal detected", zaps...)
+	return result
+}
+
+// signal builds the signal of a rule that holds, rules are either true or false so the signal has full strength.
+func (ea *ExpressionAdapter) signal(action model.StockAction, rule *expression.Program) model.TradingSignal {
+	stopLoss, target := ea.swing.exits(action, ea.CurrentData.Close)
+	return model.TradingSignal{
+		Time:     ea.CurrentData.Time,
+		Action:   action,
+		Strength: 1,
+		StopLoss: stopLoss,
+		Target:   target,
+		Rationale: []model.Rationale{{
+			Source:    ea.Name(),
+			Indicator: fmt.Sprintf("%s rule", action),
+			Event:     "holds:",
+			Reference: rule.Source(),
+		}},
+	}
+}
+
+// IndicatorValues returns the values of the rules on the latest bar, 1 or 0 for conditions.
+func (ea *ExpressionAdapter) IndicatorValues() map[string]float64 {
+	values := make(map[string]float64, 2)
+	if ea.BuyRule != nil && ea.BuyRule.Ready() {
+		values["buy_rule"] = ea.BuyRule.Value()
+	}
+	if ea.SellRule != nil && ea.SellRule.Ready() {
+		values["sell_rule"] = ea.SellRule.Value()
+	}
+	return values
+}
+
+// Function to retrieve and update the context with common labels and the adapter name
+func (ea *ExpressionAdapter) getUpdateContext(ctx context.Context) context.Context {
+	ctx = getUpdatedCommonLabelsContext(ctx)
+	return context.WithValue(ctx, ADAPTOR_NAME_LABEL, ea.Name())
+}
+
+func ruleSource(rule *expression.Program) string {
+	if rule == nil {
+		return ""
+	}
+	return rule.Source()
+}
+
+// expressionAdapterState is the saved state of an ExpressionAdapter, a rule that is not set has no state.
+type expressionAdapterState struct {
+	Adaptor     string
+	BuyRule     *expression.ProgramState
+	SellRule    *expression.ProgramState
+	CurrentData model.DataPoint
+	Swing       priceSwing
+}
+
+// MarshalState encodes the full state of the adapter, including the indicators of both rules.
+func (ea *ExpressionAdapter) MarshalState(format indicator.StateFormat) ([]byte, error) {
+	state := expressionAdapterState{Adaptor: ea.Name(), CurrentData: ea.CurrentData, Swing: ea.swing}
+	if ea.BuyRule != nil {
+		buy := ea.BuyRule.State()
+		state.BuyRule = &buy
+	}
+	if ea.SellRule != nil {
+		sell := ea.SellRule.State()
+		state.SellRule = &sell
+	}
+	return indicator.EncodeState(format, state)
+}
+
+// UnmarshalState replaces the state of the adapter with one saved by an adapter with the same rules.
+func (ea *ExpressionAdapter) UnmarshalState(format indicator.StateFormat, data []byte) error {
+	var state expressionAdapterState
+	if err := indicator.DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	complete := (ea.BuyRule == nil) == (state.BuyRule == nil) && (ea.SellRule == nil) == (state.SellRule == nil)
+	if err := checkSavedState(state.Adaptor, ea.Name(), complete); err != nil {
+		return err
+	}
+	if ea.BuyRule != nil {
+		if err := ea.BuyRule.Restore(*state.BuyRule); err != nil {
+			return err
+		}
+	}
+	if ea.SellRule != nil {
+		if err := ea.SellRule.Restore(*state.SellRule); err != nil {
+			return err
+		}
+	}
+	ea.CurrentData, ea.swing = state.CurrentData, state.Swing
+	return nil
+}
@@ -0,0 +1,64 @@
+package indicator_adaptor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+// TestNewExpressionAdapterInvalidRule tests that invalid rules are rejected.
+func TestNewExpressionAdapterInvalidRule(t *testing.T) {
+	mock := test_utils.NewMockMetricsCollector(t)
+	_, err := indicator_adaptor.NewExpressionAdapter(context.Background(), "close >", "", mock)
+	if err == nil {
+		t.Fatalf("expected error for invalid buy rule")
+	}
+}
+
+// TestExpressionAdapterName tests the generation of the ExpressionAdapter's name.
+func TestExpressionAdapterName(t *testing.T) {
+	mock := test_utils.NewMockMetricsCollector(t)
+	adapter, err := indicator_adaptor.NewExpressionAdapter(context.Background(), "rsi < 30", "rsi > 70", mock)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedName := "Expression_B(rsi < 30)_S(rsi > 70)"
+	if adapter.Name() != expectedName {
+		t.Errorf("expected name %s, got %s", expectedName, adapter.Name())
+	}
+}
+
+// TestExpressionAdapterGetSignal tests the signal generation logic of the ExpressionAdapter.
+func TestExpressionAdapterGetSignal(t *testing.T) {
+	mock := test_utils.NewMockMetricsCollector(t)
+	adapter, err := indicator_adaptor.NewExpressionAdapter(context.Background(), "crosses_above(close, ema(3))", "crosses_below(close, ema(3))", mock)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+	closes := []float64{10, 9, 8, 7, 12, 13, 14, 6}
+	expected := []model.StockAction{model.Wait, model.Wait, model.Wait, model.Wait, model.Buy, model.Wait, model.Wait, model.Sell}
+	for i, close := range closes {
+		err := adapter.AddDataPoint(ctx, model.DataPoint{Time: int64(i + 1), Close: close})
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		signal := adapter.GetSignal(ctx).Action
+		if signal != expected[i] {
+			t.Errorf("bar %d: expected signal %v, got %v", i, expected[i], signal)
+		}
+	}
+
+	clone := adapter.Clone(ctx)
+	if clone.Name() != adapter.Name() {
+		t.Errorf("expected clone name %s, got %s", adapter.Name(), clone.Name())
+	}
+	if signal := clone.GetSignal(ctx).Action; signal != model.Wait {
+		t.Errorf("expected fresh clone to wait, got %v", signal)
+	}
+}
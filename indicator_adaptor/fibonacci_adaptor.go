@@ -2,6 +2,7 @@ package indicator_adaptor
 
 import (
 	"context"
+	"fmt"
 	"math"
 
 	"github.com/vd09/trading-algorithm-backtesting-system/constraint"
@@ -9,6 +10,7 @@ import (
 	"github.com/vd09/trading-algorithm-backtesting-system/logger"
 	"github.com/vd09/trading-algorithm-backtesting-system/model"
 	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
 	"github.com/vd09/trading-algorithm-backtesting-system/utils"
 	"go.uber.org/zap"
 )
@@ -34,6 +36,19 @@ type FibonacciAdapter struct {
 	metrics                *FibonacciMetrics
 }
 
+func init() {
+	registry.RegisterAdaptor(registry.AdaptorEntry{
+		Name:        "fibonacci",
+		Description: "Signals when the price crosses Fibonacci retracement levels",
+		Params: []registry.Param{
+			{Name: "size", Type: registry.IntParam, Default: 20, Min: 2, Max: 100000},
+		},
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (IndicatorAdaptor, error) {
+			return NewFibonacciAdapter(ctx, p.Int("size"), m), nil
+		},
+	})
+}
+
 // NewFibonacciAdapter initializes and returns a new FibonacciAdapter instance.
 func NewFibonacciAdapter(ctx context.Context, size int, monitor monitor.Monitoring) *FibonacciAdapter {
 	adapter := &FibonacciAdapter{
@@ -64,13 +79,13 @@ func (fa *FibonacciAdapter) AddDataPoint(ctx context.Context, data model.DataPoi
 	ctx = fa.getUpdateContext(ctx)
 	fa.logger.Debug(ctx, "Adding data point to FibonacciAdapter", zap.Int64("timestamp", data.Time))
 
-	if err := fa.Fibonacci.AddDataPoint(ctx, data); err != nil {
+	if err := fa.Fibonacci.Update(ctx, data); err != nil {
 		fa.logger.Error(ctx, "Failed to add data point to Fibonacci", zap.Error(err))
 		return err
 	}
 
 	fa.CurrentData = data
-	fa.CurrentFibonacciLevels = fa.Fibonacci.GetFibonacciLevels()
+	fa.CurrentFibonacciLevels = fa.Fibonacci.Value()
 	fa.HistoricalValues = append(fa.HistoricalValues, data.Close)
 	if len(fa.HistoricalValues) > fa.MaxTotalHistoricalData {
 		fa.HistoricalValues = fa.HistoricalValues[1:]
@@ -92,49 +107,77 @@ func (fa *FibonacciAdapter) Name() string {
 }
 
 // GetSignal generates a trading signal based on the Fibonacci retracement levels.
-func (fa *FibonacciAdapter) GetSignal(ctx context.Context) (result model.StockAction) {
+func (fa *FibonacciAdapter) GetSignal(ctx context.Context) (result model.TradingSignal) {
 	ctx = fa.getUpdateContext(ctx)
 	defer func() {
-		fa.metrics.SignalCounter.IncrementCounter(ctx, monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result)))
+		fa.metrics.SignalCounter.IncrementCounter(ctx, monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result.Action)))
 	}()
+	result = model.TradingSignal{Time: fa.CurrentData.Time, Action: model.Wait}
 
-	if !fa.Fibonacci.IsInitialized {
+	if !fa.Fibonacci.Ready() {
 		fa.logger.Debug(ctx, "Fibonacci not initialized")
-		return model.Wait
+		return result
 	}
 
 	currentData := fa.CurrentData
 	currentLevels := fa.CurrentFibonacciLevels
 	if fa.isOutsideLevels(currentData.Close, currentLevels[indicator.TwentyThree], currentLevels[indicator.SeventySix]) {
 		fa.logger.Debug(ctx, "Price outside key Fibonacci levels", zap.Float64("currentPrice", currentData.Close))
-		return model.Wait
+		return result
 	}
 
-	result = fa.evaluateHistoricalPrices(currentLevels)
-	fa.logger.Info(ctx, "Generated signal", zap.String("signal", string(result)), zap.Float64("currentPrice", currentData.Close))
+	action, previousPrice := fa.evaluateHistoricalPrices(currentLevels)
+	if action != model.Wait {
+		result = fa.signal(action, previousPrice)
+	}
+	fa.logger.Info(ctx, "Generated signal", zap.String("signal", string(result.Action)), zap.Float64("currentPrice", currentData.Close))
 	return result
 }
 
+// signal builds a signal for the price moving back inside the key levels from the top or the bottom of the range.
+// The stop loss sits on the range extreme, and the further the price has moved away from it the stronger the signal.
+func (fa *FibonacciAdapter) signal(action model.StockAction, previousPrice float64) model.TradingSignal {
+	levels := fa.CurrentFibonacciLevels
+	price := fa.CurrentData.Close
+	rangeDiff := levels[indicator.Zero] - levels[indicator.Hundred]
+
+	signal := model.TradingSignal{Time: fa.CurrentData.Time, Action: action}
+	rationale := model.Rationale{Source: fa.Name(), Indicator: "Price", Values: []float64{previousPrice, price}}
+	if action == model.Buy {
+		signal.StopLoss, signal.Target = levels[indicator.Hundred], levels[indicator.TwentyThree]
+		rationale.Event, rationale.Reference = "rebounded from", fmt.Sprintf("100%% retracement %.2f", levels[indicator.Hundred])
+	} else {
+		signal.StopLoss, signal.Target = levels[indicator.Zero], levels[indicator.SeventySix]
+		rationale.Event, rationale.Reference = "fell back from", fmt.Sprintf("0%% retracement %.2f", levels[indicator.Zero])
+	}
+	if rangeDiff > 0 {
+		signal.Strength = clampStrength(0.5 + math.Abs(price-signal.StopLoss)/rangeDiff)
+	}
+	signal.Rationale = []model.Rationale{rationale}
+	return signal
+}
+
 // isOutsideLevels checks if the current price is outside the key Fibonacci levels.
 func (fa *FibonacciAdapter) isOutsideLevels(currentPrice, lowerLevel, upperLevel float64) bool {
 	return currentPrice <= lowerLevel || currentPrice >= upperLevel
 }
 
-// evaluateHistoricalPrices checks historical prices to determine the appropriate trading signal.
-func (fa *FibonacciAdapter) evaluateHistoricalPrices(currentLevels map[indicator.FibonacciLevel]float64) model.StockAction {
+// evaluateHistoricalPrices checks historical prices to determine the appropriate trading signal,
+// it also returns the historical price the signal is based on.
+func (fa *FibonacciAdapter) evaluateHistoricalPrices(currentLevels map[indicator.FibonacciLevel]float64) (model.StockAction, float64) {
 	for i := len(fa.HistoricalValues) - 2; i >= 0; i-- {
 		prevPrice := fa.HistoricalValues[i]
 		if fa.isWithinRange(prevPrice, currentLevels[indicator.TwentyThree], currentLevels[indicator.Zero], currentLevels[indicator.ThirtyEight]-currentLevels[indicator.TwentyThree]) {
-			return model.Sell
+			return model.Sell, prevPrice
 		}
 		if fa.isWithinRange(prevPrice, currentLevels[indicator.Hundred], currentLevels[indicator.SeventySix], currentLevels[indicator.Hundred]-currentLevels[indicator.SeventySix]) {
-			return model.Buy
+			return model.Buy, prevPrice
 		}
 		if prevPrice > currentLevels[indicator.TwentyThree] && prevPrice < currentLevels[indicator.SeventySix] {
 			break
 		}
 	}
-	return model.Wait
+	return model.Wait, 0
 }
 
 // isWithinRange determines if a value is within a specified range with a buffer.
@@ -143,8 +186,58 @@ func (fa *FibonacciAdapter) isWithinRange(value, lowerReference, upperReference,
 	return value >= (lowerReference-buffer) && value <= (upperReference+buffer)
 }
 
+// IndicatorValues returns the range of the window and its key retracement levels.
+func (fa *FibonacciAdapter) IndicatorValues() map[string]float64 {
+	if !fa.Fibonacci.Ready() {
+		return nil
+	}
+	levels := fa.CurrentFibonacciLevels
+	return map[string]float64{
+		"high":       fa.Fibonacci.High,
+		"low":        fa.Fibonacci.Low,
+		"level_38.2": levels[indicator.ThirtyEight],
+		"level_50":   levels[indicator.Fifty],
+		"level_61.8": levels[indicator.SixtyOne],
+	}
+}
+
 // getUpdateContext retrieves and updates the context with common labels and the adapter name
 func (fa *FibonacciAdapter) getUpdateContext(ctx context.Context) context.Context {
 	ctx = getUpdatedCommonLabelsContext(ctx)
 	return context.WithValue(ctx, ADAPTOR_NAME_LABEL, fa.Name())
 }
+
+// fibonacciAdapterState is the saved state of a FibonacciAdapter.
+type fibonacciAdapterState struct {
+	Adaptor          string
+	Fibonacci        *indicator.Fibonacci
+	HistoricalValues []float64
+	CurrentData      model.DataPoint
+}
+
+// MarshalState encodes the full state of the adapter.
+func (fa *FibonacciAdapter) MarshalState(format indicator.StateFormat) ([]byte, error) {
+	return indicator.EncodeState(format, fibonacciAdapterState{
+		Adaptor:          fa.Name(),
+		Fibonacci:        fa.Fibonacci,
+		HistoricalValues: fa.HistoricalValues,
+		CurrentData:      fa.CurrentData,
+	})
+}
+
+// UnmarshalState replaces the state of the adapter with one saved by an adapter with the same parameters.
+func (fa *FibonacciAdapter) UnmarshalState(format indicator.StateFormat, data []byte) error {
+	var state fibonacciAdapterState
+	if err := indicator.DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	if err := checkSavedState(state.Adaptor, fa.Name(), state.Fibonacci != nil); err != nil {
+		return err
+	}
+	if state.Fibonacci.Levels == nil {
+		state.Fibonacci.Levels = make(map[indicator.FibonacciLevel]float64)
+	}
+	fa.Fibonacci, fa.HistoricalValues, fa.CurrentData = state.Fibonacci, state.HistoricalValues, state.CurrentData
+	fa.CurrentFibonacciLevels = fa.Fibonacci.Value()
+	return nil
+}
@@ -0,0 +1,289 @@
+package indicator_adaptor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/constraint"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator"
+	"github.com/vd09/trading-algorithm-backtesting-system/logger"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
+	"go.uber.org/zap"
+)
+
+const (
+	ICHIMOKU_LINE_LABEL = "ichimoku_line_type"
+)
+
+// IchimokuSignal selects which Ichimoku event the IchimokuAdapter signals on.
+type IchimokuSignal string
+
+const (
+	// IchimokuTKCross buys when the Tenkan crosses above the Kijun and sells when it crosses below.
+	IchimokuTKCross IchimokuSignal = "tk_cross"
+	// IchimokuCloudBreakout buys when the close breaks above the cloud and sells when it breaks below.
+	IchimokuCloudBreakout IchimokuSignal = "cloud_breakout"
+	// IchimokuCloudColour buys when the leading cloud turns bullish, Senkou A crossing above Senkou B,
+	// and sells when it turns bearish.
+	IchimokuCloudColour IchimokuSignal = "cloud_colour"
+)
+
+type IchimokuMetrics struct {
+	SignalCounter monitor.CounterMetric
+	LineGauge     monitor.GaugeMetric
+	monitor       monitor.Monitoring
+}
+
+// IchimokuAdapter signals on one of the standard Ichimoku events. It only compares values known at the
+// current bar: the cloud under it is the one projected Displacement bars ago.
+type IchimokuAdapter struct {
+	Ichimoku       *indicator.Ichimoku
+	Signal         IchimokuSignal
+	PreviousValues indicator.IchimokuValues
+	// HasPrevious is set when PreviousValues hold the lines of the bar before the current one.
+	HasPrevious  bool
+	PreviousData model.DataPoint
+	CurrentData  model.DataPoint
+	swing        priceSwing
+	logger       logger.LoggerInterface
+	metrics      *IchimokuMetrics
+}
+
+func init() {
+	registry.RegisterAdaptor(registry.AdaptorEntry{
+		Name:        "ichimoku",
+		Description: "Signals on Ichimoku Tenkan/Kijun crosses, cloud breakouts or cloud colour changes",
+		Params: []registry.Param{
+			{Name: "tenkan", Type: registry.IntParam, Default: 9, Min: 1, Max: 1000},
+			{Name: "kijun", Type: registry.IntParam, Default: 26, Min: 1, Max: 1000},
+			{Name: "senkou_b", Type: registry.IntParam, Default: 52, Min: 1, Max: 1000},
+			{Name: "displacement", Type: registry.IntParam, Description: "Bars the Senkou spans are projected ahead", Default: 26, Min: 1, Max: 1000},
+			{Name: "signal", Type: registry.StringParam, Default: string(IchimokuTKCross), Choices: []string{string(IchimokuTKCross), string(IchimokuCloudBreakout), string(IchimokuCloudColour)}},
+		},
+		Validate: func(p registry.Params) error {
+			if p.Int("tenkan") >= p.Int("kijun") || p.Int("kijun") >= p.Int("senkou_b") {
+				return errors.New("tenkan, kijun and senkou_b must be increasing")
+			}
+			return nil
+		},
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (IndicatorAdaptor, error) {
+			return NewIchimokuAdapter(ctx, p.Int("tenkan"), p.Int("kijun"), p.Int("senkou_b"), p.Int("displacement"), IchimokuSignal(p.String("signal")), m)
+		},
+	})
+}
+
+func NewIchimokuAdapter(ctx context.Context, tenkanPeriod, kijunPeriod, senkouBPeriod, displacement int, signal IchimokuSignal, monitor monitor.Monitoring) (*IchimokuAdapter, error) {
+	switch signal {
+	case IchimokuTKCross, IchimokuCloudBreakout, IchimokuCloudColour:
+	default:
+		return nil, fmt.Errorf("unknown ichimoku signal %q", signal)
+	}
+	adapter := &IchimokuAdapter{
+		Ichimoku: indicator.NewIchimoku(tenkanPeriod, kijunPeriod, senkouBPeriod, displacement),
+		Signal:   signal,
+		logger:   logger.GetLogger(),
+	}
+	adapter.registerMetrics(ctx, monitor)
+	return adapter, nil
+}
+
+func (ia *IchimokuAdapter) registerMetrics(ctx context.Context, m monitor.Monitoring) {
+	ctx = ia.getUpdateContext(ctx)
+	ia.metrics = &IchimokuMetrics{
+		monitor:       m,
+		SignalCounter: m.RegisterCounter(ctx, "ichimoku_signals_generated", "Total number of Ichimoku signals generated", monitor.Labels{constraint.SIGNAL_TYPE_LABEL}),
+		LineGauge:     m.RegisterGauge(ctx, "ichimoku_lines", "Current Ichimoku lines", monitor.Labels{ICHIMOKU_LINE_LABEL}),
+	}
+}
+
+func (ia *IchimokuAdapter) Clone(ctx context.Context) IndicatorAdaptor {
+	ic := ia.Ichimoku
+	adapter, _ := NewIchimokuAdapter(ctx, ic.TenkanPeriod, ic.KijunPeriod, ic.SenkouBPeriod, ic.Displacement, ia.Signal, ia.metrics.monitor)
+	return adapter
+}
+
+func (ia *IchimokuAdapter) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	ctx = ia.getUpdateContext(ctx)
+	ia.logger.Debug(ctx, "Adding data point to IchimokuAdapter", zap.Int64("timestamp", data.Time))
+
+	previous, wasInitialized := ia.Ichimoku.Value(), ia.Ichimoku.Ready()
+	if err := ia.Ichimoku.Update(ctx, data); err != nil {
+		ia.logger.Error(ctx, "Failed to add data point to Ichimoku", zap.Error(err))
+		return err
+	}
+	ia.PreviousValues, ia.HasPrevious = previous, wasInitialized
+	ia.PreviousData, ia.CurrentData = ia.CurrentData, data
+	ia.swing.add(data)
+
+	if ia.Ichimoku.Ready() {
+		values := ia.Ichimoku.Value()
+		ia.metrics.LineGauge.SetGauge(ctx, values.Tenkan, monitor.NewTagsKV(ICHIMOKU_LINE_LABEL, "tenkan"))
+		ia.metrics.LineGauge.SetGauge(ctx, values.Kijun, monitor.NewTagsKV(ICHIMOKU_LINE_LABEL, "kijun"))
+		ia.metrics.LineGauge.SetGauge(ctx, values.SenkouA, monitor.NewTagsKV(ICHIMOKU_LINE_LABEL, "senkou_a"))
+		ia.metrics.LineGauge.SetGauge(ctx, values.SenkouB, monitor.NewTagsKV(ICHIMOKU_LINE_LABEL, "senkou_b"))
+	}
+	return nil
+}
+
+func (ia *IchimokuAdapter) Name() string {
+	ic := ia.Ichimoku
+	return fmt.Sprintf("Ichimoku_%d_%d_%d_%d_%s", ic.TenkanPeriod, ic.KijunPeriod, ic.SenkouBPeriod, ic.Displacement, ia.Signal)
+}
+
+func (ia *IchimokuAdapter) GetSignal(ctx context.Context) (result model.TradingSignal) {
+	ctx = ia.getUpdateContext(ctx)
+	defer func() {
+		tags := monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result.Action))
+		tags.Add(ADAPTOR_NAME_LABEL, ia.Name())
+		ia.metrics.SignalCounter.IncrementCounter(ctx, tags)
+	}()
+	result = model.TradingSignal{Time: ia.CurrentData.Time, Action: model.Wait}
+	zaps := []zap.Field{zap.String("adapter", ia.Name()), zap.Any("time", ia.CurrentData.Time)}
+
+	if !ia.HasPrevious {
+		ia.logger.Debug(ctx, "Ichimoku not initialized", zaps...)
+		return result
+	}
+
+	previous, current := ia.PreviousValues, ia.Ichimoku.Value()
+	price := ia.CurrentData.Close
+	switch ia.Signal {
+	case IchimokuTKCross:
+		if previous.Tenkan <= previous.Kijun && current.Tenkan > current.Kijun {
+			ia.logger.Info(ctx, "Buy signal detected", zaps...)
+			return ia.signal(model.Buy, ia.trendStrength(model.Buy), "Tenkan crossed above", "Kijun", []float64{current.Tenkan, current.Kijun})
+		}
+		if previous.Tenkan >= previous.Kijun && current.Tenkan < current.Kijun {
+			ia.logger.Info(ctx, "Sell signal detected", zaps...)
+			return ia.signal(model.Sell, ia.trendStrength(model.Sell), "Tenkan crossed below", "Kijun", []float64{current.Tenkan, current.Kijun})
+		}
+	case IchimokuCloudBreakout:
+		if ia.PreviousData.Close <= previous.CloudTop() && price > current.CloudTop() {
+			ia.logger.Info(ctx, "Buy signal detected", zaps...)
+			return ia.signal(model.Buy, ia.trendStrength(model.Buy), "closed above", "cloud", []float64{current.CloudTop(), price})
+		}
+		if ia.PreviousData.Close >= previous.CloudBottom() && price < current.CloudBottom() {
+			ia.logger.Info(ctx, "Sell signal detected", zaps...)
+			return ia.signal(model.Sell, ia.trendStrength(model.Sell), "closed below", "cloud", []float64{current.CloudBottom(), price})
+		}
+	case IchimokuCloudColour:
+		if previous.LeadingSenkouA <= previous.LeadingSenkouB && current.LeadingSenkouA > current.LeadingSenkouB {
+			ia.logger.Info(ctx, "Buy signal detected", zaps...)
+			return ia.signal(model.Buy, ia.trendStrength(model.Buy), "leading Senkou A crossed above", "Senkou B", []float64{current.LeadingSenkouA, current.LeadingSenkouB})
+		}
+		if previous.LeadingSenkouA >= previous.LeadingSenkouB && current.LeadingSenkouA < current.LeadingSenkouB {
+			ia.logger.Info(ctx, "Sell signal detected", zaps...)
+			return ia.signal(model.Sell, ia.trendStrength(model.Sell), "leading Senkou A crossed below", "Senkou B", []float64{current.LeadingSenkouA, current.LeadingSenkouB})
+		}
+	}
+
+	ia.logger.Debug(ctx, "No trading signal detected", zaps...)
+	return result
+}
+
+// trendStrength counts how many of the price against the cloud, the Chikou against the price it is plotted
+// at and the Kijun against the cloud confirm the action, from 0.5 with none to 1 with all three.
+func (ia *IchimokuAdapter) trendStrength(action model.StockAction) float64 {
+	values := ia.Ichimoku.Value()
+	price := ia.CurrentData.Close
+	var confirmations int
+	if action == model.Buy {
+		confirmations = countTrue(price > values.CloudTop(), values.Chikou > values.ChikouPrice, values.Kijun > values.CloudTop())
+	} else {
+		confirmations = countTrue(price < values.CloudBottom(), values.Chikou < values.ChikouPrice, values.Kijun < values.CloudBottom())
+	}
+	return 0.5 + float64(confirmations)/6
+}
+
+func countTrue(conditions ...bool) int {
+	count := 0
+	for _, condition := range conditions {
+		if condition {
+			count++
+		}
+	}
+	return count
+}
+
+// signal builds an Ichimoku signal with the stop loss behind the recent swing.
+func (ia *IchimokuAdapter) signal(action model.StockAction, strength float64, event, reference string, values []float64) model.TradingSignal {
+	stopLoss, target := ia.swing.exits(action, ia.CurrentData.Close)
+	return model.TradingSignal{
+		Time:     ia.CurrentData.Time,
+		Action:   action,
+		Strength: clampStrength(strength),
+		StopLoss: stopLoss,
+		Target:   target,
+		Rationale: []model.Rationale{{
+			Source:    ia.Name(),
+			Indicator: "Ichimoku",
+			Event:     event,
+			Reference: reference,
+			Values:    values,
+		}},
+	}
+}
+
+// IndicatorValues returns the latest Ichimoku lines and the cloud under the current bar.
+func (ia *IchimokuAdapter) IndicatorValues() map[string]float64 {
+	if !ia.Ichimoku.Ready() {
+		return nil
+	}
+	values := ia.Ichimoku.Value()
+	return map[string]float64{
+		"tenkan":           values.Tenkan,
+		"kijun":            values.Kijun,
+		"senkou_a":         values.SenkouA,
+		"senkou_b":         values.SenkouB,
+		"leading_senkou_a": values.LeadingSenkouA,
+		"leading_senkou_b": values.LeadingSenkouB,
+		"chikou":           values.Chikou,
+	}
+}
+
+// Function to retrieve and update the slice from context
+func (ia *IchimokuAdapter) getUpdateContext(ctx context.Context) context.Context {
+	ctx = getUpdatedCommonLabelsContext(ctx)
+	return context.WithValue(ctx, ADAPTOR_NAME_LABEL, ia.Name())
+}
+
+// ichimokuAdapterState is the saved state of an IchimokuAdapter.
+type ichimokuAdapterState struct {
+	Adaptor        string
+	Ichimoku       *indicator.Ichimoku
+	PreviousValues indicator.IchimokuValues
+	HasPrevious    bool
+	PreviousData   model.DataPoint
+	CurrentData    model.DataPoint
+	Swing          priceSwing
+}
+
+// MarshalState encodes the full state of the adapter.
+func (ia *IchimokuAdapter) MarshalState(format indicator.StateFormat) ([]byte, error) {
+	return indicator.EncodeState(format, ichimokuAdapterState{
+		Adaptor:        ia.Name(),
+		Ichimoku:       ia.Ichimoku,
+		PreviousValues: ia.PreviousValues,
+		HasPrevious:    ia.HasPrevious,
+		PreviousData:   ia.PreviousData,
+		CurrentData:    ia.CurrentData,
+		Swing:          ia.swing,
+	})
+}
+
+// UnmarshalState replaces the state of the adapter with one saved by an adapter with the same parameters.
+func (ia *IchimokuAdapter) UnmarshalState(format indicator.StateFormat, data []byte) error {
+	var state ichimokuAdapterState
+	if err := indicator.DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	if err := checkSavedState(state.Adaptor, ia.Name(), state.Ichimoku != nil); err != nil {
+		return err
+	}
+	ia.Ichimoku, ia.PreviousValues, ia.HasPrevious = state.Ichimoku, state.PreviousValues, state.HasPrevious
+	ia.PreviousData, ia.CurrentData, ia.swing = state.PreviousData, state.CurrentData, state.Swing
+	return nil
+}
@@ -0,0 +1,56 @@
+package indicator_adaptor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+// vShape falls by a point a bar for 20 bars, then rises by a point a bar for 20 bars.
+func vShape() []model.DataPoint {
+	var data []model.DataPoint
+	for i := 0; i < 40; i++ {
+		price := 100 - float64(i)
+		if i >= 20 {
+			price = 60 + float64(i)
+		}
+		data = append(data, model.DataPoint{Time: int64(i + 1), Open: price, High: price + 1, Low: price - 1, Close: price})
+	}
+	return data
+}
+
+func TestIchimokuAdapterSignals(t *testing.T) {
+	tests := []struct {
+		signal indicator_adaptor.IchimokuSignal
+		// want is the bar, counted from 1, of the only buy signal.
+		want int64
+	}{
+		{indicator_adaptor.IchimokuTKCross, 23},
+		{indicator_adaptor.IchimokuCloudBreakout, 23},
+		{indicator_adaptor.IchimokuCloudColour, 23},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		adapter, err := indicator_adaptor.NewIchimokuAdapter(ctx, 2, 3, 4, 2, tt.signal, test_utils.NewMockMetricsCollector(t))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var buys []int64
+		for _, dp := range vShape() {
+			adapter.AddDataPoint(ctx, dp)
+			signal := adapter.GetSignal(ctx)
+			test_utils.AssertTrue(t, signal.Action != model.Sell, string(tt.signal)+": the lines start below the cloud and should not sell")
+			if signal.Action == model.Buy {
+				buys = append(buys, signal.Time)
+			}
+		}
+		test_utils.AssertEqual(t, []int64{tt.want}, buys, string(tt.signal)+": buy bars do not match")
+	}
+
+	_, err := indicator_adaptor.NewIchimokuAdapter(ctx, 9, 26, 52, 26, "kumo_twist", test_utils.NewMockMetricsCollector(t))
+	test_utils.AssertTrue(t, err != nil, "Unknown signal should be rejected")
+}
@@ -0,0 +1,275 @@
+package indicator_adaptor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/constraint"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator"
+	"github.com/vd09/trading-algorithm-backtesting-system/logger"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
+	"go.uber.org/zap"
+)
+
+const (
+	KELTNER_CHANNEL_LABEL = "keltner_channel_type"
+)
+
+// KeltnerMode selects which price action around the channels the KeltnerAdapter signals on.
+type KeltnerMode string
+
+const (
+	// KeltnerBreakout buys when the close breaks above the upper channel and sells when it breaks below the lower one.
+	KeltnerBreakout KeltnerMode = "breakout"
+	// KeltnerSqueeze signals when Bollinger Bands of the same period, squeezed inside the channels, expand
+	// out of them again. It buys when the close is above the middle line and sells when it is below.
+	KeltnerSqueeze KeltnerMode = "squeeze"
+)
+
+type KeltnerMetrics struct {
+	SignalCounter monitor.CounterMetric
+	ChannelGauge  monitor.GaugeMetric
+	monitor       monitor.Monitoring
+}
+
+type KeltnerAdapter struct {
+	Keltner *indicator.KeltnerChannels
+	// Bollinger is only kept by KeltnerSqueeze.
+	Bollinger      *indicator.BollingerBands
+	Mode           KeltnerMode
+	PreviousValues indicator.KeltnerValues
+	PreviousData   model.DataPoint
+	// InSqueeze is set while the Bollinger Bands are inside the channels, Released on the bar they leave them.
+	InSqueeze   bool
+	Released    bool
+	CurrentData model.DataPoint
+	swing       priceSwing
+	logger      logger.LoggerInterface
+	metrics     *KeltnerMetrics
+}
+
+func init() {
+	registry.RegisterAdaptor(registry.AdaptorEntry{
+		Name:        "keltner",
+		Description: "Signals on Keltner Channels breakouts or when Bollinger Bands expand out of the channels",
+		Params: []registry.Param{
+			{Name: "period", Type: registry.IntParam, Description: "Bars of the EMA middle line", Default: 20, Min: 2, Max: 1000},
+			{Name: "atr_period", Type: registry.IntParam, Default: 10, Min: 1, Max: 1000},
+			{Name: "multiplier", Type: registry.FloatParam, Description: "Average true ranges between the middle line and the channels", Default: 2.0, Min: 0.1, Max: 10},
+			{Name: "mode", Type: registry.StringParam, Default: string(KeltnerBreakout), Choices: []string{string(KeltnerBreakout), string(KeltnerSqueeze)}},
+			{Name: "bollinger_multiplier", Type: registry.FloatParam, Description: "Standard deviations of the Bollinger Bands compared in the squeeze mode", Default: 2.0, Min: 0.1, Max: 10},
+		},
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (IndicatorAdaptor, error) {
+			return NewKeltnerAdapter(ctx, p.Int("period"), p.Int("atr_period"), p.Float("multiplier"), KeltnerMode(p.String("mode")), p.Float("bollinger_multiplier"), m)
+		},
+	})
+}
+
+// NewKeltnerAdapter creates a Keltner Channels adapter signalling in the given mode.
+// The Bollinger multiplier is only used by KeltnerSqueeze.
+func NewKeltnerAdapter(ctx context.Context, period, atrPeriod int, multiplier float64, mode KeltnerMode, bollingerMultiplier float64, monitor monitor.Monitoring) (*KeltnerAdapter, error) {
+	adapter := &KeltnerAdapter{
+		Keltner: indicator.NewKeltnerChannels(period, atrPeriod, multiplier),
+		Mode:    mode,
+		logger:  logger.GetLogger(),
+	}
+	switch mode {
+	case KeltnerBreakout:
+	case KeltnerSqueeze:
+		if period < 2 || bollingerMultiplier <= 0 {
+			return nil, fmt.Errorf("squeeze needs a period of at least 2 and a positive bollinger multiplier, got %d and %v", period, bollingerMultiplier)
+		}
+		adapter.Bollinger = indicator.NewBollingerBandsWithMultiplier(period, bollingerMultiplier)
+	default:
+		return nil, fmt.Errorf("unknown keltner mode %q", mode)
+	}
+	adapter.registerMetrics(ctx, monitor)
+	return adapter, nil
+}
+
+func (ka *KeltnerAdapter) registerMetrics(ctx context.Context, m monitor.Monitoring) {
+	ctx = ka.getUpdateContext(ctx)
+	ka.metrics = &KeltnerMetrics{
+		monitor:       m,
+		SignalCounter: m.RegisterCounter(ctx, "keltner_signals_generated", "Total number of Keltner Channels signals generated", monitor.Labels{constraint.SIGNAL_TYPE_LABEL}),
+		ChannelGauge:  m.RegisterGauge(ctx, "keltner_channels", "Current levels of the Keltner Channels", monitor.Labels{KELTNER_CHANNEL_LABEL}),
+	}
+}
+
+func (ka *KeltnerAdapter) Clone(ctx context.Context) IndicatorAdaptor {
+	adapter, _ := NewKeltnerAdapter(ctx, ka.Keltner.MovingAverage.Period, ka.Keltner.AverageTrueRange.Period, ka.Keltner.Multiplier, ka.Mode, ka.bollingerMultiplier(), ka.metrics.monitor)
+	return adapter
+}
+
+func (ka *KeltnerAdapter) bollingerMultiplier() float64 {
+	if ka.Bollinger == nil {
+		return 0
+	}
+	return ka.Bollinger.Multiplier
+}
+
+func (ka *KeltnerAdapter) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	ctx = ka.getUpdateContext(ctx)
+	ka.logger.Debug(ctx, "Adding data point to KeltnerAdapter", zap.Int64("timestamp", data.Time))
+
+	previousValues, wasInitialized := ka.Keltner.Value(), ka.Keltner.Ready()
+	if err := ka.Keltner.Update(ctx, data); err != nil {
+		ka.logger.Error(ctx, "Failed to add data point to Keltner Channels", zap.Error(err))
+		return err
+	}
+	if ka.Bollinger != nil {
+		if err := ka.Bollinger.Update(ctx, data); err != nil {
+			ka.logger.Error(ctx, "Failed to add data point to Bollinger Bands", zap.Error(err))
+			return err
+		}
+	}
+	if wasInitialized {
+		ka.PreviousValues, ka.PreviousData = previousValues, ka.CurrentData
+	}
+	ka.CurrentData = data
+	ka.swing.add(data)
+	if !ka.Keltner.Ready() {
+		return nil
+	}
+
+	channels := ka.Keltner.Value()
+	if ka.Bollinger != nil && ka.Bollinger.Ready() {
+		bands := ka.Bollinger.Value()
+		inSqueeze := bands.UpperBand < channels.Upper && bands.LowerBand > channels.Lower
+		ka.Released = ka.InSqueeze && !inSqueeze
+		ka.InSqueeze = inSqueeze
+	}
+	ka.metrics.ChannelGauge.SetGauge(ctx, channels.Upper, monitor.NewTagsKV(KELTNER_CHANNEL_LABEL, "upper"))
+	ka.metrics.ChannelGauge.SetGauge(ctx, channels.Middle, monitor.NewTagsKV(KELTNER_CHANNEL_LABEL, "middle"))
+	ka.metrics.ChannelGauge.SetGauge(ctx, channels.Lower, monitor.NewTagsKV(KELTNER_CHANNEL_LABEL, "lower"))
+	return nil
+}
+
+func (ka *KeltnerAdapter) Name() string {
+	name := fmt.Sprintf("Keltner_%d_%d_%.2f_%s", ka.Keltner.MovingAverage.Period, ka.Keltner.AverageTrueRange.Period, ka.Keltner.Multiplier, ka.Mode)
+	if ka.Mode == KeltnerSqueeze {
+		name += fmt.Sprintf("_%.2f", ka.bollingerMultiplier())
+	}
+	return name
+}
+
+func (ka *KeltnerAdapter) GetSignal(ctx context.Context) (result model.TradingSignal) {
+	ctx = ka.getUpdateContext(ctx)
+	defer func() {
+		tags := monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result.Action))
+		tags.Add(ADAPTOR_NAME_LABEL, ka.Name())
+		ka.metrics.SignalCounter.IncrementCounter(ctx, tags)
+	}()
+	result = model.TradingSignal{Time: ka.CurrentData.Time, Action: model.Wait}
+	zaps := []zap.Field{zap.String("adapter", ka.Name()), zap.Any("time", ka.CurrentData.Time)}
+
+	if ka.PreviousData.Time == 0 || !ka.Keltner.Ready() {
+		ka.logger.Debug(ctx, "Keltner Channels not initialized", zaps...)
+		return result
+	}
+
+	previous, current := ka.PreviousData.Close, ka.CurrentData.Close
+	channels := ka.Keltner.Value()
+	switch ka.Mode {
+	case KeltnerBreakout:
+		if previous <= ka.PreviousValues.Upper && current > channels.Upper {
+			ka.logger.Info(ctx, "Buy signal detected", zaps...)
+			return ka.signal(model.Buy, "closed above", "upper channel", current-channels.Upper)
+		}
+		if previous >= ka.PreviousValues.Lower && current < channels.Lower {
+			ka.logger.Info(ctx, "Sell signal detected", zaps...)
+			return ka.signal(model.Sell, "closed below", "lower channel", channels.Lower-current)
+		}
+	case KeltnerSqueeze:
+		if ka.Released && current > channels.Middle {
+			ka.logger.Info(ctx, "Buy signal detected", zaps...)
+			return ka.signal(model.Buy, "squeeze released above", "middle line", current-channels.Middle)
+		}
+		if ka.Released && current < channels.Middle {
+			ka.logger.Info(ctx, "Sell signal detected", zaps...)
+			return ka.signal(model.Sell, "squeeze released below", "middle line", channels.Middle-current)
+		}
+	}
+
+	ka.logger.Debug(ctx, "No trading signal detected", zaps...)
+	return result
+}
+
+// signal builds a channel signal with the stop loss at the middle line,
+// the further the close is past the reference the stronger the signal.
+func (ka *KeltnerAdapter) signal(action model.StockAction, event, reference string, gap float64) model.TradingSignal {
+	channels := ka.Keltner.Value()
+	return model.TradingSignal{
+		Time:     ka.CurrentData.Time,
+		Action:   action,
+		Strength: relativeStrength(gap, ka.CurrentData.Close),
+		StopLoss: channels.Middle,
+		Target:   rewardTarget(ka.CurrentData.Close, channels.Middle),
+		Rationale: []model.Rationale{{
+			Source:    ka.Name(),
+			Indicator: "Keltner Channels",
+			Event:     event,
+			Reference: reference,
+			Values:    []float64{ka.PreviousData.Close, ka.CurrentData.Close},
+		}},
+	}
+}
+
+// IndicatorValues returns the latest channels.
+func (ka *KeltnerAdapter) IndicatorValues() map[string]float64 {
+	if !ka.Keltner.Ready() {
+		return nil
+	}
+	channels := ka.Keltner.Value()
+	return map[string]float64{"keltner_upper": channels.Upper, "keltner_middle": channels.Middle, "keltner_lower": channels.Lower}
+}
+
+// Function to retrieve and update the slice from context
+func (ka *KeltnerAdapter) getUpdateContext(ctx context.Context) context.Context {
+	ctx = getUpdatedCommonLabelsContext(ctx)
+	return context.WithValue(ctx, ADAPTOR_NAME_LABEL, ka.Name())
+}
+
+// keltnerAdapterState is the saved state of a KeltnerAdapter.
+type keltnerAdapterState struct {
+	Adaptor        string
+	Keltner        *indicator.KeltnerChannels
+	Bollinger      *indicator.BollingerBands
+	PreviousValues indicator.KeltnerValues
+	PreviousData   model.DataPoint
+	InSqueeze      bool
+	Released       bool
+	CurrentData    model.DataPoint
+	Swing          priceSwing
+}
+
+// MarshalState encodes the full state of the adapter.
+func (ka *KeltnerAdapter) MarshalState(format indicator.StateFormat) ([]byte, error) {
+	return indicator.EncodeState(format, keltnerAdapterState{
+		Adaptor:        ka.Name(),
+		Keltner:        ka.Keltner,
+		Bollinger:      ka.Bollinger,
+		PreviousValues: ka.PreviousValues,
+		PreviousData:   ka.PreviousData,
+		InSqueeze:      ka.InSqueeze,
+		Released:       ka.Released,
+		CurrentData:    ka.CurrentData,
+		Swing:          ka.swing,
+	})
+}
+
+// UnmarshalState replaces the state of the adapter with one saved by an adapter with the same parameters.
+func (ka *KeltnerAdapter) UnmarshalState(format indicator.StateFormat, data []byte) error {
+	var state keltnerAdapterState
+	if err := indicator.DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	if err := checkSavedState(state.Adaptor, ka.Name(), state.Keltner != nil && (state.Bollinger != nil) == (ka.Bollinger != nil)); err != nil {
+		return err
+	}
+	ka.Keltner, ka.Bollinger, ka.PreviousValues, ka.PreviousData = state.Keltner, state.Bollinger, state.PreviousValues, state.PreviousData
+	ka.InSqueeze, ka.Released, ka.CurrentData, ka.swing = state.InSqueeze, state.Released, state.CurrentData, state.Swing
+	return nil
+}
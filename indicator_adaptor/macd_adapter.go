@@ -2,6 +2,7 @@ package indicator_adaptor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/vd09/trading-algorithm-backtesting-system/constraint"
@@ -9,6 +10,7 @@ import (
 	"github.com/vd09/trading-algorithm-backtesting-system/logger"
 	"github.com/vd09/trading-algorithm-backtesting-system/model"
 	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
 	"github.com/vd09/trading-algorithm-backtesting-system/utils"
 	"go.uber.org/zap"
 )
@@ -27,10 +29,33 @@ type MACDAdapter struct {
 	CurrentData            model.DataPoint
 	MaxTotalHistoricalData int
 	HistoricalValues       []indicator.MACDResult
+	swing                  priceSwing
 	logger                 logger.LoggerInterface
 	metrics                *MACDMetrics
 }
 
+func init() {
+	registry.RegisterAdaptor(registry.AdaptorEntry{
+		Name:        "macd",
+		Description: "Signals when the MACD line crosses its signal line",
+		Params: []registry.Param{
+			{Name: "short_period", Type: registry.IntParam, Default: 12, Min: 1, Max: 1000},
+			{Name: "long_period", Type: registry.IntParam, Default: 26, Min: 2, Max: 1000},
+			{Name: "signal_period", Type: registry.IntParam, Default: 9, Min: 1, Max: 1000},
+			{Name: "max_history", Type: registry.IntParam, Default: 10, Min: 2, Max: 100000},
+		},
+		Validate: func(p registry.Params) error {
+			if p.Int("short_period") >= p.Int("long_period") {
+				return errors.New("short_period must be lower than long_period")
+			}
+			return nil
+		},
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (IndicatorAdaptor, error) {
+			return NewMACDAdapter(ctx, p.Int("short_period"), p.Int("long_period"), p.Int("signal_period"), p.Int("max_history"), m), nil
+		},
+	})
+}
+
 // NewMACDAdapter initializes a new MACDAdapter instance.
 func NewMACDAdapter(ctx context.Context, shortPeriod, longPeriod, signalPeriod, maxTotalHistoricalData int, monitor monitor.Monitoring) *MACDAdapter {
 	macdAdapter := &MACDAdapter{
@@ -64,12 +89,13 @@ func (ma *MACDAdapter) AddDataPoint(ctx context.Context, data model.DataPoint) e
 	ma.logger.Debug(ctx, "Adding data point to MACDAdapter", zap.Int64("timestamp", data.Time))
 
 	ma.CurrentData = data
-	if err := ma.MACD.AddDataPoint(ctx, data); err != nil {
+	ma.swing.add(data)
+	if err := ma.MACD.Update(ctx, data); err != nil {
 		ma.logger.Error(ctx, "Failed to add data point to MACD", zap.Error(err))
 		return err
 	}
-	if ma.MACD.Initialized {
-		macdResult := ma.MACD.CalculateMACD()
+	if ma.MACD.Ready() {
+		macdResult := ma.MACD.Value()
 		ma.HistoricalValues = append(ma.HistoricalValues, macdResult)
 		if len(ma.HistoricalValues) > ma.MaxTotalHistoricalData {
 			ma.HistoricalValues = ma.HistoricalValues[1:]
@@ -88,34 +114,56 @@ func (ma *MACDAdapter) Name() string {
 }
 
 // GetSignal returns a trading signal based on the MACD logic.
-func (ma *MACDAdapter) GetSignal(ctx context.Context) (result model.StockAction) {
+func (ma *MACDAdapter) GetSignal(ctx context.Context) (result model.TradingSignal) {
 	ctx = ma.getUpdateContext(ctx)
 	defer func() {
-		tags := monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result))
+		tags := monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result.Action))
 		tags.Add(ADAPTOR_NAME_LABEL, ma.Name())
 		ma.metrics.SignalCounter.IncrementCounter(ctx, tags)
 	}()
+	result = model.TradingSignal{Time: ma.CurrentData.Time, Action: model.Wait}
 	zaps := []zap.Field{zap.String("adapter", ma.Name()), zap.Any("time", ma.CurrentData.Time)}
 
 	if len(ma.HistoricalValues) < 2 {
 		ma.logger.Debug(ctx, "Not enough historical data for signal generation", zaps...)
-		return model.Wait
+		return result
 	}
-	if !ma.MACD.Initialized {
+	if !ma.MACD.Ready() {
 		ma.logger.Debug(ctx, "MACD not initialized", zaps...)
-		return model.Wait
+		return result
 	}
 
 	if ma.checkForBuySignal() {
 		ma.logger.Info(ctx, "Buy signal detected", zaps...)
-		return model.Buy
+		return ma.signal(model.Buy, "crossed above")
 	} else if ma.checkForSellSignal() {
 		ma.logger.Info(ctx, "Sell signal detected", zaps...)
-		return model.Sell
+		return ma.signal(model.Sell, "crossed below")
 	}
 
 	ma.logger.Debug(ctx, "No trading signal detected", zaps...)
-	return model.Wait
+	return result
+}
+
+// signal builds a signal line crossover signal, its strength grows with the current histogram.
+func (ma *MACDAdapter) signal(action model.StockAction, event string) model.TradingSignal {
+	first := ma.HistoricalValues[0]
+	last := ma.HistoricalValues[len(ma.HistoricalValues)-1]
+	stopLoss, target := ma.swing.exits(action, ma.CurrentData.Close)
+	return model.TradingSignal{
+		Time:     ma.CurrentData.Time,
+		Action:   action,
+		Strength: relativeStrength(last.MACDLine-last.MACDSignal, ma.CurrentData.Close),
+		StopLoss: stopLoss,
+		Target:   target,
+		Rationale: []model.Rationale{{
+			Source:    ma.Name(),
+			Indicator: "MACD",
+			Event:     event,
+			Reference: "signal line",
+			Values:    []float64{first.MACDLine, last.MACDLine},
+		}},
+	}
 }
 
 func (ma *MACDAdapter) checkForBuySignal() bool {
@@ -150,8 +198,50 @@ func (ma *MACDAdapter) checkForSellSignal() bool {
 	return false
 }
 
+// IndicatorValues returns the latest MACD line, signal line and histogram.
+func (ma *MACDAdapter) IndicatorValues() map[string]float64 {
+	if len(ma.HistoricalValues) == 0 {
+		return nil
+	}
+	latest := ma.HistoricalValues[len(ma.HistoricalValues)-1]
+	return map[string]float64{"macd_line": latest.MACDLine, "macd_signal": latest.MACDSignal, "macd_histogram": latest.MACDHistogram}
+}
+
 // Function to retrieve and update the slice from context
 func (ma *MACDAdapter) getUpdateContext(ctx context.Context) context.Context {
 	ctx = getUpdatedCommonLabelsContext(ctx)
 	return context.WithValue(ctx, ADAPTOR_NAME_LABEL, ma.Name())
 }
+
+// macdAdapterState is the saved state of a MACDAdapter.
+type macdAdapterState struct {
+	Adaptor          string
+	MACD             *indicator.MACD
+	HistoricalValues []indicator.MACDResult
+	CurrentData      model.DataPoint
+	Swing            priceSwing
+}
+
+// MarshalState encodes the full state of the adapter.
+func (ma *MACDAdapter) MarshalState(format indicator.StateFormat) ([]byte, error) {
+	return indicator.EncodeState(format, macdAdapterState{
+		Adaptor:          ma.Name(),
+		MACD:             ma.MACD,
+		HistoricalValues: ma.HistoricalValues,
+		CurrentData:      ma.CurrentData,
+		Swing:            ma.swing,
+	})
+}
+
+// UnmarshalState replaces the state of the adapter with one saved by an adapter with the same parameters.
+func (ma *MACDAdapter) UnmarshalState(format indicator.StateFormat, data []byte) error {
+	var state macdAdapterState
+	if err := indicator.DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	if err := checkSavedState(state.Adaptor, ma.Name(), state.MACD != nil); err != nil {
+		return err
+	}
+	ma.MACD, ma.HistoricalValues, ma.CurrentData, ma.swing = state.MACD, state.HistoricalValues, state.CurrentData, state.Swing
+	return nil
+}
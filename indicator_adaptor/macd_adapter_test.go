@@ -110,7 +110,7 @@ func TestMACDGetSignal(t *testing.T) {
 	}
 
 	// Check the initial signal, should be Wait
-	signal := adapter.GetSignal(ctx)
+	signal := adapter.GetSignal(ctx).Action
 	if signal != model.Wait {
 		t.Errorf("expected signal %v, got %v", model.Wait, signal)
 	}
@@ -126,7 +126,7 @@ func TestMACDGetSignal(t *testing.T) {
 	adapter.HistoricalValues = append(adapter.HistoricalValues, indicator.MACDResult{
 		MACDLine: 25.0, MACDSignal: 20.0, MACDHistogram: 5.0,
 	})
-	signal = adapter.GetSignal(ctx)
+	signal = adapter.GetSignal(ctx).Action
 	if signal != model.Buy {
 		t.Errorf("expected signal %v, got %v", model.Buy, signal)
 	}
@@ -142,7 +142,7 @@ func TestMACDGetSignal(t *testing.T) {
 	adapter.HistoricalValues = append(adapter.HistoricalValues, indicator.MACDResult{
 		MACDLine: 20.0, MACDSignal: 25.0, MACDHistogram: 5.0,
 	})
-	signal = adapter.GetSignal(ctx)
+	signal = adapter.GetSignal(ctx).Action
 	if signal != model.Sell {
 		t.Errorf("expected signal %v, got %v", model.Sell, signal)
 	}
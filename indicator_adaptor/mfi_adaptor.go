@@ -0,0 +1,203 @@
+package indicator_adaptor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/constraint"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator"
+	"github.com/vd09/trading-algorithm-backtesting-system/logger"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
+	"go.uber.org/zap"
+)
+
+type MFIMetrics struct {
+	SignalCounter monitor.CounterMetric
+	ValueGauge    monitor.GaugeMetric
+	monitor       monitor.Monitoring
+}
+
+// MFIAdapter buys when the Money Flow Index climbs back out of the oversold zone and sells when it falls
+// back out of the overbought zone, e.g. 20 and 80.
+type MFIAdapter struct {
+	MoneyFlowIndex      *indicator.MoneyFlowIndex
+	OverboughtThreshold float64
+	OversoldThreshold   float64
+	PreviousValue       float64
+	// HasPrevious is set when PreviousValue holds the index of the bar before the current one.
+	HasPrevious bool
+	CurrentData model.DataPoint
+	swing       priceSwing
+	logger      logger.LoggerInterface
+	metrics     *MFIMetrics
+}
+
+func init() {
+	registry.RegisterAdaptor(registry.AdaptorEntry{
+		Name:        "mfi",
+		Description: "Buys when the Money Flow Index leaves the oversold zone and sells when it leaves the overbought zone",
+		Params: []registry.Param{
+			{Name: "period", Type: registry.IntParam, Default: 14, Min: 1, Max: 1000},
+			{Name: "overbought", Type: registry.FloatParam, Default: 80.0, Min: 1, Max: 99},
+			{Name: "oversold", Type: registry.FloatParam, Default: 20.0, Min: 1, Max: 99},
+		},
+		Validate: func(p registry.Params) error {
+			if p.Float("oversold") >= p.Float("overbought") {
+				return errors.New("oversold must be lower than overbought")
+			}
+			return nil
+		},
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (IndicatorAdaptor, error) {
+			return NewMFIAdapter(ctx, p.Int("period"), p.Float("overbought"), p.Float("oversold"), m), nil
+		},
+	})
+}
+
+func NewMFIAdapter(ctx context.Context, period int, overboughtThreshold, oversoldThreshold float64, monitor monitor.Monitoring) *MFIAdapter {
+	adapter := &MFIAdapter{
+		MoneyFlowIndex:      indicator.NewMoneyFlowIndex(period),
+		OverboughtThreshold: overboughtThreshold,
+		OversoldThreshold:   oversoldThreshold,
+		logger:              logger.GetLogger(),
+	}
+	adapter.registerMetrics(ctx, monitor)
+	return adapter
+}
+
+func (ma *MFIAdapter) registerMetrics(ctx context.Context, m monitor.Monitoring) {
+	ctx = ma.getUpdateContext(ctx)
+	ma.metrics = &MFIMetrics{
+		monitor:       m,
+		SignalCounter: m.RegisterCounter(ctx, "mfi_signals_generated", "Total number of Money Flow Index signals generated", monitor.Labels{constraint.SIGNAL_TYPE_LABEL}),
+		ValueGauge:    m.RegisterGauge(ctx, "mfi_value", "Current value of the Money Flow Index", nil),
+	}
+}
+
+func (ma *MFIAdapter) Clone(ctx context.Context) IndicatorAdaptor {
+	return NewMFIAdapter(ctx, ma.MoneyFlowIndex.Period, ma.OverboughtThreshold, ma.OversoldThreshold, ma.metrics.monitor)
+}
+
+func (ma *MFIAdapter) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	ctx = ma.getUpdateContext(ctx)
+	ma.logger.Debug(ctx, "Adding data point to MFIAdapter", zap.Int64("timestamp", data.Time))
+
+	previous, wasInitialized := ma.MoneyFlowIndex.Value(), ma.MoneyFlowIndex.Ready()
+	if err := ma.MoneyFlowIndex.Update(ctx, data); err != nil {
+		ma.logger.Error(ctx, "Failed to add data point to Money Flow Index", zap.Error(err))
+		return err
+	}
+	ma.CurrentData = data
+	ma.swing.add(data)
+	ma.PreviousValue, ma.HasPrevious = previous, wasInitialized
+
+	if ma.MoneyFlowIndex.Ready() {
+		ma.metrics.ValueGauge.SetGauge(ctx, ma.MoneyFlowIndex.Value(), nil)
+	}
+	return nil
+}
+
+func (ma *MFIAdapter) Name() string {
+	return fmt.Sprintf("MFI_%d_%.0f_%.0f", ma.MoneyFlowIndex.Period, ma.OverboughtThreshold, ma.OversoldThreshold)
+}
+
+func (ma *MFIAdapter) GetSignal(ctx context.Context) (result model.TradingSignal) {
+	ctx = ma.getUpdateContext(ctx)
+	defer func() {
+		tags := monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result.Action))
+		tags.Add(ADAPTOR_NAME_LABEL, ma.Name())
+		ma.metrics.SignalCounter.IncrementCounter(ctx, tags)
+	}()
+	result = model.TradingSignal{Time: ma.CurrentData.Time, Action: model.Wait}
+	zaps := []zap.Field{zap.String("adapter", ma.Name()), zap.Any("time", ma.CurrentData.Time)}
+
+	if !ma.HasPrevious {
+		ma.logger.Debug(ctx, "Not enough Money Flow Index values for signal generation", zaps...)
+		return result
+	}
+
+	previous, current := ma.PreviousValue, ma.MoneyFlowIndex.Value()
+	if previous < ma.OversoldThreshold && current >= ma.OversoldThreshold {
+		ma.logger.Info(ctx, "Buy signal detected", zaps...)
+		return ma.signal(model.Buy, (ma.OversoldThreshold-previous)/ma.OversoldThreshold, "crossed up through", ma.OversoldThreshold)
+	}
+	if previous > ma.OverboughtThreshold && current <= ma.OverboughtThreshold {
+		ma.logger.Info(ctx, "Sell signal detected", zaps...)
+		return ma.signal(model.Sell, (previous-ma.OverboughtThreshold)/(100-ma.OverboughtThreshold), "crossed down through", ma.OverboughtThreshold)
+	}
+
+	ma.logger.Debug(ctx, "No trading signal detected", zaps...)
+	return result
+}
+
+// signal builds a zone exit signal, the deeper the index went into the zone the stronger the signal.
+func (ma *MFIAdapter) signal(action model.StockAction, depth float64, event string, threshold float64) model.TradingSignal {
+	stopLoss, target := ma.swing.exits(action, ma.CurrentData.Close)
+	return model.TradingSignal{
+		Time:     ma.CurrentData.Time,
+		Action:   action,
+		Strength: clampStrength(0.5 + depth),
+		StopLoss: stopLoss,
+		Target:   target,
+		Rationale: []model.Rationale{{
+			Source:    ma.Name(),
+			Indicator: "MFI",
+			Event:     event,
+			Reference: strconv.FormatFloat(threshold, 'g', -1, 64),
+			Values:    []float64{ma.PreviousValue, ma.MoneyFlowIndex.Value()},
+		}},
+	}
+}
+
+// IndicatorValues returns the latest Money Flow Index.
+func (ma *MFIAdapter) IndicatorValues() map[string]float64 {
+	if !ma.MoneyFlowIndex.Ready() {
+		return nil
+	}
+	return map[string]float64{"mfi": ma.MoneyFlowIndex.Value()}
+}
+
+// Function to retrieve and update the slice from context
+func (ma *MFIAdapter) getUpdateContext(ctx context.Context) context.Context {
+	ctx = getUpdatedCommonLabelsContext(ctx)
+	return context.WithValue(ctx, ADAPTOR_NAME_LABEL, ma.Name())
+}
+
+// mfiAdapterState is the saved state of a MFIAdapter.
+type mfiAdapterState struct {
+	Adaptor        string
+	MoneyFlowIndex *indicator.MoneyFlowIndex
+	PreviousValue  float64
+	HasPrevious    bool
+	CurrentData    model.DataPoint
+	Swing          priceSwing
+}
+
+// MarshalState encodes the full state of the adapter.
+func (ma *MFIAdapter) MarshalState(format indicator.StateFormat) ([]byte, error) {
+	return indicator.EncodeState(format, mfiAdapterState{
+		Adaptor:        ma.Name(),
+		MoneyFlowIndex: ma.MoneyFlowIndex,
+		PreviousValue:  ma.PreviousValue,
+		HasPrevious:    ma.HasPrevious,
+		CurrentData:    ma.CurrentData,
+		Swing:          ma.swing,
+	})
+}
+
+// UnmarshalState replaces the state of the adapter with one saved by an adapter with the same parameters.
+func (ma *MFIAdapter) UnmarshalState(format indicator.StateFormat, data []byte) error {
+	var state mfiAdapterState
+	if err := indicator.DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	if err := checkSavedState(state.Adaptor, ma.Name(), state.MoneyFlowIndex != nil); err != nil {
+		return err
+	}
+	ma.MoneyFlowIndex, ma.PreviousValue, ma.HasPrevious = state.MoneyFlowIndex, state.PreviousValue, state.HasPrevious
+	ma.CurrentData, ma.swing = state.CurrentData, state.Swing
+	return nil
+}
@@ -0,0 +1,436 @@
+package indicator_adaptor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/constraint"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator"
+	"github.com/vd09/trading-algorithm-backtesting-system/logger"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
+	"go.uber.org/zap"
+)
+
+const (
+	MOMENTUM_OSCILLATOR_LABEL = "momentum_oscillator"
+)
+
+// MomentumOscillator selects the oscillator a MomentumAdapter follows.
+type MomentumOscillator string
+
+const (
+	CommodityChannelIndex MomentumOscillator = "cci"
+	RateOfChange          MomentumOscillator = "roc"
+	RawMomentum           MomentumOscillator = "momentum"
+	TRIX                  MomentumOscillator = "trix"
+	UltimateOscillator    MomentumOscillator = "ultimate_oscillator"
+)
+
+// MomentumCross selects the line whose crossing gives a MomentumAdapter signal.
+type MomentumCross string
+
+const (
+	// MomentumZeroLine buys when the oscillator crosses above its centre line and sells when it crosses below.
+	MomentumZeroLine MomentumCross = "zero_line"
+	// MomentumThreshold buys when the oscillator climbs back above the oversold threshold and sells when it
+	// falls back below the overbought threshold.
+	MomentumThreshold MomentumCross = "threshold"
+)
+
+type MomentumMetrics struct {
+	SignalCounter monitor.CounterMetric
+	ValueGauge    monitor.GaugeMetric
+	monitor       monitor.Monitoring
+}
+
+// MomentumAdapter trades the crosses of a momentum oscillator, the Commodity Channel Index, Rate of Change,
+// raw Momentum, TRIX or Ultimate Oscillator, through its centre line or its thresholds. The centre line is
+// 50 for the Ultimate Oscillator and 0 for the others.
+type MomentumAdapter struct {
+	Oscillator MomentumOscillator
+	// Periods holds the period of the oscillator, the short, medium and long ones for the Ultimate Oscillator.
+	Periods             []int
+	Cross               MomentumCross
+	OverboughtThreshold float64
+	OversoldThreshold   float64
+	CCI                 *indicator.CCI
+	RateOfChange        *indicator.RateOfChange
+	Momentum            *indicator.Momentum
+	TRIX                *indicator.TRIX
+	UltimateOscillator  *indicator.UltimateOscillator
+	PreviousValue       float64
+	// HasPrevious is set when PreviousValue holds the oscillator of the bar before the current one.
+	HasPrevious bool
+	CurrentData model.DataPoint
+	swing       priceSwing
+	logger      logger.LoggerInterface
+	metrics     *MomentumMetrics
+}
+
+func init() {
+	crosses := []string{string(MomentumZeroLine), string(MomentumThreshold)}
+	registry.RegisterAdaptor(registry.AdaptorEntry{
+		Name:        "cci",
+		Description: "Trades the Commodity Channel Index crossing zero or climbing back out of its ±100 zones",
+		Params: []registry.Param{
+			{Name: "period", Type: registry.IntParam, Default: 20, Min: 1, Max: 1000},
+			{Name: "cross", Type: registry.StringParam, Description: "Line whose crossing gives a signal", Default: string(MomentumThreshold), Choices: crosses},
+			{Name: "overbought", Type: registry.FloatParam, Description: "Level the index falls back below to sell, the oversold one is climbed back above to buy", Default: 100.0, Min: -1000, Max: 1000},
+			{Name: "oversold", Type: registry.FloatParam, Default: -100.0, Min: -1000, Max: 1000},
+		},
+		Validate: func(p registry.Params) error {
+			if p.Float("oversold") >= p.Float("overbought") {
+				return errors.New("oversold must be lower than overbought")
+			}
+			return nil
+		},
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (IndicatorAdaptor, error) {
+			return NewMomentumAdapter(ctx, CommodityChannelIndex, []int{p.Int("period")}, MomentumCross(p.String("cross")), p.Float("overbought"), p.Float("oversold"), m)
+		},
+	})
+	registry.RegisterAdaptor(registry.AdaptorEntry{
+		Name:        "roc",
+		Description: "Trades the percentage Rate of Change crossing zero or leaving its extreme zones",
+		Params: []registry.Param{
+			{Name: "period", Type: registry.IntParam, Default: 12, Min: 1, Max: 1000},
+			{Name: "cross", Type: registry.StringParam, Description: "Line whose crossing gives a signal", Default: string(MomentumZeroLine), Choices: crosses},
+			{Name: "overbought", Type: registry.FloatParam, Description: "Percentage change the rate falls back below to sell in the threshold cross", Default: 5.0, Min: -1000, Max: 1000},
+			{Name: "oversold", Type: registry.FloatParam, Default: -5.0, Min: -1000, Max: 1000},
+		},
+		Validate: func(p registry.Params) error {
+			if p.Float("oversold") >= p.Float("overbought") {
+				return errors.New("oversold must be lower than overbought")
+			}
+			return nil
+		},
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (IndicatorAdaptor, error) {
+			return NewMomentumAdapter(ctx, RateOfChange, []int{p.Int("period")}, MomentumCross(p.String("cross")), p.Float("overbought"), p.Float("oversold"), m)
+		},
+	})
+	registry.RegisterAdaptor(registry.AdaptorEntry{
+		Name:        "momentum",
+		Description: "Trades the raw price Momentum crossing zero or leaving its extreme zones",
+		Params: []registry.Param{
+			{Name: "period", Type: registry.IntParam, Default: 10, Min: 1, Max: 1000},
+			{Name: "cross", Type: registry.StringParam, Description: "Line whose crossing gives a signal", Default: string(MomentumZeroLine), Choices: crosses},
+			{Name: "overbought", Type: registry.FloatParam, Description: "Price change the momentum falls back below to sell in the threshold cross", Default: 2.0, Min: -1000, Max: 1000},
+			{Name: "oversold", Type: registry.FloatParam, Default: -2.0, Min: -1000, Max: 1000},
+		},
+		Validate: func(p registry.Params) error {
+			if p.Float("oversold") >= p.Float("overbought") {
+				return errors.New("oversold must be lower than overbought")
+			}
+			return nil
+		},
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (IndicatorAdaptor, error) {
+			return NewMomentumAdapter(ctx, RawMomentum, []int{p.Int("period")}, MomentumCross(p.String("cross")), p.Float("overbought"), p.Float("oversold"), m)
+		},
+	})
+	registry.RegisterAdaptor(registry.AdaptorEntry{
+		Name:        "trix",
+		Description: "Trades the TRIX, the rate of change of a triple smoothed EMA, crossing zero or leaving its extreme zones",
+		Params: []registry.Param{
+			{Name: "period", Type: registry.IntParam, Default: 15, Min: 1, Max: 1000},
+			{Name: "cross", Type: registry.StringParam, Description: "Line whose crossing gives a signal", Default: string(MomentumZeroLine), Choices: crosses},
+			{Name: "overbought", Type: registry.FloatParam, Description: "Percentage change the TRIX falls back below to sell in the threshold cross", Default: 0.1, Min: -1000, Max: 1000},
+			{Name: "oversold", Type: registry.FloatParam, Default: -0.1, Min: -1000, Max: 1000},
+		},
+		Validate: func(p registry.Params) error {
+			if p.Float("oversold") >= p.Float("overbought") {
+				return errors.New("oversold must be lower than overbought")
+			}
+			return nil
+		},
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (IndicatorAdaptor, error) {
+			return NewMomentumAdapter(ctx, TRIX, []int{p.Int("period")}, MomentumCross(p.String("cross")), p.Float("overbought"), p.Float("oversold"), m)
+		},
+	})
+	registry.RegisterAdaptor(registry.AdaptorEntry{
+		Name:        "ultimate_oscillator",
+		Description: "Trades the Ultimate Oscillator crossing 50 or climbing back out of its overbought and oversold zones",
+		Params: []registry.Param{
+			{Name: "short_period", Type: registry.IntParam, Default: 7, Min: 1, Max: 1000},
+			{Name: "medium_period", Type: registry.IntParam, Default: 14, Min: 1, Max: 1000},
+			{Name: "long_period", Type: registry.IntParam, Default: 28, Min: 1, Max: 1000},
+			{Name: "cross", Type: registry.StringParam, Description: "Line whose crossing gives a signal", Default: string(MomentumThreshold), Choices: crosses},
+			{Name: "overbought", Type: registry.FloatParam, Description: "Level the oscillator falls back below to sell, the oversold one is climbed back above to buy", Default: 70.0, Min: -1000, Max: 1000},
+			{Name: "oversold", Type: registry.FloatParam, Default: 30.0, Min: -1000, Max: 1000},
+		},
+		Validate: func(p registry.Params) error {
+			if p.Float("oversold") >= p.Float("overbought") {
+				return errors.New("oversold must be lower than overbought")
+			}
+			if p.Int("short_period") >= p.Int("medium_period") || p.Int("medium_period") >= p.Int("long_period") {
+				return errors.New("short, medium and long periods must be increasing")
+			}
+			return nil
+		},
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (IndicatorAdaptor, error) {
+			return NewMomentumAdapter(ctx, UltimateOscillator, []int{p.Int("short_period"), p.Int("medium_period"), p.Int("long_period")}, MomentumCross(p.String("cross")), p.Float("overbought"), p.Float("oversold"), m)
+		},
+	})
+}
+
+func NewMomentumAdapter(ctx context.Context, oscillator MomentumOscillator, periods []int, cross MomentumCross, overboughtThreshold, oversoldThreshold float64, monitor monitor.Monitoring) (*MomentumAdapter, error) {
+	adapter := &MomentumAdapter{
+		Oscillator:          oscillator,
+		Periods:             periods,
+		Cross:               cross,
+		OverboughtThreshold: overboughtThreshold,
+		OversoldThreshold:   oversoldThreshold,
+		logger:              logger.GetLogger(),
+	}
+	want := 1
+	if oscillator == UltimateOscillator {
+		want = 3
+	}
+	if len(periods) != want {
+		return nil, fmt.Errorf("%s needs %d periods, got %d", oscillator, want, len(periods))
+	}
+	for _, period := range periods {
+		if period < 1 {
+			return nil, fmt.Errorf("periods must be positive, got %v", periods)
+		}
+	}
+	switch oscillator {
+	case CommodityChannelIndex:
+		adapter.CCI = indicator.NewCCI(periods[0])
+	case RateOfChange:
+		adapter.RateOfChange = indicator.NewRateOfChange(periods[0])
+	case RawMomentum:
+		adapter.Momentum = indicator.NewMomentum(periods[0])
+	case TRIX:
+		adapter.TRIX = indicator.NewTRIX(periods[0])
+	case UltimateOscillator:
+		if periods[0] >= periods[1] || periods[1] >= periods[2] {
+			return nil, fmt.Errorf("ultimate oscillator periods must be increasing, got %v", periods)
+		}
+		adapter.UltimateOscillator = indicator.NewUltimateOscillator(periods[0], periods[1], periods[2])
+	default:
+		return nil, fmt.Errorf("unknown momentum oscillator %q", oscillator)
+	}
+	switch cross {
+	case MomentumZeroLine, MomentumThreshold:
+	default:
+		return nil, fmt.Errorf("unknown momentum cross %q", cross)
+	}
+	if centre := adapter.centre(); oversoldThreshold >= centre || overboughtThreshold <= centre {
+		return nil, fmt.Errorf("thresholds must surround the centre line %v, got %v and %v", centre, oversoldThreshold, overboughtThreshold)
+	}
+	adapter.registerMetrics(ctx, monitor)
+	return adapter, nil
+}
+
+func (ma *MomentumAdapter) registerMetrics(ctx context.Context, m monitor.Monitoring) {
+	ctx = ma.getUpdateContext(ctx)
+	ma.metrics = &MomentumMetrics{
+		monitor:       m,
+		SignalCounter: m.RegisterCounter(ctx, "momentum_signals_generated", "Total number of momentum oscillator signals generated", monitor.Labels{constraint.SIGNAL_TYPE_LABEL}),
+		ValueGauge:    m.RegisterGauge(ctx, "momentum_value", "Current value of the momentum oscillator", monitor.Labels{MOMENTUM_OSCILLATOR_LABEL}),
+	}
+}
+
+func (ma *MomentumAdapter) Clone(ctx context.Context) IndicatorAdaptor {
+	adapter, _ := NewMomentumAdapter(ctx, ma.Oscillator, ma.Periods, ma.Cross, ma.OverboughtThreshold, ma.OversoldThreshold, ma.metrics.monitor)
+	return adapter
+}
+
+func (ma *MomentumAdapter) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	ctx = ma.getUpdateContext(ctx)
+	ma.logger.Debug(ctx, "Adding data point to MomentumAdapter", zap.Int64("timestamp", data.Time))
+
+	previous, wasReady := ma.value()
+	var err error
+	switch {
+	case ma.CCI != nil:
+		err = ma.CCI.Update(ctx, data)
+	case ma.RateOfChange != nil:
+		err = ma.RateOfChange.Update(ctx, data)
+	case ma.Momentum != nil:
+		err = ma.Momentum.Update(ctx, data)
+	case ma.TRIX != nil:
+		err = ma.TRIX.Update(ctx, data)
+	default:
+		err = ma.UltimateOscillator.Update(ctx, data)
+	}
+	if err != nil {
+		ma.logger.Error(ctx, "Failed to add data point to momentum oscillator", zap.Error(err))
+		return err
+	}
+	ma.CurrentData = data
+	ma.swing.add(data)
+	ma.PreviousValue, ma.HasPrevious = previous, wasReady
+
+	if current, ready := ma.value(); ready {
+		ma.metrics.ValueGauge.SetGauge(ctx, current, monitor.NewTagsKV(MOMENTUM_OSCILLATOR_LABEL, string(ma.Oscillator)))
+	}
+	return nil
+}
+
+// value returns the latest oscillator and whether it is ready.
+func (ma *MomentumAdapter) value() (float64, bool) {
+	switch {
+	case ma.CCI != nil:
+		return ma.CCI.Value(), ma.CCI.Ready()
+	case ma.RateOfChange != nil:
+		return ma.RateOfChange.Value(), ma.RateOfChange.Ready()
+	case ma.Momentum != nil:
+		return ma.Momentum.Value(), ma.Momentum.Ready()
+	case ma.TRIX != nil:
+		return ma.TRIX.Value(), ma.TRIX.Ready()
+	default:
+		return ma.UltimateOscillator.Value(), ma.UltimateOscillator.Ready()
+	}
+}
+
+// CurrentValue returns the latest oscillator, zero before it is ready.
+func (ma *MomentumAdapter) CurrentValue() float64 {
+	current, _ := ma.value()
+	return current
+}
+
+// centre returns the centre line of the oscillator.
+func (ma *MomentumAdapter) centre() float64 {
+	if ma.Oscillator == UltimateOscillator {
+		return 50
+	}
+	return 0
+}
+
+func (ma *MomentumAdapter) Name() string {
+	periods := make([]string, len(ma.Periods))
+	for i, period := range ma.Periods {
+		periods[i] = strconv.Itoa(period)
+	}
+	return fmt.Sprintf("Momentum_%s_%s_%s_%g_%g", ma.Oscillator, strings.Join(periods, "_"), ma.Cross, ma.OverboughtThreshold, ma.OversoldThreshold)
+}
+
+func (ma *MomentumAdapter) GetSignal(ctx context.Context) (result model.TradingSignal) {
+	ctx = ma.getUpdateContext(ctx)
+	defer func() {
+		tags := monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result.Action))
+		tags.Add(ADAPTOR_NAME_LABEL, ma.Name())
+		ma.metrics.SignalCounter.IncrementCounter(ctx, tags)
+	}()
+	result = model.TradingSignal{Time: ma.CurrentData.Time, Action: model.Wait}
+	zaps := []zap.Field{zap.String("adapter", ma.Name()), zap.Any("time", ma.CurrentData.Time)}
+
+	if !ma.HasPrevious {
+		ma.logger.Debug(ctx, "Not enough momentum oscillator values for signal generation", zaps...)
+		return result
+	}
+
+	previous, current := ma.PreviousValue, ma.CurrentValue()
+	centre := ma.centre()
+	upper, lower := ma.OverboughtThreshold-centre, centre-ma.OversoldThreshold
+	if ma.Cross == MomentumZeroLine {
+		if previous <= centre && current > centre {
+			ma.logger.Info(ctx, "Buy signal detected", zaps...)
+			return ma.signal(model.Buy, (current-centre)/upper, "crossed above", centre)
+		}
+		if previous >= centre && current < centre {
+			ma.logger.Info(ctx, "Sell signal detected", zaps...)
+			return ma.signal(model.Sell, (centre-current)/lower, "crossed below", centre)
+		}
+	} else {
+		if previous < ma.OversoldThreshold && current >= ma.OversoldThreshold {
+			ma.logger.Info(ctx, "Buy signal detected", zaps...)
+			return ma.signal(model.Buy, (ma.OversoldThreshold-previous)/lower, "crossed up through", ma.OversoldThreshold)
+		}
+		if previous > ma.OverboughtThreshold && current <= ma.OverboughtThreshold {
+			ma.logger.Info(ctx, "Sell signal detected", zaps...)
+			return ma.signal(model.Sell, (previous-ma.OverboughtThreshold)/upper, "crossed down through", ma.OverboughtThreshold)
+		}
+	}
+
+	ma.logger.Debug(ctx, "No trading signal detected", zaps...)
+	return result
+}
+
+// signal builds a cross signal. The depth is how far the oscillator went past the crossed line, relative to
+// the distance between the centre line and the threshold on that side.
+func (ma *MomentumAdapter) signal(action model.StockAction, depth float64, event string, level float64) model.TradingSignal {
+	stopLoss, target := ma.swing.exits(action, ma.CurrentData.Close)
+	return model.TradingSignal{
+		Time:     ma.CurrentData.Time,
+		Action:   action,
+		Strength: clampStrength(0.5 + 0.5*math.Abs(depth)),
+		StopLoss: stopLoss,
+		Target:   target,
+		Rationale: []model.Rationale{{
+			Source:    ma.Name(),
+			Indicator: string(ma.Oscillator),
+			Event:     event,
+			Reference: strconv.FormatFloat(level, 'g', -1, 64),
+			Values:    []float64{ma.PreviousValue, ma.CurrentValue()},
+		}},
+	}
+}
+
+// IndicatorValues returns the latest oscillator.
+func (ma *MomentumAdapter) IndicatorValues() map[string]float64 {
+	current, ready := ma.value()
+	if !ready {
+		return nil
+	}
+	return map[string]float64{string(ma.Oscillator): current}
+}
+
+// Function to retrieve and update the slice from context
+func (ma *MomentumAdapter) getUpdateContext(ctx context.Context) context.Context {
+	ctx = getUpdatedCommonLabelsContext(ctx)
+	return context.WithValue(ctx, ADAPTOR_NAME_LABEL, ma.Name())
+}
+
+// momentumAdapterState is the saved state of a MomentumAdapter.
+type momentumAdapterState struct {
+	Adaptor            string
+	CCI                *indicator.CCI
+	RateOfChange       *indicator.RateOfChange
+	Momentum           *indicator.Momentum
+	TRIX               *indicator.TRIX
+	UltimateOscillator *indicator.UltimateOscillator
+	PreviousValue      float64
+	HasPrevious        bool
+	CurrentData        model.DataPoint
+	Swing              priceSwing
+}
+
+// MarshalState encodes the full state of the adapter.
+func (ma *MomentumAdapter) MarshalState(format indicator.StateFormat) ([]byte, error) {
+	return indicator.EncodeState(format, momentumAdapterState{
+		Adaptor:            ma.Name(),
+		CCI:                ma.CCI,
+		RateOfChange:       ma.RateOfChange,
+		Momentum:           ma.Momentum,
+		TRIX:               ma.TRIX,
+		UltimateOscillator: ma.UltimateOscillator,
+		PreviousValue:      ma.PreviousValue,
+		HasPrevious:        ma.HasPrevious,
+		CurrentData:        ma.CurrentData,
+		Swing:              ma.swing,
+	})
+}
+
+// UnmarshalState replaces the state of the adapter with one saved by an adapter with the same parameters.
+func (ma *MomentumAdapter) UnmarshalState(format indicator.StateFormat, data []byte) error {
+	var state momentumAdapterState
+	if err := indicator.DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	complete := (state.CCI != nil) == (ma.CCI != nil) && (state.RateOfChange != nil) == (ma.RateOfChange != nil) &&
+		(state.Momentum != nil) == (ma.Momentum != nil) && (state.TRIX != nil) == (ma.TRIX != nil) &&
+		(state.UltimateOscillator != nil) == (ma.UltimateOscillator != nil)
+	if err := checkSavedState(state.Adaptor, ma.Name(), complete); err != nil {
+		return err
+	}
+	ma.CCI, ma.RateOfChange, ma.Momentum, ma.TRIX, ma.UltimateOscillator = state.CCI, state.RateOfChange, state.Momentum, state.TRIX, state.UltimateOscillator
+	ma.PreviousValue, ma.HasPrevious, ma.CurrentData, ma.swing = state.PreviousValue, state.HasPrevious, state.CurrentData, state.Swing
+	return nil
+}
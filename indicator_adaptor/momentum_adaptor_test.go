@@ -0,0 +1,92 @@
+package indicator_adaptor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+// vBottomData falls by 1 a bar for 15 bars closing on the lows, then rises by 2 a bar closing on the highs.
+func vBottomData() []model.DataPoint {
+	var data []model.DataPoint
+	for i := 1; i <= 30; i++ {
+		mid, open, close := 120-float64(i), 121-float64(i), 119-float64(i)
+		if i > 15 {
+			mid = 105 + float64(i-15)*2
+			open, close = mid-1, mid+1
+		}
+		data = append(data, model.DataPoint{Time: int64(i), Open: open, High: mid + 1, Low: mid - 1, Close: close})
+	}
+	return data
+}
+
+func TestMomentumAdapterCrosses(t *testing.T) {
+	tests := []struct {
+		oscillator indicator_adaptor.MomentumOscillator
+		periods    []int
+		cross      indicator_adaptor.MomentumCross
+		overbought float64
+		oversold   float64
+	}{
+		{indicator_adaptor.CommodityChannelIndex, []int{5}, indicator_adaptor.MomentumZeroLine, 100, -100},
+		{indicator_adaptor.CommodityChannelIndex, []int{5}, indicator_adaptor.MomentumThreshold, 100, -100},
+		{indicator_adaptor.RateOfChange, []int{3}, indicator_adaptor.MomentumZeroLine, 2, -2},
+		{indicator_adaptor.RateOfChange, []int{3}, indicator_adaptor.MomentumThreshold, 2, -2},
+		{indicator_adaptor.RawMomentum, []int{3}, indicator_adaptor.MomentumZeroLine, 2, -2},
+		{indicator_adaptor.TRIX, []int{3}, indicator_adaptor.MomentumZeroLine, 0.1, -0.1},
+		{indicator_adaptor.UltimateOscillator, []int{2, 4, 8}, indicator_adaptor.MomentumZeroLine, 70, 30},
+		{indicator_adaptor.UltimateOscillator, []int{2, 4, 8}, indicator_adaptor.MomentumThreshold, 70, 30},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		for _, want := range []model.StockAction{model.Buy, model.Sell} {
+			data := vBottomData()
+			if want == model.Sell {
+				data = mirror(data)
+			}
+			adapter, err := indicator_adaptor.NewMomentumAdapter(ctx, tt.oscillator, tt.periods, tt.cross, tt.overbought, tt.oversold, test_utils.NewMockMetricsCollector(t))
+			if err != nil {
+				t.Fatalf("%s %s: unexpected error: %v", tt.oscillator, tt.cross, err)
+			}
+			name := adapter.Name() + " " + string(want)
+
+			signals := signalBars(t, adapter, data)
+			test_utils.AssertEqual(t, 1, len(signals), name+": should signal once")
+			for time, signal := range signals {
+				test_utils.AssertEqual(t, want, signal.Action, name+": signal does not match")
+				test_utils.AssertTrue(t, time > 15, name+": should signal after the turn")
+				test_utils.AssertTrue(t, signal.Strength >= 0.5 && signal.Strength <= 1, name+": strength out of range")
+				test_utils.AssertEqual(t, string(tt.oscillator), signal.Rationale[0].Indicator, name+": rationale should name the oscillator")
+			}
+			test_utils.AssertTrue(t, adapter.IndicatorValues()[string(tt.oscillator)] == adapter.CurrentValue(), name+": indicator values should hold the oscillator")
+		}
+	}
+}
+
+func TestMomentumAdapterRejectsInvalidParameters(t *testing.T) {
+	tests := []struct {
+		name       string
+		oscillator indicator_adaptor.MomentumOscillator
+		periods    []int
+		cross      indicator_adaptor.MomentumCross
+		overbought float64
+		oversold   float64
+	}{
+		{"unknown oscillator", "kst", []int{10}, indicator_adaptor.MomentumZeroLine, 1, -1},
+		{"unknown cross", indicator_adaptor.RawMomentum, []int{10}, "signal_line", 1, -1},
+		{"missing periods", indicator_adaptor.UltimateOscillator, []int{7}, indicator_adaptor.MomentumZeroLine, 70, 30},
+		{"decreasing periods", indicator_adaptor.UltimateOscillator, []int{28, 14, 7}, indicator_adaptor.MomentumZeroLine, 70, 30},
+		{"zero period", indicator_adaptor.CommodityChannelIndex, []int{0}, indicator_adaptor.MomentumZeroLine, 100, -100},
+		{"thresholds beside the centre", indicator_adaptor.UltimateOscillator, []int{7, 14, 28}, indicator_adaptor.MomentumThreshold, 20, 10},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		_, err := indicator_adaptor.NewMomentumAdapter(ctx, tt.oscillator, tt.periods, tt.cross, tt.overbought, tt.oversold, test_utils.NewMockMetricsCollector(t))
+		test_utils.AssertTrue(t, err != nil, tt.name+": expected an error")
+	}
+}
@@ -0,0 +1,282 @@
+package indicator_adaptor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/constraint"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator"
+	"github.com/vd09/trading-algorithm-backtesting-system/logger"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	MOVING_AVERAGE_LABEL = "moving_average"
+)
+
+// MovingAverageLine is one line of a MovingAverageAdapter, e.g. the 9 bar Hull moving average.
+type MovingAverageLine struct {
+	Type   indicator.MovingAverageType
+	Period int
+}
+
+// String returns the line as e.g. "HMA(9)".
+func (l MovingAverageLine) String() string {
+	return fmt.Sprintf("%s(%d)", strings.ToUpper(string(l.Type)), l.Period)
+}
+
+type MovingAverageMetrics struct {
+	SignalCounter monitor.CounterMetric
+	AverageGauge  monitor.GaugeMetric
+	monitor       monitor.Monitoring
+}
+
+// MovingAverageAdapter generalises the EMAAdapter to moving averages of any kind: it buys when the first line
+// crosses above all the others within the latest MaxTotalHistoricalData bars, e.g. HMA(9) over SMA(50), and
+// sells when it crosses below them.
+type MovingAverageAdapter struct {
+	Lines                  []MovingAverageLine
+	Averages               []indicator.MovingAverage
+	MaxTotalHistoricalData int
+	// HistoricalValues holds the latest values of every line since it is ready, in the order of Lines.
+	HistoricalValues [][]float64
+	CurrentData      model.DataPoint
+	swing            priceSwing
+	logger           logger.LoggerInterface
+	metrics          *MovingAverageMetrics
+}
+
+func init() {
+	registry.RegisterAdaptor(registry.AdaptorEntry{
+		Name:        "moving_average",
+		Description: "Signals when the first moving average crosses the others, e.g. HMA(9) over SMA(50)",
+		Params: []registry.Param{
+			{Name: "types", Type: registry.StringListParam, Description: "Kind of every line: sma, ema, wma, hma, dema, tema, kama or alma", Required: true},
+			{Name: "periods", Type: registry.IntListParam, Description: "Period of every line, the first line is the one crossing the others", Required: true, Min: 1, Max: 1000},
+			{Name: "max_history", Type: registry.IntParam, Default: 10, Min: 2, Max: 100000},
+		},
+		Validate: func(p registry.Params) error {
+			if len(p.Strings("types")) != len(p.Ints("periods")) {
+				return errors.New("types and periods must have the same length")
+			}
+			if len(p.Ints("periods")) < 2 {
+				return errors.New("at least two lines are needed")
+			}
+			for _, kind := range p.Strings("types") {
+				if _, err := indicator.NewMovingAverage(indicator.MovingAverageType(kind), 1); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (IndicatorAdaptor, error) {
+			lines := make([]MovingAverageLine, len(p.Ints("periods")))
+			for i, period := range p.Ints("periods") {
+				lines[i] = MovingAverageLine{Type: indicator.MovingAverageType(p.Strings("types")[i]), Period: period}
+			}
+			return NewMovingAverageAdapter(ctx, lines, p.Int("max_history"), m)
+		},
+	})
+}
+
+func NewMovingAverageAdapter(ctx context.Context, lines []MovingAverageLine, maxTotalHistoricalData int, monitor monitor.Monitoring) (*MovingAverageAdapter, error) {
+	if len(lines) < 2 {
+		return nil, errors.New("a moving average crossover needs at least two lines")
+	}
+	if maxTotalHistoricalData < 2 {
+		return nil, fmt.Errorf("max history must be at least 2, got %d", maxTotalHistoricalData)
+	}
+	adapter := &MovingAverageAdapter{
+		Lines:                  lines,
+		Averages:               make([]indicator.MovingAverage, len(lines)),
+		MaxTotalHistoricalData: maxTotalHistoricalData,
+		HistoricalValues:       make([][]float64, len(lines)),
+		logger:                 logger.GetLogger(),
+	}
+	for i, line := range lines {
+		average, err := indicator.NewMovingAverage(line.Type, line.Period)
+		if err != nil {
+			return nil, err
+		}
+		adapter.Averages[i] = average
+	}
+	adapter.registerMetrics(ctx, monitor)
+	return adapter, nil
+}
+
+func (ma *MovingAverageAdapter) registerMetrics(ctx context.Context, m monitor.Monitoring) {
+	ctx = ma.getUpdateContext(ctx)
+	ma.metrics = &MovingAverageMetrics{
+		monitor:       m,
+		SignalCounter: m.RegisterCounter(ctx, "moving_average_signals_generated", "Total number of moving average crossover signals generated", monitor.Labels{constraint.SIGNAL_TYPE_LABEL}),
+		AverageGauge:  m.RegisterGauge(ctx, "moving_average", "Current value of the moving averages", monitor.Labels{MOVING_AVERAGE_LABEL}),
+	}
+}
+
+func (ma *MovingAverageAdapter) Clone(ctx context.Context) IndicatorAdaptor {
+	adapter, _ := NewMovingAverageAdapter(ctx, ma.Lines, ma.MaxTotalHistoricalData, ma.metrics.monitor)
+	return adapter
+}
+
+func (ma *MovingAverageAdapter) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	ctx = ma.getUpdateContext(ctx)
+	ma.logger.Debug(ctx, "Adding data point to MovingAverageAdapter", zap.Int64("timestamp", data.Time))
+
+	for _, average := range ma.Averages {
+		if err := average.Update(ctx, data); err != nil {
+			ma.logger.Error(ctx, "Failed to add data point to moving average", zap.Error(err))
+			return err
+		}
+	}
+	ma.CurrentData = data
+	ma.swing.add(data)
+	for i, average := range ma.Averages {
+		if average.Ready() {
+			ma.metrics.AverageGauge.SetGauge(ctx, average.Value(), monitor.NewTagsKV(MOVING_AVERAGE_LABEL, ma.Lines[i].String()))
+			ma.HistoricalValues[i] = utils.AppendWindow(ma.HistoricalValues[i], average.Value(), ma.MaxTotalHistoricalData)
+		}
+	}
+	return nil
+}
+
+func (ma *MovingAverageAdapter) Name() string {
+	parts := make([]string, len(ma.Lines))
+	for i, line := range ma.Lines {
+		parts[i] = fmt.Sprintf("%s_%d", line.Type, line.Period)
+	}
+	return "MA_" + strings.Join(parts, "_")
+}
+
+func (ma *MovingAverageAdapter) GetSignal(ctx context.Context) (result model.TradingSignal) {
+	ctx = ma.getUpdateContext(ctx)
+	defer func() {
+		tags := monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result.Action))
+		tags.Add(ADAPTOR_NAME_LABEL, ma.Name())
+		ma.metrics.SignalCounter.IncrementCounter(ctx, tags)
+	}()
+	result = model.TradingSignal{Time: ma.CurrentData.Time, Action: model.Wait}
+	zaps := []zap.Field{zap.String("adapter", ma.Name()), zap.Any("time", ma.CurrentData.Time)}
+
+	for _, values := range ma.HistoricalValues {
+		if len(values) < 2 {
+			ma.logger.Debug(ctx, "Not enough moving average values for signal generation", zaps...)
+			return result
+		}
+	}
+
+	fastest, others := ma.HistoricalValues[0], ma.HistoricalValues[1:]
+	if crossedLines(model.Buy, fastest, others...) {
+		ma.logger.Info(ctx, "Buy signal detected", zaps...)
+		return ma.signal(model.Buy, "crossed above")
+	}
+	if crossedLines(model.Sell, fastest, others...) {
+		ma.logger.Info(ctx, "Sell signal detected", zaps...)
+		return ma.signal(model.Sell, "crossed below")
+	}
+
+	ma.logger.Debug(ctx, "No trading signal detected", zaps...)
+	return result
+}
+
+// signal builds a crossover signal, its strength grows with the gap between the first and the last line.
+func (ma *MovingAverageAdapter) signal(action model.StockAction, event string) model.TradingSignal {
+	fastest := ma.HistoricalValues[0]
+	slowest := ma.HistoricalValues[len(ma.HistoricalValues)-1]
+	stopLoss, target := ma.swing.exits(action, ma.CurrentData.Close)
+
+	signal := model.TradingSignal{
+		Time:     ma.CurrentData.Time,
+		Action:   action,
+		Strength: relativeStrength(fastest[len(fastest)-1]-slowest[len(slowest)-1], ma.CurrentData.Close),
+		StopLoss: stopLoss,
+		Target:   target,
+	}
+	for _, line := range ma.Lines[1:] {
+		signal.Rationale = append(signal.Rationale, model.Rationale{
+			Source:    ma.Name(),
+			Indicator: ma.Lines[0].String(),
+			Event:     event,
+			Reference: line.String(),
+			Values:    []float64{fastest[0], fastest[len(fastest)-1]},
+		})
+	}
+	return signal
+}
+
+// IndicatorValues returns the latest value of every ready line, e.g. "hma_9".
+func (ma *MovingAverageAdapter) IndicatorValues() map[string]float64 {
+	values := make(map[string]float64, len(ma.Averages))
+	for i, average := range ma.Averages {
+		if average.Ready() {
+			values[fmt.Sprintf("%s_%d", ma.Lines[i].Type, ma.Lines[i].Period)] = average.Value()
+		}
+	}
+	return values
+}
+
+// Function to retrieve and update the slice from context
+func (ma *MovingAverageAdapter) getUpdateContext(ctx context.Context) context.Context {
+	ctx = getUpdatedCommonLabelsContext(ctx)
+	return context.WithValue(ctx, ADAPTOR_NAME_LABEL, ma.Name())
+}
+
+// movingAverageAdapterState is the saved state of a MovingAverageAdapter. The averages are saved in the same
+// format by their own MarshalState, as their types differ.
+type movingAverageAdapterState struct {
+	Adaptor          string
+	Averages         [][]byte
+	HistoricalValues [][]float64
+	CurrentData      model.DataPoint
+	Swing            priceSwing
+}
+
+// MarshalState encodes the full state of the adapter.
+func (ma *MovingAverageAdapter) MarshalState(format indicator.StateFormat) ([]byte, error) {
+	averages := make([][]byte, len(ma.Averages))
+	for i, average := range ma.Averages {
+		saved, err := average.MarshalState(format)
+		if err != nil {
+			return nil, err
+		}
+		averages[i] = saved
+	}
+	return indicator.EncodeState(format, movingAverageAdapterState{
+		Adaptor:          ma.Name(),
+		Averages:         averages,
+		HistoricalValues: ma.HistoricalValues,
+		CurrentData:      ma.CurrentData,
+		Swing:            ma.swing,
+	})
+}
+
+// UnmarshalState replaces the state of the adapter with one saved by an adapter with the same parameters.
+func (ma *MovingAverageAdapter) UnmarshalState(format indicator.StateFormat, data []byte) error {
+	var state movingAverageAdapterState
+	if err := indicator.DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	if err := checkSavedState(state.Adaptor, ma.Name(), len(state.Averages) == len(ma.Lines)); err != nil {
+		return err
+	}
+	averages := make([]indicator.MovingAverage, len(ma.Lines))
+	for i, line := range ma.Lines {
+		average, err := indicator.NewMovingAverage(line.Type, line.Period)
+		if err != nil {
+			return err
+		}
+		if err := average.UnmarshalState(format, state.Averages[i]); err != nil {
+			return err
+		}
+		averages[i] = average
+	}
+	historicalValues := make([][]float64, len(ma.Lines))
+	copy(historicalValues, state.HistoricalValues)
+	ma.Averages, ma.HistoricalValues, ma.CurrentData, ma.swing = averages, historicalValues, state.CurrentData, state.Swing
+	return nil
+}
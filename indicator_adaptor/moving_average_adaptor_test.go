@@ -0,0 +1,72 @@
+package indicator_adaptor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+func TestMovingAverageAdapterMixedCrossover(t *testing.T) {
+	lines := []indicator_adaptor.MovingAverageLine{{Type: indicator.HMAType, Period: 4}, {Type: indicator.SMAType, Period: 10}}
+
+	ctx := context.Background()
+	for _, want := range []model.StockAction{model.Buy, model.Sell} {
+		data := vBottomData()
+		if want == model.Sell {
+			data = mirror(data)
+		}
+		adapter, err := indicator_adaptor.NewMovingAverageAdapter(ctx, lines, 2, test_utils.NewMockMetricsCollector(t))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		test_utils.AssertEqual(t, "MA_hma_4_sma_10", adapter.Name(), "name does not match")
+
+		signals := signalBars(t, adapter, data)
+		test_utils.AssertEqual(t, 1, len(signals), string(want)+": the HMA should cross the SMA once")
+		for time, signal := range signals {
+			test_utils.AssertEqual(t, want, signal.Action, string(want)+": signal does not match")
+			test_utils.AssertTrue(t, time > 15, string(want)+": should signal after the turn")
+			test_utils.AssertEqual(t, "HMA(4)", signal.Rationale[0].Indicator, string(want)+": rationale should name the fast line")
+			test_utils.AssertEqual(t, "SMA(10)", signal.Rationale[0].Reference, string(want)+": rationale should name the slow line")
+		}
+		test_utils.AssertEqual(t, 2, len(adapter.IndicatorValues()), string(want)+": both lines should be reported")
+	}
+}
+
+func TestMovingAverageAdapterMatchesEMAAdapter(t *testing.T) {
+	lines := []indicator_adaptor.MovingAverageLine{{Type: indicator.EMAType, Period: 3}, {Type: indicator.EMAType, Period: 8}}
+
+	mock := test_utils.NewMockMetricsCollector(t)
+	adapter, err := indicator_adaptor.NewMovingAverageAdapter(context.Background(), lines, 5, mock)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data := append(vBottomData(), mirror(vBottomData())...)
+	for i := range data {
+		data[i].Time = int64(i + 1)
+	}
+	want := signalBars(t, indicator_adaptor.NewEMAAdapter(context.Background(), []int{3, 8}, 5, mock), data)
+	got := signalBars(t, adapter, data)
+	test_utils.AssertTrue(t, len(want) > 0, "the EMAs should cross")
+	test_utils.AssertEqual(t, len(want), len(got), "an EMA crossover should signal like the EMAAdapter")
+	for time, signal := range want {
+		test_utils.AssertEqual(t, signal.Action, got[time].Action, "an EMA crossover should signal like the EMAAdapter")
+	}
+}
+
+func TestMovingAverageAdapterRejectsInvalidLines(t *testing.T) {
+	tests := map[string][]indicator_adaptor.MovingAverageLine{
+		"single line":  {{Type: indicator.SMAType, Period: 10}},
+		"unknown type": {{Type: "vwma", Period: 10}, {Type: indicator.SMAType, Period: 20}},
+		"zero period":  {{Type: indicator.HMAType, Period: 0}, {Type: indicator.SMAType, Period: 20}},
+	}
+
+	for name, lines := range tests {
+		_, err := indicator_adaptor.NewMovingAverageAdapter(context.Background(), lines, 10, test_utils.NewMockMetricsCollector(t))
+		test_utils.AssertTrue(t, err != nil, name+": expected an error")
+	}
+}
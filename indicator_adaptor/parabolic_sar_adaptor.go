@@ -0,0 +1,175 @@
+package indicator_adaptor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/constraint"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator"
+	"github.com/vd09/trading-algorithm-backtesting-system/logger"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
+	"go.uber.org/zap"
+)
+
+type ParabolicSARMetrics struct {
+	SignalCounter monitor.CounterMetric
+	SARGauge      monitor.GaugeMetric
+	monitor       monitor.Monitoring
+}
+
+// ParabolicSARAdapter buys when the Parabolic SAR flips below the price and sells when it flips above,
+// with the stop loss at the new SAR.
+type ParabolicSARAdapter struct {
+	SAR         *indicator.ParabolicSAR
+	CurrentData model.DataPoint
+	logger      logger.LoggerInterface
+	metrics     *ParabolicSARMetrics
+}
+
+func init() {
+	registry.RegisterAdaptor(registry.AdaptorEntry{
+		Name:        "parabolic_sar",
+		Description: "Signals when the Parabolic SAR flips to the other side of the price",
+		Params: []registry.Param{
+			{Name: "step", Type: registry.FloatParam, Description: "Acceleration added on every new extreme point", Default: 0.02, Min: 0.001, Max: 1},
+			{Name: "maximum", Type: registry.FloatParam, Description: "Largest acceleration", Default: 0.2, Min: 0.001, Max: 1},
+		},
+		Validate: func(p registry.Params) error {
+			if p.Float("step") > p.Float("maximum") {
+				return errors.New("step must not be larger than maximum")
+			}
+			return nil
+		},
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (IndicatorAdaptor, error) {
+			return NewParabolicSARAdapter(ctx, p.Float("step"), p.Float("maximum"), m), nil
+		},
+	})
+}
+
+func NewParabolicSARAdapter(ctx context.Context, step, maximum float64, monitor monitor.Monitoring) *ParabolicSARAdapter {
+	adapter := &ParabolicSARAdapter{
+		SAR:    indicator.NewParabolicSAR(step, maximum),
+		logger: logger.GetLogger(),
+	}
+	adapter.registerMetrics(ctx, monitor)
+	return adapter
+}
+
+func (pa *ParabolicSARAdapter) registerMetrics(ctx context.Context, m monitor.Monitoring) {
+	ctx = pa.getUpdateContext(ctx)
+	pa.metrics = &ParabolicSARMetrics{
+		monitor:       m,
+		SignalCounter: m.RegisterCounter(ctx, "parabolic_sar_signals_generated", "Total number of Parabolic SAR signals generated", monitor.Labels{constraint.SIGNAL_TYPE_LABEL}),
+		SARGauge:      m.RegisterGauge(ctx, "parabolic_sar_value", "Current value of the Parabolic SAR", nil),
+	}
+}
+
+func (pa *ParabolicSARAdapter) Clone(ctx context.Context) IndicatorAdaptor {
+	return NewParabolicSARAdapter(ctx, pa.SAR.Step, pa.SAR.Maximum, pa.metrics.monitor)
+}
+
+func (pa *ParabolicSARAdapter) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	ctx = pa.getUpdateContext(ctx)
+	pa.logger.Debug(ctx, "Adding data point to ParabolicSARAdapter", zap.Int64("timestamp", data.Time))
+
+	if err := pa.SAR.Update(ctx, data); err != nil {
+		pa.logger.Error(ctx, "Failed to add data point to Parabolic SAR", zap.Error(err))
+		return err
+	}
+	pa.CurrentData = data
+
+	if pa.SAR.Ready() {
+		pa.metrics.SARGauge.SetGauge(ctx, pa.SAR.Value(), nil)
+	}
+	return nil
+}
+
+func (pa *ParabolicSARAdapter) Name() string {
+	return fmt.Sprintf("ParabolicSAR_%.3f_%.2f", pa.SAR.Step, pa.SAR.Maximum)
+}
+
+func (pa *ParabolicSARAdapter) GetSignal(ctx context.Context) (result model.TradingSignal) {
+	ctx = pa.getUpdateContext(ctx)
+	defer func() {
+		tags := monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result.Action))
+		tags.Add(ADAPTOR_NAME_LABEL, pa.Name())
+		pa.metrics.SignalCounter.IncrementCounter(ctx, tags)
+	}()
+	result = model.TradingSignal{Time: pa.CurrentData.Time, Action: model.Wait}
+	zaps := []zap.Field{zap.String("adapter", pa.Name()), zap.Any("time", pa.CurrentData.Time)}
+
+	if !pa.SAR.Reversed {
+		pa.logger.Debug(ctx, "No trading signal detected", zaps...)
+		return result
+	}
+	if pa.SAR.Long {
+		pa.logger.Info(ctx, "Buy signal detected", zaps...)
+		return pa.signal(model.Buy, "flipped below the price")
+	}
+	pa.logger.Info(ctx, "Sell signal detected", zaps...)
+	return pa.signal(model.Sell, "flipped above the price")
+}
+
+// signal builds a SAR flip signal with the stop loss at the SAR.
+func (pa *ParabolicSARAdapter) signal(action model.StockAction, event string) model.TradingSignal {
+	sar := pa.SAR.Value()
+	return model.TradingSignal{
+		Time:     pa.CurrentData.Time,
+		Action:   action,
+		Strength: relativeStrength(pa.CurrentData.Close-sar, pa.CurrentData.Close),
+		StopLoss: sar,
+		Target:   rewardTarget(pa.CurrentData.Close, sar),
+		Rationale: []model.Rationale{{
+			Source:    pa.Name(),
+			Indicator: "Parabolic SAR",
+			Event:     event,
+			Values:    []float64{sar, pa.CurrentData.Close},
+		}},
+	}
+}
+
+// IndicatorValues returns the latest SAR and its acceleration.
+func (pa *ParabolicSARAdapter) IndicatorValues() map[string]float64 {
+	if !pa.SAR.Ready() {
+		return nil
+	}
+	return map[string]float64{"sar": pa.SAR.Value(), "sar_acceleration": pa.SAR.Acceleration}
+}
+
+// Function to retrieve and update the slice from context
+func (pa *ParabolicSARAdapter) getUpdateContext(ctx context.Context) context.Context {
+	ctx = getUpdatedCommonLabelsContext(ctx)
+	return context.WithValue(ctx, ADAPTOR_NAME_LABEL, pa.Name())
+}
+
+// parabolicSARAdapterState is the saved state of a ParabolicSARAdapter.
+type parabolicSARAdapterState struct {
+	Adaptor     string
+	SAR         *indicator.ParabolicSAR
+	CurrentData model.DataPoint
+}
+
+// MarshalState encodes the full state of the adapter.
+func (pa *ParabolicSARAdapter) MarshalState(format indicator.StateFormat) ([]byte, error) {
+	return indicator.EncodeState(format, parabolicSARAdapterState{
+		Adaptor:     pa.Name(),
+		SAR:         pa.SAR,
+		CurrentData: pa.CurrentData,
+	})
+}
+
+// UnmarshalState replaces the state of the adapter with one saved by an adapter with the same parameters.
+func (pa *ParabolicSARAdapter) UnmarshalState(format indicator.StateFormat, data []byte) error {
+	var state parabolicSARAdapterState
+	if err := indicator.DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	if err := checkSavedState(state.Adaptor, pa.Name(), state.SAR != nil); err != nil {
+		return err
+	}
+	pa.SAR, pa.CurrentData = state.SAR, state.CurrentData
+	return nil
+}
@@ -0,0 +1,236 @@
+package indicator_adaptor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/constraint"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator"
+	"github.com/vd09/trading-algorithm-backtesting-system/logger"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/patterns"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
+	"go.uber.org/zap"
+)
+
+const (
+	PATTERN_LABEL = "pattern"
+)
+
+type PatternMetrics struct {
+	SignalCounter  monitor.CounterMetric
+	PatternCounter monitor.CounterMetric
+	monitor        monitor.Monitoring
+}
+
+// PatternAdapter buys on bullish candlestick patterns and sells on bearish ones. Bars matching patterns of
+// both biases give no signal, neutral patterns such as the plain doji are only reported in the rationale.
+type PatternAdapter struct {
+	Thresholds patterns.Thresholds
+	// History holds the latest bars the patterns are detected in.
+	History []model.DataPoint
+	// Matches are the patterns completed on the current bar.
+	Matches     []patterns.Match
+	CurrentData model.DataPoint
+	logger      logger.LoggerInterface
+	metrics     *PatternMetrics
+}
+
+func init() {
+	defaults := patterns.DefaultThresholds()
+	registry.RegisterAdaptor(registry.AdaptorEntry{
+		Name:        "candlestick",
+		Description: "Buys on bullish and sells on bearish candlestick patterns",
+		Params: []registry.Param{
+			{Name: "doji_body", Type: registry.FloatParam, Description: "Largest body of a doji, as a share of the bar's range", Default: defaults.DojiBody, Min: 0.01, Max: 0.5},
+			{Name: "doji_shadow", Type: registry.FloatParam, Description: "Largest short shadow of a dragonfly or gravestone doji", Default: defaults.DojiShadow, Min: 0, Max: 0.5},
+			{Name: "long_legged_shadow", Type: registry.FloatParam, Description: "Smallest shadows of a long-legged doji", Default: defaults.LongLeggedShadow, Min: 0, Max: 0.5},
+			{Name: "hammer_shadow", Type: registry.FloatParam, Description: "Smallest lower shadow of a hammer, as a multiple of its body", Default: defaults.HammerShadow, Min: 1, Max: 10},
+			{Name: "hammer_upper_shadow", Type: registry.FloatParam, Description: "Largest upper shadow of a hammer", Default: defaults.HammerUpperShadow, Min: 0, Max: 0.5},
+			{Name: "long_body", Type: registry.FloatParam, Description: "Smallest body of the long bars of multi-bar patterns", Default: defaults.LongBody, Min: 0.1, Max: 1},
+			{Name: "star_body", Type: registry.FloatParam, Description: "Largest body of the middle bar of a star", Default: defaults.StarBody, Min: 0.01, Max: 1},
+			{Name: "trend_bars", Type: registry.IntParam, Description: "Bars whose closes tell the trend before a hammer or hanging man", Default: defaults.TrendBars, Min: 2, Max: 100},
+		},
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (IndicatorAdaptor, error) {
+			return NewPatternAdapter(ctx, patterns.Thresholds{
+				DojiBody:          p.Float("doji_body"),
+				DojiShadow:        p.Float("doji_shadow"),
+				LongLeggedShadow:  p.Float("long_legged_shadow"),
+				HammerShadow:      p.Float("hammer_shadow"),
+				HammerUpperShadow: p.Float("hammer_upper_shadow"),
+				LongBody:          p.Float("long_body"),
+				StarBody:          p.Float("star_body"),
+				TrendBars:         p.Int("trend_bars"),
+			}, m), nil
+		},
+	})
+}
+
+func NewPatternAdapter(ctx context.Context, thresholds patterns.Thresholds, monitor monitor.Monitoring) *PatternAdapter {
+	adapter := &PatternAdapter{
+		Thresholds: thresholds,
+		logger:     logger.GetLogger(),
+	}
+	adapter.registerMetrics(ctx, monitor)
+	return adapter
+}
+
+func (pa *PatternAdapter) registerMetrics(ctx context.Context, m monitor.Monitoring) {
+	ctx = pa.getUpdateContext(ctx)
+	pa.metrics = &PatternMetrics{
+		monitor:        m,
+		SignalCounter:  m.RegisterCounter(ctx, "pattern_signals_generated", "Total number of candlestick pattern signals generated", monitor.Labels{constraint.SIGNAL_TYPE_LABEL}),
+		PatternCounter: m.RegisterCounter(ctx, "patterns_detected", "Total number of candlestick patterns detected", monitor.Labels{PATTERN_LABEL}),
+	}
+}
+
+func (pa *PatternAdapter) Clone(ctx context.Context) IndicatorAdaptor {
+	return NewPatternAdapter(ctx, pa.Thresholds, pa.metrics.monitor)
+}
+
+func (pa *PatternAdapter) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	ctx = pa.getUpdateContext(ctx)
+	pa.logger.Debug(ctx, "Adding data point to PatternAdapter", zap.Int64("timestamp", data.Time))
+
+	if len(pa.History) > 0 && data.Time <= pa.History[len(pa.History)-1].Time {
+		err := errors.New("data point is not in chronological order")
+		pa.logger.Error(ctx, "Failed to add data point to PatternAdapter", zap.Error(err))
+		return err
+	}
+	pa.History = append(pa.History, data)
+	if len(pa.History) > pa.Thresholds.MaxBars() {
+		pa.History = pa.History[1:]
+	}
+	pa.CurrentData = data
+
+	pa.Matches = patterns.Detect(pa.History, pa.Thresholds)
+	for _, match := range pa.Matches {
+		pa.metrics.PatternCounter.IncrementCounter(ctx, monitor.NewTagsKV(PATTERN_LABEL, string(match.Pattern)))
+	}
+	return nil
+}
+
+func (pa *PatternAdapter) Name() string {
+	t := pa.Thresholds
+	return fmt.Sprintf("Patterns_%.2f_%.2f_%.2f_%.2f_%.2f_%.2f_%.2f_%d", t.DojiBody, t.DojiShadow, t.LongLeggedShadow, t.HammerShadow, t.HammerUpperShadow, t.LongBody, t.StarBody, t.TrendBars)
+}
+
+func (pa *PatternAdapter) GetSignal(ctx context.Context) (result model.TradingSignal) {
+	ctx = pa.getUpdateContext(ctx)
+	defer func() {
+		tags := monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result.Action))
+		tags.Add(ADAPTOR_NAME_LABEL, pa.Name())
+		pa.metrics.SignalCounter.IncrementCounter(ctx, tags)
+	}()
+	result = model.TradingSignal{Time: pa.CurrentData.Time, Action: model.Wait}
+	zaps := []zap.Field{zap.String("adapter", pa.Name()), zap.Any("time", pa.CurrentData.Time)}
+
+	bullish, bearish := 0, 0
+	for _, match := range pa.Matches {
+		switch match.Bias {
+		case patterns.Bullish:
+			bullish++
+		case patterns.Bearish:
+			bearish++
+		}
+	}
+	switch {
+	case bullish > 0 && bearish == 0:
+		pa.logger.Info(ctx, "Buy signal detected", zaps...)
+		return pa.signal(model.Buy)
+	case bearish > 0 && bullish == 0:
+		pa.logger.Info(ctx, "Sell signal detected", zaps...)
+		return pa.signal(model.Sell)
+	}
+
+	pa.logger.Debug(ctx, "No trading signal detected", append(zaps, zap.Int("bullish", bullish), zap.Int("bearish", bearish))...)
+	return result
+}
+
+// signal builds a pattern signal with the stop loss behind the bars of the longest pattern,
+// patterns of more bars give stronger signals.
+func (pa *PatternAdapter) signal(action model.StockAction) model.TradingSignal {
+	bars := 0
+	var rationale []model.Rationale
+	for _, match := range pa.Matches {
+		bars = max(bars, match.Bars)
+		rationale = append(rationale, model.Rationale{
+			Source:    pa.Name(),
+			Indicator: "Candlestick",
+			Event:     string(match.Pattern),
+			Reference: string(match.Bias),
+		})
+	}
+
+	pattern := pa.History[len(pa.History)-bars:]
+	stopLoss := pattern[0].Low
+	if action == model.Sell {
+		stopLoss = pattern[0].High
+	}
+	for _, data := range pattern[1:] {
+		if action == model.Buy {
+			stopLoss = min(stopLoss, data.Low)
+		} else {
+			stopLoss = max(stopLoss, data.High)
+		}
+	}
+	return model.TradingSignal{
+		Time:      pa.CurrentData.Time,
+		Action:    action,
+		Strength:  clampStrength(0.4 + 0.15*float64(bars)),
+		StopLoss:  stopLoss,
+		Target:    rewardTarget(pa.CurrentData.Close, stopLoss),
+		Rationale: rationale,
+	}
+}
+
+// IndicatorValues returns 1 for every pattern completed on the current bar.
+func (pa *PatternAdapter) IndicatorValues() map[string]float64 {
+	if len(pa.Matches) == 0 {
+		return nil
+	}
+	values := map[string]float64{}
+	for _, match := range pa.Matches {
+		values[string(match.Pattern)] = 1
+	}
+	return values
+}
+
+// Function to retrieve and update the slice from context
+func (pa *PatternAdapter) getUpdateContext(ctx context.Context) context.Context {
+	ctx = getUpdatedCommonLabelsContext(ctx)
+	return context.WithValue(ctx, ADAPTOR_NAME_LABEL, pa.Name())
+}
+
+// patternAdapterState is the saved state of a PatternAdapter.
+type patternAdapterState struct {
+	Adaptor     string
+	History     []model.DataPoint
+	Matches     []patterns.Match
+	CurrentData model.DataPoint
+}
+
+// MarshalState encodes the full state of the adapter.
+func (pa *PatternAdapter) MarshalState(format indicator.StateFormat) ([]byte, error) {
+	return indicator.EncodeState(format, patternAdapterState{
+		Adaptor:     pa.Name(),
+		History:     pa.History,
+		Matches:     pa.Matches,
+		CurrentData: pa.CurrentData,
+	})
+}
+
+// UnmarshalState replaces the state of the adapter with one saved by an adapter with the same parameters.
+func (pa *PatternAdapter) UnmarshalState(format indicator.StateFormat, data []byte) error {
+	var state patternAdapterState
+	if err := indicator.DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	if err := checkSavedState(state.Adaptor, pa.Name(), true); err != nil {
+		return err
+	}
+	pa.History, pa.Matches, pa.CurrentData = state.History, state.Matches, state.CurrentData
+	return nil
+}
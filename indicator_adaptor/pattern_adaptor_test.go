@@ -0,0 +1,58 @@
+package indicator_adaptor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/patterns"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+// starData falls for three bars and ends in a morning star whose middle bar has the lowest low of 99.
+func starData() []model.DataPoint {
+	return []model.DataPoint{
+		{Time: 1, Open: 108, High: 108.5, Low: 107.5, Close: 108},
+		{Time: 2, Open: 107, High: 107.5, Low: 106.5, Close: 107},
+		{Time: 3, Open: 106, High: 106.5, Low: 105.5, Close: 106},
+		{Time: 4, Open: 105, High: 105.2, Low: 100.8, Close: 101},
+		{Time: 5, Open: 100, High: 100.8, Low: 99, Close: 99.8},
+		{Time: 6, Open: 100.5, High: 104.2, Low: 100.3, Close: 104},
+	}
+}
+
+func TestPatternAdapterSignals(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []model.DataPoint
+		pattern  patterns.Pattern
+		want     model.StockAction
+		stopLoss float64
+	}{
+		{"morning star", starData(), patterns.MorningStar, model.Buy, 99},
+		{"evening star", mirror(starData()), patterns.EveningStar, model.Sell, 101},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		adapter := indicator_adaptor.NewPatternAdapter(ctx, patterns.DefaultThresholds(), test_utils.NewMockMetricsCollector(t))
+		signals := signalBars(t, adapter, tt.data)
+		signal, ok := signals[6]
+		test_utils.AssertTrue(t, ok, tt.name+": should signal on the third bar of the star")
+		test_utils.AssertEqual(t, tt.want, signal.Action, tt.name+": signal does not match")
+		test_utils.AssertEqual(t, tt.stopLoss, signal.StopLoss, tt.name+": stop loss should be behind the star")
+		test_utils.AssertTrue(t, signal.Strength >= 0.85, tt.name+": three bar pattern should be strong")
+		test_utils.AssertEqual(t, 1.0, adapter.IndicatorValues()[string(tt.pattern)], tt.name+": pattern should be reported")
+	}
+}
+
+func TestPatternAdapterNeutralPatterns(t *testing.T) {
+	ctx := context.Background()
+	adapter := indicator_adaptor.NewPatternAdapter(ctx, patterns.DefaultThresholds(), test_utils.NewMockMetricsCollector(t))
+	adapter.AddDataPoint(ctx, model.DataPoint{Time: 1, Open: 100, High: 102, Low: 98, Close: 100.1})
+
+	test_utils.AssertEqual(t, 1.0, adapter.IndicatorValues()[string(patterns.LongLeggedDoji)], "doji should be detected")
+	test_utils.AssertEqual(t, model.StockAction(model.Wait), adapter.GetSignal(ctx).Action, "neutral pattern should not signal")
+	test_utils.AssertTrue(t, adapter.AddDataPoint(ctx, model.DataPoint{Time: 1}) != nil, "out of order bar should be rejected")
+}
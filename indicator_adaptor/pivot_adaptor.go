@@ -10,6 +10,7 @@ import (
 	"github.com/vd09/trading-algorithm-backtesting-system/logger"
 	"github.com/vd09/trading-algorithm-backtesting-system/model"
 	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
 	"github.com/vd09/trading-algorithm-backtesting-system/utils"
 	"go.uber.org/zap"
 )
@@ -34,6 +35,20 @@ type PivotPointAdapter struct {
 	metrics                *PivotPointMetrics
 }
 
+func init() {
+	registry.RegisterAdaptor(registry.AdaptorEntry{
+		Name:        "pivot",
+		Description: "Signals when the price breaks through pivot support or resistance levels",
+		Params: []registry.Param{
+			{Name: "max_history", Type: registry.IntParam, Default: 10, Min: 1, Max: 100000},
+			{Name: "threshold", Type: registry.IntParam, Default: 2, Min: 0, Max: 100},
+		},
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (IndicatorAdaptor, error) {
+			return NewPivotPointAdapter(ctx, p.Int("max_history"), p.Int("threshold"), m), nil
+		},
+	})
+}
+
 func NewPivotPointAdapter(ctx context.Context, maxTotalHistoricalData, threshold int, monitor monitor.Monitoring) *PivotPointAdapter {
 	adapter := &PivotPointAdapter{
 		PivotPoint:             indicator.NewPivotPoint(),
@@ -63,7 +78,7 @@ func (ppa *PivotPointAdapter) AddDataPoint(ctx context.Context, data model.DataP
 	ctx = ppa.getUpdateContext(ctx)
 	ppa.logger.Debug(ctx, "Adding data point to PivotPointAdapter", zap.Int64("timestamp", data.Time))
 
-	if err := ppa.PivotPoint.AddDataPoint(ctx, data); err != nil {
+	if err := ppa.PivotPoint.Update(ctx, data); err != nil {
 		return err
 	}
 	ppa.CurrentData = data
@@ -72,7 +87,7 @@ func (ppa *PivotPointAdapter) AddDataPoint(ctx context.Context, data model.DataP
 }
 
 func (ppa *PivotPointAdapter) updateHistoricalValues(ctx context.Context) {
-	levels := ppa.PivotPoint.GetPivotLevels()
+	levels := ppa.PivotPoint.Value()
 	ppa.HistoricalValues = append(ppa.HistoricalValues, ppa.CurrentData)
 	if len(ppa.HistoricalValues) > ppa.MaxTotalHistoricalData {
 		ppa.HistoricalValues = ppa.HistoricalValues[1:]
@@ -97,41 +112,72 @@ func (ppa *PivotPointAdapter) Name() string {
 	return fmt.Sprintf("PivotPoint_%d_%d", ppa.MaxTotalHistoricalData, ppa.Threshold)
 }
 
-func (ppa *PivotPointAdapter) GetSignal(ctx context.Context) (result model.StockAction) {
+func (ppa *PivotPointAdapter) GetSignal(ctx context.Context) (result model.TradingSignal) {
 	ctx = ppa.getUpdateContext(ctx)
 	defer func() {
-		tags := monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result))
+		tags := monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result.Action))
 		tags.Add(ADAPTOR_NAME_LABEL, ppa.Name())
 		ppa.metrics.SignalCounter.IncrementCounter(ctx, tags)
 	}()
+	result = model.TradingSignal{Time: ppa.CurrentData.Time, Action: model.Wait}
 
 	zaps := []zap.Field{zap.String("adapter", ppa.Name()), zap.Any("time", ppa.CurrentData.Time)}
 	if len(ppa.HistoricalValues) == 0 {
 		ppa.logger.Debug(ctx, "No historical data available", zaps...)
-		return model.Wait
+		return result
 	}
-	if !ppa.PivotPoint.Initialized {
+	// The levels come from the previous bar, so there are none until the second bar.
+	if !ppa.PivotPoint.Ready() {
 		ppa.logger.Debug(ctx, "PivotPoint not initialized", zaps...)
-		return model.Wait
+		return result
 	}
 
 	lastData := ppa.CurrentData
-	lastPivot := ppa.PivotPoint.Levels
+	lastPivot := ppa.PivotPoint.Value()
 
 	recentTests := ppa.calculateRecentTests()
 
-	if ppa.evaluateBuySignal(lastData, lastPivot, recentTests) {
+	if level := ppa.evaluateBuySignal(lastData, lastPivot, recentTests); level > 0 {
 		ppa.logger.Info(ctx, "Buy signal detected", zaps...)
-		return model.Buy
+		return ppa.signal(model.Buy, level, "R", []float64{lastPivot.Pivot, lastPivot.Resistance1, lastPivot.Resistance2, lastPivot.Resistance3})
 	}
 
-	if ppa.evaluateSellSignal(lastData, lastPivot, recentTests) {
+	if level := ppa.evaluateSellSignal(lastData, lastPivot, recentTests); level > 0 {
 		ppa.logger.Info(ctx, "Sell signal detected", zaps...)
-		return model.Sell
+		return ppa.signal(model.Sell, level, "S", []float64{lastPivot.Pivot, lastPivot.Support1, lastPivot.Support2, lastPivot.Support3})
 	}
 
 	ppa.logger.Debug(ctx, "No trading signal detected", zaps...)
-	return model.Wait
+	return result
+}
+
+// signal builds a breakout signal through levels[level], ordered from the pivot outwards.
+// The stop loss sits on the level before the broken one and the target on the one after it,
+// breaking an outer level gives a stronger signal.
+func (ppa *PivotPointAdapter) signal(action model.StockAction, level int, prefix string, levels []float64) model.TradingSignal {
+	broken := levels[level]
+	target := broken + (broken - levels[level-1])
+	if level+1 < len(levels) {
+		target = levels[level+1]
+	}
+	event := "broke above"
+	if action == model.Sell {
+		event = "broke below"
+	}
+	return model.TradingSignal{
+		Time:     ppa.CurrentData.Time,
+		Action:   action,
+		Strength: clampStrength(0.25 + 0.25*float64(level)),
+		StopLoss: levels[level-1],
+		Target:   target,
+		Rationale: []model.Rationale{{
+			Source:    ppa.Name(),
+			Indicator: "Close",
+			Event:     event,
+			Reference: fmt.Sprintf("%s%d %.2f", prefix, level, broken),
+			Values:    []float64{ppa.CurrentData.Open, ppa.CurrentData.Close},
+		}},
+	}
 }
 
 func (ppa *PivotPointAdapter) calculateRecentTests() map[string]int {
@@ -144,7 +190,7 @@ func (ppa *PivotPointAdapter) calculateRecentTests() map[string]int {
 		"Support3":    0,
 	}
 
-	pivot := ppa.PivotPoint.Levels
+	pivot := ppa.PivotPoint.Value()
 	for i := len(ppa.HistoricalValues) - 1; i >= 0 && i >= len(ppa.HistoricalValues)-5; i-- {
 		dataPoint := ppa.HistoricalValues[i]
 		if isWithinRange(dataPoint.Close, pivot.Pivot, pivot.Resistance1, pivot.Resistance2) {
@@ -170,30 +216,49 @@ func (ppa *PivotPointAdapter) calculateRecentTests() map[string]int {
 	return recentTests
 }
 
-func (ppa *PivotPointAdapter) evaluateBuySignal(lastData model.DataPoint, lastPivot indicator.PivotLevels, recentTests map[string]int) bool {
+// evaluateBuySignal returns the number of the resistance level broken, or 0 when none is.
+func (ppa *PivotPointAdapter) evaluateBuySignal(lastData model.DataPoint, lastPivot indicator.PivotLevels, recentTests map[string]int) int {
 	if recentTests["Resistance3"] >= ppa.Threshold && lastData.High > lastPivot.Resistance3 && lastData.Low < lastPivot.Resistance3 && lastData.Close > lastPivot.Resistance3 {
-		return true
+		return 3
 	}
 	if recentTests["Resistance2"] >= ppa.Threshold && lastData.High > lastPivot.Resistance2 && lastData.Low < lastPivot.Resistance2 && lastData.Close > lastPivot.Resistance2 {
-		return true
+		return 2
 	}
 	if recentTests["Resistance1"] >= ppa.Threshold && lastData.High > lastPivot.Resistance1 && lastData.Low < lastPivot.Resistance1 && lastData.Close > lastPivot.Resistance1 {
-		return true
+		return 1
 	}
-	return false
+	return 0
 }
 
-func (ppa *PivotPointAdapter) evaluateSellSignal(lastData model.DataPoint, lastPivot indicator.PivotLevels, recentTests map[string]int) bool {
+// evaluateSellSignal returns the number of the support level broken, or 0 when none is.
+func (ppa *PivotPointAdapter) evaluateSellSignal(lastData model.DataPoint, lastPivot indicator.PivotLevels, recentTests map[string]int) int {
 	if recentTests["Support3"] >= ppa.Threshold && lastData.Low < lastPivot.Support3 && lastData.High > lastPivot.Support3 && lastData.Close < lastPivot.Support3 {
-		return true
+		return 3
 	}
 	if recentTests["Support2"] >= ppa.Threshold && lastData.Low < lastPivot.Support2 && lastData.High > lastPivot.Support2 && lastData.Close < lastPivot.Support2 {
-		return true
+		return 2
 	}
 	if recentTests["Support1"] >= ppa.Threshold && lastData.Low < lastPivot.Support1 && lastData.High > lastPivot.Support1 && lastData.Close < lastPivot.Support1 {
-		return true
+		return 1
+	}
+	return 0
+}
+
+// IndicatorValues returns the current pivot levels, there are none before the second bar.
+func (ppa *PivotPointAdapter) IndicatorValues() map[string]float64 {
+	if !ppa.PivotPoint.Ready() {
+		return nil
+	}
+	levels := ppa.PivotPoint.Value()
+	return map[string]float64{
+		"pivot": levels.Pivot,
+		"r1":    levels.Resistance1,
+		"r2":    levels.Resistance2,
+		"r3":    levels.Resistance3,
+		"s1":    levels.Support1,
+		"s2":    levels.Support2,
+		"s3":    levels.Support3,
 	}
-	return false
 }
 
 // Function to retrieve and update the slice from context
@@ -208,3 +273,38 @@ func isWithinRange(value, lowerReference, middleReference, upperReference float6
 	upperBound := middleReference + diff
 	return value >= lowerBound && value <= upperBound
 }
+
+// pivotPointAdapterState is the saved state of a PivotPointAdapter.
+type pivotPointAdapterState struct {
+	Adaptor          string
+	PivotPoint       *indicator.PivotPoint
+	HistoricalValues []model.DataPoint
+	CurrentData      model.DataPoint
+}
+
+// MarshalState encodes the full state of the adapter.
+func (ppa *PivotPointAdapter) MarshalState(format indicator.StateFormat) ([]byte, error) {
+	return indicator.EncodeState(format, pivotPointAdapterState{
+		Adaptor:          ppa.Name(),
+		PivotPoint:       ppa.PivotPoint,
+		HistoricalValues: ppa.HistoricalValues,
+		CurrentData:      ppa.CurrentData,
+	})
+}
+
+// UnmarshalState replaces the state of the adapter with one saved by an adapter with the same parameters.
+func (ppa *PivotPointAdapter) UnmarshalState(format indicator.StateFormat, data []byte) error {
+	var state pivotPointAdapterState
+	if err := indicator.DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	if state.PivotPoint == nil && state.CurrentData.Time == 0 {
+		// A pivot point without any data has only zero fields, which the binary format leaves out.
+		state.PivotPoint = indicator.NewPivotPoint()
+	}
+	if err := checkSavedState(state.Adaptor, ppa.Name(), state.PivotPoint != nil); err != nil {
+		return err
+	}
+	ppa.PivotPoint, ppa.HistoricalValues, ppa.CurrentData = state.PivotPoint, state.HistoricalValues, state.CurrentData
+	return nil
+}
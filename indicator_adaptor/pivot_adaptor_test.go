@@ -99,6 +99,32 @@ func TestPivotPointGetSignal(t *testing.T) {
 	verifySignal(t, adapter, expectedSignal)
 }
 
+// TestPivotPointWaitsForLevels checks the adapter reads no levels on the first bar, the levels are those of the previous bar.
+func TestPivotPointWaitsForLevels(t *testing.T) {
+	mock := test_utils.NewMockMetricsCollector(t)
+	adapter := indicator_adaptor.NewPivotPointAdapter(context.Background(), 5, 0, mock)
+	ctx := context.Background()
+
+	dataPoints := []model.DataPoint{
+		{Time: 1, High: 110, Low: 90, Close: 100},
+		{Time: 2, High: 160, Low: 90, Close: 150},
+	}
+	if err := adapter.AddDataPoint(ctx, dataPoints[0]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	test_utils.AssertTrue(t, adapter.IndicatorValues() == nil, "Expected no levels after the first bar")
+	verifySignal(t, adapter, model.Wait)
+
+	if err := adapter.AddDataPoint(ctx, dataPoints[1]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	values := adapter.IndicatorValues()
+	test_utils.AssertEqual(t, 100.0, values["pivot"], "Pivot does not match the first bar")
+	test_utils.AssertEqual(t, 110.0, values["r1"], "R1 does not match the first bar")
+	test_utils.AssertEqual(t, 90.0, values["s1"], "S1 does not match the first bar")
+	verifySignal(t, adapter, model.Buy)
+}
+
 // generateTestDataPoints generates a slice of test DataPoint instances.
 func generateTestDataPoints(count int) []model.DataPoint {
 	dataPoints := make([]model.DataPoint, count)
@@ -116,7 +142,7 @@ func generateTestDataPoints(count int) []model.DataPoint {
 // verifySignal checks the signal from the adapter and compares it with the expected signal.
 func verifySignal(t *testing.T, adapter *indicator_adaptor.PivotPointAdapter, expectedSignal model.StockAction) {
 	ctx := context.Background()
-	signal := adapter.GetSignal(ctx)
+	signal := adapter.GetSignal(ctx).Action
 	if signal != expectedSignal {
 		t.Errorf("expected signal %v, got %v", expectedSignal, signal)
 	}
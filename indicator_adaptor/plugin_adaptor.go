@@ -0,0 +1,179 @@
+package indicator_adaptor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/constraint"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator"
+	"github.com/vd09/trading-algorithm-backtesting-system/logger"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/plugin"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
+	"go.uber.org/zap"
+)
+
+type PluginMetrics struct {
+	SignalCounter  monitor.CounterMetric
+	RestartCounter monitor.CounterMetric
+	monitor        monitor.Monitoring
+}
+
+// PluginAdapter streams every data point to an external plugin process and reports the signal it answers with.
+// It holds the process until Close is called.
+type PluginAdapter struct {
+	Config plugin.Config
+	client *plugin.Client
+	// stopped is set once the process failed to start or was closed, the adapter only waits from then on.
+	stopped bool
+	name    string
+	current model.TradingSignal
+	logger  logger.LoggerInterface
+	metrics *PluginMetrics
+}
+
+func init() {
+	registry.RegisterAdaptor(registry.AdaptorEntry{
+		Name:        "plugin",
+		Description: "Runs an external executable speaking the JSON lines plugin protocol",
+		Params:      registry.PluginParams(),
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (IndicatorAdaptor, error) {
+			return NewPluginAdapter(ctx, registry.PluginConfig(p), m)
+		},
+	})
+}
+
+// NewPluginAdapter starts the plugin and initializes a new PluginAdapter instance.
+func NewPluginAdapter(ctx context.Context, config plugin.Config, monitor monitor.Monitoring) (*PluginAdapter, error) {
+	client, err := plugin.Start(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	adapter := &PluginAdapter{
+		Config: config,
+		client: client,
+		name:   client.Name(),
+		logger: logger.GetLogger(),
+	}
+	adapter.registerMetrics(ctx, monitor)
+	return adapter, nil
+}
+
+func (pa *PluginAdapter) registerMetrics(ctx context.Context, m monitor.Monitoring) {
+	ctx = pa.getUpdateContext(ctx)
+	pa.metrics = &PluginMetrics{
+		monitor:        m,
+		SignalCounter:  m.RegisterCounter(ctx, "plugin_signals_generated", "Total number of plugin signals generated", monitor.Labels{constraint.SIGNAL_TYPE_LABEL}),
+		RestartCounter: m.RegisterCounter(ctx, "plugin_restarts", "Total number of plugin restarts", nil),
+	}
+}
+
+// Clone returns an adapter with its own process of the plugin. The process is only started with the
+// first data point, so clones which are never fed hold no process. If it fails to start the clone only waits.
+func (pa *PluginAdapter) Clone(ctx context.Context) IndicatorAdaptor {
+	clone := &PluginAdapter{Config: pa.Config, name: pa.name, logger: pa.logger}
+	clone.registerMetrics(ctx, pa.metrics.monitor)
+	return clone
+}
+
+// start starts the plugin process of a clone on first use.
+func (pa *PluginAdapter) start(ctx context.Context) error {
+	if pa.client != nil {
+		return nil
+	}
+	if pa.stopped {
+		return errors.New("plugin is not running")
+	}
+	client, err := plugin.Start(ctx, pa.Config)
+	if err != nil {
+		pa.stopped = true
+		pa.logger.Error(ctx, "Failed to start plugin clone", zap.String("plugin", pa.name), zap.Error(err))
+		return err
+	}
+	pa.client = client
+	return nil
+}
+
+func (pa *PluginAdapter) Name() string {
+	return fmt.Sprintf("Plugin_%s", pa.name)
+}
+
+func (pa *PluginAdapter) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	ctx = pa.getUpdateContext(ctx)
+	pa.current = model.TradingSignal{Time: data.Time, Action: model.Wait}
+	if err := pa.start(ctx); err != nil {
+		return err
+	}
+
+	signals, err := pa.client.Send(ctx, []model.DataPoint{data})
+	pa.metrics.RestartCounter.SetValue(ctx, float64(pa.client.Restarts()), nil)
+	if err != nil {
+		pa.logger.Error(ctx, "Failed to get signal from plugin", zap.Error(err))
+		return err
+	}
+	pa.current = signals[0]
+	return nil
+}
+
+func (pa *PluginAdapter) GetSignal(ctx context.Context) (result model.TradingSignal) {
+	ctx = pa.getUpdateContext(ctx)
+	defer func() {
+		pa.metrics.SignalCounter.IncrementCounter(ctx, monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result.Action)))
+	}()
+	return pa.current
+}
+
+// Close shuts the plugin process down, the adapter does not start it again.
+func (pa *PluginAdapter) Close() error {
+	pa.stopped = true
+	if pa.client == nil {
+		return nil
+	}
+	client := pa.client
+	pa.client = nil
+	return client.Close()
+}
+
+// pluginAdapterState is the saved state of a PluginAdapter. The plugin keeps its own state in its
+// process, so the adapter saves the latest bars instead and replays them on restore.
+type pluginAdapterState struct {
+	Adaptor string
+	History []model.DataPoint
+	Current model.TradingSignal
+}
+
+// MarshalState encodes the latest bars sent to the plugin, up to ReplayBars of them, and the current signal.
+func (pa *PluginAdapter) MarshalState(format indicator.StateFormat) ([]byte, error) {
+	state := pluginAdapterState{Adaptor: pa.Name(), Current: pa.current}
+	if pa.client != nil {
+		state.History = pa.client.History()
+	}
+	return indicator.EncodeState(format, state)
+}
+
+// UnmarshalState replays the saved bars to the plugin, which must not have been sent any data yet.
+func (pa *PluginAdapter) UnmarshalState(format indicator.StateFormat, data []byte) error {
+	var state pluginAdapterState
+	if err := indicator.DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	if err := checkSavedState(state.Adaptor, pa.Name(), true); err != nil {
+		return err
+	}
+	if err := pa.start(context.Background()); err != nil {
+		return err
+	}
+	if err := pa.client.Replay(context.Background(), state.History); err != nil {
+		return err
+	}
+	pa.current = state.Current
+	return nil
+}
+
+// Function to retrieve and update the slice from context
+func (pa *PluginAdapter) getUpdateContext(ctx context.Context) context.Context {
+	ctx = getUpdatedCommonLabelsContext(ctx)
+	return context.WithValue(ctx, ADAPTOR_NAME_LABEL, pa.Name())
+}
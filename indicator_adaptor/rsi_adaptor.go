@@ -2,13 +2,16 @@ package indicator_adaptor
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/vd09/trading-algorithm-backtesting-system/constraint"
 	"github.com/vd09/trading-algorithm-backtesting-system/indicator"
 	"github.com/vd09/trading-algorithm-backtesting-system/logger"
 	"github.com/vd09/trading-algorithm-backtesting-system/model"
 	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
 	"go.uber.org/zap"
 )
 
@@ -29,10 +32,34 @@ type RSIAdapter struct {
 	HistoricalValues       []float64
 	OverboughtThreshold    float64
 	OversoldThreshold      float64
+	CurrentData            model.DataPoint
+	swing                  priceSwing
 	logger                 logger.LoggerInterface
 	metrics                *RSIMetrics
 }
 
+func init() {
+	registry.RegisterAdaptor(registry.AdaptorEntry{
+		Name:        "rsi",
+		Description: "Buys when the RSI recovers from oversold and sells when it falls back from overbought",
+		Params: []registry.Param{
+			{Name: "period", Type: registry.IntParam, Default: 14, Min: 2, Max: 1000},
+			{Name: "max_history", Type: registry.IntParam, Default: 100, Min: 2, Max: 100000},
+			{Name: "overbought", Type: registry.FloatParam, Default: 70.0, Min: 0, Max: 100},
+			{Name: "oversold", Type: registry.FloatParam, Default: 30.0, Min: 0, Max: 100},
+		},
+		Validate: func(p registry.Params) error {
+			if p.Float("oversold") >= p.Float("overbought") {
+				return errors.New("oversold must be lower than overbought")
+			}
+			return nil
+		},
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (IndicatorAdaptor, error) {
+			return NewRSIAdapter(ctx, p.Int("period"), p.Int("max_history"), p.Float("overbought"), p.Float("oversold"), m), nil
+		},
+	})
+}
+
 func NewRSIAdapter(ctx context.Context, period, maxTotalHistoricalData int, overboughtThreshold, oversoldThreshold float64, monitor monitor.Monitoring) *RSIAdapter {
 	adapter := &RSIAdapter{
 		RSI:                    indicator.NewRSI(period),
@@ -66,13 +93,15 @@ func (ra *RSIAdapter) AddDataPoint(ctx context.Context, data model.DataPoint) er
 	ra.metrics.LevelGauge.SetGauge(ctx, ra.OverboughtThreshold, monitor.NewTagsKV(RSI_LEVEL_LABEL, "over_bought_threshold"))
 
 	ra.logger.Debug(ctx, "Adding data point to RSIAdapter", zap.Int64("timestamp", data.Time))
-	if err := ra.RSI.AddDataPoint(ctx, data); err != nil {
+	if err := ra.RSI.Update(ctx, data); err != nil {
 		ra.logger.Error(ctx, "Failed to add data point to RSI", zap.Error(err))
 		return err
 	}
+	ra.CurrentData = data
+	ra.swing.add(data)
 
-	if ra.RSI.Initialized {
-		newRSI := ra.RSI.CalculateRSI()
+	if ra.RSI.Ready() {
+		newRSI := ra.RSI.Value()
 		ra.HistoricalValues = append(ra.HistoricalValues, newRSI)
 		if len(ra.HistoricalValues) > ra.MaxTotalHistoricalData {
 			ra.HistoricalValues = ra.HistoricalValues[1:]
@@ -87,19 +116,20 @@ func (ra *RSIAdapter) Name() string {
 	return fmt.Sprintf("RSI_P(%d)_OBT(%f)_OST(%f)_L(%d)", ra.RSI.Period, ra.OverboughtThreshold, ra.OversoldThreshold, ra.MaxTotalHistoricalData)
 }
 
-func (ra *RSIAdapter) GetSignal(ctx context.Context) (result model.StockAction) {
+func (ra *RSIAdapter) GetSignal(ctx context.Context) (result model.TradingSignal) {
 	ctx = ra.getUpdateContext(ctx)
 	defer func() {
-		ra.metrics.SignalCounter.IncrementCounter(ctx, monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result)))
+		ra.metrics.SignalCounter.IncrementCounter(ctx, monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result.Action)))
 	}()
+	result = model.TradingSignal{Time: ra.CurrentData.Time, Action: model.Wait}
 
 	if len(ra.HistoricalValues) < 2 {
 		ra.logger.Debug(ctx, "Not enough historical values for RSI signal generation", zap.Int("historicalValuesLength", len(ra.HistoricalValues)))
-		return model.Wait
+		return result
 	}
-	if !ra.RSI.Initialized {
+	if !ra.RSI.Ready() {
 		ra.logger.Debug(ctx, "RSI not initialized")
-		return model.Wait
+		return result
 	}
 
 	currentRSI := ra.HistoricalValues[len(ra.HistoricalValues)-1]
@@ -109,13 +139,15 @@ func (ra *RSIAdapter) GetSignal(ctx context.Context) (result model.StockAction)
 		if ra.HistoricalValues[i] > ra.OverboughtThreshold {
 			if currentRSI <= ra.OverboughtThreshold {
 				ra.logger.Info(ctx, "Sell signal detected", zap.Float64("currentRSI", currentRSI), zap.Float64("overboughtThreshold", ra.OverboughtThreshold))
-				return model.Sell
+				depth := (ra.HistoricalValues[i] - ra.OverboughtThreshold) / (100 - ra.OverboughtThreshold)
+				return ra.signal(model.Sell, depth, "crossed down through", ra.OverboughtThreshold, ra.HistoricalValues[i], currentRSI)
 			}
 			break
 		} else if ra.HistoricalValues[i] < ra.OversoldThreshold {
 			if currentRSI >= ra.OversoldThreshold {
 				ra.logger.Info(ctx, "Buy signal detected", zap.Float64("currentRSI", currentRSI), zap.Float64("oversoldThreshold", ra.OversoldThreshold))
-				return model.Buy
+				depth := (ra.OversoldThreshold - ra.HistoricalValues[i]) / ra.OversoldThreshold
+				return ra.signal(model.Buy, depth, "crossed up through", ra.OversoldThreshold, ra.HistoricalValues[i], currentRSI)
 			}
 			break
 		} else if ra.HistoricalValues[i] > ra.OversoldThreshold && ra.HistoricalValues[i] < ra.OverboughtThreshold {
@@ -124,7 +156,34 @@ func (ra *RSIAdapter) GetSignal(ctx context.Context) (result model.StockAction)
 	}
 
 	ra.logger.Debug(ctx, "No trading signal detected", zap.Float64("currentRSI", currentRSI))
-	return model.Wait
+	return result
+}
+
+// signal builds a threshold cross signal, the deeper the RSI went past the threshold the stronger the signal.
+func (ra *RSIAdapter) signal(action model.StockAction, depth float64, event string, threshold, extremeRSI, currentRSI float64) model.TradingSignal {
+	stopLoss, target := ra.swing.exits(action, ra.CurrentData.Close)
+	return model.TradingSignal{
+		Time:     ra.CurrentData.Time,
+		Action:   action,
+		Strength: clampStrength(0.5 + depth),
+		StopLoss: stopLoss,
+		Target:   target,
+		Rationale: []model.Rationale{{
+			Source:    ra.Name(),
+			Indicator: "RSI",
+			Event:     event,
+			Reference: strconv.FormatFloat(threshold, 'g', -1, 64),
+			Values:    []float64{extremeRSI, currentRSI},
+		}},
+	}
+}
+
+// IndicatorValues returns the latest RSI.
+func (ra *RSIAdapter) IndicatorValues() map[string]float64 {
+	if len(ra.HistoricalValues) == 0 {
+		return nil
+	}
+	return map[string]float64{"rsi": ra.HistoricalValues[len(ra.HistoricalValues)-1]}
 }
 
 // Function to retrieve and update the slice from context
@@ -132,3 +191,36 @@ func (ra *RSIAdapter) getUpdateContext(ctx context.Context) context.Context {
 	ctx = getUpdatedCommonLabelsContext(ctx)
 	return context.WithValue(ctx, ADAPTOR_NAME_LABEL, ra.Name())
 }
+
+// rsiAdapterState is the saved state of an RSIAdapter.
+type rsiAdapterState struct {
+	Adaptor          string
+	RSI              *indicator.RSI
+	HistoricalValues []float64
+	CurrentData      model.DataPoint
+	Swing            priceSwing
+}
+
+// MarshalState encodes the full state of the adapter.
+func (ra *RSIAdapter) MarshalState(format indicator.StateFormat) ([]byte, error) {
+	return indicator.EncodeState(format, rsiAdapterState{
+		Adaptor:          ra.Name(),
+		RSI:              ra.RSI,
+		HistoricalValues: ra.HistoricalValues,
+		CurrentData:      ra.CurrentData,
+		Swing:            ra.swing,
+	})
+}
+
+// UnmarshalState replaces the state of the adapter with one saved by an adapter with the same parameters.
+func (ra *RSIAdapter) UnmarshalState(format indicator.StateFormat, data []byte) error {
+	var state rsiAdapterState
+	if err := indicator.DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	if err := checkSavedState(state.Adaptor, ra.Name(), state.RSI != nil); err != nil {
+		return err
+	}
+	ra.RSI, ra.HistoricalValues, ra.CurrentData, ra.swing = state.RSI, state.HistoricalValues, state.CurrentData, state.Swing
+	return nil
+}
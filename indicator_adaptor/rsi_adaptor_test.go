@@ -2,6 +2,7 @@ package indicator_adaptor_test
 
 import (
 	"context"
+	"math"
 	"testing"
 	"time"
 
@@ -88,7 +89,7 @@ func TestRSIAdapterGetSignal(t *testing.T) {
 	}
 
 	// Check the initial signal, should be Wait
-	signal := adapter.GetSignal(ctx)
+	signal := adapter.GetSignal(ctx).Action
 	if signal != model.Wait {
 		t.Errorf("expected signal %v, got %v", model.Wait, signal)
 	}
@@ -100,7 +101,7 @@ func TestRSIAdapterGetSignal(t *testing.T) {
 	}
 	adapter.HistoricalValues[14] = 65.0 // Cross below the overbought threshold
 
-	signal = adapter.GetSignal(ctx)
+	signal = adapter.GetSignal(ctx).Action
 	if signal != model.Sell {
 		t.Errorf("expected signal %v, got %v", model.Sell, signal)
 	}
@@ -112,8 +113,15 @@ func TestRSIAdapterGetSignal(t *testing.T) {
 	}
 	adapter.HistoricalValues[14] = 35.0 // Cross above the oversold threshold
 
-	signal = adapter.GetSignal(ctx)
+	signal = adapter.GetSignal(ctx).Action
 	if signal != model.Buy {
 		t.Errorf("expected signal %v, got %v", model.Buy, signal)
 	}
+
+	detailed := adapter.GetSignal(ctx)
+	test_utils.AssertEqual(t, dataPoints[len(dataPoints)-1].Time, detailed.Time, "Signal time does not match")
+	test_utils.AssertEqual(t, 0.67, math.Round(detailed.Strength*100)/100, "Signal strength does not match")
+	test_utils.AssertEqual(t, 1, len(detailed.Rationale), "Number of rationales does not match")
+	test_utils.AssertEqual(t, "RSI crossed up through 30 at 25→35", detailed.Rationale[0].String(), "Rationale does not match")
+	test_utils.AssertTrue(t, detailed.StopLoss < dataPoints[len(dataPoints)-1].Close && detailed.Target > dataPoints[len(dataPoints)-1].Close, "Expected the stop loss below and the target above the price")
 }
@@ -0,0 +1,25 @@
+package indicator_adaptor
+
+import (
+	"fmt"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator"
+)
+
+// StatefulAdaptor is an adaptor whose full state can be saved mid-run, e.g. at the end of a historical backtest,
+// and restored into an adaptor built with the same parameters to continue from there.
+type StatefulAdaptor interface {
+	IndicatorAdaptor
+	indicator.Stateful
+}
+
+// checkSavedState rejects state saved by an adaptor other than name, or missing its indicator.
+func checkSavedState(savedBy, name string, complete bool) error {
+	if savedBy != name {
+		return fmt.Errorf("state saved by %s cannot be restored into %s", savedBy, name)
+	}
+	if !complete {
+		return fmt.Errorf("state of %s is incomplete", name)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package indicator_adaptor_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/patterns"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+func TestAdaptorStateRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	mock := test_utils.NewMockMetricsCollector(t)
+	adaptors := map[string]func() indicator_adaptor.StatefulAdaptor{
+		"rsi": func() indicator_adaptor.StatefulAdaptor {
+			return indicator_adaptor.NewRSIAdapter(ctx, 14, 100, 70, 30, mock)
+		},
+		"ema": func() indicator_adaptor.StatefulAdaptor {
+			return indicator_adaptor.NewEMAAdapter(ctx, []int{5, 20}, 10, mock)
+		},
+		"moving_average": func() indicator_adaptor.StatefulAdaptor {
+			adapter, _ := indicator_adaptor.NewMovingAverageAdapter(ctx, []indicator_adaptor.MovingAverageLine{{Type: indicator.HMAType, Period: 9}, {Type: indicator.KAMAType, Period: 10}, {Type: indicator.SMAType, Period: 30}}, 10, mock)
+			return adapter
+		},
+		"macd": func() indicator_adaptor.StatefulAdaptor {
+			return indicator_adaptor.NewMACDAdapter(ctx, 12, 26, 9, 10, mock)
+		},
+		"supertrend": func() indicator_adaptor.StatefulAdaptor {
+			return indicator_adaptor.NewSuperTrendAdapter(ctx, 10, 3, mock)
+		},
+		"pivot": func() indicator_adaptor.StatefulAdaptor {
+			return indicator_adaptor.NewPivotPointAdapter(ctx, 10, 2, mock)
+		},
+		"fibonacci": func() indicator_adaptor.StatefulAdaptor { return indicator_adaptor.NewFibonacciAdapter(ctx, 20, mock) },
+		"atr": func() indicator_adaptor.StatefulAdaptor {
+			return indicator_adaptor.NewATRAdapter(ctx, 14, indicator.WilderSmoothing, 1.5, mock)
+		},
+		"stochastic": func() indicator_adaptor.StatefulAdaptor {
+			return indicator_adaptor.NewStochasticAdapter(ctx, 14, 3, 3, 80, 20, mock)
+		},
+		"williams_r": func() indicator_adaptor.StatefulAdaptor {
+			return indicator_adaptor.NewWilliamsRAdapter(ctx, 14, -20, -80, mock)
+		},
+		"adx": func() indicator_adaptor.StatefulAdaptor {
+			return indicator_adaptor.NewADXAdapter(ctx, 14, 20, mock)
+		},
+		"ichimoku": func() indicator_adaptor.StatefulAdaptor {
+			adapter, _ := indicator_adaptor.NewIchimokuAdapter(ctx, 9, 26, 52, 26, indicator_adaptor.IchimokuTKCross, mock)
+			return adapter
+		},
+		"obv": func() indicator_adaptor.StatefulAdaptor {
+			adapter, _ := indicator_adaptor.NewVolumeLineAdapter(ctx, indicator_adaptor.OnBalanceVolume, 20, mock)
+			return adapter
+		},
+		"accumulation_distribution": func() indicator_adaptor.StatefulAdaptor {
+			adapter, _ := indicator_adaptor.NewVolumeLineAdapter(ctx, indicator_adaptor.AccumulationDistribution, 20, mock)
+			return adapter
+		},
+		"vwap": func() indicator_adaptor.StatefulAdaptor { return indicator_adaptor.NewVWAPAdapter(ctx, 2, mock) },
+		"mfi": func() indicator_adaptor.StatefulAdaptor {
+			return indicator_adaptor.NewMFIAdapter(ctx, 14, 80, 20, mock)
+		},
+		"cmf": func() indicator_adaptor.StatefulAdaptor { return indicator_adaptor.NewCMFAdapter(ctx, 20, 0.05, mock) },
+		"parabolic_sar": func() indicator_adaptor.StatefulAdaptor {
+			return indicator_adaptor.NewParabolicSARAdapter(ctx, 0.02, 0.2, mock)
+		},
+		"keltner": func() indicator_adaptor.StatefulAdaptor {
+			adapter, _ := indicator_adaptor.NewKeltnerAdapter(ctx, 20, 10, 1.5, indicator_adaptor.KeltnerSqueeze, 2, mock)
+			return adapter
+		},
+		"donchian": func() indicator_adaptor.StatefulAdaptor {
+			return indicator_adaptor.NewDonchianAdapter(ctx, 20, 10, mock)
+		},
+		"candlestick": func() indicator_adaptor.StatefulAdaptor {
+			return indicator_adaptor.NewPatternAdapter(ctx, patterns.DefaultThresholds(), mock)
+		},
+		"cci": func() indicator_adaptor.StatefulAdaptor {
+			adapter, _ := indicator_adaptor.NewMomentumAdapter(ctx, indicator_adaptor.CommodityChannelIndex, []int{20}, indicator_adaptor.MomentumThreshold, 100, -100, mock)
+			return adapter
+		},
+		"trix": func() indicator_adaptor.StatefulAdaptor {
+			adapter, _ := indicator_adaptor.NewMomentumAdapter(ctx, indicator_adaptor.TRIX, []int{9}, indicator_adaptor.MomentumZeroLine, 0.1, -0.1, mock)
+			return adapter
+		},
+		"ultimate_oscillator": func() indicator_adaptor.StatefulAdaptor {
+			adapter, _ := indicator_adaptor.NewMomentumAdapter(ctx, indicator_adaptor.UltimateOscillator, []int{7, 14, 28}, indicator_adaptor.MomentumThreshold, 70, 30, mock)
+			return adapter
+		},
+		"bollinger": func() indicator_adaptor.StatefulAdaptor {
+			adapter, _ := indicator_adaptor.NewBollingerAdapter(ctx, 20, 2, indicator_adaptor.BollingerSqueeze, 40, 20, mock)
+			return adapter
+		},
+		"expression": func() indicator_adaptor.StatefulAdaptor {
+			adapter, _ := indicator_adaptor.NewExpressionAdapter(ctx, "crosses_above(ema(5), ema(20)) and rsi(14) < 70", "close < pivot.s1 or crosses_below(macd.line, macd.signal)", mock)
+			return adapter
+		},
+	}
+	data := make([]model.DataPoint, 160)
+	for i := range data {
+		price := 100 + 20*math.Sin(float64(i)/8) + 5*math.Sin(float64(i)/2)
+		data[i] = model.DataPoint{Time: int64(i+1) * 60, Open: price - 0.3, High: price + 1, Low: price - 1, Close: price, Volume: 1000}
+	}
+
+	for name, build := range adaptors {
+		for _, format := range []indicator.StateFormat{indicator.JSONState, indicator.BinaryState} {
+			original := build()
+			for _, dp := range data[:80] {
+				original.AddDataPoint(ctx, dp)
+			}
+			saved, err := original.MarshalState(format)
+			if err != nil {
+				t.Fatalf("%s %s: unexpected error saving: %v", name, format, err)
+			}
+			restored := build()
+			if err := restored.UnmarshalState(format, saved); err != nil {
+				t.Fatalf("%s %s: unexpected error restoring: %v", name, format, err)
+			}
+
+			for _, dp := range data[80:] {
+				original.AddDataPoint(ctx, dp)
+				restored.AddDataPoint(ctx, dp)
+				test_utils.AssertEqual(t, original.GetSignal(ctx), restored.GetSignal(ctx), name+" "+string(format)+": restored signal diverged")
+			}
+			want, _ := original.MarshalState(indicator.JSONState)
+			got, _ := restored.MarshalState(indicator.JSONState)
+			test_utils.AssertEqual(t, string(want), string(got), name+" "+string(format)+": restored state diverged")
+		}
+	}
+}
+
+func TestAdaptorStateRejectsOtherAdaptor(t *testing.T) {
+	ctx := context.Background()
+	mock := test_utils.NewMockMetricsCollector(t)
+	saved, err := indicator_adaptor.NewRSIAdapter(ctx, 14, 100, 70, 30, mock).MarshalState(indicator.JSONState)
+	test_utils.AssertEqual(t, nil, err, "Saving an unused adaptor should succeed")
+
+	err = indicator_adaptor.NewRSIAdapter(ctx, 7, 100, 70, 30, mock).UnmarshalState(indicator.JSONState, saved)
+	test_utils.AssertTrue(t, err != nil, "State should not be restored into an adaptor with other parameters")
+	err = indicator_adaptor.NewMACDAdapter(ctx, 12, 26, 9, 10, mock).UnmarshalState(indicator.JSONState, saved)
+	test_utils.AssertTrue(t, err != nil, "State should not be restored into another kind of adaptor")
+}
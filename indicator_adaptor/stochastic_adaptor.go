@@ -0,0 +1,213 @@
+package indicator_adaptor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/constraint"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator"
+	"github.com/vd09/trading-algorithm-backtesting-system/logger"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
+	"go.uber.org/zap"
+)
+
+const (
+	STOCHASTIC_LINE_LABEL = "stochastic_line_type"
+)
+
+type StochasticMetrics struct {
+	SignalCounter monitor.CounterMetric
+	LineGauge     monitor.GaugeMetric
+	monitor       monitor.Monitoring
+}
+
+// StochasticAdapter buys when %K crosses above %D while %D is in the oversold zone, and sells when it crosses
+// below %D while %D is in the overbought zone.
+type StochasticAdapter struct {
+	Stochastic          *indicator.Stochastic
+	OverboughtThreshold float64
+	OversoldThreshold   float64
+	PreviousValues      indicator.StochasticValues
+	// HasPrevious is set when PreviousValues hold the lines of the bar before the current one.
+	HasPrevious bool
+	CurrentData model.DataPoint
+	swing       priceSwing
+	logger      logger.LoggerInterface
+	metrics     *StochasticMetrics
+}
+
+func init() {
+	registry.RegisterAdaptor(registry.AdaptorEntry{
+		Name:        "stochastic",
+		Description: "Signals when the Stochastic %K crosses %D inside the overbought or oversold zone",
+		Params: []registry.Param{
+			{Name: "period", Type: registry.IntParam, Default: 14, Min: 1, Max: 1000},
+			{Name: "k_smoothing", Type: registry.IntParam, Description: "Raw %K values averaged into %K, 1 for the fast Stochastic and 3 for the slow one", Default: 3, Min: 1, Max: 100},
+			{Name: "d_period", Type: registry.IntParam, Default: 3, Min: 1, Max: 100},
+			{Name: "overbought", Type: registry.FloatParam, Default: 80.0, Min: 0, Max: 100},
+			{Name: "oversold", Type: registry.FloatParam, Default: 20.0, Min: 0, Max: 100},
+		},
+		Validate: func(p registry.Params) error {
+			if p.Float("oversold") >= p.Float("overbought") {
+				return errors.New("oversold must be lower than overbought")
+			}
+			return nil
+		},
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (IndicatorAdaptor, error) {
+			return NewStochasticAdapter(ctx, p.Int("period"), p.Int("k_smoothing"), p.Int("d_period"), p.Float("overbought"), p.Float("oversold"), m), nil
+		},
+	})
+}
+
+func NewStochasticAdapter(ctx context.Context, period, kSmoothing, dPeriod int, overboughtThreshold, oversoldThreshold float64, monitor monitor.Monitoring) *StochasticAdapter {
+	adapter := &StochasticAdapter{
+		Stochastic:          indicator.NewStochastic(period, kSmoothing, dPeriod),
+		OverboughtThreshold: overboughtThreshold,
+		OversoldThreshold:   oversoldThreshold,
+		logger:              logger.GetLogger(),
+	}
+	adapter.registerMetrics(ctx, monitor)
+	return adapter
+}
+
+func (sa *StochasticAdapter) registerMetrics(ctx context.Context, m monitor.Monitoring) {
+	ctx = sa.getUpdateContext(ctx)
+	sa.metrics = &StochasticMetrics{
+		monitor:       m,
+		SignalCounter: m.RegisterCounter(ctx, "stochastic_signals_generated", "Total number of Stochastic signals generated", monitor.Labels{constraint.SIGNAL_TYPE_LABEL}),
+		LineGauge:     m.RegisterGauge(ctx, "stochastic_lines", "Current %K and %D of the Stochastic", monitor.Labels{STOCHASTIC_LINE_LABEL}),
+	}
+}
+
+func (sa *StochasticAdapter) Clone(ctx context.Context) IndicatorAdaptor {
+	return NewStochasticAdapter(ctx, sa.Stochastic.Period, sa.Stochastic.KSmoothing, sa.Stochastic.DPeriod, sa.OverboughtThreshold, sa.OversoldThreshold, sa.metrics.monitor)
+}
+
+func (sa *StochasticAdapter) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	ctx = sa.getUpdateContext(ctx)
+	sa.logger.Debug(ctx, "Adding data point to StochasticAdapter", zap.Int64("timestamp", data.Time))
+
+	previous, wasInitialized := sa.Stochastic.Value(), sa.Stochastic.Ready()
+	if err := sa.Stochastic.Update(ctx, data); err != nil {
+		sa.logger.Error(ctx, "Failed to add data point to Stochastic", zap.Error(err))
+		return err
+	}
+	sa.CurrentData = data
+	sa.swing.add(data)
+	sa.PreviousValues, sa.HasPrevious = previous, wasInitialized
+
+	if sa.Stochastic.Ready() {
+		values := sa.Stochastic.Value()
+		sa.metrics.LineGauge.SetGauge(ctx, values.K, monitor.NewTagsKV(STOCHASTIC_LINE_LABEL, "k"))
+		sa.metrics.LineGauge.SetGauge(ctx, values.D, monitor.NewTagsKV(STOCHASTIC_LINE_LABEL, "d"))
+	}
+	return nil
+}
+
+func (sa *StochasticAdapter) Name() string {
+	return fmt.Sprintf("Stochastic_%d_%d_%d_%.0f_%.0f", sa.Stochastic.Period, sa.Stochastic.KSmoothing, sa.Stochastic.DPeriod, sa.OverboughtThreshold, sa.OversoldThreshold)
+}
+
+func (sa *StochasticAdapter) GetSignal(ctx context.Context) (result model.TradingSignal) {
+	ctx = sa.getUpdateContext(ctx)
+	defer func() {
+		tags := monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result.Action))
+		tags.Add(ADAPTOR_NAME_LABEL, sa.Name())
+		sa.metrics.SignalCounter.IncrementCounter(ctx, tags)
+	}()
+	result = model.TradingSignal{Time: sa.CurrentData.Time, Action: model.Wait}
+	zaps := []zap.Field{zap.String("adapter", sa.Name()), zap.Any("time", sa.CurrentData.Time)}
+
+	if !sa.HasPrevious {
+		sa.logger.Debug(ctx, "Not enough Stochastic values for signal generation", zaps...)
+		return result
+	}
+
+	previous, current := sa.PreviousValues, sa.Stochastic.Value()
+	if previous.K <= previous.D && current.K > current.D && current.D < sa.OversoldThreshold {
+		sa.logger.Info(ctx, "Buy signal detected", zaps...)
+		return sa.signal(model.Buy, (sa.OversoldThreshold-current.D)/sa.OversoldThreshold, "crossed above", sa.OversoldThreshold)
+	}
+	if previous.K >= previous.D && current.K < current.D && current.D > sa.OverboughtThreshold {
+		sa.logger.Info(ctx, "Sell signal detected", zaps...)
+		return sa.signal(model.Sell, (current.D-sa.OverboughtThreshold)/(100-sa.OverboughtThreshold), "crossed below", sa.OverboughtThreshold)
+	}
+
+	sa.logger.Debug(ctx, "No trading signal detected", zaps...)
+	return result
+}
+
+// signal builds a %K/%D cross signal, the deeper inside the zone the cross the stronger the signal.
+func (sa *StochasticAdapter) signal(action model.StockAction, depth float64, event string, threshold float64) model.TradingSignal {
+	stopLoss, target := sa.swing.exits(action, sa.CurrentData.Close)
+	current := sa.Stochastic.Value()
+	return model.TradingSignal{
+		Time:     sa.CurrentData.Time,
+		Action:   action,
+		Strength: clampStrength(0.5 + depth),
+		StopLoss: stopLoss,
+		Target:   target,
+		Rationale: []model.Rationale{{
+			Source:    sa.Name(),
+			Indicator: "Stochastic",
+			Event:     "%K " + event + " %D beyond",
+			Reference: strconv.FormatFloat(threshold, 'g', -1, 64),
+			Values:    []float64{current.K, current.D},
+		}},
+	}
+}
+
+// IndicatorValues returns the latest %K and %D.
+func (sa *StochasticAdapter) IndicatorValues() map[string]float64 {
+	if !sa.Stochastic.Ready() {
+		return nil
+	}
+	values := sa.Stochastic.Value()
+	return map[string]float64{"stochastic_k": values.K, "stochastic_d": values.D}
+}
+
+// Function to retrieve and update the slice from context
+func (sa *StochasticAdapter) getUpdateContext(ctx context.Context) context.Context {
+	ctx = getUpdatedCommonLabelsContext(ctx)
+	return context.WithValue(ctx, ADAPTOR_NAME_LABEL, sa.Name())
+}
+
+// stochasticAdapterState is the saved state of a StochasticAdapter.
+type stochasticAdapterState struct {
+	Adaptor        string
+	Stochastic     *indicator.Stochastic
+	PreviousValues indicator.StochasticValues
+	HasPrevious    bool
+	CurrentData    model.DataPoint
+	Swing          priceSwing
+}
+
+// MarshalState encodes the full state of the adapter.
+func (sa *StochasticAdapter) MarshalState(format indicator.StateFormat) ([]byte, error) {
+	return indicator.EncodeState(format, stochasticAdapterState{
+		Adaptor:        sa.Name(),
+		Stochastic:     sa.Stochastic,
+		PreviousValues: sa.PreviousValues,
+		HasPrevious:    sa.HasPrevious,
+		CurrentData:    sa.CurrentData,
+		Swing:          sa.swing,
+	})
+}
+
+// UnmarshalState replaces the state of the adapter with one saved by an adapter with the same parameters.
+func (sa *StochasticAdapter) UnmarshalState(format indicator.StateFormat, data []byte) error {
+	var state stochasticAdapterState
+	if err := indicator.DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	if err := checkSavedState(state.Adaptor, sa.Name(), state.Stochastic != nil); err != nil {
+		return err
+	}
+	sa.Stochastic, sa.PreviousValues, sa.HasPrevious = state.Stochastic, state.PreviousValues, state.HasPrevious
+	sa.CurrentData, sa.swing = state.CurrentData, state.Swing
+	return nil
+}
@@ -0,0 +1,99 @@
+package indicator_adaptor_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/algorithm"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+// bottomingData falls for six bars closing on the lows, then bounces to 30% of the last three bars' range.
+func bottomingData() []model.DataPoint {
+	var data []model.DataPoint
+	for i := 1; i <= 6; i++ {
+		mid := 100 - float64(i)*2
+		data = append(data, model.DataPoint{Time: int64(i), Open: mid + 1, High: mid + 1, Low: mid - 1, Close: mid - 1})
+	}
+	return append(data, model.DataPoint{Time: 7, Open: 87, High: 88, Low: 87, Close: 88.2})
+}
+
+// toppingData mirrors bottomingData around 100.
+func toppingData() []model.DataPoint {
+	data := bottomingData()
+	for i := range data {
+		data[i].Open, data[i].High, data[i].Low, data[i].Close = 200-data[i].Open, 200-data[i].Low, 200-data[i].High, 200-data[i].Close
+	}
+	return data
+}
+
+func TestStochasticAdapterSignals(t *testing.T) {
+	tests := []struct {
+		name string
+		data []model.DataPoint
+		want model.StockAction
+	}{
+		{"cross up in oversold zone", bottomingData(), model.Buy},
+		{"cross down in overbought zone", toppingData(), model.Sell},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		adapter := indicator_adaptor.NewStochasticAdapter(ctx, 3, 1, 2, 80, 20, test_utils.NewMockMetricsCollector(t))
+		var signal model.TradingSignal
+		for _, dp := range tt.data {
+			if err := adapter.AddDataPoint(ctx, dp); err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			signal = adapter.GetSignal(ctx)
+			if dp.Time < 7 {
+				test_utils.AssertEqual(t, model.StockAction(model.Wait), signal.Action, tt.name+": should wait before the cross")
+			}
+		}
+		test_utils.AssertEqual(t, tt.want, signal.Action, tt.name+": signal does not match")
+		test_utils.AssertTrue(t, signal.Strength > 0.5, tt.name+": cross inside the zone should be strong")
+	}
+}
+
+func TestWilliamsRAdapterSignals(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  []model.DataPoint
+		want  model.StockAction
+		wantR float64
+	}{
+		{"leaves oversold zone", bottomingData(), model.Buy, -70},
+		{"leaves overbought zone", toppingData(), model.Sell, -30},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		adapter := indicator_adaptor.NewWilliamsRAdapter(ctx, 3, -20, -80, test_utils.NewMockMetricsCollector(t))
+		var signal model.TradingSignal
+		for _, dp := range tt.data {
+			adapter.AddDataPoint(ctx, dp)
+			signal = adapter.GetSignal(ctx)
+		}
+		test_utils.AssertEqual(t, tt.want, signal.Action, tt.name+": signal does not match")
+		test_utils.AssertTrue(t, math.Abs(adapter.IndicatorValues()["williams_r"]-tt.wantR) < 1e-9, tt.name+": %R does not match")
+	}
+}
+
+func TestOscillatorsInCombination(t *testing.T) {
+	ctx := context.Background()
+	mock := test_utils.NewMockMetricsCollector(t)
+	adaptors := []indicator_adaptor.IndicatorAdaptor{
+		indicator_adaptor.NewStochasticAdapter(ctx, 3, 1, 2, 80, 20, mock),
+		indicator_adaptor.NewWilliamsRAdapter(ctx, 3, -20, -80, mock),
+	}
+	algo := algorithm.NewCombinationTradingAlgorithm(ctx, adaptors, mock)
+
+	var signal model.TradingSignal
+	for _, dp := range bottomingData() {
+		signal = algo.Evaluate(ctx, dp)
+	}
+	test_utils.AssertEqual(t, model.StockAction(model.Buy), signal.Action, "Both oscillators should agree on the bounce")
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/vd09/trading-algorithm-backtesting-system/logger"
 	"github.com/vd09/trading-algorithm-backtesting-system/model"
 	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
 	"github.com/vd09/trading-algorithm-backtesting-system/utils"
 	"go.uber.org/zap"
 )
@@ -37,11 +38,28 @@ type SuperTrendAdapter struct {
 	SuperTrend    *indicator.SuperTrend
 	PreviousTrend bool
 	CurrentTrend  bool
+	PreviousLine  float64
+	CurrentLine   float64
+	CurrentData   model.DataPoint
 	initialized   InitializeStatus
 	logger        logger.LoggerInterface
 	metrics       *SuperTrendMetrics
 }
 
+func init() {
+	registry.RegisterAdaptor(registry.AdaptorEntry{
+		Name:        "supertrend",
+		Description: "Signals when the SuperTrend flips direction",
+		Params: []registry.Param{
+			{Name: "period", Type: registry.IntParam, Default: 10, Min: 2, Max: 1000},
+			{Name: "multiplier", Type: registry.FloatParam, Default: 3.0, Min: 0.1, Max: 100},
+		},
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (IndicatorAdaptor, error) {
+			return NewSuperTrendAdapter(ctx, p.Int("period"), p.Float("multiplier"), m), nil
+		},
+	})
+}
+
 // NewSuperTrendAdapter initializes a new SuperTrendAdapter instance.
 func NewSuperTrendAdapter(ctx context.Context, period int, multiplier float64, monitor monitor.Monitoring) *SuperTrendAdapter {
 	adapter := &SuperTrendAdapter{
@@ -73,22 +91,25 @@ func (sta *SuperTrendAdapter) AddDataPoint(ctx context.Context, data model.DataP
 	ctx = sta.getUpdateContext(ctx)
 	sta.logger.Debug(ctx, "Adding data point to SuperTrendAdapter", zap.Int64("timestamp", data.Time))
 
-	if err := sta.SuperTrend.AddDataPoint(ctx, data); err != nil {
+	if err := sta.SuperTrend.Update(ctx, data); err != nil {
 		sta.logger.Error(ctx, "Failed to add data point to SuperTrend", zap.Error(err))
 		return err
 	}
 
-	if sta.SuperTrend.Initialized {
-		SuperTrendLine, isUpTrend := sta.SuperTrend.CalculateSuperTrend()
+	sta.CurrentData = data
+	if sta.SuperTrend.Ready() {
+		values := sta.SuperTrend.Value()
 		if sta.initialized == NOT_INITIALIZED {
 			sta.initialized = INITIALIZED
 		} else {
 			sta.PreviousTrend = sta.CurrentTrend
+			sta.PreviousLine = sta.CurrentLine
 			sta.initialized = START_SIGNALING
 		}
-		sta.CurrentTrend = isUpTrend
+		sta.CurrentTrend = values.UpTrend
+		sta.CurrentLine = values.Line
 		sta.metrics.TrendCounter.SetValue(ctx, utils.B2F(sta.CurrentTrend), nil)
-		sta.metrics.TrendLine.SetValue(ctx, SuperTrendLine, nil)
+		sta.metrics.TrendLine.SetValue(ctx, sta.CurrentLine, nil)
 	}
 	return nil
 }
@@ -99,32 +120,59 @@ func (sta *SuperTrendAdapter) Name() string {
 }
 
 // GetSignal returns a trading signal based on the SuperTrend logic.
-// GetSignal returns a trading signal based on the SuperTrend logic.
-func (sta *SuperTrendAdapter) GetSignal(ctx context.Context) (result model.StockAction) {
+func (sta *SuperTrendAdapter) GetSignal(ctx context.Context) (result model.TradingSignal) {
 	ctx = sta.getUpdateContext(ctx)
 	defer func() {
-		sta.metrics.SignalCounter.IncrementCounter(ctx, monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result)))
+		sta.metrics.SignalCounter.IncrementCounter(ctx, monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result.Action)))
 	}()
+	result = model.TradingSignal{Time: sta.CurrentData.Time, Action: model.Wait}
 
 	if sta.initialized != START_SIGNALING {
 		sta.logger.Debug(ctx, "SuperTrend not ready for signaling", zap.String("status", "NOT_START_SIGNALING"))
-		return model.Wait
+		return result
 	}
-	if !sta.SuperTrend.Initialized {
+	if !sta.SuperTrend.Ready() {
 		sta.logger.Debug(ctx, "SuperTrend not initialized")
-		return model.Wait
+		return result
 	}
 
 	if sta.PreviousTrend == false && sta.CurrentTrend == true {
 		sta.logger.Info(ctx, "Buy signal detected", zap.Bool("previousTrend", sta.PreviousTrend), zap.Bool("currentTrend", sta.CurrentTrend))
-		return model.Buy
+		return sta.signal(model.Buy, "flipped to uptrend")
 	} else if sta.PreviousTrend == true && sta.CurrentTrend == false {
 		sta.logger.Info(ctx, "Sell signal detected", zap.Bool("previousTrend", sta.PreviousTrend), zap.Bool("currentTrend", sta.CurrentTrend))
-		return model.Sell
+		return sta.signal(model.Sell, "flipped to downtrend")
 	}
 
 	sta.logger.Debug(ctx, "No trading signal detected", zap.Bool("previousTrend", sta.PreviousTrend), zap.Bool("currentTrend", sta.CurrentTrend))
-	return model.Wait
+	return result
+}
+
+// signal builds a trend flip signal with the stop loss on the SuperTrend line.
+// The further the price closed from the line, the stronger the signal.
+func (sta *SuperTrendAdapter) signal(action model.StockAction, event string) model.TradingSignal {
+	price := sta.CurrentData.Close
+	return model.TradingSignal{
+		Time:     sta.CurrentData.Time,
+		Action:   action,
+		Strength: relativeStrength(price-sta.CurrentLine, price),
+		StopLoss: sta.CurrentLine,
+		Target:   rewardTarget(price, sta.CurrentLine),
+		Rationale: []model.Rationale{{
+			Source:    sta.Name(),
+			Indicator: "SuperTrend",
+			Event:     event,
+			Values:    []float64{sta.PreviousLine, sta.CurrentLine},
+		}},
+	}
+}
+
+// IndicatorValues returns the latest SuperTrend line and whether it is in an uptrend, as 1 or 0.
+func (sta *SuperTrendAdapter) IndicatorValues() map[string]float64 {
+	if !sta.SuperTrend.Ready() {
+		return nil
+	}
+	return map[string]float64{"supertrend_line": sta.CurrentLine, "uptrend": utils.B2F(sta.CurrentTrend)}
 }
 
 // Function to retrieve and update the context with common labels and the adapter name
@@ -132,3 +180,45 @@ func (sta *SuperTrendAdapter) getUpdateContext(ctx context.Context) context.Cont
 	ctx = getUpdatedCommonLabelsContext(ctx)
 	return context.WithValue(ctx, ADAPTOR_NAME_LABEL, sta.Name())
 }
+
+// superTrendAdapterState is the saved state of a SuperTrendAdapter.
+type superTrendAdapterState struct {
+	Adaptor       string
+	SuperTrend    *indicator.SuperTrend
+	PreviousTrend bool
+	CurrentTrend  bool
+	PreviousLine  float64
+	CurrentLine   float64
+	CurrentData   model.DataPoint
+	Initialized   InitializeStatus
+}
+
+// MarshalState encodes the full state of the adapter.
+func (sta *SuperTrendAdapter) MarshalState(format indicator.StateFormat) ([]byte, error) {
+	return indicator.EncodeState(format, superTrendAdapterState{
+		Adaptor:       sta.Name(),
+		SuperTrend:    sta.SuperTrend,
+		PreviousTrend: sta.PreviousTrend,
+		CurrentTrend:  sta.CurrentTrend,
+		PreviousLine:  sta.PreviousLine,
+		CurrentLine:   sta.CurrentLine,
+		CurrentData:   sta.CurrentData,
+		Initialized:   sta.initialized,
+	})
+}
+
+// UnmarshalState replaces the state of the adapter with one saved by an adapter with the same parameters.
+func (sta *SuperTrendAdapter) UnmarshalState(format indicator.StateFormat, data []byte) error {
+	var state superTrendAdapterState
+	if err := indicator.DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	if err := checkSavedState(state.Adaptor, sta.Name(), state.SuperTrend != nil); err != nil {
+		return err
+	}
+	sta.SuperTrend = state.SuperTrend
+	sta.PreviousTrend, sta.CurrentTrend = state.PreviousTrend, state.CurrentTrend
+	sta.PreviousLine, sta.CurrentLine = state.PreviousLine, state.CurrentLine
+	sta.CurrentData, sta.initialized = state.CurrentData, state.Initialized
+	return nil
+}
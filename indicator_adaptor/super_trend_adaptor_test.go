@@ -161,7 +161,7 @@ func TestGetSignal_BeforeInitialization(t *testing.T) {
 	ctx := context.Background()
 	mock := test_utils.NewMockMetricsCollector(t)
 	sta := indicator_adaptor.NewSuperTrendAdapter(ctx, 14, 3.0, mock)
-	signal := sta.GetSignal(ctx)
+	signal := sta.GetSignal(ctx).Action
 	if signal != model.Wait {
 		t.Fatalf("Expected signal to be Wait before initialization, got %v", signal)
 	}
@@ -182,7 +182,7 @@ func TestGetSignal_AfterInitialization_NoTrendChange(t *testing.T) {
 		}
 	}
 
-	signal := sta.GetSignal(ctx)
+	signal := sta.GetSignal(ctx).Action
 	if signal != model.Wait {
 		t.Fatalf("Expected signal to be Wait after initialization with no trend change, got %v", signal)
 	}
@@ -216,10 +216,16 @@ func TestGetSignal_TrendChangeToUptrend(t *testing.T) {
 		t.Fatalf("Unexpected error: %v", err)
 	}
 
-	signal := sta.GetSignal(ctx)
+	signal := sta.GetSignal(ctx).Action
 	if signal != model.Buy {
 		t.Fatalf("Expected signal to be Buy, got %v", signal)
 	}
+
+	detailed := sta.GetSignal(ctx)
+	test_utils.AssertEqual(t, sta.CurrentLine, detailed.StopLoss, "Stop loss should sit on the SuperTrend line")
+	test_utils.AssertEqual(t, data.Close+2*(data.Close-sta.CurrentLine), detailed.Target, "Target does not match")
+	test_utils.AssertTrue(t, detailed.Strength > 0.5 && detailed.Strength <= 1, "Signal strength is out of range")
+	test_utils.AssertEqual(t, "flipped to uptrend", detailed.Rationale[0].Event, "Rationale event does not match")
 }
 
 func TestGetSignal_TrendChangeToDowntrend(t *testing.T) {
@@ -251,7 +257,7 @@ func TestGetSignal_TrendChangeToDowntrend(t *testing.T) {
 		t.Fatalf("Unexpected error: %v", err)
 	}
 
-	signal := sta.GetSignal(ctx)
+	signal := sta.GetSignal(ctx).Action
 	if signal != model.Sell {
 		t.Fatalf("Expected signal to be Sell, got %v", signal)
 	}
@@ -285,7 +291,7 @@ func TestGetSignal_NoTrendChange(t *testing.T) {
 		t.Fatalf("Unexpected error: %v", err)
 	}
 
-	signal := sta.GetSignal(ctx)
+	signal := sta.GetSignal(ctx).Action
 	if signal != model.Wait {
 		t.Fatalf("Expected signal to be Wait, got %v", signal)
 	}
@@ -1,14 +1,14 @@
 package indicator_adaptor
 
 import (
-	"context"
-
-	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
 )
 
-type IndicatorAdaptor interface {
-	Name() string
-	Clone(ctx context.Context) IndicatorAdaptor
-	AddDataPoint(ctx context.Context, data model.DataPoint) error
-	GetSignal(ctx context.Context) model.StockAction
+// IndicatorAdaptor turns the bars it is fed into signals. It is declared by the registry, which the adaptors register with.
+type IndicatorAdaptor = registry.IndicatorAdaptor
+
+// ValueReporter is an adaptor that reports its key indicator values on the latest bar, e.g. for audit records.
+// Values that are not available yet are left out.
+type ValueReporter interface {
+	IndicatorValues() map[string]float64
 }
@@ -0,0 +1,120 @@
+package indicator_adaptor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/algorithm"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+// accumulationData falls for six quiet bars closing on the lows, then rallies on ten times the volume closing on the high.
+func accumulationData() []model.DataPoint {
+	var data []model.DataPoint
+	for i := 1; i <= 6; i++ {
+		mid := 100 - float64(i)*2
+		data = append(data, model.DataPoint{Time: int64(i), Open: mid + 1, High: mid + 1, Low: mid - 1, Close: mid - 1, Volume: 100})
+	}
+	return append(data, model.DataPoint{Time: 7, Open: 88, High: 92, Low: 88, Close: 92, Volume: 1000})
+}
+
+// distributionData mirrors accumulationData around 100.
+func distributionData() []model.DataPoint {
+	data := accumulationData()
+	for i := range data {
+		data[i].Open, data[i].High, data[i].Low, data[i].Close = 200-data[i].Open, 200-data[i].Low, 200-data[i].High, 200-data[i].Close
+	}
+	return data
+}
+
+func volumeAdaptors(t *testing.T, ctx context.Context, m monitor.Monitoring) map[string]indicator_adaptor.IndicatorAdaptor {
+	obv, err := indicator_adaptor.NewVolumeLineAdapter(ctx, indicator_adaptor.OnBalanceVolume, 3, m)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ad, err := indicator_adaptor.NewVolumeLineAdapter(ctx, indicator_adaptor.AccumulationDistribution, 3, m)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return map[string]indicator_adaptor.IndicatorAdaptor{
+		"obv":  obv,
+		"ad":   ad,
+		"vwap": indicator_adaptor.NewVWAPAdapter(ctx, 2, m),
+		"mfi":  indicator_adaptor.NewMFIAdapter(ctx, 3, 80, 20, m),
+		"cmf":  indicator_adaptor.NewCMFAdapter(ctx, 3, 0.05, m),
+	}
+}
+
+func TestVolumeAdaptersSignals(t *testing.T) {
+	tests := []struct {
+		name string
+		data []model.DataPoint
+		want model.StockAction
+	}{
+		{"accumulation", accumulationData(), model.Buy},
+		{"distribution", distributionData(), model.Sell},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		for name, adapter := range volumeAdaptors(t, ctx, test_utils.NewMockMetricsCollector(t)) {
+			var signal model.TradingSignal
+			for _, dp := range tt.data {
+				if err := adapter.AddDataPoint(ctx, dp); err != nil {
+					t.Fatalf("%s %s: unexpected error: %v", tt.name, name, err)
+				}
+				signal = adapter.GetSignal(ctx)
+				if dp.Time < 7 {
+					test_utils.AssertEqual(t, model.StockAction(model.Wait), signal.Action, tt.name+" "+name+": should wait before the volume turns")
+				}
+			}
+			test_utils.AssertEqual(t, tt.want, signal.Action, tt.name+" "+name+": signal does not match")
+			test_utils.AssertTrue(t, signal.Strength >= 0.5, tt.name+" "+name+": volume surge should be strong")
+			if tt.want == model.Buy {
+				test_utils.AssertTrue(t, signal.StopLoss < 92 && signal.Target > 92, tt.name+" "+name+": exits should surround the close")
+			} else {
+				test_utils.AssertTrue(t, signal.StopLoss > 108 && signal.Target < 108, tt.name+" "+name+": exits should surround the close")
+			}
+		}
+	}
+}
+
+func TestVWAPAdapterSession(t *testing.T) {
+	ctx := context.Background()
+	adapter := indicator_adaptor.NewVWAPAdapter(ctx, 2, test_utils.NewMockMetricsCollector(t))
+	data := accumulationData()
+	for _, dp := range data[:6] {
+		adapter.AddDataPoint(ctx, dp)
+	}
+
+	// The rally opens the next session, there is no VWAP of that session to cross yet.
+	rally := data[6]
+	rally.Time = 24 * 60 * 60 * 1000
+	adapter.AddDataPoint(ctx, rally)
+	test_utils.AssertEqual(t, model.StockAction(model.Wait), adapter.GetSignal(ctx).Action, "first bar of a session should not cross the previous session's VWAP")
+}
+
+func TestNewVolumeLineAdapterUnknownLine(t *testing.T) {
+	_, err := indicator_adaptor.NewVolumeLineAdapter(context.Background(), "volume_profile", 20, test_utils.NewMockMetricsCollector(t))
+	test_utils.AssertTrue(t, err != nil, "unknown volume line should be rejected")
+}
+
+func TestVolumeConfirmationInCombination(t *testing.T) {
+	ctx := context.Background()
+	mock := test_utils.NewMockMetricsCollector(t)
+	adaptors := volumeAdaptors(t, ctx, mock)
+	algo := algorithm.NewCombinationTradingAlgorithm(ctx, []indicator_adaptor.IndicatorAdaptor{
+		indicator_adaptor.NewWilliamsRAdapter(ctx, 3, -20, -80, mock),
+		adaptors["obv"],
+		adaptors["cmf"],
+	}, mock)
+
+	var signal model.TradingSignal
+	for _, dp := range accumulationData() {
+		signal = algo.Evaluate(ctx, dp)
+	}
+	test_utils.AssertEqual(t, model.StockAction(model.Buy), signal.Action, "Volume should confirm the bounce")
+}
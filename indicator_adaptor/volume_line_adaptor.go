@@ -0,0 +1,266 @@
+package indicator_adaptor
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/constraint"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator"
+	"github.com/vd09/trading-algorithm-backtesting-system/logger"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	VOLUME_LINE_LABEL = "volume_line_type"
+)
+
+// VolumeLine selects the cumulative volume line a VolumeLineAdapter follows.
+type VolumeLine string
+
+const (
+	OnBalanceVolume          VolumeLine = "obv"
+	AccumulationDistribution VolumeLine = "accumulation_distribution"
+)
+
+type VolumeLineMetrics struct {
+	SignalCounter monitor.CounterMetric
+	LineGauge     monitor.GaugeMetric
+	monitor       monitor.Monitoring
+}
+
+// VolumeLineAdapter buys when the On-Balance Volume or Accumulation/Distribution line crosses above its
+// Period bar average, volume flowing in, and sells when it crosses below.
+type VolumeLineAdapter struct {
+	Line                     VolumeLine
+	Period                   int
+	OBV                      *indicator.OBV
+	AccumulationDistribution *indicator.AccumulationDistribution
+	// Lines and Volumes hold the line and the volume of the latest Period bars.
+	Lines       []float64
+	Volumes     []float64
+	PreviousGap float64
+	// HasPrevious is set when PreviousGap holds the gap to the average of the bar before the current one.
+	HasPrevious bool
+	CurrentData model.DataPoint
+	swing       priceSwing
+	logger      logger.LoggerInterface
+	metrics     *VolumeLineMetrics
+}
+
+func init() {
+	registry.RegisterAdaptor(registry.AdaptorEntry{
+		Name:        "obv",
+		Description: "Signals when On-Balance Volume crosses its moving average",
+		Params: []registry.Param{
+			{Name: "period", Type: registry.IntParam, Description: "Bars the average of the line is taken over", Default: 20, Min: 2, Max: 1000},
+		},
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (IndicatorAdaptor, error) {
+			return NewVolumeLineAdapter(ctx, OnBalanceVolume, p.Int("period"), m)
+		},
+	})
+	registry.RegisterAdaptor(registry.AdaptorEntry{
+		Name:        "accumulation_distribution",
+		Description: "Signals when the Accumulation/Distribution line crosses its moving average",
+		Params: []registry.Param{
+			{Name: "period", Type: registry.IntParam, Description: "Bars the average of the line is taken over", Default: 20, Min: 2, Max: 1000},
+		},
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (IndicatorAdaptor, error) {
+			return NewVolumeLineAdapter(ctx, AccumulationDistribution, p.Int("period"), m)
+		},
+	})
+}
+
+func NewVolumeLineAdapter(ctx context.Context, line VolumeLine, period int, monitor monitor.Monitoring) (*VolumeLineAdapter, error) {
+	adapter := &VolumeLineAdapter{
+		Line:   line,
+		Period: period,
+		logger: logger.GetLogger(),
+	}
+	switch line {
+	case OnBalanceVolume:
+		adapter.OBV = indicator.NewOBV()
+	case AccumulationDistribution:
+		adapter.AccumulationDistribution = indicator.NewAccumulationDistribution()
+	default:
+		return nil, fmt.Errorf("unknown volume line %q", line)
+	}
+	adapter.registerMetrics(ctx, monitor)
+	return adapter, nil
+}
+
+func (va *VolumeLineAdapter) registerMetrics(ctx context.Context, m monitor.Monitoring) {
+	ctx = va.getUpdateContext(ctx)
+	va.metrics = &VolumeLineMetrics{
+		monitor:       m,
+		SignalCounter: m.RegisterCounter(ctx, "volume_line_signals_generated", "Total number of volume line signals generated", monitor.Labels{constraint.SIGNAL_TYPE_LABEL}),
+		LineGauge:     m.RegisterGauge(ctx, "volume_line", "Current volume line and its average", monitor.Labels{VOLUME_LINE_LABEL}),
+	}
+}
+
+func (va *VolumeLineAdapter) Clone(ctx context.Context) IndicatorAdaptor {
+	adapter, _ := NewVolumeLineAdapter(ctx, va.Line, va.Period, va.metrics.monitor)
+	return adapter
+}
+
+func (va *VolumeLineAdapter) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	ctx = va.getUpdateContext(ctx)
+	va.logger.Debug(ctx, "Adding data point to VolumeLineAdapter", zap.Int64("timestamp", data.Time))
+
+	var err error
+	if va.OBV != nil {
+		err = va.OBV.Update(ctx, data)
+	} else {
+		err = va.AccumulationDistribution.Update(ctx, data)
+	}
+	if err != nil {
+		va.logger.Error(ctx, "Failed to add data point to volume line", zap.Error(err))
+		return err
+	}
+	va.CurrentData = data
+	va.swing.add(data)
+
+	previousGap, wasFull := va.gap(), len(va.Lines) == va.Period
+	va.Lines = utils.AppendWindow(va.Lines, va.value(), va.Period)
+	va.Volumes = utils.AppendWindow(va.Volumes, data.Volume, va.Period)
+	va.PreviousGap, va.HasPrevious = previousGap, wasFull
+
+	va.metrics.LineGauge.SetGauge(ctx, va.value(), monitor.NewTagsKV(VOLUME_LINE_LABEL, "line"))
+	va.metrics.LineGauge.SetGauge(ctx, mean(va.Lines), monitor.NewTagsKV(VOLUME_LINE_LABEL, "average"))
+	return nil
+}
+
+func (va *VolumeLineAdapter) value() float64 {
+	if va.OBV != nil {
+		return va.OBV.Value()
+	}
+	return va.AccumulationDistribution.Value()
+}
+
+// gap returns how far the line is above its average, zero before a full period.
+func (va *VolumeLineAdapter) gap() float64 {
+	if len(va.Lines) < va.Period {
+		return 0
+	}
+	return va.Lines[len(va.Lines)-1] - mean(va.Lines)
+}
+
+func (va *VolumeLineAdapter) Name() string {
+	return fmt.Sprintf("VolumeLine_%s_%d", va.Line, va.Period)
+}
+
+func (va *VolumeLineAdapter) GetSignal(ctx context.Context) (result model.TradingSignal) {
+	ctx = va.getUpdateContext(ctx)
+	defer func() {
+		tags := monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result.Action))
+		tags.Add(ADAPTOR_NAME_LABEL, va.Name())
+		va.metrics.SignalCounter.IncrementCounter(ctx, tags)
+	}()
+	result = model.TradingSignal{Time: va.CurrentData.Time, Action: model.Wait}
+	zaps := []zap.Field{zap.String("adapter", va.Name()), zap.Any("time", va.CurrentData.Time)}
+
+	if !va.HasPrevious {
+		va.logger.Debug(ctx, "Not enough volume line values for signal generation", zaps...)
+		return result
+	}
+
+	gap := va.gap()
+	if va.PreviousGap <= 0 && gap > 0 {
+		va.logger.Info(ctx, "Buy signal detected", zaps...)
+		return va.signal(model.Buy, gap, "crossed above")
+	}
+	if va.PreviousGap >= 0 && gap < 0 {
+		va.logger.Info(ctx, "Sell signal detected", zaps...)
+		return va.signal(model.Sell, gap, "crossed below")
+	}
+
+	va.logger.Debug(ctx, "No trading signal detected", zaps...)
+	return result
+}
+
+// signal builds a line crossover signal, a gap of a bar's average volume or more gives full strength.
+func (va *VolumeLineAdapter) signal(action model.StockAction, gap float64, event string) model.TradingSignal {
+	stopLoss, target := va.swing.exits(action, va.CurrentData.Close)
+	strength := 0.5
+	if volume := mean(va.Volumes); volume > 0 {
+		strength = clampStrength(0.5 + 0.5*math.Abs(gap)/volume)
+	}
+	return model.TradingSignal{
+		Time:     va.CurrentData.Time,
+		Action:   action,
+		Strength: strength,
+		StopLoss: stopLoss,
+		Target:   target,
+		Rationale: []model.Rationale{{
+			Source:    va.Name(),
+			Indicator: string(va.Line),
+			Event:     event,
+			Reference: fmt.Sprintf("%d bar average", va.Period),
+			Values:    []float64{mean(va.Lines), va.value()},
+		}},
+	}
+}
+
+// IndicatorValues returns the latest line and its average.
+func (va *VolumeLineAdapter) IndicatorValues() map[string]float64 {
+	if len(va.Lines) == 0 {
+		return nil
+	}
+	return map[string]float64{string(va.Line): va.value(), string(va.Line) + "_average": mean(va.Lines)}
+}
+
+// Function to retrieve and update the slice from context
+func (va *VolumeLineAdapter) getUpdateContext(ctx context.Context) context.Context {
+	ctx = getUpdatedCommonLabelsContext(ctx)
+	return context.WithValue(ctx, ADAPTOR_NAME_LABEL, va.Name())
+}
+
+// volumeLineAdapterState is the saved state of a VolumeLineAdapter.
+type volumeLineAdapterState struct {
+	Adaptor                  string
+	OBV                      *indicator.OBV
+	AccumulationDistribution *indicator.AccumulationDistribution
+	Lines                    []float64
+	Volumes                  []float64
+	PreviousGap              float64
+	HasPrevious              bool
+	CurrentData              model.DataPoint
+	Swing                    priceSwing
+}
+
+// MarshalState encodes the full state of the adapter.
+func (va *VolumeLineAdapter) MarshalState(format indicator.StateFormat) ([]byte, error) {
+	return indicator.EncodeState(format, volumeLineAdapterState{
+		Adaptor:                  va.Name(),
+		OBV:                      va.OBV,
+		AccumulationDistribution: va.AccumulationDistribution,
+		Lines:                    va.Lines,
+		Volumes:                  va.Volumes,
+		PreviousGap:              va.PreviousGap,
+		HasPrevious:              va.HasPrevious,
+		CurrentData:              va.CurrentData,
+		Swing:                    va.swing,
+	})
+}
+
+// UnmarshalState replaces the state of the adapter with one saved by an adapter with the same parameters.
+func (va *VolumeLineAdapter) UnmarshalState(format indicator.StateFormat, data []byte) error {
+	var state volumeLineAdapterState
+	if err := indicator.DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	if len(state.Lines) == 0 {
+		// Lines without any data have only zero fields, which the binary format leaves out.
+		state.OBV, state.AccumulationDistribution = va.OBV, va.AccumulationDistribution
+	}
+	if err := checkSavedState(state.Adaptor, va.Name(), (state.OBV != nil) == (va.OBV != nil) && (state.AccumulationDistribution != nil) == (va.AccumulationDistribution != nil)); err != nil {
+		return err
+	}
+	va.OBV, va.AccumulationDistribution, va.Lines, va.Volumes = state.OBV, state.AccumulationDistribution, state.Lines, state.Volumes
+	va.PreviousGap, va.HasPrevious, va.CurrentData, va.swing = state.PreviousGap, state.HasPrevious, state.CurrentData, state.Swing
+	return nil
+}
@@ -0,0 +1,208 @@
+package indicator_adaptor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/constraint"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator"
+	"github.com/vd09/trading-algorithm-backtesting-system/logger"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
+	"go.uber.org/zap"
+)
+
+const (
+	VWAP_LINE_LABEL = "vwap_line_type"
+)
+
+type VWAPMetrics struct {
+	SignalCounter monitor.CounterMetric
+	LineGauge     monitor.GaugeMetric
+	monitor       monitor.Monitoring
+}
+
+// VWAPAdapter buys when the close crosses above the session VWAP and sells when it crosses below.
+// Crosses are only taken within a session, the first bar of a session starts a new VWAP.
+// The stop loss and target are placed at the standard deviation bands.
+type VWAPAdapter struct {
+	VWAP          *indicator.VWAP
+	PreviousClose float64
+	PreviousVWAP  float64
+	// HasPrevious is set when the previous values belong to the bar before the current one in the same session.
+	HasPrevious bool
+	CurrentData model.DataPoint
+	swing       priceSwing
+	logger      logger.LoggerInterface
+	metrics     *VWAPMetrics
+}
+
+func init() {
+	registry.RegisterAdaptor(registry.AdaptorEntry{
+		Name:        "vwap",
+		Description: "Signals when the close crosses the session VWAP, with exits at its standard deviation bands",
+		Params: []registry.Param{
+			{Name: "band_multiplier", Type: registry.FloatParam, Description: "Standard deviations between the VWAP and the bands", Default: 2.0, Min: 0.1, Max: 10},
+		},
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (IndicatorAdaptor, error) {
+			return NewVWAPAdapter(ctx, p.Float("band_multiplier"), m), nil
+		},
+	})
+}
+
+func NewVWAPAdapter(ctx context.Context, bandMultiplier float64, monitor monitor.Monitoring) *VWAPAdapter {
+	adapter := &VWAPAdapter{
+		VWAP:   indicator.NewVWAP(bandMultiplier),
+		logger: logger.GetLogger(),
+	}
+	adapter.registerMetrics(ctx, monitor)
+	return adapter
+}
+
+func (va *VWAPAdapter) registerMetrics(ctx context.Context, m monitor.Monitoring) {
+	ctx = va.getUpdateContext(ctx)
+	va.metrics = &VWAPMetrics{
+		monitor:       m,
+		SignalCounter: m.RegisterCounter(ctx, "vwap_signals_generated", "Total number of VWAP signals generated", monitor.Labels{constraint.SIGNAL_TYPE_LABEL}),
+		LineGauge:     m.RegisterGauge(ctx, "vwap_lines", "Current VWAP and its bands", monitor.Labels{VWAP_LINE_LABEL}),
+	}
+}
+
+func (va *VWAPAdapter) Clone(ctx context.Context) IndicatorAdaptor {
+	return NewVWAPAdapter(ctx, va.VWAP.BandMultiplier, va.metrics.monitor)
+}
+
+func (va *VWAPAdapter) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	ctx = va.getUpdateContext(ctx)
+	va.logger.Debug(ctx, "Adding data point to VWAPAdapter", zap.Int64("timestamp", data.Time))
+
+	previous, session := va.VWAP.Value(), va.VWAP.Session
+	if err := va.VWAP.Update(ctx, data); err != nil {
+		va.logger.Error(ctx, "Failed to add data point to VWAP", zap.Error(err))
+		return err
+	}
+	va.PreviousClose, va.PreviousVWAP = va.CurrentData.Close, previous.VWAP
+	va.HasPrevious = session != "" && session == va.VWAP.Session
+	va.CurrentData = data
+	va.swing.add(data)
+
+	values := va.VWAP.Value()
+	va.metrics.LineGauge.SetGauge(ctx, values.VWAP, monitor.NewTagsKV(VWAP_LINE_LABEL, "vwap"))
+	va.metrics.LineGauge.SetGauge(ctx, values.UpperBand, monitor.NewTagsKV(VWAP_LINE_LABEL, "upper_band"))
+	va.metrics.LineGauge.SetGauge(ctx, values.LowerBand, monitor.NewTagsKV(VWAP_LINE_LABEL, "lower_band"))
+	return nil
+}
+
+func (va *VWAPAdapter) Name() string {
+	return fmt.Sprintf("VWAP_%.2f", va.VWAP.BandMultiplier)
+}
+
+func (va *VWAPAdapter) GetSignal(ctx context.Context) (result model.TradingSignal) {
+	ctx = va.getUpdateContext(ctx)
+	defer func() {
+		tags := monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result.Action))
+		tags.Add(ADAPTOR_NAME_LABEL, va.Name())
+		va.metrics.SignalCounter.IncrementCounter(ctx, tags)
+	}()
+	result = model.TradingSignal{Time: va.CurrentData.Time, Action: model.Wait}
+	zaps := []zap.Field{zap.String("adapter", va.Name()), zap.Any("time", va.CurrentData.Time)}
+
+	if !va.HasPrevious {
+		va.logger.Debug(ctx, "No previous bar in the session", zaps...)
+		return result
+	}
+
+	current := va.VWAP.Value()
+	if va.PreviousClose <= va.PreviousVWAP && va.CurrentData.Close > current.VWAP {
+		va.logger.Info(ctx, "Buy signal detected", zaps...)
+		return va.signal(model.Buy, "close crossed above")
+	}
+	if va.PreviousClose >= va.PreviousVWAP && va.CurrentData.Close < current.VWAP {
+		va.logger.Info(ctx, "Sell signal detected", zaps...)
+		return va.signal(model.Sell, "close crossed below")
+	}
+
+	va.logger.Debug(ctx, "No trading signal detected", zaps...)
+	return result
+}
+
+// signal builds a VWAP cross signal with the exits at the bands, or behind the recent swing while
+// the bands do not surround the close.
+func (va *VWAPAdapter) signal(action model.StockAction, event string) model.TradingSignal {
+	current, price := va.VWAP.Value(), va.CurrentData.Close
+	stopLoss, target := va.swing.exits(action, price)
+	if current.LowerBand < price && price < current.UpperBand {
+		stopLoss, target = current.LowerBand, current.UpperBand
+		if action == model.Sell {
+			stopLoss, target = current.UpperBand, current.LowerBand
+		}
+	}
+	return model.TradingSignal{
+		Time:     va.CurrentData.Time,
+		Action:   action,
+		Strength: relativeStrength(price-current.VWAP, price),
+		StopLoss: stopLoss,
+		Target:   target,
+		Rationale: []model.Rationale{{
+			Source:    va.Name(),
+			Indicator: "VWAP",
+			Event:     event,
+			Reference: "session VWAP",
+			Values:    []float64{current.VWAP, price},
+		}},
+	}
+}
+
+// IndicatorValues returns the latest VWAP and bands.
+func (va *VWAPAdapter) IndicatorValues() map[string]float64 {
+	if !va.VWAP.Ready() {
+		return nil
+	}
+	values := va.VWAP.Value()
+	return map[string]float64{"vwap": values.VWAP, "vwap_upper": values.UpperBand, "vwap_lower": values.LowerBand}
+}
+
+// Function to retrieve and update the slice from context
+func (va *VWAPAdapter) getUpdateContext(ctx context.Context) context.Context {
+	ctx = getUpdatedCommonLabelsContext(ctx)
+	return context.WithValue(ctx, ADAPTOR_NAME_LABEL, va.Name())
+}
+
+// vwapAdapterState is the saved state of a VWAPAdapter.
+type vwapAdapterState struct {
+	Adaptor       string
+	VWAP          *indicator.VWAP
+	PreviousClose float64
+	PreviousVWAP  float64
+	HasPrevious   bool
+	CurrentData   model.DataPoint
+	Swing         priceSwing
+}
+
+// MarshalState encodes the full state of the adapter.
+func (va *VWAPAdapter) MarshalState(format indicator.StateFormat) ([]byte, error) {
+	return indicator.EncodeState(format, vwapAdapterState{
+		Adaptor:       va.Name(),
+		VWAP:          va.VWAP,
+		PreviousClose: va.PreviousClose,
+		PreviousVWAP:  va.PreviousVWAP,
+		HasPrevious:   va.HasPrevious,
+		CurrentData:   va.CurrentData,
+		Swing:         va.swing,
+	})
+}
+
+// UnmarshalState replaces the state of the adapter with one saved by an adapter with the same parameters.
+func (va *VWAPAdapter) UnmarshalState(format indicator.StateFormat, data []byte) error {
+	var state vwapAdapterState
+	if err := indicator.DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	if err := checkSavedState(state.Adaptor, va.Name(), state.VWAP != nil); err != nil {
+		return err
+	}
+	va.VWAP, va.PreviousClose, va.PreviousVWAP, va.HasPrevious = state.VWAP, state.PreviousClose, state.PreviousVWAP, state.HasPrevious
+	va.CurrentData, va.swing = state.CurrentData, state.Swing
+	return nil
+}
@@ -0,0 +1,203 @@
+package indicator_adaptor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/constraint"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator"
+	"github.com/vd09/trading-algorithm-backtesting-system/logger"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
+	"go.uber.org/zap"
+)
+
+type WilliamsRMetrics struct {
+	SignalCounter monitor.CounterMetric
+	ValueGauge    monitor.GaugeMetric
+	monitor       monitor.Monitoring
+}
+
+// WilliamsRAdapter buys when %R climbs back out of the oversold zone and sells when it falls back out of
+// the overbought zone. The thresholds are on the -100 to 0 scale of %R, e.g. -20 and -80.
+type WilliamsRAdapter struct {
+	WilliamsR           *indicator.WilliamsR
+	OverboughtThreshold float64
+	OversoldThreshold   float64
+	PreviousValue       float64
+	// HasPrevious is set when PreviousValue holds the %R of the bar before the current one.
+	HasPrevious bool
+	CurrentData model.DataPoint
+	swing       priceSwing
+	logger      logger.LoggerInterface
+	metrics     *WilliamsRMetrics
+}
+
+func init() {
+	registry.RegisterAdaptor(registry.AdaptorEntry{
+		Name:        "williams_r",
+		Description: "Buys when Williams %R leaves the oversold zone and sells when it leaves the overbought zone",
+		Params: []registry.Param{
+			{Name: "period", Type: registry.IntParam, Default: 14, Min: 1, Max: 1000},
+			{Name: "overbought", Type: registry.FloatParam, Default: -20.0, Min: -99, Max: -1},
+			{Name: "oversold", Type: registry.FloatParam, Default: -80.0, Min: -99, Max: -1},
+		},
+		Validate: func(p registry.Params) error {
+			if p.Float("oversold") >= p.Float("overbought") {
+				return errors.New("oversold must be lower than overbought")
+			}
+			return nil
+		},
+		New: func(ctx context.Context, p registry.Params, m monitor.Monitoring) (IndicatorAdaptor, error) {
+			return NewWilliamsRAdapter(ctx, p.Int("period"), p.Float("overbought"), p.Float("oversold"), m), nil
+		},
+	})
+}
+
+func NewWilliamsRAdapter(ctx context.Context, period int, overboughtThreshold, oversoldThreshold float64, monitor monitor.Monitoring) *WilliamsRAdapter {
+	adapter := &WilliamsRAdapter{
+		WilliamsR:           indicator.NewWilliamsR(period),
+		OverboughtThreshold: overboughtThreshold,
+		OversoldThreshold:   oversoldThreshold,
+		logger:              logger.GetLogger(),
+	}
+	adapter.registerMetrics(ctx, monitor)
+	return adapter
+}
+
+func (wa *WilliamsRAdapter) registerMetrics(ctx context.Context, m monitor.Monitoring) {
+	ctx = wa.getUpdateContext(ctx)
+	wa.metrics = &WilliamsRMetrics{
+		monitor:       m,
+		SignalCounter: m.RegisterCounter(ctx, "williams_r_signals_generated", "Total number of Williams %R signals generated", monitor.Labels{constraint.SIGNAL_TYPE_LABEL}),
+		ValueGauge:    m.RegisterGauge(ctx, "williams_r_value", "Current value of Williams %R", nil),
+	}
+}
+
+func (wa *WilliamsRAdapter) Clone(ctx context.Context) IndicatorAdaptor {
+	return NewWilliamsRAdapter(ctx, wa.WilliamsR.Period, wa.OverboughtThreshold, wa.OversoldThreshold, wa.metrics.monitor)
+}
+
+func (wa *WilliamsRAdapter) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	ctx = wa.getUpdateContext(ctx)
+	wa.logger.Debug(ctx, "Adding data point to WilliamsRAdapter", zap.Int64("timestamp", data.Time))
+
+	previous, wasInitialized := wa.WilliamsR.Value(), wa.WilliamsR.Ready()
+	if err := wa.WilliamsR.Update(ctx, data); err != nil {
+		wa.logger.Error(ctx, "Failed to add data point to Williams %R", zap.Error(err))
+		return err
+	}
+	wa.CurrentData = data
+	wa.swing.add(data)
+	wa.PreviousValue, wa.HasPrevious = previous, wasInitialized
+
+	if wa.WilliamsR.Ready() {
+		wa.metrics.ValueGauge.SetGauge(ctx, wa.WilliamsR.Value(), nil)
+	}
+	return nil
+}
+
+func (wa *WilliamsRAdapter) Name() string {
+	return fmt.Sprintf("WilliamsR_%d_%.0f_%.0f", wa.WilliamsR.Period, wa.OverboughtThreshold, wa.OversoldThreshold)
+}
+
+func (wa *WilliamsRAdapter) GetSignal(ctx context.Context) (result model.TradingSignal) {
+	ctx = wa.getUpdateContext(ctx)
+	defer func() {
+		tags := monitor.NewTagsKV(constraint.SIGNAL_TYPE_LABEL, string(result.Action))
+		tags.Add(ADAPTOR_NAME_LABEL, wa.Name())
+		wa.metrics.SignalCounter.IncrementCounter(ctx, tags)
+	}()
+	result = model.TradingSignal{Time: wa.CurrentData.Time, Action: model.Wait}
+	zaps := []zap.Field{zap.String("adapter", wa.Name()), zap.Any("time", wa.CurrentData.Time)}
+
+	if !wa.HasPrevious {
+		wa.logger.Debug(ctx, "Not enough Williams %R values for signal generation", zaps...)
+		return result
+	}
+
+	previous, current := wa.PreviousValue, wa.WilliamsR.Value()
+	if previous < wa.OversoldThreshold && current >= wa.OversoldThreshold {
+		wa.logger.Info(ctx, "Buy signal detected", zaps...)
+		return wa.signal(model.Buy, (wa.OversoldThreshold-previous)/(100+wa.OversoldThreshold), "crossed up through", wa.OversoldThreshold)
+	}
+	if previous > wa.OverboughtThreshold && current <= wa.OverboughtThreshold {
+		wa.logger.Info(ctx, "Sell signal detected", zaps...)
+		return wa.signal(model.Sell, (previous-wa.OverboughtThreshold)/-wa.OverboughtThreshold, "crossed down through", wa.OverboughtThreshold)
+	}
+
+	wa.logger.Debug(ctx, "No trading signal detected", zaps...)
+	return result
+}
+
+// signal builds a zone exit signal, the deeper %R went into the zone the stronger the signal.
+func (wa *WilliamsRAdapter) signal(action model.StockAction, depth float64, event string, threshold float64) model.TradingSignal {
+	stopLoss, target := wa.swing.exits(action, wa.CurrentData.Close)
+	return model.TradingSignal{
+		Time:     wa.CurrentData.Time,
+		Action:   action,
+		Strength: clampStrength(0.5 + depth),
+		StopLoss: stopLoss,
+		Target:   target,
+		Rationale: []model.Rationale{{
+			Source:    wa.Name(),
+			Indicator: "Williams %R",
+			Event:     event,
+			Reference: strconv.FormatFloat(threshold, 'g', -1, 64),
+			Values:    []float64{wa.PreviousValue, wa.WilliamsR.Value()},
+		}},
+	}
+}
+
+// IndicatorValues returns the latest %R.
+func (wa *WilliamsRAdapter) IndicatorValues() map[string]float64 {
+	if !wa.WilliamsR.Ready() {
+		return nil
+	}
+	return map[string]float64{"williams_r": wa.WilliamsR.Value()}
+}
+
+// Function to retrieve and update the slice from context
+func (wa *WilliamsRAdapter) getUpdateContext(ctx context.Context) context.Context {
+	ctx = getUpdatedCommonLabelsContext(ctx)
+	return context.WithValue(ctx, ADAPTOR_NAME_LABEL, wa.Name())
+}
+
+// williamsRAdapterState is the saved state of a WilliamsRAdapter.
+type williamsRAdapterState struct {
+	Adaptor       string
+	WilliamsR     *indicator.WilliamsR
+	PreviousValue float64
+	HasPrevious   bool
+	CurrentData   model.DataPoint
+	Swing         priceSwing
+}
+
+// MarshalState encodes the full state of the adapter.
+func (wa *WilliamsRAdapter) MarshalState(format indicator.StateFormat) ([]byte, error) {
+	return indicator.EncodeState(format, williamsRAdapterState{
+		Adaptor:       wa.Name(),
+		WilliamsR:     wa.WilliamsR,
+		PreviousValue: wa.PreviousValue,
+		HasPrevious:   wa.HasPrevious,
+		CurrentData:   wa.CurrentData,
+		Swing:         wa.swing,
+	})
+}
+
+// UnmarshalState replaces the state of the adapter with one saved by an adapter with the same parameters.
+func (wa *WilliamsRAdapter) UnmarshalState(format indicator.StateFormat, data []byte) error {
+	var state williamsRAdapterState
+	if err := indicator.DecodeState(format, data, &state); err != nil {
+		return err
+	}
+	if err := checkSavedState(state.Adaptor, wa.Name(), state.WilliamsR != nil); err != nil {
+		return err
+	}
+	wa.WilliamsR, wa.PreviousValue, wa.HasPrevious = state.WilliamsR, state.PreviousValue, state.HasPrevious
+	wa.CurrentData, wa.swing = state.CurrentData, state.Swing
+	return nil
+}
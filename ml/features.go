@@ -0,0 +1,237 @@
+package ml
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// FeatureConfig holds the indicator periods features are built from.
+type FeatureConfig struct {
+	RSIPeriod            int     `json:"rsi_period"`
+	MACDShortPeriod      int     `json:"macd_short_period"`
+	MACDLongPeriod       int     `json:"macd_long_period"`
+	MACDSignalPeriod     int     `json:"macd_signal_period"`
+	SuperTrendPeriod     int     `json:"supertrend_period"`
+	SuperTrendMultiplier float64 `json:"supertrend_multiplier"`
+	BollingerPeriod      int     `json:"bollinger_period"`
+	EMAFastPeriod        int     `json:"ema_fast_period"`
+	EMASlowPeriod        int     `json:"ema_slow_period"`
+	FibonacciSize        int     `json:"fibonacci_size"`
+}
+
+// DefaultFeatureConfig returns the commonly used indicator periods.
+func DefaultFeatureConfig() FeatureConfig {
+	return FeatureConfig{
+		RSIPeriod:            14,
+		MACDShortPeriod:      12,
+		MACDLongPeriod:       26,
+		MACDSignalPeriod:     9,
+		SuperTrendPeriod:     10,
+		SuperTrendMultiplier: 3,
+		BollingerPeriod:      20,
+		EMAFastPeriod:        10,
+		EMASlowPeriod:        30,
+		FibonacciSize:        30,
+	}
+}
+
+// FeatureNames lists the features in the order of the vectors returned by FeatureBuilder.
+var FeatureNames = []string{
+	"rsi",
+	"macd_line",
+	"macd_histogram",
+	"supertrend_distance",
+	"supertrend_uptrend",
+	"bollinger_percent_b",
+	"bollinger_width",
+	"ema_spread",
+	"pivot_distance",
+	"fibonacci_position",
+	"return_1",
+}
+
+// FeatureBuilder feeds the indicators bar by bar and turns their values into a feature vector.
+// Price based values are relative to the close so features are comparable between instruments.
+type FeatureBuilder struct {
+	Config FeatureConfig
+
+	rsi        *indicator.RSI
+	macd       *indicator.MACD
+	superTrend *indicator.SuperTrend
+	bollinger  *indicator.BollingerBands
+	fastEMA    *indicator.EMA
+	slowEMA    *indicator.EMA
+	pivot      *indicator.PivotPoint
+	fibonacci  *indicator.Fibonacci
+	bars       int
+	lastTime   int64
+	lastClose  float64
+	features   []float64
+}
+
+// NewFeatureBuilder initializes a new FeatureBuilder instance.
+func NewFeatureBuilder(config FeatureConfig) *FeatureBuilder {
+	return &FeatureBuilder{
+		Config:     config,
+		rsi:        indicator.NewRSI(config.RSIPeriod),
+		macd:       indicator.NewMACD(config.MACDShortPeriod, config.MACDLongPeriod, config.MACDSignalPeriod),
+		superTrend: indicator.NewSuperTrend(config.SuperTrendPeriod, config.SuperTrendMultiplier),
+		bollinger:  indicator.NewBollingerBands(config.BollingerPeriod),
+		fastEMA:    indicator.NewEMA(config.EMAFastPeriod),
+		slowEMA:    indicator.NewEMA(config.EMASlowPeriod),
+		pivot:      indicator.NewPivotPoint(),
+		fibonacci:  indicator.NewFibonacci(config.FibonacciSize),
+	}
+}
+
+// WarmUp returns the number of bars after which every indicator is filled.
+func (c FeatureConfig) WarmUp() int {
+	warmUp := 0
+	for _, period := range []int{
+		c.RSIPeriod + 1,
+		c.MACDLongPeriod + c.MACDSignalPeriod,
+		c.SuperTrendPeriod + 1,
+		c.BollingerPeriod,
+		c.EMAFastPeriod,
+		c.EMASlowPeriod,
+		c.FibonacciSize,
+		2,
+	} {
+		if period > warmUp {
+			warmUp = period
+		}
+	}
+	return warmUp
+}
+
+// AddDataPoint adds a new data point to every indicator and rebuilds the feature vector.
+func (fb *FeatureBuilder) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	if fb.bars > 0 && data.Time <= fb.lastTime {
+		return errors.New("data point is not in chronological order")
+	}
+	for _, streaming := range []indicator.Streaming{fb.rsi, fb.macd, fb.superTrend, fb.bollinger, fb.fastEMA, fb.slowEMA, fb.pivot, fb.fibonacci} {
+		if err := streaming.Update(ctx, data); err != nil {
+			return err
+		}
+	}
+
+	previousClose := fb.lastClose
+	fb.bars++
+	fb.lastTime = data.Time
+	fb.lastClose = data.Close
+	if fb.bars < fb.Config.WarmUp() || data.Close == 0 || previousClose == 0 {
+		fb.features = nil
+		return nil
+	}
+	fb.features = fb.build(data, previousClose)
+	return nil
+}
+
+func (fb *FeatureBuilder) build(data model.DataPoint, previousClose float64) []float64 {
+	price := data.Close
+	macd := fb.macd.Value()
+	superTrend := fb.superTrend.Value()
+	trend := -1.0
+	if superTrend.UpTrend {
+		trend = 1
+	}
+	bands := fb.bollinger.Value()
+	percentB := 0.5
+	if width := bands.UpperBand - bands.LowerBand; width > 0 {
+		percentB = (price - bands.LowerBand) / width
+	}
+	fibonacci := 0.5
+	if fb.fibonacci.High > fb.fibonacci.Low {
+		fibonacci = (price - fb.fibonacci.Low) / (fb.fibonacci.High - fb.fibonacci.Low)
+	}
+
+	return []float64{
+		fb.rsi.Value() / 100,
+		macd.MACDLine / price,
+		macd.MACDHistogram / price,
+		(price - superTrend.Line) / price,
+		trend,
+		percentB,
+		(bands.UpperBand - bands.LowerBand) / price,
+		(fb.fastEMA.Value() - fb.slowEMA.Value()) / price,
+		(price - fb.pivot.Value().Pivot) / price,
+		fibonacci,
+		math.Log(price / previousClose),
+	}
+}
+
+// Features returns the feature vector of the latest bar, false while the indicators are warming up.
+func (fb *FeatureBuilder) Features() ([]float64, bool) {
+	return fb.features, fb.features != nil
+}
+
+// SuperTrend returns the current SuperTrend line and direction.
+func (fb *FeatureBuilder) SuperTrend() (float64, bool) {
+	value := fb.superTrend.Value()
+	return value.Line, value.UpTrend
+}
+
+// Dataset is a set of feature vectors with their labels.
+type Dataset struct {
+	Features [][]float64
+	// Labels are 1 when the forward return exceeds the threshold, 0 otherwise.
+	Labels []float64
+	// Returns are the forward returns the labels are derived from.
+	Returns []float64
+	Times   []int64
+}
+
+// Len returns the number of samples.
+func (ds Dataset) Len() int {
+	return len(ds.Labels)
+}
+
+// Split returns the samples before and from index i, e.g. to keep a chronological test set.
+// Returns and Times are optional and only split when set.
+func (ds Dataset) Split(i int) (Dataset, Dataset) {
+	before := Dataset{Features: ds.Features[:i], Labels: ds.Labels[:i]}
+	after := Dataset{Features: ds.Features[i:], Labels: ds.Labels[i:]}
+	if len(ds.Returns) == ds.Len() {
+		before.Returns, after.Returns = ds.Returns[:i], ds.Returns[i:]
+	}
+	if len(ds.Times) == ds.Len() {
+		before.Times, after.Times = ds.Times[:i], ds.Times[i:]
+	}
+	return before, after
+}
+
+// BuildDataset builds the features of every bar labelled with the return over the next horizon bars.
+// Bars without a full horizon ahead or still warming up are left out.
+func BuildDataset(ctx context.Context, config FeatureConfig, data []model.DataPoint, horizon int, threshold float64) (Dataset, error) {
+	if horizon < 1 {
+		return Dataset{}, errors.New("horizon must be at least one bar")
+	}
+	var ds Dataset
+	builder := NewFeatureBuilder(config)
+	for i, dp := range data {
+		if err := builder.AddDataPoint(ctx, dp); err != nil {
+			return Dataset{}, err
+		}
+		features, ok := builder.Features()
+		if !ok || i+horizon >= len(data) {
+			continue
+		}
+		forward := data[i+horizon].Close/dp.Close - 1
+		label := 0.0
+		if forward > threshold {
+			label = 1
+		}
+		ds.Features = append(ds.Features, features)
+		ds.Labels = append(ds.Labels, label)
+		ds.Returns = append(ds.Returns, forward)
+		ds.Times = append(ds.Times, dp.Time)
+	}
+	if ds.Len() == 0 {
+		return ds, errors.New("not enough data points to build a dataset")
+	}
+	return ds, nil
+}
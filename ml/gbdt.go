@@ -0,0 +1,213 @@
+package ml
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+const (
+	GradientBoostingType = "gradient_boosting"
+)
+
+// TreeNode is a node of a regression tree, a leaf when Left and Right are nil.
+type TreeNode struct {
+	Feature   int       `json:"feature,omitempty"`
+	Threshold float64   `json:"threshold,omitempty"`
+	Left      *TreeNode `json:"left,omitempty"`
+	Right     *TreeNode `json:"right,omitempty"`
+	Value     float64   `json:"value,omitempty"`
+}
+
+func (n *TreeNode) predict(features []float64) float64 {
+	for n.Left != nil {
+		if features[n.Feature] <= n.Threshold {
+			n = n.Left
+		} else {
+			n = n.Right
+		}
+	}
+	return n.Value
+}
+
+// check verifies every split of the tree reads one of the given number of features.
+func (n *TreeNode) check(features int) error {
+	if n == nil {
+		return errors.New("tree has a missing node")
+	}
+	if n.Left == nil && n.Right == nil {
+		return nil
+	}
+	if n.Feature < 0 || n.Feature >= features {
+		return fmt.Errorf("split on feature %d, expected fewer than %d features", n.Feature, features)
+	}
+	if err := n.Left.check(features); err != nil {
+		return err
+	}
+	return n.Right.check(features)
+}
+
+// GradientBoostedTrees is an ensemble of regression trees boosted on the log loss.
+type GradientBoostedTrees struct {
+	// Base is the log odds every prediction starts from.
+	Base         float64     `json:"base"`
+	LearningRate float64     `json:"learning_rate"`
+	Trees        []*TreeNode `json:"trees"`
+}
+
+// BoostingConfig configures TrainGradientBoostedTrees.
+type BoostingConfig struct {
+	Trees        int
+	MaxDepth     int
+	LearningRate float64
+	// MinLeafSize is the minimum number of samples in a leaf.
+	MinLeafSize int
+}
+
+// DefaultBoostingConfig returns a configuration of shallow trees which is hard to overfit.
+func DefaultBoostingConfig() BoostingConfig {
+	return BoostingConfig{Trees: 100, MaxDepth: 3, LearningRate: 0.1, MinLeafSize: 10}
+}
+
+// TrainGradientBoostedTrees fits trees one after the other on the log loss gradients of the ensemble so far.
+// Leaf values are Newton steps, as in the usual logistic boosting.
+func TrainGradientBoostedTrees(ds Dataset, config BoostingConfig) (*GradientBoostedTrees, error) {
+	if err := validateDataset(ds); err != nil {
+		return nil, err
+	}
+	if config.Trees < 1 || config.MaxDepth < 1 || config.LearningRate <= 0 {
+		return nil, errors.New("trees, max depth and learning rate must be positive")
+	}
+	if config.MinLeafSize < 1 {
+		config.MinLeafSize = 1
+	}
+
+	positives := 0.0
+	for _, label := range ds.Labels {
+		positives += label
+	}
+	rate := math.Min(math.Max(positives/float64(ds.Len()), 1e-6), 1-1e-6)
+	gbt := &GradientBoostedTrees{Base: math.Log(rate / (1 - rate)), LearningRate: config.LearningRate}
+
+	scores := make([]float64, ds.Len())
+	for i := range scores {
+		scores[i] = gbt.Base
+	}
+	gradients := make([]float64, ds.Len())
+	hessians := make([]float64, ds.Len())
+	indexes := make([]int, ds.Len())
+	for t := 0; t < config.Trees; t++ {
+		for i, score := range scores {
+			p := sigmoid(score)
+			gradients[i] = ds.Labels[i] - p
+			hessians[i] = p * (1 - p)
+			indexes[i] = i
+		}
+		builder := treeBuilder{features: ds.Features, gradients: gradients, hessians: hessians, config: config}
+		tree := builder.build(indexes, 0)
+		gbt.Trees = append(gbt.Trees, tree)
+		for i, x := range ds.Features {
+			scores[i] += config.LearningRate * tree.predict(x)
+		}
+	}
+	return gbt, nil
+}
+
+// Type returns the type the model is saved under.
+func (gbt *GradientBoostedTrees) Type() string {
+	return GradientBoostingType
+}
+
+// Predict returns the probability of a positive label.
+func (gbt *GradientBoostedTrees) Predict(features []float64) float64 {
+	score := gbt.Base
+	for _, tree := range gbt.Trees {
+		score += gbt.LearningRate * tree.predict(features)
+	}
+	return sigmoid(score)
+}
+
+// checkFeatures verifies no tree splits on a feature outside the given number of features.
+func (gbt *GradientBoostedTrees) checkFeatures(features int) error {
+	for i, tree := range gbt.Trees {
+		if err := tree.check(features); err != nil {
+			return fmt.Errorf("tree %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+type treeBuilder struct {
+	features  [][]float64
+	gradients []float64
+	hessians  []float64
+	config    BoostingConfig
+}
+
+func (tb *treeBuilder) build(indexes []int, depth int) *TreeNode {
+	if depth >= tb.config.MaxDepth || len(indexes) < 2*tb.config.MinLeafSize {
+		return tb.leaf(indexes)
+	}
+	feature, threshold, ok := tb.bestSplit(indexes)
+	if !ok {
+		return tb.leaf(indexes)
+	}
+
+	var left, right []int
+	for _, i := range indexes {
+		if tb.features[i][feature] <= threshold {
+			left = append(left, i)
+		} else {
+			right = append(right, i)
+		}
+	}
+	return &TreeNode{
+		Feature:   feature,
+		Threshold: threshold,
+		Left:      tb.build(left, depth+1),
+		Right:     tb.build(right, depth+1),
+	}
+}
+
+func (tb *treeBuilder) leaf(indexes []int) *TreeNode {
+	g, h := tb.sums(indexes)
+	return &TreeNode{Value: g / (h + 1e-9)}
+}
+
+func (tb *treeBuilder) sums(indexes []int) (g, h float64) {
+	for _, i := range indexes {
+		g += tb.gradients[i]
+		h += tb.hessians[i]
+	}
+	return g, h
+}
+
+// bestSplit returns the split with the highest gain, thresholds lie halfway between neighbouring values.
+func (tb *treeBuilder) bestSplit(indexes []int) (bestFeature int, bestThreshold float64, found bool) {
+	totalG, totalH := tb.sums(indexes)
+	bestGain := 1e-12
+	sorted := make([]int, len(indexes))
+	for feature := range tb.features[indexes[0]] {
+		copy(sorted, indexes)
+		sort.SliceStable(sorted, func(a, b int) bool {
+			return tb.features[sorted[a]][feature] < tb.features[sorted[b]][feature]
+		})
+
+		leftG, leftH := 0.0, 0.0
+		for k := 0; k < len(sorted)-1; k++ {
+			leftG += tb.gradients[sorted[k]]
+			leftH += tb.hessians[sorted[k]]
+			current, next := tb.features[sorted[k]][feature], tb.features[sorted[k+1]][feature]
+			if k+1 < tb.config.MinLeafSize || len(sorted)-k-1 < tb.config.MinLeafSize || current == next {
+				continue
+			}
+			rightG, rightH := totalG-leftG, totalH-leftH
+			gain := leftG*leftG/(leftH+1e-9) + rightG*rightG/(rightH+1e-9) - totalG*totalG/(totalH+1e-9)
+			if gain > bestGain {
+				bestGain, bestFeature, bestThreshold, found = gain, feature, (current+next)/2, true
+			}
+		}
+	}
+	return bestFeature, bestThreshold, found
+}
@@ -0,0 +1,157 @@
+package ml
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	LogisticRegressionType = "logistic_regression"
+)
+
+// Model predicts the probability of a positive label from a feature vector.
+type Model interface {
+	Type() string
+	Predict(features []float64) float64
+}
+
+// LogisticRegression is a linear model over standardised features.
+type LogisticRegression struct {
+	Weights []float64 `json:"weights"`
+	Bias    float64   `json:"bias"`
+	// Means and Scales standardise the features as seen during training.
+	Means  []float64 `json:"means"`
+	Scales []float64 `json:"scales"`
+}
+
+// LogisticConfig configures the gradient descent of TrainLogisticRegression.
+type LogisticConfig struct {
+	LearningRate float64
+	Epochs       int
+	// L2 is the ridge penalty on the weights.
+	L2 float64
+}
+
+// DefaultLogisticConfig returns a configuration suited to the standardised indicator features.
+func DefaultLogisticConfig() LogisticConfig {
+	return LogisticConfig{LearningRate: 0.1, Epochs: 500, L2: 0.001}
+}
+
+// TrainLogisticRegression fits a logistic regression with full batch gradient descent.
+func TrainLogisticRegression(ds Dataset, config LogisticConfig) (*LogisticRegression, error) {
+	if err := validateDataset(ds); err != nil {
+		return nil, err
+	}
+	if config.LearningRate <= 0 || config.Epochs < 1 {
+		return nil, errors.New("learning rate and epochs must be positive")
+	}
+
+	means, scales := standardisation(ds.Features)
+	lr := &LogisticRegression{
+		Weights: make([]float64, len(means)),
+		Means:   means,
+		Scales:  scales,
+	}
+	samples := make([][]float64, ds.Len())
+	for i, features := range ds.Features {
+		samples[i] = lr.standardise(features)
+	}
+
+	n := float64(ds.Len())
+	gradient := make([]float64, len(lr.Weights))
+	for epoch := 0; epoch < config.Epochs; epoch++ {
+		for j := range gradient {
+			gradient[j] = config.L2 * lr.Weights[j]
+		}
+		biasGradient := 0.0
+		for i, x := range samples {
+			residual := sigmoid(lr.linear(x)) - ds.Labels[i]
+			for j, value := range x {
+				gradient[j] += residual * value / n
+			}
+			biasGradient += residual / n
+		}
+		for j := range lr.Weights {
+			lr.Weights[j] -= config.LearningRate * gradient[j]
+		}
+		lr.Bias -= config.LearningRate * biasGradient
+	}
+	return lr, nil
+}
+
+// Type returns the type the model is saved under.
+func (lr *LogisticRegression) Type() string {
+	return LogisticRegressionType
+}
+
+// Predict returns the probability of a positive label.
+func (lr *LogisticRegression) Predict(features []float64) float64 {
+	return sigmoid(lr.linear(lr.standardise(features)))
+}
+
+// checkFeatures verifies the model was trained on the given number of features.
+func (lr *LogisticRegression) checkFeatures(features int) error {
+	if len(lr.Weights) != features || len(lr.Means) != features || len(lr.Scales) != features {
+		return fmt.Errorf("model has %d weights, %d means and %d scales, expected %d features",
+			len(lr.Weights), len(lr.Means), len(lr.Scales), features)
+	}
+	return nil
+}
+
+func (lr *LogisticRegression) linear(x []float64) float64 {
+	z := lr.Bias
+	for j, w := range lr.Weights {
+		z += w * x[j]
+	}
+	return z
+}
+
+func (lr *LogisticRegression) standardise(features []float64) []float64 {
+	x := make([]float64, len(lr.Means))
+	for j := range x {
+		x[j] = (features[j] - lr.Means[j]) / lr.Scales[j]
+	}
+	return x
+}
+
+// standardisation returns the mean and standard deviation of every feature, constant features get a scale of 1.
+func standardisation(features [][]float64) (means, scales []float64) {
+	n := float64(len(features))
+	means = make([]float64, len(features[0]))
+	scales = make([]float64, len(features[0]))
+	for _, x := range features {
+		for j, value := range x {
+			means[j] += value / n
+		}
+	}
+	for _, x := range features {
+		for j, value := range x {
+			scales[j] += (value - means[j]) * (value - means[j]) / n
+		}
+	}
+	for j := range scales {
+		scales[j] = math.Sqrt(scales[j])
+		if scales[j] == 0 {
+			scales[j] = 1
+		}
+	}
+	return means, scales
+}
+
+func sigmoid(z float64) float64 {
+	return 1 / (1 + math.Exp(-z))
+}
+
+func validateDataset(ds Dataset) error {
+	if ds.Len() == 0 || len(ds.Features) != ds.Len() {
+		return errors.New("dataset is empty or its features and labels differ in length")
+	}
+	width := len(ds.Features[0])
+	for _, x := range ds.Features {
+		if len(x) != width {
+			return errors.New("feature vectors differ in length")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package ml_test
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"path/filepath"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/ml"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+func sineData(bars int) []model.DataPoint {
+	data := make([]model.DataPoint, bars)
+	for i := range data {
+		close := 100 + 10*math.Sin(float64(i)/4)
+		data[i] = model.DataPoint{Time: int64(i + 1), Open: close, High: close + 1, Low: close - 1, Close: close}
+	}
+	return data
+}
+
+// separable labels points by the sign of x0 + x1, with x2 as noise.
+func separable(n int) ml.Dataset {
+	rng := rand.New(rand.NewSource(1))
+	var ds ml.Dataset
+	for i := 0; i < n; i++ {
+		x := []float64{rng.NormFloat64(), rng.NormFloat64(), rng.NormFloat64()}
+		label := 0.0
+		if x[0]+x[1] > 0 {
+			label = 1
+		}
+		ds.Features = append(ds.Features, x)
+		ds.Labels = append(ds.Labels, label)
+	}
+	return ds
+}
+
+func accuracy(m ml.Model, ds ml.Dataset) float64 {
+	correct := 0
+	for i, x := range ds.Features {
+		if (m.Predict(x) >= 0.5) == (ds.Labels[i] == 1) {
+			correct++
+		}
+	}
+	return float64(correct) / float64(ds.Len())
+}
+
+func TestBuildDataset(t *testing.T) {
+	config := ml.DefaultFeatureConfig()
+	data := sineData(120)
+	ds, err := ml.BuildDataset(context.Background(), config, data, 5, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	test_utils.AssertEqual(t, 120-config.WarmUp()+1-5, ds.Len(), "Number of samples does not match")
+	test_utils.AssertEqual(t, len(ml.FeatureNames), len(ds.Features[0]), "Number of features does not match")
+	test_utils.AssertEqual(t, int64(config.WarmUp()), ds.Times[0], "First sample time does not match")
+	for i, r := range ds.Returns {
+		test_utils.AssertEqual(t, r > 0, ds.Labels[i] == 1, "Label does not match the forward return")
+	}
+
+	if _, err := ml.BuildDataset(context.Background(), config, data[:20], 5, 0); err == nil {
+		t.Fatalf("expected error for too few data points")
+	}
+}
+
+func TestTrainers(t *testing.T) {
+	train, test := separable(600).Split(400)
+
+	logistic, err := ml.TrainLogisticRegression(train, ml.DefaultLogisticConfig())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	test_utils.AssertTrue(t, accuracy(logistic, test) > 0.95, "Logistic regression accuracy is too low")
+
+	boosted, err := ml.TrainGradientBoostedTrees(train, ml.DefaultBoostingConfig())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	test_utils.AssertTrue(t, accuracy(boosted, test) > 0.85, "Gradient boosting accuracy is too low")
+
+	if _, err := ml.TrainLogisticRegression(ml.Dataset{}, ml.DefaultLogisticConfig()); err == nil {
+		t.Fatalf("expected error for an empty dataset")
+	}
+}
+
+func TestGradientBoostingNonLinear(t *testing.T) {
+	// The label is positive inside a band, which a linear model cannot separate.
+	rng := rand.New(rand.NewSource(2))
+	var ds ml.Dataset
+	for i := 0; i < 500; i++ {
+		x := rng.Float64()*2 - 1
+		label := 0.0
+		if math.Abs(x) < 0.5 {
+			label = 1
+		}
+		ds.Features = append(ds.Features, []float64{x})
+		ds.Labels = append(ds.Labels, label)
+	}
+
+	boosted, err := ml.TrainGradientBoostedTrees(ds, ml.DefaultBoostingConfig())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	test_utils.AssertTrue(t, accuracy(boosted, ds) > 0.95, "Gradient boosting accuracy is too low")
+	test_utils.AssertTrue(t, boosted.Predict([]float64{0}) > 0.9, "Expected a high probability inside the band")
+	test_utils.AssertTrue(t, boosted.Predict([]float64{0.9}) < 0.1, "Expected a low probability outside the band")
+}
+
+func TestSaveLoadModel(t *testing.T) {
+	config := ml.DefaultFeatureConfig()
+	train, err := ml.BuildDataset(context.Background(), config, sineData(200), 5, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logistic, err := ml.TrainLogisticRegression(train, ml.DefaultLogisticConfig())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	boosted, err := ml.TrainGradientBoostedTrees(train, ml.BoostingConfig{Trees: 10, MaxDepth: 2, LearningRate: 0.3, MinLeafSize: 5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, trained := range []ml.Model{logistic, boosted} {
+		path := filepath.Join(t.TempDir(), "model.json")
+		if err := ml.SaveModel(path, trained, config, 5, 0.01); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		loaded, saved, err := ml.LoadModel(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		test_utils.AssertEqual(t, trained.Type(), loaded.Type(), "Model type does not match")
+		test_utils.AssertEqual(t, config, saved.Features, "Feature configuration does not match")
+		test_utils.AssertEqual(t, 5, saved.Horizon, "Horizon does not match")
+		for _, x := range train.Features[:20] {
+			test_utils.AssertEqual(t, trained.Predict(x), loaded.Predict(x), "Loaded model predicts differently")
+		}
+	}
+}
+
+func TestLoadModelRejectsFeatureMismatch(t *testing.T) {
+	// The separable dataset has 3 features, fewer than the feature builder produces.
+	train := separable(200)
+	logistic, err := ml.TrainLogisticRegression(train, ml.DefaultLogisticConfig())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	outOfRange := &ml.GradientBoostedTrees{Trees: []*ml.TreeNode{{
+		Feature: len(ml.FeatureNames),
+		Left:    &ml.TreeNode{Value: -1},
+		Right:   &ml.TreeNode{Value: 1},
+	}}}
+	halfSplit := &ml.GradientBoostedTrees{Trees: []*ml.TreeNode{{Left: &ml.TreeNode{Value: -1}}}}
+
+	for _, stale := range []ml.Model{logistic, outOfRange, halfSplit} {
+		path := filepath.Join(t.TempDir(), "model.json")
+		if err := ml.SaveModel(path, stale, ml.DefaultFeatureConfig(), 5, 0); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, _, err := ml.LoadModel(path); err == nil {
+			t.Errorf("expected an error loading a %s model that does not match the features", stale.Type())
+		}
+	}
+}
+
+func TestOnlineLearners(t *testing.T) {
+	// Expert 0 is always right, expert 1 always wrong and expert 2 abstains.
+	rng := rand.New(rand.NewSource(3))
+	hedge := ml.NewHedge(3, 0.3)
+	perceptron := ml.NewPerceptron(3, 0.1)
+	for i := 0; i < 200; i++ {
+		outcome := rng.NormFloat64()
+		direction := math.Copysign(1, outcome)
+		votes := []float64{direction, -direction, 0}
+		hedge.Update(votes, outcome)
+		perceptron.Update(votes, outcome)
+	}
+
+	weights := hedge.Weights()
+	test_utils.AssertTrue(t, weights[0] > 0.99, "Expected Hedge to trust the right expert")
+	test_utils.AssertTrue(t, weights[1] < weights[2], "Expected Hedge to trust the wrong expert less than the abstaining one")
+	test_utils.AssertTrue(t, hedge.Predict([]float64{1, -1, 0}) > 0.9, "Expected Hedge to follow the right expert")
+
+	// The perceptron stops learning once it stops making mistakes.
+	test_utils.AssertTrue(t, perceptron.Predict([]float64{1, -1, 0}) > 0, "Expected the perceptron to follow the right expert")
+	test_utils.AssertTrue(t, perceptron.Predict([]float64{-1, 1, 0}) < 0, "Expected the perceptron to follow the right expert")
+	test_utils.AssertTrue(t, perceptron.Weights()[0] > perceptron.Weights()[1], "Expected the perceptron to trust the right expert more")
+}
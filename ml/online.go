@@ -0,0 +1,124 @@
+package ml
+
+import (
+	"math"
+)
+
+// OnlineLearner combines the votes of experts, each -1, 0 or +1, and learns from every outcome as it arrives.
+type OnlineLearner interface {
+	Name() string
+	// Predict returns the combined vote between -1 and +1.
+	Predict(votes []float64) float64
+	// Update adjusts the trust in every expert given the sign of the outcome the votes were made for.
+	Update(votes []float64, outcome float64)
+	// Weights returns the current trust in every expert.
+	Weights() []float64
+}
+
+// Hedge is the exponentially weighted experts algorithm. Every expert loses weight in
+// proportion to its loss, 0 for a correct vote, 1 for a wrong one and 0.5 for abstaining.
+type Hedge struct {
+	// LearningRate is how fast weights react to losses, typically 0.1 to 0.5.
+	LearningRate float64
+	weights      []float64
+}
+
+// NewHedge initializes a new Hedge instance with equal weights.
+func NewHedge(experts int, learningRate float64) *Hedge {
+	weights := make([]float64, experts)
+	for i := range weights {
+		weights[i] = 1 / float64(experts)
+	}
+	return &Hedge{LearningRate: learningRate, weights: weights}
+}
+
+// Name returns the name of the learner.
+func (h *Hedge) Name() string {
+	return "Hedge"
+}
+
+// Predict returns the weighted average vote.
+func (h *Hedge) Predict(votes []float64) float64 {
+	prediction := 0.0
+	for i, vote := range votes {
+		prediction += h.weights[i] * vote
+	}
+	return prediction
+}
+
+// Update applies the multiplicative weight update and renormalises the weights.
+func (h *Hedge) Update(votes []float64, outcome float64) {
+	if outcome == 0 {
+		return
+	}
+	outcome = math.Copysign(1, outcome)
+	total := 0.0
+	for i, vote := range votes {
+		loss := (1 - vote*outcome) / 2
+		h.weights[i] *= math.Exp(-h.LearningRate * loss)
+		total += h.weights[i]
+	}
+	for i := range h.weights {
+		h.weights[i] /= total
+	}
+}
+
+// Weights returns the current weights, which sum to 1.
+func (h *Hedge) Weights() []float64 {
+	return append([]float64{}, h.weights...)
+}
+
+// Perceptron is an online linear classifier over the votes which only learns from its mistakes,
+// so it can also learn to trust an expert inversely.
+type Perceptron struct {
+	LearningRate float64
+	weights      []float64
+	bias         float64
+}
+
+// NewPerceptron initializes a new Perceptron instance with equal positive weights.
+func NewPerceptron(experts int, learningRate float64) *Perceptron {
+	weights := make([]float64, experts)
+	for i := range weights {
+		weights[i] = 1 / float64(experts)
+	}
+	return &Perceptron{LearningRate: learningRate, weights: weights}
+}
+
+// Name returns the name of the learner.
+func (p *Perceptron) Name() string {
+	return "Perceptron"
+}
+
+// Predict squashes the linear score into -1 to +1.
+func (p *Perceptron) Predict(votes []float64) float64 {
+	return math.Tanh(p.score(votes))
+}
+
+func (p *Perceptron) score(votes []float64) float64 {
+	score := p.bias
+	for i, vote := range votes {
+		score += p.weights[i] * vote
+	}
+	return score
+}
+
+// Update moves the weights towards the outcome when the score has the wrong sign.
+func (p *Perceptron) Update(votes []float64, outcome float64) {
+	if outcome == 0 {
+		return
+	}
+	outcome = math.Copysign(1, outcome)
+	if p.score(votes)*outcome > 0 {
+		return
+	}
+	for i, vote := range votes {
+		p.weights[i] += p.LearningRate * outcome * vote
+	}
+	p.bias += p.LearningRate * outcome
+}
+
+// Weights returns the current weights, without the bias.
+func (p *Perceptron) Weights() []float64 {
+	return append([]float64{}, p.weights...)
+}
@@ -0,0 +1,76 @@
+package ml
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/fileutils"
+)
+
+// SavedModel is a trained model together with the features it was trained on.
+type SavedModel struct {
+	Type     string        `json:"type"`
+	Features FeatureConfig `json:"features"`
+	// Horizon and Threshold are the labelling the model was trained with, for reference.
+	Horizon   int             `json:"horizon"`
+	Threshold float64         `json:"threshold"`
+	Model     json.RawMessage `json:"model"`
+}
+
+// SaveModel writes the model and its feature configuration to path as JSON.
+func SaveModel(path string, model Model, features FeatureConfig, horizon int, threshold float64) error {
+	encoded, err := json.Marshal(model)
+	if err != nil {
+		return fmt.Errorf("error encoding model: %w", err)
+	}
+	data, err := json.MarshalIndent(SavedModel{
+		Type:      model.Type(),
+		Features:  features,
+		Horizon:   horizon,
+		Threshold: threshold,
+		Model:     encoded,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding model: %w", err)
+	}
+	if err := fileutils.WriteFileAtomic(path, data); err != nil {
+		return fmt.Errorf("error writing model file: %w", err)
+	}
+	return nil
+}
+
+// LoadModel reads a model written by SaveModel.
+func LoadModel(path string) (Model, SavedModel, error) {
+	var saved SavedModel
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, saved, fmt.Errorf("error reading model file: %w", err)
+	}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, saved, fmt.Errorf("error decoding model file %s: %w", path, err)
+	}
+
+	var model loadedModel
+	switch saved.Type {
+	case LogisticRegressionType:
+		model = &LogisticRegression{}
+	case GradientBoostingType:
+		model = &GradientBoostedTrees{}
+	default:
+		return nil, saved, fmt.Errorf("unknown model type %q in %s", saved.Type, path)
+	}
+	if err := json.Unmarshal(saved.Model, model); err != nil {
+		return nil, saved, fmt.Errorf("error decoding %s model: %w", saved.Type, err)
+	}
+	if err := model.checkFeatures(len(FeatureNames)); err != nil {
+		return nil, saved, fmt.Errorf("invalid %s model in %s: %w", saved.Type, path, err)
+	}
+	return model, saved, nil
+}
+
+// loadedModel is a model LoadModel can check against the feature vectors it will be given.
+type loadedModel interface {
+	Model
+	checkFeatures(features int) error
+}
@@ -1,5 +1,12 @@
 package model
 
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
 // StockAction represents the possible actions in the stock market.
 type StockAction string
 
@@ -12,4 +19,52 @@ const (
 type TradingSignal struct {
 	Time   int64
 	Action StockAction
+	// Strength is the confidence in the signal from 0 to 1, it is 0 for Wait signals.
+	Strength float64
+	// StopLoss and Target are the suggested exit prices, 0 when no exit is suggested.
+	StopLoss float64
+	Target   float64
+	// Rationale lists the observations the signal is based on.
+	Rationale []Rationale
+	// Regime is the market regime the signal was generated in, empty when not classified.
+	Regime string
+	// Legs are the coordinated trades of a signal spanning several instruments, empty for single instrument signals.
+	Legs []Leg
+}
+
+// Leg is the trade of one instrument within a multi-instrument signal.
+type Leg struct {
+	Ticker string
+	Action StockAction
+	// Weight is the share of the signal's notional traded in this leg, the weights of all legs add up to 1.
+	Weight float64
+}
+
+// Rationale is a structured reason behind a signal, e.g. "RSI crossed up through 30 at 28.4→31.2".
+type Rationale struct {
+	// Source is the name of the adaptor that made the observation.
+	Source    string
+	Indicator string
+	Event     string
+	// Reference is the level or line the event relates to, if any.
+	Reference string
+	// Values are the indicator values leading to the event, oldest first.
+	Values []float64
+}
+
+func (r Rationale) String() string {
+	parts := []string{r.Indicator, r.Event}
+	if r.Reference != "" {
+		parts = append(parts, r.Reference)
+	}
+	text := strings.Join(parts, " ")
+	if len(r.Values) == 0 {
+		return text
+	}
+
+	values := make([]string, len(r.Values))
+	for i, value := range r.Values {
+		values[i] = strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%s at %s", text, strings.Join(values, "→"))
 }
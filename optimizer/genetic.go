@@ -0,0 +1,533 @@
+package optimizer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/audit"
+	"github.com/vd09/trading-algorithm-backtesting-system/fileutils"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+)
+
+const (
+	tournamentSize   = 3
+	maxSampleRetries = 20
+)
+
+// Gene selects one adaptor spec together with its parameter values.
+type Gene struct {
+	Spec   string `json:"spec"`
+	Params Params `json:"params"`
+}
+
+// Genome is the set of adaptors combined into one candidate, at most one gene per spec.
+type Genome []Gene
+
+// Key identifies the genome, genomes with the same key backtest identically.
+func (g Genome) Key() string {
+	parts := make([]string, len(g))
+	for i, gene := range g {
+		parts[i] = fmt.Sprintf("%s(%s)", gene.Spec, gene.Params)
+	}
+	return strings.Join(parts, "+")
+}
+
+func (g Genome) clone() Genome {
+	clone := make(Genome, len(g))
+	for i, gene := range g {
+		clone[i] = Gene{Spec: gene.Spec, Params: gene.Params.Clone()}
+	}
+	return clone
+}
+
+// Individual is an evaluated genome.
+type Individual struct {
+	Genome Genome `json:"genome"`
+	// Fitness is the train score, reduced by the overfit penalty when a holdout is used.
+	Fitness      float64 `json:"fitness"`
+	Train        Result  `json:"train"`
+	Holdout      *Result `json:"holdout,omitempty"`
+	HoldoutScore float64 `json:"holdout_score"`
+}
+
+// GenerationStats summarises the fitness of one evaluated generation.
+type GenerationStats struct {
+	Generation  int     `json:"generation"`
+	BestFitness float64 `json:"best_fitness"`
+	MeanFitness float64 `json:"mean_fitness"`
+	BestGenome  string  `json:"best_genome"`
+}
+
+// Checkpoint is the state of a genetic search between two generations.
+type Checkpoint struct {
+	Objective string `json:"objective"`
+	Seed      int64  `json:"seed"`
+	// Generation is the index of the next generation to evaluate.
+	Generation int               `json:"generation"`
+	Population []Genome          `json:"population"`
+	Best       *Individual       `json:"best,omitempty"`
+	History    []GenerationStats `json:"history"`
+}
+
+// GeneticResult holds the outcome of a genetic search.
+type GeneticResult struct {
+	Objective string
+	Best      *Individual
+	History   []GenerationStats
+	// Final is the last evaluated generation, sorted by fitness, best first.
+	Final []Individual
+}
+
+// GeneticSearch evolves both the adaptors making up a combination and their parameters.
+type GeneticSearch struct {
+	Specs           []AdaptorSpec
+	Objective       Objective
+	TrackIterations int
+	Workers         int
+	Monitor         monitor.Monitoring
+
+	PopulationSize int
+	Generations    int
+	// Elitism is the number of best individuals copied unchanged into the next generation.
+	Elitism       int
+	CrossoverRate float64
+	// MutationRate is the probability of mutating each parameter, and of adding or removing a gene.
+	MutationRate float64
+	// MaxGenes limits the number of adaptors per genome, defaults to the number of specs.
+	MaxGenes int
+	// HoldoutFraction reserves the trailing fraction of the data to measure overfitting.
+	HoldoutFraction float64
+	// OverfitPenalty scales how much of the gap between train and holdout scores is subtracted from the fitness.
+	OverfitPenalty float64
+	Seed           int64
+	// SeedPopulation is placed in the initial population before it is filled with random genomes.
+	SeedPopulation []Genome
+	// OnGeneration is called after every generation, returning an error stops the search.
+	OnGeneration func(cp Checkpoint) error
+}
+
+// Run evolves a fresh population over data. Decisions are recorded to the audit recorder of ctx, if any,
+// tagged with the phase, train or holdout, and the generation of the backtest.
+func (gs *GeneticSearch) Run(ctx context.Context, data []model.DataPoint) (*GeneticResult, error) {
+	if err := gs.validate(); err != nil {
+		return nil, err
+	}
+	population, err := gs.initialPopulation()
+	if err != nil {
+		return nil, err
+	}
+	return gs.evolve(ctx, data, Checkpoint{Objective: gs.Objective.Name, Seed: gs.Seed, Population: population})
+}
+
+// Resume continues a search from a checkpoint written by a previous run, using the checkpoint's seed.
+func (gs *GeneticSearch) Resume(ctx context.Context, data []model.DataPoint, cp Checkpoint) (*GeneticResult, error) {
+	if err := gs.validate(); err != nil {
+		return nil, err
+	}
+	if cp.Objective != gs.Objective.Name {
+		return nil, fmt.Errorf("checkpoint objective %q does not match %q", cp.Objective, gs.Objective.Name)
+	}
+	for _, genome := range cp.Population {
+		if err := gs.checkGenome(genome); err != nil {
+			return nil, err
+		}
+	}
+	return gs.evolve(ctx, data, cp)
+}
+
+func (gs *GeneticSearch) validate() error {
+	if len(gs.Specs) == 0 {
+		return errors.New("genetic search needs at least one adaptor spec")
+	}
+	if gs.Objective.Score == nil {
+		return errors.New("genetic search needs an objective")
+	}
+	if err := checkSpecNames(gs.Specs); err != nil {
+		return err
+	}
+	if gs.PopulationSize < 2 {
+		return errors.New("genetic search needs a population of at least 2")
+	}
+	if gs.Elitism < 0 || gs.Elitism >= gs.PopulationSize {
+		return errors.New("elitism must be lower than the population size")
+	}
+	if gs.HoldoutFraction < 0 || gs.HoldoutFraction >= 1 {
+		return errors.New("holdout fraction must be in [0, 1)")
+	}
+	return nil
+}
+
+func (gs *GeneticSearch) maxGenes() int {
+	if gs.MaxGenes <= 0 || gs.MaxGenes > len(gs.Specs) {
+		return len(gs.Specs)
+	}
+	return gs.MaxGenes
+}
+
+func (gs *GeneticSearch) specIndex(name string) int {
+	for i, spec := range gs.Specs {
+		if spec.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (gs *GeneticSearch) checkGenome(genome Genome) error {
+	if len(genome) == 0 {
+		return errors.New("genome has no genes")
+	}
+	seen := make(map[string]bool)
+	for _, gene := range genome {
+		i := gs.specIndex(gene.Spec)
+		if i < 0 {
+			return fmt.Errorf("genome uses unknown adaptor spec %q", gene.Spec)
+		}
+		if seen[gene.Spec] {
+			return fmt.Errorf("genome uses adaptor spec %q twice", gene.Spec)
+		}
+		seen[gene.Spec] = true
+		if err := gs.Specs[i].validate(gene.Params); err != nil {
+			return fmt.Errorf("invalid %s parameters %s: %w", gene.Spec, gene.Params, err)
+		}
+	}
+	return nil
+}
+
+func (gs *GeneticSearch) initialPopulation() ([]Genome, error) {
+	rng := rand.New(rand.NewSource(gs.Seed))
+	population := make([]Genome, 0, gs.PopulationSize)
+	for _, genome := range gs.SeedPopulation {
+		if err := gs.checkGenome(genome); err != nil {
+			return nil, fmt.Errorf("invalid seed genome %s: %w", genome.Key(), err)
+		}
+		if len(population) < gs.PopulationSize {
+			population = append(population, gs.normalize(genome.clone()))
+		}
+	}
+	for len(population) < gs.PopulationSize {
+		genome, err := gs.randomGenome(rng)
+		if err != nil {
+			return nil, err
+		}
+		population = append(population, genome)
+	}
+	return population, nil
+}
+
+// evolve evaluates and breeds generations starting from the checkpoint state.
+func (gs *GeneticSearch) evolve(ctx context.Context, data []model.DataPoint, cp Checkpoint) (*GeneticResult, error) {
+	split := len(data) - int(float64(len(data))*gs.HoldoutFraction)
+	if split <= 0 {
+		return nil, errors.New("not enough data to train on")
+	}
+	train := &evaluator{data: data[:split], objective: gs.Objective, trackIterations: gs.TrackIterations, monitor: gs.Monitor}
+	var holdout *evaluator
+	if split < len(data) {
+		// The holdout runs over the full data so indicators are warmed up when the holdout starts.
+		holdout = &evaluator{data: data, objective: gs.Objective, trackIterations: gs.TrackIterations, monitor: gs.Monitor, scoreFrom: data[split].Time}
+	}
+
+	cache := make(map[string]Individual)
+	result := &GeneticResult{Objective: gs.Objective.Name}
+	for cp.Generation < gs.Generations {
+		individuals, err := gs.evaluatePopulation(ctx, cp.Generation, train, holdout, cp.Population, cache)
+		if err != nil {
+			return nil, err
+		}
+		rankIndividuals(individuals)
+
+		stats := GenerationStats{Generation: cp.Generation, BestFitness: individuals[0].Fitness, BestGenome: individuals[0].Genome.Key()}
+		for _, individual := range individuals {
+			stats.MeanFitness += individual.Fitness
+		}
+		stats.MeanFitness /= float64(len(individuals))
+		cp.History = append(cp.History, stats)
+		if cp.Best == nil || individuals[0].Fitness > cp.Best.Fitness {
+			best := individuals[0]
+			cp.Best = &best
+		}
+
+		cp.Generation++
+		cp.Population = gs.breed(rand.New(rand.NewSource(cp.Seed+int64(cp.Generation))), individuals)
+		result.Final = individuals
+		if gs.OnGeneration != nil {
+			if err := gs.OnGeneration(cp); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	result.Best = cp.Best
+	result.History = cp.History
+	return result, nil
+}
+
+// evaluatePopulation backtests the genomes not evaluated yet and computes the fitness of every genome.
+func (gs *GeneticSearch) evaluatePopulation(ctx context.Context, generation int, train, holdout *evaluator, population []Genome, cache map[string]Individual) ([]Individual, error) {
+	var pending []Genome
+	var candidates []candidate
+	queued := make(map[string]bool)
+	for _, genome := range population {
+		key := genome.Key()
+		if _, ok := cache[key]; ok || queued[key] {
+			continue
+		}
+		queued[key] = true
+		pending = append(pending, genome)
+		candidates = append(candidates, gs.candidate(genome))
+	}
+
+	trainResults, err := train.evaluateAll(phaseContext(ctx, "train", generation), candidates, gs.Workers)
+	if err != nil {
+		return nil, err
+	}
+	var holdoutResults []Result
+	if holdout != nil {
+		if holdoutResults, err = holdout.evaluateAll(phaseContext(ctx, "holdout", generation), candidates, gs.Workers); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, genome := range pending {
+		individual := Individual{Genome: genome, Train: trainResults[i], Fitness: trainResults[i].Score}
+		if holdout != nil {
+			individual.Holdout = &holdoutResults[i]
+			individual.HoldoutScore = holdoutResults[i].Score
+			individual.Fitness -= gs.OverfitPenalty * math.Max(0, individual.Train.Score-individual.HoldoutScore)
+			individual.Fitness = math.Max(individual.Fitness, minScore)
+		}
+		cache[genome.Key()] = individual
+	}
+
+	individuals := make([]Individual, len(population))
+	for i, genome := range population {
+		individuals[i] = cache[genome.Key()]
+	}
+	return individuals, nil
+}
+
+// phaseContext tags the decisions recorded under ctx with the phase and generation, if they are recorded at all.
+func phaseContext(ctx context.Context, phase string, generation int) context.Context {
+	recorder := audit.RecorderFromContext(ctx)
+	if recorder == nil {
+		return ctx
+	}
+	return audit.WithRecorder(ctx, audit.Tagged(recorder, phase, generation))
+}
+
+func (gs *GeneticSearch) candidate(genome Genome) candidate {
+	c := candidate{specs: make([]AdaptorSpec, len(genome)), params: make([]Params, len(genome))}
+	for i, gene := range genome {
+		c.specs[i] = gs.Specs[gs.specIndex(gene.Spec)]
+		c.params[i] = gene.Params
+	}
+	return c
+}
+
+// breed creates the next generation from individuals ranked by fitness.
+func (gs *GeneticSearch) breed(rng *rand.Rand, ranked []Individual) []Genome {
+	next := make([]Genome, 0, gs.PopulationSize)
+	for i := 0; i < gs.Elitism && i < len(ranked); i++ {
+		next = append(next, ranked[i].Genome.clone())
+	}
+	for len(next) < gs.PopulationSize {
+		child := tournament(rng, ranked).Genome.clone()
+		if rng.Float64() < gs.CrossoverRate {
+			child = gs.crossover(rng, child, tournament(rng, ranked).Genome)
+		}
+		next = append(next, gs.mutate(rng, child))
+	}
+	return next
+}
+
+// tournament returns the fittest of a few randomly drawn individuals.
+func tournament(rng *rand.Rand, individuals []Individual) Individual {
+	best := individuals[rng.Intn(len(individuals))]
+	for i := 1; i < tournamentSize; i++ {
+		if contender := individuals[rng.Intn(len(individuals))]; contender.Fitness > best.Fitness {
+			best = contender
+		}
+	}
+	return best
+}
+
+// crossover mixes two genomes uniformly, spec by spec and, for shared specs, parameter by parameter.
+func (gs *GeneticSearch) crossover(rng *rand.Rand, a, b Genome) Genome {
+	genesA, genesB := genesBySpec(a), genesBySpec(b)
+	var child Genome
+	for _, spec := range gs.Specs {
+		geneA, inA := genesA[spec.Name]
+		geneB, inB := genesB[spec.Name]
+		switch {
+		case inA && inB:
+			params := geneA.Params.Clone()
+			for _, parameter := range spec.Parameters {
+				if rng.Float64() < 0.5 {
+					params[parameter.Name] = geneB.Params[parameter.Name]
+				}
+			}
+			if spec.validate(params) != nil {
+				params = geneA.Params.Clone()
+			}
+			child = append(child, Gene{Spec: spec.Name, Params: params})
+		case inA && rng.Float64() < 0.5:
+			child = append(child, Gene{Spec: spec.Name, Params: geneA.Params.Clone()})
+		case inB && rng.Float64() < 0.5:
+			child = append(child, Gene{Spec: spec.Name, Params: geneB.Params.Clone()})
+		}
+	}
+	if len(child) == 0 {
+		child = a.clone()
+	}
+	for len(child) > gs.maxGenes() {
+		i := rng.Intn(len(child))
+		child = append(child[:i], child[i+1:]...)
+	}
+	return child
+}
+
+// mutate nudges parameters to neighbouring values and occasionally adds or removes an adaptor.
+func (gs *GeneticSearch) mutate(rng *rand.Rand, genome Genome) Genome {
+	for i, gene := range genome {
+		spec := gs.Specs[gs.specIndex(gene.Spec)]
+		for attempt := 0; attempt < maxSampleRetries; attempt++ {
+			mutated := gene.Params.Clone()
+			for _, parameter := range spec.Parameters {
+				if rng.Float64() < gs.MutationRate {
+					mutated[parameter.Name] = neighbourValue(rng, parameter, mutated[parameter.Name])
+				}
+			}
+			if spec.validate(mutated) == nil {
+				genome[i].Params = mutated
+				break
+			}
+		}
+	}
+
+	if len(genome) < gs.maxGenes() && rng.Float64() < gs.MutationRate {
+		present := genesBySpec(genome)
+		var unused []AdaptorSpec
+		for _, spec := range gs.Specs {
+			if _, ok := present[spec.Name]; !ok {
+				unused = append(unused, spec)
+			}
+		}
+		spec := unused[rng.Intn(len(unused))]
+		if params, ok := randomParams(rng, spec); ok {
+			genome = append(genome, Gene{Spec: spec.Name, Params: params})
+		}
+	}
+	if len(genome) > 1 && rng.Float64() < gs.MutationRate {
+		i := rng.Intn(len(genome))
+		genome = append(genome[:i], genome[i+1:]...)
+	}
+	return gs.normalize(genome)
+}
+
+// randomGenome picks a random subset of the specs with random valid parameters.
+// It fails when no spec yields valid parameters within the retry budget.
+func (gs *GeneticSearch) randomGenome(rng *rand.Rand) (Genome, error) {
+	for attempt := 0; attempt < maxSampleRetries; attempt++ {
+		var genome Genome
+		size := 1 + rng.Intn(gs.maxGenes())
+		for _, i := range rng.Perm(len(gs.Specs))[:size] {
+			if params, ok := randomParams(rng, gs.Specs[i]); ok {
+				genome = append(genome, Gene{Spec: gs.Specs[i].Name, Params: params})
+			}
+		}
+		if len(genome) > 0 {
+			return gs.normalize(genome), nil
+		}
+	}
+	return nil, fmt.Errorf("no adaptor spec produced valid parameters after %d attempts, check the parameter ranges", maxSampleRetries)
+}
+
+// normalize orders the genes like the specs so equivalent genomes share a key.
+func (gs *GeneticSearch) normalize(genome Genome) Genome {
+	sort.SliceStable(genome, func(i, j int) bool {
+		return gs.specIndex(genome[i].Spec) < gs.specIndex(genome[j].Spec)
+	})
+	return genome
+}
+
+func genesBySpec(genome Genome) map[string]Gene {
+	genes := make(map[string]Gene, len(genome))
+	for _, gene := range genome {
+		genes[gene.Spec] = gene
+	}
+	return genes
+}
+
+// randomParams samples parameter values until the spec accepts them.
+func randomParams(rng *rand.Rand, spec AdaptorSpec) (Params, bool) {
+	for attempt := 0; attempt < maxSampleRetries; attempt++ {
+		params := make(Params, len(spec.Parameters))
+		for _, parameter := range spec.Parameters {
+			values := parameter.Values()
+			params[parameter.Name] = values[rng.Intn(len(values))]
+		}
+		if spec.validate(params) == nil {
+			return params, true
+		}
+	}
+	return nil, false
+}
+
+// neighbourValue moves the value one step up or down the parameter's values.
+func neighbourValue(rng *rand.Rand, parameter Parameter, current float64) float64 {
+	values := parameter.Values()
+	index := 0
+	for i, value := range values {
+		if math.Abs(value-current) < math.Abs(values[index]-current) {
+			index = i
+		}
+	}
+	if len(values) == 1 {
+		return values[0]
+	}
+	if (rng.Intn(2) == 0 && index > 0) || index == len(values)-1 {
+		return values[index-1]
+	}
+	return values[index+1]
+}
+
+// rankIndividuals sorts individuals by fitness, best first, keeping the population order for ties.
+func rankIndividuals(individuals []Individual) {
+	sort.SliceStable(individuals, func(i, j int) bool {
+		return individuals[i].Fitness > individuals[j].Fitness
+	})
+}
+
+// SaveCheckpoint writes the checkpoint as JSON, replacing the file atomically.
+func SaveCheckpoint(path string, cp Checkpoint) error {
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding checkpoint: %w", err)
+	}
+	if err := fileutils.WriteFileAtomic(path, data); err != nil {
+		return fmt.Errorf("error writing checkpoint: %w", err)
+	}
+	return nil
+}
+
+// LoadCheckpoint reads a checkpoint written by SaveCheckpoint.
+func LoadCheckpoint(path string) (Checkpoint, error) {
+	var cp Checkpoint
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cp, fmt.Errorf("error reading checkpoint: %w", err)
+	}
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return cp, fmt.Errorf("error decoding checkpoint %s: %w", path, err)
+	}
+	return cp, nil
+}
@@ -0,0 +1,189 @@
+package optimizer_test
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/audit"
+	"github.com/vd09/trading-algorithm-backtesting-system/backtesting"
+	"github.com/vd09/trading-algorithm-backtesting-system/optimizer"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+func newGeneticSearch(t *testing.T) *optimizer.GeneticSearch {
+	return &optimizer.GeneticSearch{
+		Specs:           optimizer.DefaultAdaptorSpecs(),
+		Objective:       optimizer.TotalProfit,
+		TrackIterations: 5,
+		Workers:         4,
+		Monitor:         test_utils.NewMockMetricsCollector(t),
+		PopulationSize:  8,
+		Generations:     4,
+		Elitism:         2,
+		CrossoverRate:   0.7,
+		MutationRate:    0.3,
+		MaxGenes:        2,
+		HoldoutFraction: 0.25,
+		OverfitPenalty:  0.5,
+		Seed:            42,
+	}
+}
+
+func TestGeneticSearchRun(t *testing.T) {
+	data := sineData(200)
+	seed := optimizer.Genome{{Spec: "rsi", Params: optimizer.Params{"period": 8, "oversold": 30, "overbought": 70}}}
+
+	search := newGeneticSearch(t)
+	search.SeedPopulation = []optimizer.Genome{seed}
+	var checkpoints []optimizer.Checkpoint
+	search.OnGeneration = func(cp optimizer.Checkpoint) error {
+		checkpoints = append(checkpoints, cp)
+		return nil
+	}
+	result, err := search.Run(context.Background(), data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	test_utils.AssertEqual(t, 4, len(result.History), "Number of generations does not match")
+	test_utils.AssertEqual(t, 4, len(checkpoints), "Number of checkpoints does not match")
+	test_utils.AssertTrue(t, result.Best != nil && result.Best.Holdout != nil, "Expected the best individual to be scored on the holdout")
+	for i := 1; i < len(result.History); i++ {
+		// Elitism keeps the best genome, so the best fitness never drops.
+		test_utils.AssertTrue(t, result.History[i].BestFitness >= result.History[i-1].BestFitness, "Best fitness dropped between generations")
+	}
+	for _, individual := range result.Final {
+		test_utils.AssertTrue(t, len(individual.Genome) >= 1 && len(individual.Genome) <= 2, "Genome size is out of bounds")
+	}
+
+	rerun := newGeneticSearch(t)
+	rerun.SeedPopulation = []optimizer.Genome{seed}
+	rerunResult, err := rerun.Run(context.Background(), data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	test_utils.AssertEqual(t, result.History, rerunResult.History, "Runs with the same seed differ")
+}
+
+func TestGeneticSearchResume(t *testing.T) {
+	data := sineData(200)
+	full, err := newGeneticSearch(t).Run(context.Background(), data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	interrupted := newGeneticSearch(t)
+	interrupted.Generations = 2
+	interrupted.OnGeneration = func(cp optimizer.Checkpoint) error {
+		return optimizer.SaveCheckpoint(path, cp)
+	}
+	if _, err := interrupted.Run(context.Background(), data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cp, err := optimizer.LoadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	test_utils.AssertEqual(t, 2, cp.Generation, "Checkpoint generation does not match")
+
+	resumed, err := newGeneticSearch(t).Resume(context.Background(), data, cp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	test_utils.AssertEqual(t, full.History, resumed.History, "Resumed run differs from the uninterrupted run")
+	test_utils.AssertEqual(t, full.Best.Genome.Key(), resumed.Best.Genome.Key(), "Best genome does not match")
+
+	cp.Objective = optimizer.WinRate.Name
+	if _, err := newGeneticSearch(t).Resume(context.Background(), data, cp); err == nil {
+		t.Fatalf("expected objective mismatch error")
+	}
+}
+
+func TestGeneticSearchInvalidSeed(t *testing.T) {
+	search := newGeneticSearch(t)
+	search.SeedPopulation = []optimizer.Genome{{{Spec: "ema", Params: optimizer.Params{"fast": 30, "slow": 20}}}}
+	if _, err := search.Run(context.Background(), sineData(50)); err == nil {
+		t.Fatalf("expected invalid seed genome error")
+	}
+}
+
+func TestGeneticSearchUnsampleableSpecs(t *testing.T) {
+	search := newGeneticSearch(t)
+	for _, spec := range search.Specs {
+		if spec.Name == "ema" {
+			// The fast range never falls below the slow range, so no parameters validate.
+			spec.Parameters = []optimizer.Parameter{optimizer.IntRange("fast", 50, 60, 5), optimizer.IntRange("slow", 20, 40, 10)}
+			search.Specs = []optimizer.AdaptorSpec{spec}
+			break
+		}
+	}
+	if _, err := search.Run(context.Background(), sineData(50)); err == nil {
+		t.Fatalf("expected an error for specs without valid parameters")
+	}
+}
+
+func TestGeneticSearchRecordsDecisions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	journal, err := audit.OpenJournal(path, audit.NewRunID())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	search := newGeneticSearch(t)
+	search.Generations = 1
+	if _, err := search.Run(audit.WithRecorder(context.Background(), journal), sineData(100)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := journal.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	decisions, err := audit.Query(path, audit.Filter{RunID: journal.RunID()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	test_utils.AssertTrue(t, len(decisions) > 0, "Expected the backtests to record their decisions")
+
+	generation := 0
+	for _, phase := range []string{"train", "holdout"} {
+		tagged, err := audit.Query(path, audit.Filter{Phase: phase, Generation: &generation})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		test_utils.AssertTrue(t, len(tagged) > 0, "Expected decisions of the "+phase+" phase in the first generation")
+	}
+	for _, decision := range decisions {
+		test_utils.AssertTrue(t, decision.Phase != "" && decision.Generation != nil, "Expected every decision to be tagged")
+	}
+}
+
+func TestGeneticSearchKeepsFitnessFinite(t *testing.T) {
+	search := newGeneticSearch(t)
+	search.Generations = 2
+	search.OverfitPenalty = 3
+	search.Objective = optimizer.Objective{Name: "undefined", Score: func(s backtesting.PerformanceSummary) float64 {
+		if s.Trades == 0 {
+			return math.Inf(-1)
+		}
+		return s.TotalProfitPercentage
+	}}
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	search.OnGeneration = func(cp optimizer.Checkpoint) error {
+		return optimizer.SaveCheckpoint(path, cp)
+	}
+
+	result, err := search.Run(context.Background(), sineData(100))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, individual := range result.Final {
+		test_utils.AssertTrue(t, !math.IsInf(individual.Fitness, 0) && !math.IsNaN(individual.Fitness), "Expected a finite fitness")
+	}
+	for _, stats := range result.History {
+		test_utils.AssertTrue(t, !math.IsInf(stats.MeanFitness, 0), "Expected a finite mean fitness")
+	}
+}
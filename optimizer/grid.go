@@ -0,0 +1,123 @@
+package optimizer
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+)
+
+// GridSearch backtests every combination of parameter values of its adaptor specs.
+// Each candidate combines one adaptor per spec, like CombinationTradingAlgorithm does.
+type GridSearch struct {
+	Specs           []AdaptorSpec
+	Objective       Objective
+	TrackIterations int
+	// Workers is the number of candidates backtested in parallel, defaults to the number of CPUs.
+	Workers int
+	Monitor monitor.Monitoring
+}
+
+// GridResult holds the ranked results of a grid search.
+type GridResult struct {
+	Objective string
+	// Results are sorted by score, best first.
+	Results []Result
+	// Skipped counts the candidates rejected by a spec's Validate function.
+	Skipped     int
+	Sensitivity []SensitivityRow
+}
+
+// Best returns the highest scoring result.
+func (gr *GridResult) Best() (Result, bool) {
+	if len(gr.Results) == 0 {
+		return Result{}, false
+	}
+	return gr.Results[0], true
+}
+
+// Expand returns the parameter values of every valid candidate and the number of rejected ones.
+func (gs *GridSearch) Expand() ([][]Params, int) {
+	perSpec := make([][]Params, len(gs.Specs))
+	skipped := 0
+	for i, spec := range gs.Specs {
+		for _, params := range expandParameters(spec.Parameters) {
+			if spec.validate(params) != nil {
+				skipped++
+				continue
+			}
+			perSpec[i] = append(perSpec[i], params)
+		}
+	}
+
+	candidates := [][]Params{{}}
+	for _, options := range perSpec {
+		var next [][]Params
+		for _, prefix := range candidates {
+			for _, params := range options {
+				next = append(next, append(append([]Params{}, prefix...), params))
+			}
+		}
+		candidates = next
+	}
+	return candidates, skipped
+}
+
+// Run backtests every candidate over data and ranks them by the objective.
+func (gs *GridSearch) Run(ctx context.Context, data []model.DataPoint) (*GridResult, error) {
+	if len(gs.Specs) == 0 {
+		return nil, errors.New("grid search needs at least one adaptor spec")
+	}
+	if gs.Objective.Score == nil {
+		return nil, errors.New("grid search needs an objective")
+	}
+	if err := checkSpecNames(gs.Specs); err != nil {
+		return nil, err
+	}
+
+	expanded, skipped := gs.Expand()
+	candidates := make([]candidate, len(expanded))
+	for i, params := range expanded {
+		candidates[i] = candidate{specs: gs.Specs, params: params}
+	}
+
+	e := &evaluator{data: data, objective: gs.Objective, trackIterations: gs.TrackIterations, monitor: gs.Monitor}
+	results, err := e.evaluateAll(ctx, candidates, gs.Workers)
+	if err != nil {
+		return nil, err
+	}
+
+	rankResults(results)
+	return &GridResult{
+		Objective:   gs.Objective.Name,
+		Results:     results,
+		Skipped:     skipped,
+		Sensitivity: Sensitivity(gs.Specs, results),
+	}, nil
+}
+
+// expandParameters returns the cartesian product of the parameter values.
+func expandParameters(parameters []Parameter) []Params {
+	grid := []Params{{}}
+	for _, parameter := range parameters {
+		var next []Params
+		for _, params := range grid {
+			for _, value := range parameter.Values() {
+				expanded := params.Clone()
+				expanded[parameter.Name] = value
+				next = append(next, expanded)
+			}
+		}
+		grid = next
+	}
+	return grid
+}
+
+// rankResults sorts results by score, best first, keeping the candidate order for ties.
+func rankResults(results []Result) {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+}
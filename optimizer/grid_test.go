@@ -0,0 +1,125 @@
+package optimizer_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/optimizer"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+var rsiSpec = optimizer.AdaptorSpec{
+	Name: "rsi",
+	Parameters: []optimizer.Parameter{
+		optimizer.IntRange("period", 5, 9, 2),
+		optimizer.Choice("oversold", 30, 40),
+		optimizer.Choice("overbought", 60, 70),
+	},
+	Validate: func(p optimizer.Params) error {
+		if p.Float("oversold") == 40 && p.Float("overbought") == 60 {
+			return errors.New("band too narrow")
+		}
+		return nil
+	},
+	Build: func(ctx context.Context, p optimizer.Params, m monitor.Monitoring) (indicator_adaptor.IndicatorAdaptor, error) {
+		return indicator_adaptor.NewRSIAdapter(ctx, p.Int("period"), 20, p.Float("overbought"), p.Float("oversold"), m), nil
+	},
+}
+
+func sineData(bars int) []model.DataPoint {
+	data := make([]model.DataPoint, bars)
+	for i := range data {
+		close := 100 + 10*math.Sin(float64(i)/4) + float64(i%3)
+		data[i] = model.DataPoint{Time: int64(i + 1), Open: close, High: close + 1, Low: close - 1, Close: close}
+	}
+	return data
+}
+
+func TestParameterValues(t *testing.T) {
+	test_utils.AssertEqual(t, []float64{5, 7, 9}, optimizer.IntRange("period", 5, 10, 2).Values(), "Integer range does not match")
+	test_utils.AssertEqual(t, []float64{1, 1.5, 2}, optimizer.FloatRange("multiplier", 1, 2, 0.5).Values(), "Float range does not match")
+	test_utils.AssertEqual(t, []float64{3, 1}, optimizer.Choice("x", 3, 1).Values(), "Choices do not match")
+}
+
+func TestGridSearchExpand(t *testing.T) {
+	search := &optimizer.GridSearch{Specs: []optimizer.AdaptorSpec{rsiSpec, rsiSpec}}
+	candidates, skipped := search.Expand()
+
+	// Each spec has 3 periods * 4 threshold pairs, 3 of which are rejected.
+	test_utils.AssertEqual(t, 6, skipped, "Number of skipped candidates does not match")
+	test_utils.AssertEqual(t, 81, len(candidates), "Number of candidates does not match")
+}
+
+func TestGridSearchRun(t *testing.T) {
+	mock := test_utils.NewMockMetricsCollector(t)
+	data := sineData(120)
+
+	search := &optimizer.GridSearch{
+		Specs:           []optimizer.AdaptorSpec{rsiSpec},
+		Objective:       optimizer.TotalProfit,
+		TrackIterations: 5,
+		Workers:         4,
+		Monitor:         mock,
+	}
+	result, err := search.Run(context.Background(), data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	test_utils.AssertEqual(t, "total_profit", result.Objective, "Objective does not match")
+	test_utils.AssertEqual(t, 9, len(result.Results), "Number of results does not match")
+	test_utils.AssertEqual(t, 3, result.Skipped, "Number of skipped candidates does not match")
+	for i := 1; i < len(result.Results); i++ {
+		test_utils.AssertTrue(t, result.Results[i-1].Score >= result.Results[i].Score, "Results are not ranked by score")
+	}
+	best, ok := result.Best()
+	test_utils.AssertTrue(t, ok, "Expected a best result")
+	test_utils.AssertTrue(t, best.Summary.Trades > 0, "Expected the best candidate to trade")
+
+	// 3 periods, 2 oversold and 2 overbought values.
+	test_utils.AssertEqual(t, 7, len(result.Sensitivity), "Number of sensitivity rows does not match")
+	test_utils.AssertEqual(t, "rsi.overbought", result.Sensitivity[0].Parameter, "Sensitivity order does not match")
+	test_utils.AssertEqual(t, 3, result.Sensitivity[0].Candidates, "Sensitivity candidates do not match")
+
+	sequential := *search
+	sequential.Workers = 1
+	sequentialResult, err := sequential.Run(context.Background(), data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	test_utils.AssertEqual(t, result.Results, sequentialResult.Results, "Parallel results differ from sequential results")
+}
+
+func TestGridSearchBuildError(t *testing.T) {
+	mock := test_utils.NewMockMetricsCollector(t)
+	failing := optimizer.AdaptorSpec{
+		Name:       "failing",
+		Parameters: []optimizer.Parameter{optimizer.Choice("x", 1)},
+		Build: func(ctx context.Context, p optimizer.Params, m monitor.Monitoring) (indicator_adaptor.IndicatorAdaptor, error) {
+			return nil, errors.New("boom")
+		},
+	}
+	search := &optimizer.GridSearch{Specs: []optimizer.AdaptorSpec{failing}, Objective: optimizer.WinRate, Monitor: mock}
+	if _, err := search.Run(context.Background(), sineData(10)); err == nil {
+		t.Fatalf("expected build error")
+	}
+}
+
+func TestGridSearchRejectsDuplicateSpecNames(t *testing.T) {
+	spec := optimizer.RegistrySpec("rsi", optimizer.Choice("period", 7))
+	search := &optimizer.GridSearch{Specs: []optimizer.AdaptorSpec{spec, spec}, Objective: optimizer.WinRate, Monitor: test_utils.NewMockMetricsCollector(t)}
+	if _, err := search.Run(context.Background(), sineData(10)); err == nil {
+		t.Fatalf("expected an error for specs sharing a name")
+	}
+
+	genetic := newGeneticSearch(t)
+	genetic.Specs = search.Specs
+	if _, err := genetic.Run(context.Background(), sineData(10)); err == nil {
+		t.Fatalf("expected an error for specs sharing a name")
+	}
+}
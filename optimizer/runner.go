@@ -0,0 +1,117 @@
+package optimizer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/algorithm"
+	"github.com/vd09/trading-algorithm-backtesting-system/backtesting"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+)
+
+// minScore is the floor of scores and fitness. Undefined scores get it so the candidate ranks last, while sums
+// over a population and the overfit penalty stay finite and checkpoints can be written.
+const minScore = -1e15
+
+// candidate pairs adaptor specs with the parameter values to build them with.
+type candidate struct {
+	specs  []AdaptorSpec
+	params []Params
+}
+
+// evaluator backtests candidates over a fixed data set.
+type evaluator struct {
+	data            []model.DataPoint
+	objective       Objective
+	trackIterations int
+	monitor         monitor.Monitoring
+	// scoreFrom restricts the summary to positions entered at or after this time, letting
+	// indicators warm up on earlier bars without those bars counting towards the score.
+	scoreFrom int64
+}
+
+// evaluate builds the candidate's adaptors, runs them as a combination through a
+// dedicated backtest engine and scores the result.
+func (e *evaluator) evaluate(ctx context.Context, c candidate) (Result, error) {
+	adaptors := make([]indicator_adaptor.IndicatorAdaptor, len(c.specs))
+	for i, spec := range c.specs {
+		adaptor, err := spec.Build(ctx, c.params[i], e.monitor)
+		if err != nil {
+			return Result{}, fmt.Errorf("error building %s(%s): %w", spec.Name, c.params[i], err)
+		}
+		adaptors[i] = adaptor
+	}
+
+	algo := algorithm.NewCombinationTradingAlgorithm(ctx, adaptors, e.monitor)
+	engine := backtesting.NewBacktestEngine(e.data, e.trackIterations)
+	engine.AddAlgorithm(algo)
+	engine.Simulate(ctx)
+	if err := engine.Close(); err != nil {
+		return Result{}, fmt.Errorf("error closing %s: %w", algo.Name(), err)
+	}
+
+	var positions []backtesting.OpenPosition
+	for _, position := range engine.Performance[algo.Name()].CompletedPositions {
+		if position.EntryPoint.Time >= e.scoreFrom {
+			positions = append(positions, position)
+		}
+	}
+	summary := backtesting.SummarizePositions(positions)
+
+	score := e.objective.Score(summary)
+	if math.IsNaN(score) || math.IsInf(score, 0) {
+		score = minScore
+	}
+	score = math.Max(score, minScore)
+	return Result{
+		Params:    c.params,
+		Algorithm: algo.Name(),
+		Summary:   summary,
+		Score:     score,
+	}, nil
+}
+
+// evaluateAll backtests the candidates on a pool of workers and returns the results in candidate order.
+func (e *evaluator) evaluateAll(ctx context.Context, candidates []candidate, workers int) ([]Result, error) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	results := make([]Result, len(candidates))
+	errs := make([]error, len(candidates))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], errs[i] = e.evaluate(ctx, candidates[i])
+			}
+		}()
+	}
+
+	for i := range candidates {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,67 @@
+package optimizer
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+// SensitivityRow aggregates the scores of every candidate sharing one parameter value.
+type SensitivityRow struct {
+	// Parameter is the spec name and parameter name joined by a dot.
+	Parameter  string
+	Value      float64
+	Candidates int
+	MeanScore  float64
+	MinScore   float64
+	MaxScore   float64
+}
+
+// Sensitivity builds the parameter sensitivity table of the results, sorted by parameter and value.
+// The specs must have unique names, the rows of specs sharing a name are merged.
+func Sensitivity(specs []AdaptorSpec, results []Result) []SensitivityRow {
+	type rowKey struct {
+		parameter string
+		value     float64
+	}
+	rows := make(map[rowKey]*SensitivityRow)
+	for _, result := range results {
+		for i, params := range result.Params {
+			for name, value := range params {
+				key := rowKey{parameter: specs[i].Name + "." + name, value: value}
+				row, exists := rows[key]
+				if !exists {
+					row = &SensitivityRow{Parameter: key.parameter, Value: value, MinScore: math.Inf(1), MaxScore: math.Inf(-1)}
+					rows[key] = row
+				}
+				row.Candidates++
+				row.MeanScore += result.Score
+				row.MinScore = math.Min(row.MinScore, result.Score)
+				row.MaxScore = math.Max(row.MaxScore, result.Score)
+			}
+		}
+	}
+
+	table := make([]SensitivityRow, 0, len(rows))
+	for _, row := range rows {
+		row.MeanScore /= float64(row.Candidates)
+		table = append(table, *row)
+	}
+	sort.Slice(table, func(i, j int) bool {
+		if table[i].Parameter != table[j].Parameter {
+			return table[i].Parameter < table[j].Parameter
+		}
+		return table[i].Value < table[j].Value
+	})
+	return table
+}
+
+// PrintSensitivity writes the sensitivity table in the same layout as the engine's iteration tables.
+func PrintSensitivity(w io.Writer, table []SensitivityRow) {
+	fmt.Fprintf(w, "|%-30s | %-10s | %-10s | %-10s | %-10s | %-10s |\n", "Parameter", "Value", "Candidates", "Mean", "Min", "Max")
+	fmt.Fprintln(w, "|---------------------------------------------------------------------------------------------------")
+	for _, row := range table {
+		fmt.Fprintf(w, "|%-30s | %-10g | %-10d | %-10.2f | %-10.2f | %-10.2f |\n", row.Parameter, row.Value, row.Candidates, row.MeanScore, row.MinScore, row.MaxScore)
+	}
+}
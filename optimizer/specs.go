@@ -0,0 +1,170 @@
+package optimizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
+)
+
+// DefaultAdaptorSpecs returns search spaces for the built-in adaptors.
+func DefaultAdaptorSpecs() []AdaptorSpec {
+	return []AdaptorSpec{
+		RegistrySpec("rsi",
+			IntRange("period", 6, 24, 2),
+			IntRange("oversold", 20, 40, 5),
+			IntRange("overbought", 60, 80, 5),
+		),
+		{
+			Name: "ema",
+			Parameters: []Parameter{
+				IntRange("fast", 5, 30, 5),
+				IntRange("slow", 20, 100, 10),
+			},
+			Validate: func(p Params) error {
+				if p.Int("fast") >= p.Int("slow") {
+					return errors.New("fast period must be lower than slow period")
+				}
+				return nil
+			},
+			Build: func(ctx context.Context, p Params, m monitor.Monitoring) (indicator_adaptor.IndicatorAdaptor, error) {
+				return registry.NewAdaptor(ctx, "ema", map[string]interface{}{"periods": []int{p.Int("fast"), p.Int("slow")}}, m)
+			},
+		},
+		{
+			Name: "moving_average",
+			Parameters: []Parameter{
+				IntRange("fast_type", 0, len(indicator.MovingAverageTypes())-1, 1),
+				IntRange("fast", 5, 30, 5),
+				IntRange("slow_type", 0, len(indicator.MovingAverageTypes())-1, 1),
+				IntRange("slow", 20, 100, 10),
+			},
+			Validate: func(p Params) error {
+				if p.Int("fast") >= p.Int("slow") {
+					return errors.New("fast period must be lower than slow period")
+				}
+				return nil
+			},
+			Build: func(ctx context.Context, p Params, m monitor.Monitoring) (indicator_adaptor.IndicatorAdaptor, error) {
+				// The types are searched by their index in indicator.MovingAverageTypes.
+				types := indicator.MovingAverageTypes()
+				return registry.NewAdaptor(ctx, "moving_average", map[string]interface{}{
+					"types":   []string{string(types[p.Int("fast_type")]), string(types[p.Int("slow_type")])},
+					"periods": []int{p.Int("fast"), p.Int("slow")},
+				}, m)
+			},
+		},
+		RegistrySpec("macd",
+			IntRange("short_period", 8, 16, 2),
+			IntRange("long_period", 20, 32, 3),
+			IntRange("signal_period", 5, 11, 2),
+		),
+		RegistrySpec("supertrend",
+			IntRange("period", 7, 21, 2),
+			FloatRange("multiplier", 1.5, 4, 0.5),
+		),
+		RegistrySpec("pivot",
+			IntRange("max_history", 5, 20, 5),
+			IntRange("threshold", 1, 3, 1),
+		),
+		RegistrySpec("fibonacci",
+			IntRange("size", 10, 60, 10),
+		),
+		RegistrySpec("bollinger",
+			IntRange("period", 10, 30, 5),
+			FloatRange("multiplier", 1.5, 3, 0.5),
+		),
+		RegistrySpec("atr",
+			IntRange("period", 7, 21, 7),
+			FloatRange("expansion", 1.5, 3, 0.5),
+		),
+		RegistrySpec("stochastic",
+			IntRange("period", 5, 21, 4),
+			IntRange("oversold", 10, 30, 10),
+			IntRange("overbought", 70, 90, 10),
+		),
+		RegistrySpec("williams_r",
+			IntRange("period", 7, 21, 7),
+		),
+		RegistrySpec("adx",
+			IntRange("period", 7, 21, 7),
+			IntRange("trend_threshold", 20, 30, 5),
+		),
+		RegistrySpec("ichimoku",
+			IntRange("tenkan", 7, 11, 2),
+			IntRange("kijun", 22, 30, 4),
+		),
+		RegistrySpec("obv",
+			IntRange("period", 10, 30, 10),
+		),
+		RegistrySpec("accumulation_distribution",
+			IntRange("period", 10, 30, 10),
+		),
+		RegistrySpec("vwap",
+			FloatRange("band_multiplier", 1, 3, 1),
+		),
+		RegistrySpec("mfi",
+			IntRange("period", 7, 21, 7),
+		),
+		RegistrySpec("cmf",
+			IntRange("period", 10, 30, 10),
+			FloatRange("threshold", 0.05, 0.15, 0.05),
+		),
+		RegistrySpec("parabolic_sar",
+			FloatRange("step", 0.01, 0.03, 0.01),
+		),
+		RegistrySpec("keltner",
+			IntRange("period", 10, 30, 10),
+			FloatRange("multiplier", 1.5, 2.5, 0.5),
+		),
+		RegistrySpec("donchian",
+			IntRange("entry_period", 20, 55, 35),
+			IntRange("exit_period", 10, 20, 10),
+		),
+		RegistrySpec("candlestick",
+			FloatRange("long_body", 0.5, 0.7, 0.1),
+		),
+		RegistrySpec("cci",
+			IntRange("period", 10, 30, 10),
+			IntRange("overbought", 100, 200, 50),
+			IntRange("oversold", -200, -100, 50),
+		),
+		RegistrySpec("roc",
+			IntRange("period", 6, 24, 6),
+		),
+		RegistrySpec("momentum",
+			IntRange("period", 5, 20, 5),
+		),
+		RegistrySpec("trix",
+			IntRange("period", 9, 21, 6),
+		),
+		RegistrySpec("ultimate_oscillator",
+			IntRange("oversold", 20, 40, 10),
+			IntRange("overbought", 60, 80, 10),
+		),
+	}
+}
+
+// RegistrySpec searches the given parameters of a registered adaptor type, the others keep their defaults.
+// Candidates the registry rejects are skipped.
+func RegistrySpec(name string, parameters ...Parameter) AdaptorSpec {
+	return AdaptorSpec{
+		Name:       name,
+		Parameters: parameters,
+		Validate: func(p Params) error {
+			entry, ok := registry.Adaptor(name)
+			if !ok {
+				return fmt.Errorf("unknown adaptor type %q", name)
+			}
+			_, err := entry.Resolve(p.values())
+			return err
+		},
+		Build: func(ctx context.Context, p Params, m monitor.Monitoring) (indicator_adaptor.IndicatorAdaptor, error) {
+			return registry.NewAdaptor(ctx, name, p.values(), m)
+		},
+	}
+}
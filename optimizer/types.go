@@ -0,0 +1,180 @@
+// Package optimizer searches adaptor parameters by running candidate strategies through the backtesting engine.
+package optimizer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/backtesting"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+)
+
+// Parameter declares the values an adaptor parameter can take, either as a stepped range or as explicit choices.
+type Parameter struct {
+	Name    string
+	Min     float64
+	Max     float64
+	Step    float64
+	Integer bool
+	Choices []float64
+}
+
+// IntRange declares an integer parameter from min to max inclusive.
+func IntRange(name string, min, max, step int) Parameter {
+	return Parameter{Name: name, Min: float64(min), Max: float64(max), Step: float64(step), Integer: true}
+}
+
+// FloatRange declares a float parameter from min to max inclusive.
+func FloatRange(name string, min, max, step float64) Parameter {
+	return Parameter{Name: name, Min: min, Max: max, Step: step}
+}
+
+// Choice declares a parameter restricted to the given values.
+func Choice(name string, values ...float64) Parameter {
+	return Parameter{Name: name, Choices: values}
+}
+
+// Values expands the parameter into every value it can take.
+func (p Parameter) Values() []float64 {
+	if len(p.Choices) > 0 {
+		return append([]float64{}, p.Choices...)
+	}
+	if p.Step <= 0 || p.Max < p.Min {
+		return []float64{p.Min}
+	}
+
+	var values []float64
+	steps := int(math.Floor((p.Max-p.Min)/p.Step + 1e-9))
+	for i := 0; i <= steps; i++ {
+		value := p.Min + float64(i)*p.Step
+		if p.Integer {
+			value = math.Round(value)
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+// Params holds one value for every parameter of an adaptor.
+type Params map[string]float64
+
+// Int returns the named parameter rounded to an integer.
+func (p Params) Int(name string) int {
+	return int(math.Round(p[name]))
+}
+
+// Float returns the named parameter.
+func (p Params) Float(name string) float64 {
+	return p[name]
+}
+
+// Clone returns a copy of the parameters.
+func (p Params) Clone() Params {
+	clone := make(Params, len(p))
+	for k, v := range p {
+		clone[k] = v
+	}
+	return clone
+}
+
+// values returns the parameters in the form the registry accepts.
+func (p Params) values() map[string]interface{} {
+	values := make(map[string]interface{}, len(p))
+	for k, v := range p {
+		values[k] = v
+	}
+	return values
+}
+
+func (p Params) String() string {
+	names := make([]string, 0, len(p))
+	for name := range p {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s=%g", name, p[name])
+	}
+	return strings.Join(parts, ",")
+}
+
+// AdaptorSpec describes how to build an adaptor from a set of parameter values.
+type AdaptorSpec struct {
+	Name       string
+	Parameters []Parameter
+	// Validate optionally rejects parameter combinations the adaptor cannot use.
+	Validate func(p Params) error
+	Build    func(ctx context.Context, p Params, m monitor.Monitoring) (indicator_adaptor.IndicatorAdaptor, error)
+}
+
+func (spec AdaptorSpec) validate(p Params) error {
+	if spec.Validate == nil {
+		return nil
+	}
+	return spec.Validate(p)
+}
+
+// checkSpecNames rejects specs sharing a name, as results, genes and sensitivity rows tell the specs apart by name.
+func checkSpecNames(specs []AdaptorSpec) error {
+	seen := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		if seen[spec.Name] {
+			return fmt.Errorf("adaptor spec %q is given more than once, specs need unique names", spec.Name)
+		}
+		seen[spec.Name] = true
+	}
+	return nil
+}
+
+// Objective scores a performance summary, higher scores rank first.
+type Objective struct {
+	Name  string
+	Score func(summary backtesting.PerformanceSummary) float64
+}
+
+var (
+	TotalProfit = Objective{Name: "total_profit", Score: func(s backtesting.PerformanceSummary) float64 {
+		return s.TotalProfitPercentage
+	}}
+	AverageProfit = Objective{Name: "average_profit", Score: func(s backtesting.PerformanceSummary) float64 {
+		return s.AverageProfitPercentage
+	}}
+	WinRate = Objective{Name: "win_rate", Score: func(s backtesting.PerformanceSummary) float64 {
+		return s.WinRate
+	}}
+	SharpeRatio = Objective{Name: "sharpe_ratio", Score: func(s backtesting.PerformanceSummary) float64 {
+		return s.SharpeRatio
+	}}
+	// ProfitOverDrawdown rewards profit while penalising the largest drawdown.
+	ProfitOverDrawdown = Objective{Name: "profit_over_drawdown", Score: func(s backtesting.PerformanceSummary) float64 {
+		return s.TotalProfitPercentage / (1 + s.MaxDrawdownPercentage)
+	}}
+)
+
+// Objectives lists the predefined objectives.
+var Objectives = []Objective{TotalProfit, AverageProfit, WinRate, SharpeRatio, ProfitOverDrawdown}
+
+// ObjectiveByName returns the predefined objective with the given name.
+func ObjectiveByName(name string) (Objective, error) {
+	for _, objective := range Objectives {
+		if objective.Name == name {
+			return objective, nil
+		}
+	}
+	return Objective{}, fmt.Errorf("unknown objective %q", name)
+}
+
+// Result is the outcome of backtesting a single candidate.
+type Result struct {
+	// Params holds the parameter values of every adaptor, in the order of the specs.
+	Params    []Params
+	Algorithm string
+	Summary   backtesting.PerformanceSummary
+	Score     float64
+}
@@ -0,0 +1,64 @@
+package patterns
+
+import (
+	"math"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// candle describes the shape of a bar.
+type candle struct {
+	model.DataPoint
+}
+
+func (c candle) body() float64 {
+	return math.Abs(c.Close - c.Open)
+}
+
+func (c candle) candleRange() float64 {
+	return c.High - c.Low
+}
+
+func (c candle) bodyTop() float64 {
+	return math.Max(c.Open, c.Close)
+}
+
+func (c candle) bodyBottom() float64 {
+	return math.Min(c.Open, c.Close)
+}
+
+func (c candle) bodyMiddle() float64 {
+	return (c.Open + c.Close) / 2
+}
+
+func (c candle) upperShadow() float64 {
+	return c.High - c.bodyTop()
+}
+
+func (c candle) lowerShadow() float64 {
+	return c.bodyBottom() - c.Low
+}
+
+func (c candle) bullish() bool {
+	return c.Close > c.Open
+}
+
+func (c candle) bearish() bool {
+	return c.Close < c.Open
+}
+
+// bodyRatio returns the share of the range covered by the body, 0 for a bar without range.
+func (c candle) bodyRatio() float64 {
+	if c.candleRange() == 0 {
+		return 0
+	}
+	return c.body() / c.candleRange()
+}
+
+// shadowRatios return the shares of the range covered by the upper and lower shadows.
+func (c candle) shadowRatios() (upper, lower float64) {
+	if c.candleRange() == 0 {
+		return 0, 0
+	}
+	return c.upperShadow() / c.candleRange(), c.lowerShadow() / c.candleRange()
+}
@@ -0,0 +1,211 @@
+// Package patterns detects classic candlestick patterns in sequences of bars.
+package patterns
+
+import (
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// Bias tells which way a pattern points.
+type Bias string
+
+const (
+	Bullish Bias = "bullish"
+	Bearish Bias = "bearish"
+	Neutral Bias = "neutral"
+)
+
+// Pattern is the name of a candlestick pattern.
+type Pattern string
+
+const (
+	Doji               Pattern = "doji"
+	DragonflyDoji      Pattern = "dragonfly_doji"
+	GravestoneDoji     Pattern = "gravestone_doji"
+	LongLeggedDoji     Pattern = "long_legged_doji"
+	Hammer             Pattern = "hammer"
+	HangingMan         Pattern = "hanging_man"
+	BullishEngulfing   Pattern = "bullish_engulfing"
+	BearishEngulfing   Pattern = "bearish_engulfing"
+	BullishHarami      Pattern = "bullish_harami"
+	BearishHarami      Pattern = "bearish_harami"
+	PiercingLine       Pattern = "piercing_line"
+	DarkCloudCover     Pattern = "dark_cloud_cover"
+	MorningStar        Pattern = "morning_star"
+	EveningStar        Pattern = "evening_star"
+	ThreeWhiteSoldiers Pattern = "three_white_soldiers"
+	ThreeBlackCrows    Pattern = "three_black_crows"
+)
+
+// Match is a pattern completed on the latest bar.
+type Match struct {
+	Pattern Pattern
+	Bias    Bias
+	// Bars is the number of bars forming the pattern, the latest ones.
+	Bars int
+}
+
+// Thresholds are the proportions, of a bar's high to low range unless noted, telling the patterns apart.
+type Thresholds struct {
+	// DojiBody is the largest body of a doji.
+	DojiBody float64
+	// DojiShadow is the largest shadow on the short side of a dragonfly or gravestone doji.
+	DojiShadow float64
+	// LongLeggedShadow is the smallest shadow on both sides of a long-legged doji.
+	LongLeggedShadow float64
+	// HammerShadow is the smallest lower shadow of a hammer or hanging man, as a multiple of its body.
+	HammerShadow float64
+	// HammerUpperShadow is the largest upper shadow of a hammer or hanging man.
+	HammerUpperShadow float64
+	// LongBody is the smallest body of the long bars of harami, piercing, dark cloud, star and soldier patterns.
+	LongBody float64
+	// StarBody is the largest body of the middle bar of a morning or evening star.
+	StarBody float64
+	// TrendBars is the number of bars before a hammer or hanging man whose first and last closes tell
+	// the trend it ends, at least 2.
+	TrendBars int
+}
+
+// DefaultThresholds returns commonly used thresholds.
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		DojiBody:          0.1,
+		DojiShadow:        0.1,
+		LongLeggedShadow:  0.3,
+		HammerShadow:      2,
+		HammerUpperShadow: 0.1,
+		LongBody:          0.6,
+		StarBody:          0.3,
+		TrendBars:         3,
+	}
+}
+
+// MaxBars returns the number of latest bars Detect needs to recognise every pattern.
+func (t Thresholds) MaxBars() int {
+	return max(3, t.TrendBars+1)
+}
+
+// Detect returns the patterns completed on the last of the bars, in chronological order.
+func Detect(bars []model.DataPoint, thresholds Thresholds) []Match {
+	if len(bars) == 0 {
+		return nil
+	}
+	candles := make([]candle, len(bars))
+	for i, bar := range bars {
+		candles[i] = candle{bar}
+	}
+
+	var matches []Match
+	matches = append(matches, singleBar(candles, thresholds)...)
+	if len(candles) >= 2 {
+		matches = append(matches, twoBars(candles[len(candles)-2], candles[len(candles)-1], thresholds)...)
+	}
+	if len(candles) >= 3 {
+		matches = append(matches, threeBars(candles[len(candles)-3], candles[len(candles)-2], candles[len(candles)-1], thresholds)...)
+	}
+	return matches
+}
+
+// singleBar detects the doji variants, hammers and hanging men on the last bar.
+func singleBar(candles []candle, t Thresholds) []Match {
+	last := candles[len(candles)-1]
+	if last.candleRange() == 0 {
+		return nil
+	}
+	upper, lower := last.shadowRatios()
+
+	if last.bodyRatio() <= t.DojiBody {
+		switch {
+		case upper <= t.DojiShadow:
+			return []Match{{DragonflyDoji, Bullish, 1}}
+		case lower <= t.DojiShadow:
+			return []Match{{GravestoneDoji, Bearish, 1}}
+		case upper >= t.LongLeggedShadow && lower >= t.LongLeggedShadow:
+			return []Match{{LongLeggedDoji, Neutral, 1}}
+		default:
+			return []Match{{Doji, Neutral, 1}}
+		}
+	}
+
+	if last.lowerShadow() >= t.HammerShadow*last.body() && upper <= t.HammerUpperShadow {
+		switch trend(candles, t.TrendBars) {
+		case Bearish:
+			return []Match{{Hammer, Bullish, 1}}
+		case Bullish:
+			return []Match{{HangingMan, Bearish, 1}}
+		}
+	}
+	return nil
+}
+
+// trend compares the first and last closes of the trendBars bars before the last one, Neutral without enough bars.
+func trend(candles []candle, trendBars int) Bias {
+	last, first := len(candles)-2, len(candles)-1-trendBars
+	if trendBars < 2 || first < 0 {
+		return Neutral
+	}
+	switch change := candles[last].Close - candles[first].Close; {
+	case change > 0:
+		return Bullish
+	case change < 0:
+		return Bearish
+	}
+	return Neutral
+}
+
+// twoBars detects engulfing, harami, piercing line and dark cloud cover patterns.
+func twoBars(first, second candle, t Thresholds) []Match {
+	var matches []Match
+	switch {
+	case first.bearish() && second.bullish() && second.Open <= first.Close && second.Close >= first.Open && second.body() > first.body():
+		matches = append(matches, Match{BullishEngulfing, Bullish, 2})
+	case first.bullish() && second.bearish() && second.Open >= first.Close && second.Close <= first.Open && second.body() > first.body():
+		matches = append(matches, Match{BearishEngulfing, Bearish, 2})
+	}
+
+	if first.bodyRatio() < t.LongBody {
+		return matches
+	}
+	inside := second.bodyTop() <= first.bodyTop() && second.bodyBottom() >= first.bodyBottom() && second.body() < first.body()
+	switch {
+	case first.bearish() && second.bullish() && inside:
+		matches = append(matches, Match{BullishHarami, Bullish, 2})
+	case first.bullish() && second.bearish() && inside:
+		matches = append(matches, Match{BearishHarami, Bearish, 2})
+	case first.bearish() && second.bullish() && second.Open < first.Close && second.Close > first.bodyMiddle() && second.Close < first.Open:
+		matches = append(matches, Match{PiercingLine, Bullish, 2})
+	case first.bullish() && second.bearish() && second.Open > first.Close && second.Close < first.bodyMiddle() && second.Close > first.Open:
+		matches = append(matches, Match{DarkCloudCover, Bearish, 2})
+	}
+	return matches
+}
+
+// threeBars detects morning and evening stars, three white soldiers and three black crows.
+func threeBars(first, second, third candle, t Thresholds) []Match {
+	long := func(c candle) bool { return c.bodyRatio() >= t.LongBody }
+
+	var matches []Match
+	if long(first) && second.bodyRatio() <= t.StarBody && long(third) {
+		switch {
+		case first.bearish() && third.bullish() && second.bodyTop() <= first.Close && third.Close > first.bodyMiddle():
+			matches = append(matches, Match{MorningStar, Bullish, 3})
+		case first.bullish() && third.bearish() && second.bodyBottom() >= first.Close && third.Close < first.bodyMiddle():
+			matches = append(matches, Match{EveningStar, Bearish, 3})
+		}
+	}
+
+	if !long(first) || !long(second) || !long(third) {
+		return matches
+	}
+	opensInside := func(previous, next candle) bool {
+		return next.Open >= previous.bodyBottom() && next.Open <= previous.bodyTop()
+	}
+	switch {
+	case first.bullish() && second.bullish() && third.bullish() &&
+		second.Close > first.Close && third.Close > second.Close && opensInside(first, second) && opensInside(second, third):
+		matches = append(matches, Match{ThreeWhiteSoldiers, Bullish, 3})
+	case first.bearish() && second.bearish() && third.bearish() &&
+		second.Close < first.Close && third.Close < second.Close && opensInside(first, second) && opensInside(second, third):
+		matches = append(matches, Match{ThreeBlackCrows, Bearish, 3})
+	}
+	return matches
+}
@@ -0,0 +1,87 @@
+package patterns_test
+
+import (
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/patterns"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+// bar builds a bar from its open, high, low and close.
+func bar(open, high, low, close float64) model.DataPoint {
+	return model.DataPoint{Open: open, High: high, Low: low, Close: close}
+}
+
+// trendBars returns three bars closing from start by step each bar, ahead of the pattern bars.
+func trendBars(start, step float64, pattern ...model.DataPoint) []model.DataPoint {
+	var data []model.DataPoint
+	for i := 0; i < 3; i++ {
+		price := start + float64(i)*step
+		data = append(data, bar(price, price+0.5, price-0.5, price))
+	}
+	data = append(data, pattern...)
+	for i := range data {
+		data[i].Time = int64(i + 1)
+	}
+	return data
+}
+
+func contains(matches []patterns.Match, want patterns.Match) bool {
+	for _, match := range matches {
+		if match == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		bars []model.DataPoint
+		want patterns.Match
+	}{
+		{"doji", trendBars(100, 0, bar(100, 100.3, 99, 100.05)), patterns.Match{Pattern: patterns.Doji, Bias: patterns.Neutral, Bars: 1}},
+		{"long-legged doji", trendBars(100, 0, bar(100, 102, 98, 100.1)), patterns.Match{Pattern: patterns.LongLeggedDoji, Bias: patterns.Neutral, Bars: 1}},
+		{"dragonfly doji", trendBars(100, 0, bar(100, 100.1, 97, 100)), patterns.Match{Pattern: patterns.DragonflyDoji, Bias: patterns.Bullish, Bars: 1}},
+		{"gravestone doji", trendBars(100, 0, bar(100, 103, 99.95, 100)), patterns.Match{Pattern: patterns.GravestoneDoji, Bias: patterns.Bearish, Bars: 1}},
+		{"hammer after a fall", trendBars(106, -2, bar(99, 100.1, 96, 100)), patterns.Match{Pattern: patterns.Hammer, Bias: patterns.Bullish, Bars: 1}},
+		{"hanging man after a rise", trendBars(94, 2, bar(99, 100.1, 96, 100)), patterns.Match{Pattern: patterns.HangingMan, Bias: patterns.Bearish, Bars: 1}},
+		{"bullish engulfing", trendBars(104, -1, bar(101, 101.5, 99.5, 100), bar(99.8, 102.5, 99.5, 102)), patterns.Match{Pattern: patterns.BullishEngulfing, Bias: patterns.Bullish, Bars: 2}},
+		{"bearish engulfing", trendBars(96, 1, bar(99, 100.5, 98.5, 100), bar(100.2, 100.5, 97.5, 98)), patterns.Match{Pattern: patterns.BearishEngulfing, Bias: patterns.Bearish, Bars: 2}},
+		{"bullish harami", trendBars(106, -1, bar(104, 104.2, 99.8, 100), bar(101, 102.5, 100.8, 102)), patterns.Match{Pattern: patterns.BullishHarami, Bias: patterns.Bullish, Bars: 2}},
+		{"bearish harami", trendBars(94, 1, bar(96, 100.2, 95.8, 100), bar(99, 99.2, 97.5, 98)), patterns.Match{Pattern: patterns.BearishHarami, Bias: patterns.Bearish, Bars: 2}},
+		{"piercing line", trendBars(106, -1, bar(104, 104.2, 99.8, 100), bar(99, 103, 98.8, 102.5)), patterns.Match{Pattern: patterns.PiercingLine, Bias: patterns.Bullish, Bars: 2}},
+		{"dark cloud cover", trendBars(94, 1, bar(96, 100.2, 95.8, 100), bar(101, 101.2, 97, 97.5)), patterns.Match{Pattern: patterns.DarkCloudCover, Bias: patterns.Bearish, Bars: 2}},
+		{"morning star", trendBars(108, -1, bar(105, 105.2, 100.8, 101), bar(100, 100.8, 99, 99.8), bar(100.5, 104.2, 100.3, 104)), patterns.Match{Pattern: patterns.MorningStar, Bias: patterns.Bullish, Bars: 3}},
+		{"evening star", trendBars(92, 1, bar(95, 99.2, 94.8, 99), bar(100, 101, 99.2, 100.2), bar(99.5, 99.7, 95.8, 96)), patterns.Match{Pattern: patterns.EveningStar, Bias: patterns.Bearish, Bars: 3}},
+		{"three white soldiers", trendBars(100, 0, bar(100, 102.1, 99.9, 102), bar(101.5, 104.1, 101.4, 104), bar(103.5, 106.1, 103.4, 106)), patterns.Match{Pattern: patterns.ThreeWhiteSoldiers, Bias: patterns.Bullish, Bars: 3}},
+		{"three black crows", trendBars(100, 0, bar(100, 100.1, 97.9, 98), bar(98.5, 98.6, 95.9, 96), bar(96.5, 96.6, 93.9, 94)), patterns.Match{Pattern: patterns.ThreeBlackCrows, Bias: patterns.Bearish, Bars: 3}},
+	}
+
+	for _, tt := range tests {
+		matches := patterns.Detect(tt.bars, patterns.DefaultThresholds())
+		test_utils.AssertTrue(t, contains(matches, tt.want), tt.name+": pattern not detected")
+		for _, match := range matches {
+			test_utils.AssertTrue(t, match.Bias == tt.want.Bias || match.Bias == patterns.Neutral, tt.name+": conflicting pattern "+string(match.Pattern))
+		}
+	}
+}
+
+func TestDetectRequiresTrendForHammer(t *testing.T) {
+	flat := trendBars(100, 0, bar(99, 100.1, 96, 100))
+	test_utils.AssertEqual(t, 0, len(patterns.Detect(flat, patterns.DefaultThresholds())), "hammer shape without a trend should not match")
+	test_utils.AssertEqual(t, 0, len(patterns.Detect(flat[2:], patterns.DefaultThresholds())), "hammer shape without enough bars should not match")
+}
+
+func TestDetectThresholds(t *testing.T) {
+	// A body of 18% of the range is not a doji by default.
+	data := trendBars(100, 0, bar(100, 101.2, 99, 100.4))
+	test_utils.AssertEqual(t, 0, len(patterns.Detect(data, patterns.DefaultThresholds())), "wide body should not be a doji")
+
+	thresholds := patterns.DefaultThresholds()
+	thresholds.DojiBody = 0.25
+	matches := patterns.Detect(data, thresholds)
+	test_utils.AssertTrue(t, contains(matches, patterns.Match{Pattern: patterns.LongLeggedDoji, Bias: patterns.Neutral, Bars: 1}), "wider doji threshold should match")
+}
@@ -0,0 +1,302 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"time"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/logger"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"go.uber.org/zap"
+)
+
+// Config describes how to run a plugin.
+type Config struct {
+	Command string
+	Args    []string
+	Dir     string
+	// Env is added to the environment of the host.
+	Env []string
+	// HandshakeTimeout bounds the wait for the "ready" message, Timeout the wait for every "signals" message.
+	HandshakeTimeout time.Duration
+	Timeout          time.Duration
+	// MaxRestarts is the number of times a crashed or unresponsive plugin is started again before giving up.
+	MaxRestarts int
+	// BatchSize is the maximum number of bars sent in one "data" message.
+	BatchSize int
+	// ReplayBars is the number of latest bars replayed to a restarted plugin to rebuild its state.
+	ReplayBars int
+}
+
+// DefaultConfig returns the configuration to run command with.
+func DefaultConfig(command string, args ...string) Config {
+	return Config{
+		Command:          command,
+		Args:             args,
+		HandshakeTimeout: 10 * time.Second,
+		Timeout:          5 * time.Second,
+		MaxRestarts:      3,
+		BatchSize:        500,
+		ReplayBars:       200,
+	}
+}
+
+// Client runs a plugin process and exchanges messages with it. It is not safe for concurrent use.
+type Client struct {
+	config   Config
+	name     string
+	cmd      *exec.Cmd
+	stdin    io.WriteCloser
+	lines    chan []byte
+	exited   chan struct{}
+	done     chan struct{}
+	history  []model.DataPoint
+	restarts int
+	logger   logger.LoggerInterface
+}
+
+// Start starts the plugin and completes the handshake.
+func Start(ctx context.Context, config Config) (*Client, error) {
+	if config.Command == "" {
+		return nil, errors.New("plugin command is required")
+	}
+	if config.BatchSize < 1 {
+		config.BatchSize = 1
+	}
+	c := &Client{config: config, logger: logger.GetLogger()}
+	if err := c.start(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Name returns the name the plugin announced in the handshake.
+func (c *Client) Name() string {
+	return c.name
+}
+
+// Restarts returns the number of times the plugin was restarted.
+func (c *Client) Restarts() int {
+	return c.restarts
+}
+
+func (c *Client) start(ctx context.Context) error {
+	cmd := exec.Command(c.config.Command, c.config.Args...)
+	cmd.Dir = c.config.Dir
+	if len(c.config.Env) > 0 {
+		cmd.Env = append(cmd.Environ(), c.config.Env...)
+	}
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("error opening plugin stdin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("error opening plugin stdout: %w", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return fmt.Errorf("error opening plugin stderr: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("error starting plugin %s: %w", c.config.Command, err)
+	}
+
+	c.cmd, c.stdin = cmd, stdin
+	c.lines = make(chan []byte)
+	c.exited = make(chan struct{})
+	c.done = make(chan struct{})
+	go c.readLines(stdout, c.lines, c.exited, c.done)
+	go c.logStderr(ctx, stderr)
+
+	reply, err := c.exchange(ctx, Message{Type: HelloMessage, Protocol: ProtocolVersion}, c.config.HandshakeTimeout)
+	if err == nil && reply.Type != ReadyMessage {
+		err = fmt.Errorf("expected %q message, got %q", ReadyMessage, reply.Type)
+	}
+	if err == nil && reply.Protocol != ProtocolVersion {
+		err = fmt.Errorf("plugin speaks protocol %d, expected %d", reply.Protocol, ProtocolVersion)
+	}
+	if err != nil {
+		c.kill()
+		return fmt.Errorf("plugin handshake failed: %w", err)
+	}
+	c.name = reply.Name
+	if c.name == "" {
+		c.name = c.config.Command
+	}
+	c.logger.Info(ctx, "Plugin started", zap.String("plugin", c.name), zap.Int("pid", cmd.Process.Pid))
+	return nil
+}
+
+// readLines forwards the lines of stdout until the plugin exits or is killed.
+func (c *Client) readLines(stdout io.Reader, lines chan<- []byte, exited, done chan struct{}) {
+	defer close(exited)
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		select {
+		case lines <- append([]byte{}, scanner.Bytes()...):
+		case <-done:
+			return
+		}
+	}
+}
+
+func (c *Client) logStderr(ctx context.Context, stderr io.Reader) {
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		c.logger.Info(ctx, "Plugin output", zap.String("plugin", c.config.Command), zap.String("line", scanner.Text()))
+	}
+}
+
+// exchange writes a message and waits for the reply line.
+func (c *Client) exchange(ctx context.Context, request Message, timeout time.Duration) (Message, error) {
+	var reply Message
+	line, err := json.Marshal(request)
+	if err != nil {
+		return reply, fmt.Errorf("error encoding %s message: %w", request.Type, err)
+	}
+	if _, err := c.stdin.Write(append(line, '\n')); err != nil {
+		return reply, fmt.Errorf("error writing to plugin: %w", err)
+	}
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case line := <-c.lines:
+		if err := json.Unmarshal(line, &reply); err != nil {
+			return reply, fmt.Errorf("error decoding plugin reply %q: %w", line, err)
+		}
+		if reply.Type == ErrorMessage {
+			return reply, fmt.Errorf("plugin error: %s", reply.Error)
+		}
+		return reply, nil
+	case <-c.exited:
+		return reply, errors.New("plugin exited")
+	case <-expired:
+		return reply, fmt.Errorf("plugin did not reply within %s", timeout)
+	case <-ctx.Done():
+		return reply, ctx.Err()
+	}
+}
+
+// Send streams the bars in batches and returns one signal per bar. A plugin failing a batch is restarted,
+// given the latest bars again as replay and asked for the batch once more, up to MaxRestarts times.
+func (c *Client) Send(ctx context.Context, points []model.DataPoint) ([]model.TradingSignal, error) {
+	signals := make([]model.TradingSignal, 0, len(points))
+	for start := 0; start < len(points); start += c.config.BatchSize {
+		batch := points[start:min(start+c.config.BatchSize, len(points))]
+		batchSignals, err := c.sendBatch(ctx, batch, false)
+		for err != nil {
+			if ctx.Err() != nil || c.restarts >= c.config.MaxRestarts {
+				return signals, err
+			}
+			c.logger.Warn(ctx, "Restarting plugin", zap.String("plugin", c.name), zap.Error(err))
+			err = c.restart(ctx)
+			if err == nil {
+				batchSignals, err = c.sendBatch(ctx, batch, false)
+			}
+		}
+		signals = append(signals, batchSignals...)
+		c.remember(batch)
+	}
+	return signals, nil
+}
+
+// Replay feeds the plugin bars it has not seen as replay, warming it up without asking for signals,
+// and keeps them for replaying after a restart.
+func (c *Client) Replay(ctx context.Context, points []model.DataPoint) error {
+	for start := 0; start < len(points); start += c.config.BatchSize {
+		batch := points[start:min(start+c.config.BatchSize, len(points))]
+		if _, err := c.sendBatch(ctx, batch, true); err != nil {
+			return fmt.Errorf("error replaying history: %w", err)
+		}
+		c.remember(batch)
+	}
+	return nil
+}
+
+// History returns the latest bars the plugin was sent, the ones replayed after a restart.
+func (c *Client) History() []model.DataPoint {
+	return append([]model.DataPoint{}, c.history...)
+}
+
+func (c *Client) sendBatch(ctx context.Context, batch []model.DataPoint, replay bool) ([]model.TradingSignal, error) {
+	reply, err := c.exchange(ctx, Message{Type: DataMessage, Replay: replay, Points: batch}, c.config.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	if reply.Type != SignalsMessage {
+		return nil, fmt.Errorf("expected %q message, got %q", SignalsMessage, reply.Type)
+	}
+	if len(reply.Signals) != len(batch) {
+		return nil, fmt.Errorf("plugin returned %d signals for %d bars", len(reply.Signals), len(batch))
+	}
+	signals := make([]model.TradingSignal, len(batch))
+	for i, s := range reply.Signals {
+		if signals[i], err = s.toTradingSignal(c.name, batch[i]); err != nil {
+			return nil, err
+		}
+	}
+	return signals, nil
+}
+
+func (c *Client) remember(batch []model.DataPoint) {
+	if c.config.ReplayBars <= 0 {
+		return
+	}
+	c.history = append(c.history, batch...)
+	if len(c.history) > c.config.ReplayBars {
+		c.history = append([]model.DataPoint{}, c.history[len(c.history)-c.config.ReplayBars:]...)
+	}
+}
+
+func (c *Client) restart(ctx context.Context) error {
+	c.restarts++
+	c.kill()
+	if err := c.start(ctx); err != nil {
+		return err
+	}
+	for start := 0; start < len(c.history); start += c.config.BatchSize {
+		if _, err := c.sendBatch(ctx, c.history[start:min(start+c.config.BatchSize, len(c.history))], true); err != nil {
+			return fmt.Errorf("error replaying history: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c *Client) kill() {
+	if c.cmd == nil {
+		return
+	}
+	close(c.done)
+	c.stdin.Close()
+	c.cmd.Process.Kill()
+	c.cmd.Wait()
+	c.cmd = nil
+}
+
+// Close asks the plugin to shut down and kills it if it has not exited within the timeout.
+func (c *Client) Close() error {
+	if c.cmd == nil {
+		return nil
+	}
+	line, _ := json.Marshal(Message{Type: ShutdownMessage})
+	c.stdin.Write(append(line, '\n'))
+	c.stdin.Close()
+	select {
+	case <-c.exited:
+	case <-time.After(c.config.Timeout):
+	}
+	c.kill()
+	return nil
+}
@@ -0,0 +1,248 @@
+package plugin_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/config"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/plugin"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+// TestMain lets the test binary act as a plugin when FAKE_PLUGIN is set.
+func TestMain(m *testing.M) {
+	if mode := os.Getenv("FAKE_PLUGIN"); mode != "" {
+		runFakePlugin(mode)
+		os.Exit(0)
+	}
+	config.InitConfig()
+	os.Exit(m.Run())
+}
+
+// runFakePlugin buys on rising closes and sells on falling ones. The "crash" and "hang" modes
+// misbehave once on the bar at FAKE_PLUGIN_AT, using FAKE_PLUGIN_MARKER to remember they did.
+// Every start appends a line to FAKE_PLUGIN_STARTS when it is set.
+func runFakePlugin(mode string) {
+	if starts := os.Getenv("FAKE_PLUGIN_STARTS"); starts != "" {
+		file, _ := os.OpenFile(starts, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		file.WriteString("started\n")
+		file.Close()
+	}
+	var failAt int64
+	fmt.Sscan(os.Getenv("FAKE_PLUGIN_AT"), &failAt)
+	marker := os.Getenv("FAKE_PLUGIN_MARKER")
+	failOnce := func() bool {
+		if _, err := os.Stat(marker); err == nil {
+			return false
+		}
+		os.WriteFile(marker, nil, 0644)
+		return true
+	}
+
+	writer := json.NewEncoder(os.Stdout)
+	scanner := bufio.NewScanner(os.Stdin)
+	lastClose := 0.0
+	for scanner.Scan() {
+		var msg plugin.Message
+		json.Unmarshal(scanner.Bytes(), &msg)
+		switch msg.Type {
+		case plugin.HelloMessage:
+			if mode == "old" {
+				writer.Encode(plugin.Message{Type: plugin.ReadyMessage, Protocol: 0})
+				continue
+			}
+			writer.Encode(plugin.Message{Type: plugin.ReadyMessage, Protocol: plugin.ProtocolVersion, Name: "Momentum"})
+		case plugin.DataMessage:
+			reply := plugin.Message{Type: plugin.SignalsMessage}
+			for _, dp := range msg.Points {
+				if dp.Time == failAt && !msg.Replay && failOnce() {
+					if mode == "crash" {
+						os.Exit(1)
+					}
+					time.Sleep(time.Second)
+				}
+				action := "wait"
+				if lastClose != 0 && dp.Close > lastClose {
+					action = "buy"
+				} else if lastClose != 0 && dp.Close < lastClose {
+					action = "sell"
+				}
+				lastClose = dp.Close
+				reply.Signals = append(reply.Signals, plugin.Signal{Time: dp.Time, Action: action, Strength: 0.5})
+			}
+			writer.Encode(reply)
+		case plugin.ShutdownMessage:
+			return
+		}
+	}
+}
+
+func fakeConfig(t *testing.T, mode string, failAt int) plugin.Config {
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	config := plugin.DefaultConfig(executable)
+	config.Env = []string{
+		"FAKE_PLUGIN=" + mode,
+		fmt.Sprintf("FAKE_PLUGIN_AT=%d", failAt),
+		"FAKE_PLUGIN_MARKER=" + filepath.Join(t.TempDir(), "failed"),
+	}
+	config.Timeout = 200 * time.Millisecond
+	config.BatchSize = 4
+	config.ReplayBars = 3
+	return config
+}
+
+// zigzag closes rise on odd bars and fall on even ones.
+func zigzag(bars int) []model.DataPoint {
+	data := make([]model.DataPoint, bars)
+	for i := range data {
+		data[i] = model.DataPoint{Time: int64(i + 1), Close: 100 + float64(i%2)}
+	}
+	return data
+}
+
+func actions(signals []model.TradingSignal) string {
+	script := ""
+	for _, signal := range signals {
+		script += string(signal.Action)[:1]
+	}
+	return script
+}
+
+func TestClientSend(t *testing.T) {
+	client, err := plugin.Start(context.Background(), fakeConfig(t, "momentum", 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer client.Close()
+	test_utils.AssertEqual(t, "Momentum", client.Name(), "Plugin name does not match")
+
+	// 10 bars go out in batches of 4, 4 and 2.
+	signals, err := client.Send(context.Background(), zigzag(10))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	test_utils.AssertEqual(t, "wbsbsbsbsb", actions(signals), "Signals do not match")
+	test_utils.AssertEqual(t, int64(10), signals[9].Time, "Signal time does not match")
+	test_utils.AssertEqual(t, 0.5, signals[9].Strength, "Signal strength does not match")
+}
+
+func TestClientRestart(t *testing.T) {
+	for _, mode := range []string{"crash", "hang"} {
+		t.Run(mode, func(t *testing.T) {
+			client, err := plugin.Start(context.Background(), fakeConfig(t, mode, 6))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer client.Close()
+
+			signals, err := client.Send(context.Background(), zigzag(10))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			test_utils.AssertEqual(t, 1, client.Restarts(), "Number of restarts does not match")
+			// The replayed bars restore the last close, so bar 5 is still compared with bar 4.
+			test_utils.AssertEqual(t, "wbsbsbsbsb", actions(signals), "Signals after restart do not match")
+		})
+	}
+}
+
+func TestClientGivesUp(t *testing.T) {
+	config := fakeConfig(t, "crash", 2)
+	config.MaxRestarts = 0
+	client, err := plugin.Start(context.Background(), config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.Send(context.Background(), zigzag(4)); err == nil {
+		t.Fatalf("expected error from a crashed plugin")
+	}
+}
+
+func TestClientHandshake(t *testing.T) {
+	if _, err := plugin.Start(context.Background(), fakeConfig(t, "old", 0)); err == nil {
+		t.Fatalf("expected protocol mismatch error")
+	}
+	if _, err := plugin.Start(context.Background(), plugin.DefaultConfig(filepath.Join(t.TempDir(), "missing"))); err == nil {
+		t.Fatalf("expected error for a missing executable")
+	}
+}
+
+func TestClientReplay(t *testing.T) {
+	config := fakeConfig(t, "momentum", 0)
+	first, err := plugin.Start(context.Background(), config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer first.Close()
+	data := zigzag(6)
+	if _, err := first.Send(context.Background(), data[:5]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	history := first.History()
+	test_utils.AssertEqual(t, 3, len(history), "History should keep ReplayBars bars")
+
+	// A fresh plugin warmed with the history continues where the first one stopped.
+	second, err := plugin.Start(context.Background(), config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer second.Close()
+	if err := second.Replay(context.Background(), history); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	signals, err := second.Send(context.Background(), data[5:])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	test_utils.AssertEqual(t, "b", actions(signals), "The replayed plugin should know the previous close")
+}
+
+func TestPluginAdapterClonesStartOnUse(t *testing.T) {
+	config := fakeConfig(t, "momentum", 0)
+	startsFile := filepath.Join(t.TempDir(), "starts")
+	config.Env = append(config.Env, "FAKE_PLUGIN_STARTS="+startsFile)
+	starts := func() int {
+		content, _ := os.ReadFile(startsFile)
+		return strings.Count(string(content), "\n")
+	}
+
+	ctx := context.Background()
+	adapter, err := indicator_adaptor.NewPluginAdapter(ctx, config, test_utils.NewMockMetricsCollector(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer adapter.Close()
+	clones := []indicator_adaptor.IndicatorAdaptor{adapter.Clone(ctx), adapter.Clone(ctx), adapter.Clone(ctx)}
+	test_utils.AssertEqual(t, 1, starts(), "Clones should not start the plugin before they are fed")
+
+	data := zigzag(2)
+	for _, dp := range data {
+		if err := clones[0].AddDataPoint(ctx, dp); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	test_utils.AssertEqual(t, 2, starts(), "Feeding a clone should start one process")
+	test_utils.AssertEqual(t, model.StockAction(model.Buy), clones[0].GetSignal(ctx).Action, "Clone signal does not match")
+
+	for _, clone := range clones {
+		clone.(*indicator_adaptor.PluginAdapter).Close()
+	}
+	if err := clones[0].AddDataPoint(ctx, model.DataPoint{Time: 3, Close: 100}); err == nil {
+		t.Fatalf("expected a closed adapter not to start the plugin again")
+	}
+	test_utils.AssertEqual(t, 2, starts(), "A closed adapter should not start the plugin again")
+}
@@ -0,0 +1,49 @@
+#!/usr/bin/env python3
+"""Example plugin: buys when the close rises above its 10 bar average and sells when it falls below.
+
+Run it from Go with plugin.DefaultConfig("python3", "plugin/examples/momentum.py").
+"""
+import json
+import sys
+from collections import deque
+
+PERIOD = 10
+
+
+def send(message):
+    sys.stdout.write(json.dumps(message) + "\n")
+    sys.stdout.flush()
+
+
+def main():
+    closes = deque(maxlen=PERIOD)
+    above = None
+    for line in sys.stdin:
+        message = json.loads(line)
+        if message["type"] == "hello":
+            send({"type": "ready", "protocol": 1, "name": "PyMomentum_%d" % PERIOD})
+        elif message["type"] == "data":
+            signals = []
+            for point in message["points"]:
+                closes.append(point["c"])
+                signal = {"t": point["t"], "action": "wait"}
+                if len(closes) == PERIOD:
+                    average = sum(closes) / PERIOD
+                    now_above = point["c"] > average
+                    if above is not None and now_above != above:
+                        signal["action"] = "buy" if now_above else "sell"
+                        signal["strength"] = min(1.0, 0.5 + 50 * abs(point["c"] - average) / point["c"])
+                        signal["rationale"] = [{
+                            "indicator": "SMA_%d" % PERIOD,
+                            "event": "crossed up through" if now_above else "crossed down through",
+                            "values": [average, point["c"]],
+                        }]
+                    above = now_above
+                signals.append(signal)
+            send({"type": "signals", "signals": signals})
+        elif message["type"] == "shutdown":
+            return
+
+
+if __name__ == "__main__":
+    main()
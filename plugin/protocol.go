@@ -0,0 +1,88 @@
+// Package plugin runs strategies as external executables talking JSON lines over stdin and stdout.
+//
+// Every line is one Message. The host opens with a "hello" carrying the protocol version, which the
+// plugin answers with "ready" and its name. The host then sends "data" messages with a batch of bars,
+// oldest first, and the plugin answers each with one "signals" message holding exactly one signal per
+// bar, in the same order. Bars flagged as replay are resent after a restart to warm the plugin up and
+// their signals are discarded. The host ends with "shutdown". A plugin may answer any request with an
+// "error" message instead. Anything written to stderr is logged.
+package plugin
+
+import (
+	"fmt"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+const (
+	ProtocolVersion = 1
+
+	HelloMessage    = "hello"
+	ReadyMessage    = "ready"
+	DataMessage     = "data"
+	SignalsMessage  = "signals"
+	ShutdownMessage = "shutdown"
+	ErrorMessage    = "error"
+)
+
+// Message is one line of the protocol, only the fields of its type are set.
+type Message struct {
+	Type     string            `json:"type"`
+	Protocol int               `json:"protocol,omitempty"`
+	Name     string            `json:"name,omitempty"`
+	Replay   bool              `json:"replay,omitempty"`
+	Points   []model.DataPoint `json:"points,omitempty"`
+	Signals  []Signal          `json:"signals,omitempty"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// Signal is the wire format of a model.TradingSignal.
+type Signal struct {
+	Time      int64       `json:"t"`
+	Action    string      `json:"action"`
+	Strength  float64     `json:"strength,omitempty"`
+	StopLoss  float64     `json:"stop_loss,omitempty"`
+	Target    float64     `json:"target,omitempty"`
+	Rationale []Rationale `json:"rationale,omitempty"`
+}
+
+// Rationale is the wire format of a model.Rationale.
+type Rationale struct {
+	Indicator string    `json:"indicator"`
+	Event     string    `json:"event"`
+	Reference string    `json:"reference,omitempty"`
+	Values    []float64 `json:"values,omitempty"`
+}
+
+// toTradingSignal validates the signal against the bar it was requested for.
+func (s Signal) toTradingSignal(source string, data model.DataPoint) (model.TradingSignal, error) {
+	if s.Time != data.Time {
+		return model.TradingSignal{}, fmt.Errorf("signal for time %d answers bar %d", s.Time, data.Time)
+	}
+	signal := model.TradingSignal{Time: s.Time, StopLoss: s.StopLoss, Target: s.Target}
+	switch model.StockAction(s.Action) {
+	case model.Buy, model.Sell:
+		signal.Action = model.StockAction(s.Action)
+		signal.Strength = s.Strength
+		if signal.Strength == 0 {
+			signal.Strength = 1
+		}
+	case model.Wait, "":
+		return model.TradingSignal{Time: s.Time, Action: model.Wait}, nil
+	default:
+		return model.TradingSignal{}, fmt.Errorf("unknown action %q", s.Action)
+	}
+	if signal.Strength < 0 || signal.Strength > 1 {
+		return model.TradingSignal{}, fmt.Errorf("strength %v is not between 0 and 1", signal.Strength)
+	}
+	for _, r := range s.Rationale {
+		signal.Rationale = append(signal.Rationale, model.Rationale{
+			Source:    source,
+			Indicator: r.Indicator,
+			Event:     r.Event,
+			Reference: r.Reference,
+			Values:    r.Values,
+		})
+	}
+	return signal, nil
+}
@@ -0,0 +1,224 @@
+package regime
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+const (
+	// minVariance keeps the emission densities finite when a state only explains identical observations.
+	minVariance = 1e-12
+	// fitTolerance stops Baum-Welch once the log likelihood improves by less than this.
+	fitTolerance = 1e-6
+)
+
+// GaussianHMM is a hidden Markov model with one-dimensional Gaussian emissions.
+type GaussianHMM struct {
+	Initial    []float64
+	Transition [][]float64
+	Means      []float64
+	Variances  []float64
+}
+
+// NewGaussianHMM initializes a model with the given number of hidden states, it has to be fitted before use.
+func NewGaussianHMM(states int) *GaussianHMM {
+	return &GaussianHMM{
+		Initial:    make([]float64, states),
+		Transition: make([][]float64, states),
+		Means:      make([]float64, states),
+		Variances:  make([]float64, states),
+	}
+}
+
+// States returns the number of hidden states.
+func (h *GaussianHMM) States() int {
+	return len(h.Means)
+}
+
+// Fit estimates the model parameters from the observations with the Baum-Welch algorithm and returns the final log likelihood.
+// The states start ordered by the spread of the observations they explain, so fitting the same data always gives the same model.
+func (h *GaussianHMM) Fit(observations []float64, iterations int) (float64, error) {
+	states := h.States()
+	if states == 0 {
+		return 0, errors.New("the model needs at least one state")
+	}
+	if len(observations) < 2*states {
+		return 0, errors.New("not enough observations to fit the model")
+	}
+	h.initialize(observations)
+
+	logLikelihood := math.Inf(-1)
+	for i := 0; i < iterations; i++ {
+		next, err := h.step(observations)
+		if err != nil {
+			return logLikelihood, err
+		}
+		converged := next-logLikelihood < fitTolerance
+		logLikelihood = next
+		if converged {
+			break
+		}
+	}
+	return logLikelihood, nil
+}
+
+// Filter advances the state probabilities by one observation. A nil prior starts from the initial distribution.
+func (h *GaussianHMM) Filter(prior []float64, observation float64) []float64 {
+	states := h.States()
+	predicted := make([]float64, states)
+	posterior := make([]float64, states)
+	predictedTotal, total := 0.0, 0.0
+	for j := 0; j < states; j++ {
+		predicted[j] = h.Initial[j]
+		if prior != nil {
+			predicted[j] = 0
+			for i := 0; i < states; i++ {
+				predicted[j] += prior[i] * h.Transition[i][j]
+			}
+		}
+		predictedTotal += predicted[j]
+		posterior[j] = predicted[j] * h.density(j, observation)
+		total += posterior[j]
+	}
+	if total == 0 {
+		// The observation is far outside every state, keep the prediction instead of dividing by zero.
+		posterior, total = predicted, predictedTotal
+	}
+	for j := range posterior {
+		posterior[j] /= total
+	}
+	return posterior
+}
+
+func (h *GaussianHMM) density(state int, x float64) float64 {
+	variance := math.Max(h.Variances[state], minVariance)
+	d := x - h.Means[state]
+	return math.Exp(-d*d/(2*variance)) / math.Sqrt(2*math.Pi*variance)
+}
+
+// initialize splits the observations by distance from their mean into equally sized groups, one per state.
+func (h *GaussianHMM) initialize(observations []float64) {
+	states := h.States()
+	mean := 0.0
+	for _, x := range observations {
+		mean += x
+	}
+	mean /= float64(len(observations))
+
+	sorted := append([]float64{}, observations...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return math.Abs(sorted[i]-mean) < math.Abs(sorted[j]-mean)
+	})
+	size := len(sorted) / states
+	for s := 0; s < states; s++ {
+		group := sorted[s*size : (s+1)*size]
+		if s == states-1 {
+			group = sorted[s*size:]
+		}
+		h.Means[s] = mean
+		for _, x := range group {
+			h.Variances[s] += (x - mean) * (x - mean)
+		}
+		h.Variances[s] = math.Max(h.Variances[s]/float64(len(group)), minVariance)
+
+		h.Initial[s] = 1 / float64(states)
+		h.Transition[s] = make([]float64, states)
+		for t := range h.Transition[s] {
+			if states == 1 {
+				h.Transition[s][t] = 1
+			} else if s == t {
+				h.Transition[s][t] = 0.9
+			} else {
+				h.Transition[s][t] = 0.1 / float64(states-1)
+			}
+		}
+	}
+}
+
+// step runs one scaled forward-backward pass, re-estimates the parameters and returns the log likelihood of the previous ones.
+func (h *GaussianHMM) step(observations []float64) (float64, error) {
+	states, steps := h.States(), len(observations)
+	alpha := make([][]float64, steps)
+	scale := make([]float64, steps)
+	logLikelihood := 0.0
+	for t, x := range observations {
+		alpha[t] = make([]float64, states)
+		for j := 0; j < states; j++ {
+			if t == 0 {
+				alpha[t][j] = h.Initial[j]
+			} else {
+				for i := 0; i < states; i++ {
+					alpha[t][j] += alpha[t-1][i] * h.Transition[i][j]
+				}
+			}
+			alpha[t][j] *= h.density(j, x)
+			scale[t] += alpha[t][j]
+		}
+		if scale[t] == 0 || math.IsNaN(scale[t]) {
+			return logLikelihood, errors.New("observations are impossible under the model")
+		}
+		for j := range alpha[t] {
+			alpha[t][j] /= scale[t]
+		}
+		logLikelihood += math.Log(scale[t])
+	}
+
+	beta := make([][]float64, steps)
+	beta[steps-1] = make([]float64, states)
+	for j := range beta[steps-1] {
+		beta[steps-1][j] = 1
+	}
+	for t := steps - 2; t >= 0; t-- {
+		beta[t] = make([]float64, states)
+		for i := 0; i < states; i++ {
+			for j := 0; j < states; j++ {
+				beta[t][i] += h.Transition[i][j] * h.density(j, observations[t+1]) * beta[t+1][j]
+			}
+			beta[t][i] /= scale[t+1]
+		}
+	}
+
+	gammaSum := make([]float64, states)
+	gammaSumExceptLast := make([]float64, states)
+	weightedSum := make([]float64, states)
+	xiSum := make([][]float64, states)
+	for i := range xiSum {
+		xiSum[i] = make([]float64, states)
+	}
+	gammas := make([][]float64, steps)
+	for t, x := range observations {
+		gammas[t] = make([]float64, states)
+		for i := 0; i < states; i++ {
+			gamma := alpha[t][i] * beta[t][i]
+			gammas[t][i] = gamma
+			gammaSum[i] += gamma
+			weightedSum[i] += gamma * x
+			if t < steps-1 {
+				gammaSumExceptLast[i] += gamma
+				for j := 0; j < states; j++ {
+					xiSum[i][j] += alpha[t][i] * h.Transition[i][j] * h.density(j, observations[t+1]) * beta[t+1][j] / scale[t+1]
+				}
+			}
+		}
+	}
+
+	for i := 0; i < states; i++ {
+		h.Initial[i] = gammas[0][i]
+		if gammaSumExceptLast[i] > 0 {
+			for j := 0; j < states; j++ {
+				h.Transition[i][j] = xiSum[i][j] / gammaSumExceptLast[i]
+			}
+		}
+		if gammaSum[i] == 0 {
+			continue
+		}
+		h.Means[i] = weightedSum[i] / gammaSum[i]
+		variance := 0.0
+		for t, x := range observations {
+			variance += gammas[t][i] * (x - h.Means[i]) * (x - h.Means[i])
+		}
+		h.Variances[i] = math.Max(variance/gammaSum[i], minVariance)
+	}
+	return logLikelihood, nil
+}
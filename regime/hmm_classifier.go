@@ -0,0 +1,145 @@
+package regime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// HMMClassifier fits a Gaussian hidden Markov model to recent log returns and labels every bar
+// with the regime of its most likely hidden state. States with the larger variances are high
+// volatility, states whose mean return is large compared to their standard deviation are trending.
+type HMMClassifier struct {
+	States int
+	// TrainSize is the number of log returns the model is fitted on.
+	TrainSize int
+	// RefitEvery refits the model on the latest returns after this many bars, 0 fits only once.
+	RefitEvery int
+	Iterations int
+	// TrendRatio is the absolute mean to standard deviation ratio from which a state is trending.
+	TrendRatio float64
+	Model      *GaussianHMM
+
+	lastTime  int64
+	lastClose float64
+	returns   []float64
+	posterior []float64
+	sinceFit  int
+}
+
+// NewHMMClassifier initializes a new HMMClassifier instance.
+func NewHMMClassifier(states, trainSize, refitEvery int, trendRatio float64) *HMMClassifier {
+	return &HMMClassifier{
+		States:     states,
+		TrainSize:  trainSize,
+		RefitEvery: refitEvery,
+		Iterations: 100,
+		TrendRatio: trendRatio,
+	}
+}
+
+// Name returns the name of the classifier.
+func (hc *HMMClassifier) Name() string {
+	return fmt.Sprintf("HMM_%d_%d_%d_%.2f", hc.States, hc.TrainSize, hc.RefitEvery, hc.TrendRatio)
+}
+
+// Clone returns an unfitted classifier with the same configuration.
+func (hc *HMMClassifier) Clone() Classifier {
+	clone := NewHMMClassifier(hc.States, hc.TrainSize, hc.RefitEvery, hc.TrendRatio)
+	clone.Iterations = hc.Iterations
+	return clone
+}
+
+// AddDataPoint adds a new data point, fitting the model once enough returns are collected.
+func (hc *HMMClassifier) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	if hc.lastTime != 0 && data.Time <= hc.lastTime {
+		return errors.New("data point is not in chronological order")
+	}
+	defer func() {
+		hc.lastTime = data.Time
+		hc.lastClose = data.Close
+	}()
+	if hc.lastClose <= 0 || data.Close <= 0 {
+		return nil
+	}
+
+	logReturn := math.Log(data.Close / hc.lastClose)
+	hc.returns = append(hc.returns, logReturn)
+	if len(hc.returns) > hc.TrainSize {
+		hc.returns = hc.returns[1:]
+	}
+
+	hc.sinceFit++
+	needsFit := hc.Model == nil || (hc.RefitEvery > 0 && hc.sinceFit >= hc.RefitEvery)
+	if needsFit && len(hc.returns) == hc.TrainSize {
+		return hc.fit()
+	}
+	if hc.Model != nil {
+		hc.posterior = hc.Model.Filter(hc.posterior, logReturn)
+	}
+	return nil
+}
+
+// fit fits a new model on the collected returns and filters them to get the current state probabilities.
+func (hc *HMMClassifier) fit() error {
+	hmm := NewGaussianHMM(hc.States)
+	if _, err := hmm.Fit(hc.returns, hc.Iterations); err != nil {
+		return fmt.Errorf("error fitting regime model: %w", err)
+	}
+	hc.Model = hmm
+	hc.sinceFit = 0
+	hc.posterior = nil
+	for _, r := range hc.returns {
+		hc.posterior = hmm.Filter(hc.posterior, r)
+	}
+	return nil
+}
+
+// Regime returns the regime of the most likely hidden state, or Unknown before the model is fitted.
+func (hc *HMMClassifier) Regime() Regime {
+	if hc.Model == nil || hc.posterior == nil {
+		return Unknown
+	}
+	return hc.StateRegime(hc.State())
+}
+
+// State returns the most likely hidden state of the latest bar.
+func (hc *HMMClassifier) State() int {
+	best := 0
+	for state, p := range hc.posterior {
+		if p > hc.posterior[best] {
+			best = state
+		}
+	}
+	return best
+}
+
+// StateRegime returns the regime a hidden state stands for.
+func (hc *HMMClassifier) StateRegime(state int) Regime {
+	order := make([]int, hc.Model.States())
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return hc.Model.Variances[order[i]] < hc.Model.Variances[order[j]]
+	})
+	rank := 0
+	for i, s := range order {
+		if s == state {
+			rank = i
+		}
+	}
+
+	regime := Regime{Trend: Ranging, Volatility: LowVolatility}
+	if 2*rank >= len(order) && len(order) > 1 {
+		regime.Volatility = HighVolatility
+	}
+	if math.Abs(hc.Model.Means[state]) >= hc.TrendRatio*math.Sqrt(hc.Model.Variances[state]) {
+		regime.Trend = Trending
+	}
+	return regime
+}
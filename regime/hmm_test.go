@@ -0,0 +1,79 @@
+package regime_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/regime"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+// switchingReturns alternates blocks of calm and volatile returns.
+func switchingReturns(blocks, size int) []float64 {
+	rng := rand.New(rand.NewSource(7))
+	var returns []float64
+	for b := 0; b < blocks; b++ {
+		sigma := 0.005
+		if b%2 == 1 {
+			sigma = 0.03
+		}
+		for i := 0; i < size; i++ {
+			returns = append(returns, sigma*rng.NormFloat64())
+		}
+	}
+	return returns
+}
+
+func TestGaussianHMMFit(t *testing.T) {
+	hmm := regime.NewGaussianHMM(2)
+	logLikelihood, err := hmm.Fit(switchingReturns(8, 50), 200)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	test_utils.AssertTrue(t, !math.IsNaN(logLikelihood), "Log likelihood is NaN")
+
+	calm, volatile := math.Sqrt(hmm.Variances[0]), math.Sqrt(hmm.Variances[1])
+	test_utils.AssertTrue(t, math.Abs(calm-0.005) < 0.002, "Calm state volatility was not recovered")
+	test_utils.AssertTrue(t, math.Abs(volatile-0.03) < 0.006, "Volatile state volatility was not recovered")
+	test_utils.AssertTrue(t, hmm.Transition[0][0] > 0.9 && hmm.Transition[1][1] > 0.9, "Expected persistent states")
+
+	posterior := hmm.Filter(nil, 0.08)
+	test_utils.AssertTrue(t, posterior[1] > 0.99, "Expected a large return to belong to the volatile state")
+}
+
+func TestGaussianHMMFilterKeepsPrediction(t *testing.T) {
+	hmm := &regime.GaussianHMM{
+		Initial:    []float64{0.5, 0.5},
+		Transition: [][]float64{{0.9, 0.1}, {0.2, 0.8}},
+		Means:      []float64{0, 0},
+		Variances:  []float64{0.0001, 0.0004},
+	}
+	// The observation has zero density in both states, so the filter falls back to the prediction.
+	posterior := hmm.Filter([]float64{1, 0}, 1000)
+	test_utils.AssertEqual(t, 0.9, posterior[0], "Predicted calm probability does not match")
+	test_utils.AssertEqual(t, 0.1, posterior[1], "Predicted volatile probability does not match")
+}
+
+func TestGaussianHMMFitNotEnoughData(t *testing.T) {
+	if _, err := regime.NewGaussianHMM(3).Fit([]float64{0.1, 0.2}, 10); err == nil {
+		t.Fatalf("expected an error for too few observations")
+	}
+}
+
+func TestHMMClassifier(t *testing.T) {
+	returns := switchingReturns(9, 50)
+	closes := []float64{100}
+	for _, r := range returns {
+		closes = append(closes, closes[len(closes)-1]*math.Exp(r))
+	}
+
+	classifier := regime.NewHMMClassifier(2, 300, 50, 0.5)
+	regimes := feed(t, classifier, bars(closes, 0.1))
+
+	test_utils.AssertEqual(t, regime.Unknown, regimes[299], "Expected an unknown regime before the model is fitted")
+	// Blocks alternate calm and volatile every 50 returns, the last block (returns 400 to 449) is calm.
+	test_utils.AssertEqual(t, regime.HighVolatility, regimes[390].Volatility, "Expected high volatility in a volatile block")
+	test_utils.AssertEqual(t, regime.LowVolatility, regimes[440].Volatility, "Expected low volatility in a calm block")
+	test_utils.AssertEqual(t, regime.Ranging, regimes[440].Trend, "Expected driftless returns to range")
+}
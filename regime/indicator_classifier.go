@@ -0,0 +1,122 @@
+package regime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// IndicatorClassifier labels the trend with the ADX and the volatility with the percentile
+// of the current realised volatility among its recent values.
+type IndicatorClassifier struct {
+	ADXPeriod int
+	// TrendThreshold is the ADX value from which the market is trending, typically 20 to 25.
+	TrendThreshold float64
+	// VolatilityPeriod is the number of log returns the realised volatility is calculated over.
+	VolatilityPeriod int
+	// PercentileWindow is the number of past volatility values the current one is ranked against.
+	PercentileWindow int
+	// HighPercentile is the percentile rank, between 0 and 1, from which volatility is high.
+	HighPercentile float64
+
+	adx          *indicator.ADX
+	lastTime     int64
+	lastClose    float64
+	returns      []float64
+	volatilities []float64
+}
+
+// NewIndicatorClassifier initializes a new IndicatorClassifier instance.
+func NewIndicatorClassifier(adxPeriod int, trendThreshold float64, volatilityPeriod, percentileWindow int, highPercentile float64) *IndicatorClassifier {
+	return &IndicatorClassifier{
+		ADXPeriod:        adxPeriod,
+		TrendThreshold:   trendThreshold,
+		VolatilityPeriod: volatilityPeriod,
+		PercentileWindow: percentileWindow,
+		HighPercentile:   highPercentile,
+		adx:              indicator.NewADX(adxPeriod),
+	}
+}
+
+// Name returns the name of the classifier.
+func (ic *IndicatorClassifier) Name() string {
+	return fmt.Sprintf("ADX_%d_%.0f_Vol_%d_%d_%.2f", ic.ADXPeriod, ic.TrendThreshold, ic.VolatilityPeriod, ic.PercentileWindow, ic.HighPercentile)
+}
+
+// Clone returns a new classifier with the same configuration.
+func (ic *IndicatorClassifier) Clone() Classifier {
+	return NewIndicatorClassifier(ic.ADXPeriod, ic.TrendThreshold, ic.VolatilityPeriod, ic.PercentileWindow, ic.HighPercentile)
+}
+
+// AddDataPoint adds a new data point and updates the ADX and the realised volatility.
+func (ic *IndicatorClassifier) AddDataPoint(ctx context.Context, data model.DataPoint) error {
+	if ic.lastTime != 0 && data.Time <= ic.lastTime {
+		return errors.New("data point is not in chronological order")
+	}
+	if err := ic.adx.Update(ctx, data); err != nil {
+		return err
+	}
+
+	if ic.lastClose > 0 && data.Close > 0 {
+		ic.returns = append(ic.returns, math.Log(data.Close/ic.lastClose))
+		if len(ic.returns) > ic.VolatilityPeriod {
+			ic.returns = ic.returns[1:]
+		}
+		if len(ic.returns) == ic.VolatilityPeriod {
+			ic.volatilities = append(ic.volatilities, standardDeviation(ic.returns))
+			if len(ic.volatilities) > ic.PercentileWindow {
+				ic.volatilities = ic.volatilities[1:]
+			}
+		}
+	}
+	ic.lastTime = data.Time
+	ic.lastClose = data.Close
+	return nil
+}
+
+// Regime returns the regime of the latest bar once both the ADX and the volatility window are filled.
+func (ic *IndicatorClassifier) Regime() Regime {
+	if !ic.adx.Ready() || len(ic.volatilities) < ic.PercentileWindow {
+		return Unknown
+	}
+
+	regime := Regime{Trend: Ranging, Volatility: LowVolatility}
+	if ic.adx.Value().ADX >= ic.TrendThreshold {
+		regime.Trend = Trending
+	}
+	if percentileRank(ic.volatilities, ic.volatilities[len(ic.volatilities)-1]) >= ic.HighPercentile {
+		regime.Volatility = HighVolatility
+	}
+	return regime
+}
+
+// ADX returns the latest ADX value.
+func (ic *IndicatorClassifier) ADX() float64 {
+	return ic.adx.Value().ADX
+}
+
+// percentileRank returns the fraction of values strictly lower than value.
+func percentileRank(values []float64, value float64) float64 {
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	return float64(sort.SearchFloat64s(sorted, value)) / float64(len(sorted))
+}
+
+func standardDeviation(values []float64) float64 {
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(variance / float64(len(values)))
+}
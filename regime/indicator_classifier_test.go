@@ -0,0 +1,93 @@
+package regime_test
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/regime"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+// bars builds bars from closes with a fixed range around each close.
+func bars(closes []float64, spread float64) []model.DataPoint {
+	data := make([]model.DataPoint, len(closes))
+	for i, c := range closes {
+		data[i] = model.DataPoint{Time: int64(i + 1), Open: c, High: c + spread, Low: c - spread, Close: c}
+	}
+	return data
+}
+
+func feed(t *testing.T, classifier regime.Classifier, data []model.DataPoint) []regime.Regime {
+	regimes := make([]regime.Regime, len(data))
+	for i, dp := range data {
+		if err := classifier.AddDataPoint(context.Background(), dp); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		regimes[i] = classifier.Regime()
+	}
+	return regimes
+}
+
+func TestIndicatorClassifierTrend(t *testing.T) {
+	var closes []float64
+	for i := 0; i < 80; i++ {
+		closes = append(closes, 100+float64(i)) // steady uptrend
+	}
+	for i := 0; i < 80; i++ {
+		closes = append(closes, 180+3*math.Sin(float64(i))) // sideways chop
+	}
+
+	classifier := regime.NewIndicatorClassifier(14, 25, 10, 20, 0.8)
+	regimes := feed(t, classifier, bars(closes, 0.5))
+
+	test_utils.AssertEqual(t, regime.Unknown, regimes[10], "Expected an unknown regime while warming up")
+	test_utils.AssertEqual(t, regime.Trending, regimes[79].Trend, "Expected a trend at the end of the uptrend")
+	test_utils.AssertEqual(t, regime.Ranging, regimes[159].Trend, "Expected a range at the end of the chop")
+	test_utils.AssertTrue(t, classifier.ADX() < 25, "Expected a low ADX in the range")
+}
+
+func TestIndicatorClassifierVolatility(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	closes := []float64{100}
+	for i := 1; i < 120; i++ {
+		volatility := 0.002
+		if i >= 100 {
+			volatility = 0.03
+		}
+		closes = append(closes, closes[i-1]*math.Exp(volatility*rng.NormFloat64()))
+	}
+
+	classifier := regime.NewIndicatorClassifier(14, 25, 10, 40, 0.8)
+	regimes := feed(t, classifier, bars(closes, 0.1))
+
+	test_utils.AssertEqual(t, regime.LowVolatility, regimes[95].Volatility, "Expected low volatility before the shock")
+	test_utils.AssertEqual(t, regime.HighVolatility, regimes[119].Volatility, "Expected high volatility after the shock")
+}
+
+func TestRegimeMatches(t *testing.T) {
+	trendHigh := regime.Regime{Trend: regime.Trending, Volatility: regime.HighVolatility}
+	test_utils.AssertTrue(t, trendHigh.Matches(regime.Regime{Trend: regime.Trending}), "Expected an empty volatility to match any")
+	test_utils.AssertTrue(t, !trendHigh.Matches(regime.Regime{Trend: regime.Ranging}), "Expected a different trend not to match")
+	test_utils.AssertTrue(t, !regime.Unknown.Matches(regime.Regime{Volatility: regime.LowVolatility}), "Expected unknown not to match a set field")
+	test_utils.AssertEqual(t, "trend/high_volatility", trendHigh.String(), "Regime string does not match")
+
+	for _, text := range []string{"trend/high_volatility", "range/any", "any/low_volatility", "any"} {
+		parsed, err := regime.ParseRegime(text)
+		if err != nil {
+			t.Fatalf("unexpected error parsing %s: %v", text, err)
+		}
+		test_utils.AssertEqual(t, text, strings.Replace(parsed.String(), "unknown", "any", 1), "Parsed regime does not match")
+	}
+	if r, _ := regime.ParseRegime("trend"); r != (regime.Regime{Trend: regime.Trending}) {
+		t.Errorf("expected a lone trend to leave the volatility empty, got %v", r)
+	}
+	for _, text := range []string{"bull", "trend/range", "trend/high_volatility/x"} {
+		if _, err := regime.ParseRegime(text); err == nil {
+			t.Errorf("expected an error parsing %s", text)
+		}
+	}
+}
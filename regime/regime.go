@@ -0,0 +1,96 @@
+// Package regime labels the market a bar belongs to, trending or ranging and with high or low volatility.
+package regime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// Trend tells whether prices move directionally or sideways.
+type Trend string
+
+const (
+	Trending Trend = "trend"
+	Ranging  Trend = "range"
+)
+
+// Volatility tells whether price changes are large or small compared to their recent history.
+type Volatility string
+
+const (
+	HighVolatility Volatility = "high_volatility"
+	LowVolatility  Volatility = "low_volatility"
+)
+
+// Regime is the market regime of a bar. Empty fields are unknown.
+type Regime struct {
+	Trend      Trend
+	Volatility Volatility
+}
+
+// Unknown is the regime reported before a classifier has seen enough data.
+var Unknown = Regime{}
+
+func (r Regime) String() string {
+	if r == Unknown {
+		return "unknown"
+	}
+	trend, volatility := string(r.Trend), string(r.Volatility)
+	if trend == "" {
+		trend = "any"
+	}
+	if volatility == "" {
+		volatility = "any"
+	}
+	return trend + "/" + volatility
+}
+
+// ParseRegime parses a regime pattern in the form String returns, e.g. "trend/high_volatility". A part
+// written "any" matches any value, and a single trend or volatility leaves the other part empty.
+func ParseRegime(text string) (Regime, error) {
+	if text == "any" || text == "unknown" {
+		return Unknown, nil
+	}
+	var r Regime
+	parts := strings.Split(text, "/")
+	if len(parts) > 2 {
+		return r, fmt.Errorf("invalid regime %q, expected trend/volatility", text)
+	}
+	for _, part := range parts {
+		switch part {
+		case string(Trending), string(Ranging):
+			if r.Trend != "" {
+				return r, fmt.Errorf("invalid regime %q, the trend is given twice", text)
+			}
+			r.Trend = Trend(part)
+		case string(HighVolatility), string(LowVolatility):
+			if r.Volatility != "" {
+				return r, fmt.Errorf("invalid regime %q, the volatility is given twice", text)
+			}
+			r.Volatility = Volatility(part)
+		case "any":
+		default:
+			return r, fmt.Errorf("unknown regime %q, expected %s or %s and %s or %s", part, Trending, Ranging, HighVolatility, LowVolatility)
+		}
+	}
+	return r, nil
+}
+
+// Matches reports whether the regime falls under pattern, empty pattern fields match anything.
+func (r Regime) Matches(pattern Regime) bool {
+	return (pattern.Trend == "" || pattern.Trend == r.Trend) &&
+		(pattern.Volatility == "" || pattern.Volatility == r.Volatility)
+}
+
+// Classifier labels every bar it is fed with a regime.
+type Classifier interface {
+	Name() string
+	// Clone returns an untrained classifier with the same configuration.
+	Clone() Classifier
+	AddDataPoint(ctx context.Context, data model.DataPoint) error
+	// Regime returns the regime of the latest bar, or Unknown while warming up.
+	Regime() Regime
+}
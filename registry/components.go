@@ -0,0 +1,30 @@
+package registry
+
+import (
+	"context"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// The component interfaces are declared here rather than in the packages implementing them, so those packages can
+// register their types next to the constructors. indicator_adaptor and algorithm refer to them by aliases.
+
+// IndicatorAdaptor turns the bars it is fed into signals, it is indicator_adaptor.IndicatorAdaptor.
+type IndicatorAdaptor interface {
+	Name() string
+	Clone(ctx context.Context) IndicatorAdaptor
+	AddDataPoint(ctx context.Context, data model.DataPoint) error
+	GetSignal(ctx context.Context) model.TradingSignal
+}
+
+// TradingAlgorithm decides on every bar, it is algorithm.TradingAlgorithm.
+type TradingAlgorithm interface {
+	Name() string
+	Evaluate(ctx context.Context, data model.DataPoint) model.TradingSignal
+}
+
+// PairTradingAlgorithm decides on the bars of two instruments for the same time, it is algorithm.PairTradingAlgorithm.
+type PairTradingAlgorithm interface {
+	Name() string
+	EvaluatePair(ctx context.Context, a, b model.DataPoint) model.TradingSignal
+}
@@ -0,0 +1,242 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParamType is the type of value a parameter takes.
+type ParamType string
+
+const (
+	IntParam        ParamType = "int"
+	FloatParam      ParamType = "float"
+	StringParam     ParamType = "string"
+	IntListParam    ParamType = "int_list"
+	StringListParam ParamType = "string_list"
+)
+
+// Param declares a parameter of a component. Numeric parameters and the items of int lists must lie within Min and Max.
+type Param struct {
+	Name        string
+	Type        ParamType
+	Description string
+	// Default is used when the parameter is not given, it is ignored for required parameters.
+	Default  interface{}
+	Required bool
+	Min      float64
+	Max      float64
+	// Choices restricts a string parameter to the given values.
+	Choices []string
+}
+
+// Range returns the valid values of the parameter as text, e.g. "2..1000".
+func (p Param) Range() string {
+	switch {
+	case len(p.Choices) > 0:
+		return strings.Join(p.Choices, "|")
+	case p.Type == IntParam || p.Type == FloatParam || p.Type == IntListParam:
+		return strconv.FormatFloat(p.Min, 'f', -1, 64) + ".." + strconv.FormatFloat(p.Max, 'f', -1, 64)
+	}
+	return ""
+}
+
+// Params holds resolved parameter values: int, float64, string, []int or []string according to their type.
+type Params map[string]interface{}
+
+func (p Params) Int(name string) int {
+	return p[name].(int)
+}
+
+func (p Params) Float(name string) float64 {
+	return p[name].(float64)
+}
+
+func (p Params) String(name string) string {
+	return p[name].(string)
+}
+
+func (p Params) Ints(name string) []int {
+	return p[name].([]int)
+}
+
+func (p Params) Strings(name string) []string {
+	return p[name].([]string)
+}
+
+// ParamError is a problem with a single parameter value.
+type ParamError struct {
+	Param string
+	Msg   string
+}
+
+func (e *ParamError) Error() string {
+	if e.Param == "" {
+		return e.Msg
+	}
+	return fmt.Sprintf("%s: %s", e.Param, e.Msg)
+}
+
+// resolve checks the values against the declared parameters and fills in the defaults.
+// Numbers are accepted as any Go numeric type, so values decoded from JSON or YAML can be passed as they are.
+func resolve(declared []Param, values map[string]interface{}, validate func(Params) error) (Params, error) {
+	names := make([]string, len(declared))
+	for i, param := range declared {
+		names[i] = param.Name
+	}
+	unknown := make([]string, 0)
+	for name := range values {
+		if !containsName(names, name) {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, &ParamError{Param: unknown[0], Msg: fmt.Sprintf("unknown parameter, expected one of: %s", strings.Join(names, ", "))}
+	}
+
+	params := Params{}
+	for _, param := range declared {
+		value, ok := values[param.Name]
+		if !ok {
+			if param.Required {
+				return nil, &ParamError{Param: param.Name, Msg: "missing required parameter"}
+			}
+			params[param.Name] = param.Default
+			continue
+		}
+		converted, err := param.convert(value)
+		if err != nil {
+			return nil, &ParamError{Param: param.Name, Msg: err.Error()}
+		}
+		params[param.Name] = converted
+	}
+	if validate != nil {
+		if err := validate(params); err != nil {
+			var paramErr *ParamError
+			if errors.As(err, &paramErr) {
+				return nil, paramErr
+			}
+			return nil, &ParamError{Msg: err.Error()}
+		}
+	}
+	return params, nil
+}
+
+func (p Param) convert(value interface{}) (interface{}, error) {
+	switch p.Type {
+	case IntParam:
+		return p.integer(value)
+	case FloatParam:
+		number, ok := toFloat(value)
+		if !ok || math.IsNaN(number) {
+			return nil, fmt.Errorf("must be a number")
+		}
+		if number < p.Min || number > p.Max {
+			return nil, fmt.Errorf("must be between %v and %v, got %v", p.Min, p.Max, number)
+		}
+		return number, nil
+	case StringParam:
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("must be a string")
+		}
+		if len(p.Choices) > 0 && !containsName(p.Choices, text) {
+			return nil, fmt.Errorf("must be one of %s, got %q", strings.Join(p.Choices, ", "), text)
+		}
+		return text, nil
+	case IntListParam:
+		items, ok := toList(value)
+		if !ok || len(items) == 0 {
+			return nil, fmt.Errorf("must be a non-empty list of integers")
+		}
+		ints := make([]int, len(items))
+		for i, item := range items {
+			number, err := p.integer(item)
+			if err != nil {
+				return nil, fmt.Errorf("item %d %w", i, err)
+			}
+			ints[i] = number
+		}
+		return ints, nil
+	case StringListParam:
+		items, ok := toList(value)
+		if !ok {
+			return nil, fmt.Errorf("must be a list of strings")
+		}
+		texts := make([]string, len(items))
+		for i, item := range items {
+			if texts[i], ok = item.(string); !ok {
+				return nil, fmt.Errorf("item %d must be a string", i)
+			}
+		}
+		return texts, nil
+	}
+	return nil, fmt.Errorf("has unsupported type %q", p.Type)
+}
+
+func (p Param) integer(value interface{}) (int, error) {
+	number, ok := toFloat(value)
+	if !ok || number != math.Trunc(number) {
+		return 0, fmt.Errorf("must be an integer")
+	}
+	if number < p.Min || number > p.Max {
+		return 0, fmt.Errorf("must be between %v and %v, got %v", p.Min, p.Max, number)
+	}
+	return int(number), nil
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func toList(value interface{}) ([]interface{}, bool) {
+	switch v := value.(type) {
+	case []interface{}:
+		return v, true
+	case []int:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = item
+		}
+		return items, true
+	case []float64:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = item
+		}
+		return items, true
+	case []string:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = item
+		}
+		return items, true
+	}
+	return nil, false
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
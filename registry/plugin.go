@@ -0,0 +1,32 @@
+package registry
+
+import (
+	"time"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/plugin"
+)
+
+// PluginParams declares the parameters of the plugin adaptor and algorithm.
+func PluginParams() []Param {
+	defaults := plugin.DefaultConfig("")
+	return []Param{
+		{Name: "command", Type: StringParam, Description: "Executable to run", Required: true},
+		{Name: "args", Type: StringListParam, Default: []string{}},
+		{Name: "timeout_ms", Type: IntParam, Default: int(defaults.Timeout / time.Millisecond), Min: 1, Max: 3600000},
+		{Name: "handshake_timeout_ms", Type: IntParam, Default: int(defaults.HandshakeTimeout / time.Millisecond), Min: 1, Max: 3600000},
+		{Name: "max_restarts", Type: IntParam, Default: defaults.MaxRestarts, Min: 0, Max: 1000},
+		{Name: "batch_size", Type: IntParam, Default: defaults.BatchSize, Min: 1, Max: 1000000},
+		{Name: "replay_bars", Type: IntParam, Default: defaults.ReplayBars, Min: 0, Max: 1000000},
+	}
+}
+
+// PluginConfig builds the plugin configuration from the parameters declared by PluginParams.
+func PluginConfig(p Params) plugin.Config {
+	config := plugin.DefaultConfig(p.String("command"), p.Strings("args")...)
+	config.Timeout = time.Duration(p.Int("timeout_ms")) * time.Millisecond
+	config.HandshakeTimeout = time.Duration(p.Int("handshake_timeout_ms")) * time.Millisecond
+	config.MaxRestarts = p.Int("max_restarts")
+	config.BatchSize = p.Int("batch_size")
+	config.ReplayBars = p.Int("replay_bars")
+	return config
+}
@@ -0,0 +1,237 @@
+// Package registry lists the adaptor, algorithm and pair algorithm types by name, with their parameters and a factory,
+// so config loaders, CLIs and optimizers can build any of them without knowing their constructors.
+//
+// Every type registers itself in an init function next to its constructor, in the indicator_adaptor and algorithm
+// packages. Programs not using those packages otherwise import algorithm for its side effects to see the built-in types.
+package registry
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+)
+
+// AdaptorEntry describes an adaptor type.
+type AdaptorEntry struct {
+	Name        string
+	Description string
+	Params      []Param
+	// Validate optionally checks constraints between resolved parameters.
+	Validate func(p Params) error
+	New      func(ctx context.Context, p Params, m monitor.Monitoring) (IndicatorAdaptor, error)
+}
+
+// Resolve checks the values and fills in the defaults.
+func (e AdaptorEntry) Resolve(values map[string]interface{}) (Params, error) {
+	return resolve(e.Params, values, e.Validate)
+}
+
+// Build resolves the values and builds the adaptor.
+func (e AdaptorEntry) Build(ctx context.Context, values map[string]interface{}, m monitor.Monitoring) (IndicatorAdaptor, error) {
+	params, err := e.Resolve(values)
+	if err != nil {
+		return nil, fmt.Errorf("adaptor %s: %w", e.Name, err)
+	}
+	return e.New(ctx, params, m)
+}
+
+// AlgorithmEntry describes an algorithm type. Algorithms combining adaptors are given them already built.
+type AlgorithmEntry struct {
+	Name        string
+	Description string
+	Params      []Param
+	// UsesAdaptors reports whether the algorithm combines adaptors, it then needs at least one.
+	UsesAdaptors bool
+	Validate     func(p Params) error
+	New          func(ctx context.Context, p Params, adaptors []IndicatorAdaptor, m monitor.Monitoring) (TradingAlgorithm, error)
+}
+
+// Resolve checks the values and fills in the defaults.
+func (e AlgorithmEntry) Resolve(values map[string]interface{}) (Params, error) {
+	return resolve(e.Params, values, e.Validate)
+}
+
+// Build resolves the values and builds the algorithm over the adaptors.
+func (e AlgorithmEntry) Build(ctx context.Context, values map[string]interface{}, adaptors []IndicatorAdaptor, m monitor.Monitoring) (TradingAlgorithm, error) {
+	if e.UsesAdaptors && len(adaptors) == 0 {
+		return nil, fmt.Errorf("algorithm %s needs at least one adaptor", e.Name)
+	}
+	if !e.UsesAdaptors && len(adaptors) > 0 {
+		return nil, fmt.Errorf("algorithm %s does not take adaptors", e.Name)
+	}
+	params, err := e.Resolve(values)
+	if err != nil {
+		return nil, fmt.Errorf("algorithm %s: %w", e.Name, err)
+	}
+	return e.New(ctx, params, adaptors, m)
+}
+
+// PairAlgorithmEntry describes a pair trading algorithm type, one trading two instruments against each other.
+type PairAlgorithmEntry struct {
+	Name        string
+	Description string
+	Params      []Param
+	Validate    func(p Params) error
+	New         func(ctx context.Context, p Params, m monitor.Monitoring) (PairTradingAlgorithm, error)
+}
+
+// Resolve checks the values and fills in the defaults.
+func (e PairAlgorithmEntry) Resolve(values map[string]interface{}) (Params, error) {
+	return resolve(e.Params, values, e.Validate)
+}
+
+// Build resolves the values and builds the pair algorithm.
+func (e PairAlgorithmEntry) Build(ctx context.Context, values map[string]interface{}, m monitor.Monitoring) (PairTradingAlgorithm, error) {
+	params, err := e.Resolve(values)
+	if err != nil {
+		return nil, fmt.Errorf("pair algorithm %s: %w", e.Name, err)
+	}
+	return e.New(ctx, params, m)
+}
+
+var (
+	mu             sync.RWMutex
+	adaptors       = make(map[string]AdaptorEntry)
+	algorithms     = make(map[string]AlgorithmEntry)
+	pairAlgorithms = make(map[string]PairAlgorithmEntry)
+)
+
+// RegisterAdaptor makes an adaptor type available by name. It panics if the name is already taken.
+func RegisterAdaptor(entry AdaptorEntry) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, exists := adaptors[entry.Name]; exists {
+		panic(fmt.Sprintf("registry: adaptor %q registered twice", entry.Name))
+	}
+	adaptors[entry.Name] = entry
+}
+
+// RegisterAlgorithm makes an algorithm type available by name. It panics if the name is already taken.
+func RegisterAlgorithm(entry AlgorithmEntry) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, exists := algorithms[entry.Name]; exists {
+		panic(fmt.Sprintf("registry: algorithm %q registered twice", entry.Name))
+	}
+	algorithms[entry.Name] = entry
+}
+
+// RegisterPairAlgorithm makes a pair algorithm type available by name. It panics if the name is already taken.
+func RegisterPairAlgorithm(entry PairAlgorithmEntry) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, exists := pairAlgorithms[entry.Name]; exists {
+		panic(fmt.Sprintf("registry: pair algorithm %q registered twice", entry.Name))
+	}
+	pairAlgorithms[entry.Name] = entry
+}
+
+// Adaptor returns the adaptor type registered under name.
+func Adaptor(name string) (AdaptorEntry, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	entry, ok := adaptors[name]
+	return entry, ok
+}
+
+// Algorithm returns the algorithm type registered under name.
+func Algorithm(name string) (AlgorithmEntry, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	entry, ok := algorithms[name]
+	return entry, ok
+}
+
+// PairAlgorithm returns the pair algorithm type registered under name.
+func PairAlgorithm(name string) (PairAlgorithmEntry, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	entry, ok := pairAlgorithms[name]
+	return entry, ok
+}
+
+// Adaptors returns every registered adaptor type, sorted by name.
+func Adaptors() []AdaptorEntry {
+	mu.RLock()
+	defer mu.RUnlock()
+	entries := make([]AdaptorEntry, 0, len(adaptors))
+	for _, entry := range adaptors {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries
+}
+
+// Algorithms returns every registered algorithm type, sorted by name.
+func Algorithms() []AlgorithmEntry {
+	mu.RLock()
+	defer mu.RUnlock()
+	entries := make([]AlgorithmEntry, 0, len(algorithms))
+	for _, entry := range algorithms {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries
+}
+
+// PairAlgorithms returns every registered pair algorithm type, sorted by name.
+func PairAlgorithms() []PairAlgorithmEntry {
+	mu.RLock()
+	defer mu.RUnlock()
+	entries := make([]PairAlgorithmEntry, 0, len(pairAlgorithms))
+	for _, entry := range pairAlgorithms {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries
+}
+
+// AdaptorNames returns the names of the registered adaptor types, sorted.
+func AdaptorNames() []string {
+	entries := Adaptors()
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Name
+	}
+	return names
+}
+
+// AlgorithmNames returns the names of the registered algorithm types, sorted.
+func AlgorithmNames() []string {
+	entries := Algorithms()
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Name
+	}
+	return names
+}
+
+// NewAdaptor builds the adaptor type registered under name.
+func NewAdaptor(ctx context.Context, name string, values map[string]interface{}, m monitor.Monitoring) (IndicatorAdaptor, error) {
+	entry, ok := Adaptor(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown adaptor type %q", name)
+	}
+	return entry.Build(ctx, values, m)
+}
+
+// NewAlgorithm builds the algorithm type registered under name.
+func NewAlgorithm(ctx context.Context, name string, values map[string]interface{}, adaptors []IndicatorAdaptor, m monitor.Monitoring) (TradingAlgorithm, error) {
+	entry, ok := Algorithm(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown algorithm type %q", name)
+	}
+	return entry.Build(ctx, values, adaptors, m)
+}
+
+// NewPairAlgorithm builds the pair algorithm type registered under name.
+func NewPairAlgorithm(ctx context.Context, name string, values map[string]interface{}, m monitor.Monitoring) (PairTradingAlgorithm, error) {
+	entry, ok := PairAlgorithm(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown pair algorithm type %q", name)
+	}
+	return entry.Build(ctx, values, m)
+}
@@ -0,0 +1,157 @@
+package registry_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	_ "github.com/vd09/trading-algorithm-backtesting-system/algorithm"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+func TestNewAdaptor(t *testing.T) {
+	mock := test_utils.NewMockMetricsCollector(t)
+	ctx := context.Background()
+
+	rsi, err := registry.NewAdaptor(ctx, "rsi", map[string]interface{}{"period": 7}, mock)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	test_utils.AssertEqual(t, "RSI_P(7)_OBT(70.000000)_OST(30.000000)_L(100)", rsi.Name(), "Defaults were not applied")
+
+	// Values decoded from JSON arrive as float64 and []interface{}.
+	var values map[string]interface{}
+	json.Unmarshal([]byte(`{"periods": [9, 21], "max_history": 5}`), &values)
+	ema, err := registry.NewAdaptor(ctx, "ema", values, mock)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	test_utils.AssertEqual(t, "EMA_9_21", ema.Name(), "EMA name does not match")
+	if _, err := registry.NewAdaptor(ctx, "ema", map[string]interface{}{"periods": []int{9}}, mock); err == nil {
+		t.Errorf("expected an error for a single EMA period")
+	}
+
+	json.Unmarshal([]byte(`{"types": ["hma", "sma"], "periods": [9, 50]}`), &values)
+	crossover, err := registry.NewAdaptor(ctx, "moving_average", values, mock)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	test_utils.AssertEqual(t, "MA_hma_9_sma_50", crossover.Name(), "Moving average name does not match")
+	values["types"] = []interface{}{"hma", "vwma"}
+	if _, err := registry.NewAdaptor(ctx, "moving_average", values, mock); err == nil {
+		t.Errorf("expected an error for an unknown moving average type")
+	}
+
+	expression, err := registry.NewAdaptor(ctx, "expression", map[string]interface{}{"buy": "rsi(14) < 30"}, mock)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	test_utils.AssertTrue(t, expression != nil, "Expected the expression adaptor to build")
+	_, err = registry.NewAdaptor(ctx, "expression", map[string]interface{}{"sell": "rsi(14) >"}, mock)
+	var paramErr *registry.ParamError
+	if !errors.As(err, &paramErr) || paramErr.Param != "sell" {
+		t.Errorf("expected a sell parameter error for an invalid rule, got %v", err)
+	}
+
+	for _, entry := range registry.Adaptors() {
+		required := false
+		for _, param := range entry.Params {
+			required = required || param.Required
+		}
+		if required {
+			continue
+		}
+		if _, err := entry.Build(ctx, nil, mock); err != nil {
+			t.Errorf("adaptor %s does not build with its defaults: %v", entry.Name, err)
+		}
+	}
+}
+
+func TestResolveErrors(t *testing.T) {
+	entry, ok := registry.Adaptor("macd")
+	test_utils.AssertTrue(t, ok, "Expected macd to be registered")
+
+	tests := []struct {
+		values map[string]interface{}
+		param  string
+	}{
+		{map[string]interface{}{"short": 5}, "short"},
+		{map[string]interface{}{"short_period": 2.5}, "short_period"},
+		{map[string]interface{}{"short_period": 0}, "short_period"},
+		{map[string]interface{}{"long_period": "26"}, "long_period"},
+		{map[string]interface{}{"short_period": 30, "long_period": 26}, ""},
+	}
+	for _, tt := range tests {
+		_, err := entry.Resolve(tt.values)
+		var paramErr *registry.ParamError
+		if !errors.As(err, &paramErr) {
+			t.Fatalf("expected a parameter error for %v, got %v", tt.values, err)
+		}
+		test_utils.AssertEqual(t, tt.param, paramErr.Param, "Parameter of the error does not match")
+	}
+
+	if _, err := registry.NewAdaptor(context.Background(), "unknown_indicator", nil, nil); err == nil {
+		t.Fatalf("expected error for an unknown adaptor type")
+	}
+	if _, err := registry.NewAdaptor(context.Background(), "ema", nil, nil); err == nil {
+		t.Fatalf("expected error for a missing required parameter")
+	}
+}
+
+func TestNewAlgorithm(t *testing.T) {
+	mock := test_utils.NewMockMetricsCollector(t)
+	ctx := context.Background()
+	rsi, _ := registry.NewAdaptor(ctx, "rsi", nil, mock)
+	macd, _ := registry.NewAdaptor(ctx, "macd", nil, mock)
+	adaptors := []indicator_adaptor.IndicatorAdaptor{rsi, macd}
+
+	sequence, err := registry.NewAlgorithm(ctx, "sequential", map[string]interface{}{"within": 2}, adaptors, mock)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	test_utils.AssertEqual(t, "Sequence("+rsi.Name()+", "+macd.Name()+" within 2)", sequence.Name(), "Sequence name does not match")
+
+	stack, err := registry.NewAlgorithm(ctx, "stacking", map[string]interface{}{"learner": "perceptron"}, adaptors, mock)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	test_utils.AssertEqual(t, "Stacking_Perceptron("+rsi.Name()+", "+macd.Name()+")", stack.Name(), "Stacking name does not match")
+
+	switching, err := registry.NewAlgorithm(ctx, "regime_switching", map[string]interface{}{"regimes": []string{"trend", "range/any"}}, adaptors, mock)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	test_utils.AssertTrue(t, switching != nil, "Expected the regime switching algorithm to build")
+	if _, err := registry.NewAlgorithm(ctx, "regime_switching", map[string]interface{}{"regimes": []string{"trend"}}, adaptors, mock); err == nil {
+		t.Fatalf("expected error for fewer regimes than adaptors")
+	}
+	if _, err := registry.NewAlgorithm(ctx, "regime_switching", map[string]interface{}{"regimes": []string{"bull", "bear"}}, adaptors, mock); err == nil {
+		t.Fatalf("expected error for an unknown regime")
+	}
+
+	pairs, err := registry.NewPairAlgorithm(ctx, "pairs", map[string]interface{}{"ticker_a": "KO", "ticker_b": "PEP", "window": 30}, mock)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	test_utils.AssertEqual(t, "Pairs_KO_PEP_30_2.00_0.50", pairs.Name(), "Pairs name does not match")
+	if _, err := registry.NewPairAlgorithm(ctx, "pairs", map[string]interface{}{"ticker_a": "KO", "ticker_b": "PEP", "significance": 0.02}, mock); err == nil {
+		t.Fatalf("expected error for a significance without critical values")
+	}
+
+	if _, err := registry.NewAlgorithm(ctx, "combination", nil, nil, mock); err == nil {
+		t.Fatalf("expected error for a combination without adaptors")
+	}
+	if _, err := registry.NewAlgorithm(ctx, "stacking", map[string]interface{}{"learner": "svm"}, adaptors, mock); err == nil {
+		t.Fatalf("expected error for an unknown learner")
+	}
+}
+
+func TestRegisterTwicePanics(t *testing.T) {
+	defer func() {
+		test_utils.AssertTrue(t, recover() != nil, "Expected registering a taken name to panic")
+	}()
+	registry.RegisterAdaptor(registry.AdaptorEntry{Name: "rsi"})
+}
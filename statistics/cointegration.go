@@ -0,0 +1,108 @@
+package statistics
+
+import (
+	"errors"
+	"fmt"
+)
+
+// CriticalValues are the test statistics below which the null hypothesis of a unit root
+// is rejected at the 1%, 5% and 10% significance levels.
+type CriticalValues struct {
+	One  float64
+	Five float64
+	Ten  float64
+}
+
+var (
+	// ADFConstantCritical applies to the ADF test with a constant, for large samples.
+	ADFConstantCritical = CriticalValues{One: -3.43, Five: -2.86, Ten: -2.57}
+	// EngleGrangerCritical applies to the ADF test on the residuals of a two variable
+	// cointegrating regression with a constant (MacKinnon, 2010).
+	EngleGrangerCritical = CriticalValues{One: -3.90, Five: -3.34, Ten: -3.04}
+)
+
+// At returns the critical value of the significance level, which must be 0.01, 0.05 or 0.10.
+func (cv CriticalValues) At(significance float64) (float64, error) {
+	switch significance {
+	case 0.01:
+		return cv.One, nil
+	case 0.05:
+		return cv.Five, nil
+	case 0.10:
+		return cv.Ten, nil
+	}
+	return 0, fmt.Errorf("no critical value for significance %v, use 0.01, 0.05 or 0.10", significance)
+}
+
+// ADF returns the augmented Dickey-Fuller t-statistic of the series with the given number of lagged differences:
+//
+//	Δy(t) = [c +] γ·y(t-1) + Σ φ(i)·Δy(t-i) + ε(t)
+//
+// The more negative the statistic, the stronger the evidence that the series is stationary.
+func ADF(series []float64, lags int, constant bool) (float64, error) {
+	if lags < 0 {
+		return 0, errors.New("lags must not be negative")
+	}
+	if len(series) < lags+10 {
+		return 0, fmt.Errorf("need at least %d observations for %d lags", lags+10, lags)
+	}
+
+	diffs := make([]float64, len(series)-1)
+	for i := range diffs {
+		diffs[i] = series[i+1] - series[i]
+	}
+	var x [][]float64
+	var y []float64
+	for t := lags; t < len(diffs); t++ {
+		row := []float64{series[t]}
+		for i := 1; i <= lags; i++ {
+			row = append(row, diffs[t-i])
+		}
+		if constant {
+			row = append(row, 1)
+		}
+		x = append(x, row)
+		y = append(y, diffs[t])
+	}
+
+	reg, err := OLS(x, y)
+	if err != nil {
+		return 0, err
+	}
+	if reg.StdErrors[0] == 0 {
+		return 0, errors.New("series has no variation")
+	}
+	return reg.Coefficients[0] / reg.StdErrors[0], nil
+}
+
+// CointegrationResult is the outcome of an Engle-Granger test.
+type CointegrationResult struct {
+	// Intercept and HedgeRatio are the coefficients of y = Intercept + HedgeRatio·x + spread.
+	Intercept  float64
+	HedgeRatio float64
+	Statistic  float64
+	Spread     []float64
+}
+
+// Cointegrated reports whether the series are cointegrated at the significance level.
+func (cr CointegrationResult) Cointegrated(significance float64) bool {
+	critical, err := EngleGrangerCritical.At(significance)
+	return err == nil && cr.Statistic < critical
+}
+
+// EngleGranger regresses y on x and tests the residual spread for a unit root with the ADF test.
+func EngleGranger(y, x []float64, lags int) (CointegrationResult, error) {
+	if len(x) != len(y) {
+		return CointegrationResult{}, errors.New("series differ in length")
+	}
+	intercept, slope, residuals, err := LinearFit(x, y)
+	if err != nil {
+		return CointegrationResult{}, err
+	}
+	// The residuals have zero mean by construction, so the test runs without a constant.
+	statistic, err := ADF(residuals, lags, false)
+	if err != nil {
+		return CointegrationResult{}, err
+	}
+	return CointegrationResult{Intercept: intercept, HedgeRatio: slope, Statistic: statistic, Spread: residuals}, nil
+}
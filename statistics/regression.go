@@ -0,0 +1,136 @@
+// Package statistics holds the regressions and stationarity tests used by the statistical strategies.
+package statistics
+
+import (
+	"errors"
+	"math"
+)
+
+// Regression is the result of an ordinary least squares fit.
+type Regression struct {
+	Coefficients []float64
+	// StdErrors are the standard errors of the coefficients.
+	StdErrors []float64
+	Residuals []float64
+}
+
+// OLS regresses y on the columns of x, one row per observation. Add a column of ones for an intercept.
+func OLS(x [][]float64, y []float64) (Regression, error) {
+	n := len(y)
+	if n == 0 || len(x) != n {
+		return Regression{}, errors.New("observations and regressors differ in length")
+	}
+	k := len(x[0])
+	if n <= k {
+		return Regression{}, errors.New("not enough observations for the number of regressors")
+	}
+
+	// Normal equations: (X'X) b = X'y.
+	xtx := make([][]float64, k)
+	xty := make([]float64, k)
+	for i := range xtx {
+		xtx[i] = make([]float64, k)
+	}
+	for row, values := range x {
+		for i := 0; i < k; i++ {
+			xty[i] += values[i] * y[row]
+			for j := 0; j < k; j++ {
+				xtx[i][j] += values[i] * values[j]
+			}
+		}
+	}
+	inverse, err := invert(xtx)
+	if err != nil {
+		return Regression{}, err
+	}
+
+	reg := Regression{Coefficients: make([]float64, k), StdErrors: make([]float64, k), Residuals: make([]float64, n)}
+	for i := 0; i < k; i++ {
+		for j := 0; j < k; j++ {
+			reg.Coefficients[i] += inverse[i][j] * xty[j]
+		}
+	}
+	sse := 0.0
+	for row, values := range x {
+		fitted := 0.0
+		for i, value := range values {
+			fitted += reg.Coefficients[i] * value
+		}
+		reg.Residuals[row] = y[row] - fitted
+		sse += reg.Residuals[row] * reg.Residuals[row]
+	}
+	variance := sse / float64(n-k)
+	for i := range reg.StdErrors {
+		reg.StdErrors[i] = math.Sqrt(variance * inverse[i][i])
+	}
+	return reg, nil
+}
+
+// LinearFit regresses y on x with an intercept.
+func LinearFit(x, y []float64) (intercept, slope float64, residuals []float64, err error) {
+	rows := make([][]float64, len(x))
+	for i, value := range x {
+		rows[i] = []float64{1, value}
+	}
+	reg, err := OLS(rows, y)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	return reg.Coefficients[0], reg.Coefficients[1], reg.Residuals, nil
+}
+
+// invert inverts a square matrix with Gauss-Jordan elimination and partial pivoting.
+func invert(matrix [][]float64) ([][]float64, error) {
+	k := len(matrix)
+	a := make([][]float64, k)
+	for i := range a {
+		a[i] = make([]float64, 2*k)
+		copy(a[i], matrix[i])
+		a[i][k+i] = 1
+	}
+	for col := 0; col < k; col++ {
+		pivot := col
+		for row := col + 1; row < k; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return nil, errors.New("regressors are collinear")
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		scale := a[col][col]
+		for j := range a[col] {
+			a[col][j] /= scale
+		}
+		for row := 0; row < k; row++ {
+			if row == col || a[row][col] == 0 {
+				continue
+			}
+			factor := a[row][col]
+			for j := range a[row] {
+				a[row][j] -= factor * a[col][j]
+			}
+		}
+	}
+	inverse := make([][]float64, k)
+	for i := range inverse {
+		inverse[i] = a[i][k:]
+	}
+	return inverse, nil
+}
+
+// MeanStd returns the mean and the population standard deviation of values.
+func MeanStd(values []float64) (mean, std float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	for _, value := range values {
+		mean += value
+	}
+	mean /= float64(len(values))
+	for _, value := range values {
+		std += (value - mean) * (value - mean)
+	}
+	return mean, math.Sqrt(std / float64(len(values)))
+}
@@ -0,0 +1,75 @@
+package statistics_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/statistics"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+func TestOLS(t *testing.T) {
+	x := [][]float64{}
+	y := []float64{}
+	for i := 0; i < 20; i++ {
+		a, b := float64(i), float64(i%3)
+		x = append(x, []float64{1, a, b})
+		y = append(y, 2+0.5*a-3*b)
+	}
+	reg, err := statistics.OLS(x, y)
+	test_utils.AssertEqual(t, nil, err, "Exact fit should succeed")
+	for i, want := range []float64{2, 0.5, -3} {
+		test_utils.AssertTrue(t, math.Abs(reg.Coefficients[i]-want) < 1e-9, "Coefficients should be recovered")
+	}
+
+	_, err = statistics.OLS([][]float64{{1, 1}, {1, 1}, {1, 1}}, []float64{1, 2, 3})
+	test_utils.AssertTrue(t, err != nil, "Collinear regressors should be rejected")
+}
+
+func TestADF(t *testing.T) {
+	random := rand.New(rand.NewSource(7))
+	walk, noise := make([]float64, 500), make([]float64, 500)
+	for i := 1; i < len(walk); i++ {
+		walk[i] = walk[i-1] + random.NormFloat64()
+		noise[i] = 0.3*noise[i-1] + random.NormFloat64()
+	}
+
+	walkStat, err := statistics.ADF(walk, 1, true)
+	test_utils.AssertEqual(t, nil, err, "Testing a random walk should succeed")
+	test_utils.AssertTrue(t, walkStat > statistics.ADFConstantCritical.Five, "A random walk should keep its unit root")
+
+	noiseStat, err := statistics.ADF(noise, 1, true)
+	test_utils.AssertEqual(t, nil, err, "Testing a stationary series should succeed")
+	test_utils.AssertTrue(t, noiseStat < statistics.ADFConstantCritical.One, "A stationary series should reject the unit root")
+
+	_, err = statistics.ADF(walk[:5], 1, true)
+	test_utils.AssertTrue(t, err != nil, "Too short a series should be rejected")
+}
+
+func TestEngleGranger(t *testing.T) {
+	random := rand.New(rand.NewSource(3))
+	x, paired, independent := make([]float64, 400), make([]float64, 400), make([]float64, 400)
+	spread := 0.0
+	for i := range x {
+		if i > 0 {
+			x[i] = x[i-1] + random.NormFloat64()
+			independent[i] = independent[i-1] + random.NormFloat64()
+		}
+		spread = 0.5*spread + random.NormFloat64()
+		paired[i] = 1 + 1.5*x[i] + spread
+	}
+
+	result, err := statistics.EngleGranger(paired, x, 1)
+	test_utils.AssertEqual(t, nil, err, "Testing the paired series should succeed")
+	test_utils.AssertTrue(t, math.Abs(result.HedgeRatio-1.5) < 0.05, "The hedge ratio should be recovered")
+	test_utils.AssertTrue(t, result.Cointegrated(0.01), "The paired series should be cointegrated")
+	test_utils.AssertEqual(t, len(x), len(result.Spread), "The spread should have one value per observation")
+
+	result, err = statistics.EngleGranger(independent, x, 1)
+	test_utils.AssertEqual(t, nil, err, "Testing the independent series should succeed")
+	test_utils.AssertTrue(t, !result.Cointegrated(0.10), "Independent random walks should not be cointegrated")
+
+	_, err = statistics.EngleGrangerCritical.At(0.2)
+	test_utils.AssertTrue(t, err != nil, "Unknown significance levels should be rejected")
+}
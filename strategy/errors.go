@@ -0,0 +1,37 @@
+package strategy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError points to the location of an invalid value in a strategy file.
+type ValidationError struct {
+	File   string
+	Line   int
+	Column int
+	Field  string
+	Msg    string
+}
+
+func (e *ValidationError) Error() string {
+	location := fmt.Sprintf("%d:%d", e.Line, e.Column)
+	if e.File != "" {
+		location = e.File + ":" + location
+	}
+	if e.Field == "" {
+		return fmt.Sprintf("%s: %s", location, e.Msg)
+	}
+	return fmt.Sprintf("%s: %s: %s", location, e.Field, e.Msg)
+}
+
+// ValidationErrors collects every problem found while validating a strategy file.
+type ValidationErrors []*ValidationError
+
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
@@ -0,0 +1,481 @@
+// Package strategy loads declarative strategy definitions from YAML or JSON files
+// and builds the trading algorithms they describe.
+//
+// A strategy file holds either a single strategy or a list under "strategies":
+//
+//	name: rsi_ema_trend
+//	combination: all
+//	adaptors:
+//	  - type: rsi
+//	    params: {period: 14, overbought: 70, oversold: 30}
+//	  - type: ema
+//	    params: {periods: [9, 21]}
+//	exit:
+//	  stop_loss_pct: 2
+//	  take_profit_pct: 5
+//	  max_bars: 20
+//	sizing:
+//	  method: fixed_fraction
+//	  value: 0.1
+//
+// Adaptors and the optional algorithm are looked up in the registry, the algorithm defaults to
+// "combination". An algorithm which does not take adaptors, such as "ml", is given without them:
+//
+//	name: model
+//	algorithm:
+//	  type: ml
+//	  params: {model: model.json}
+//
+// The exit rules and sizing apply when the strategy is run with backtesting.BacktestEngine.AddStrategy.
+// Stop loss and take profit are checked on the closes of the bars a position is held for.
+//
+// JSON files use the same keys.
+package strategy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/algorithm"
+	"github.com/vd09/trading-algorithm-backtesting-system/indicator_adaptor"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/registry"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	strategyKeys  = []string{"name", "combination", "algorithm", "adaptors", "exit", "sizing"}
+	componentKeys = []string{"type", "params"}
+	exitKeys      = []string{"stop_loss_pct", "take_profit_pct", "max_bars"}
+	sizingKeys    = []string{"method", "value"}
+)
+
+// LoadFile reads the strategy file at path and builds its trading algorithms.
+func LoadFile(ctx context.Context, path string, m monitor.Monitoring) ([]*Strategy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading strategy file: %w", err)
+	}
+	return load(ctx, path, data, m)
+}
+
+// Load parses strategy definitions from YAML or JSON content and builds their trading algorithms.
+func Load(ctx context.Context, data []byte, m monitor.Monitoring) ([]*Strategy, error) {
+	return load(ctx, "", data, m)
+}
+
+func load(ctx context.Context, file string, data []byte, m monitor.Monitoring) ([]*Strategy, error) {
+	definitions, err := parse(file, data)
+	if err != nil {
+		return nil, err
+	}
+
+	strategies := make([]*Strategy, 0, len(definitions))
+	for _, def := range definitions {
+		strategy, err := def.build(ctx, m)
+		if err != nil {
+			return nil, fmt.Errorf("strategy %s: %w", def.name, err)
+		}
+		strategies = append(strategies, strategy)
+	}
+	return strategies, nil
+}
+
+// componentDefinition is a validated adaptor or algorithm entry of a strategy file.
+type componentDefinition struct {
+	kind   string
+	values map[string]interface{}
+}
+
+// definition is a validated strategy entry of a strategy file.
+type definition struct {
+	name        string
+	combination CombinationRule
+	algorithm   componentDefinition
+	adaptors    []componentDefinition
+	exit        ExitRules
+	sizing      Sizing
+}
+
+func (def *definition) build(ctx context.Context, m monitor.Monitoring) (*Strategy, error) {
+	adaptors := make([]indicator_adaptor.IndicatorAdaptor, len(def.adaptors))
+	for i, adaptor := range def.adaptors {
+		built, err := registry.NewAdaptor(ctx, adaptor.kind, adaptor.values, m)
+		if err != nil {
+			return nil, fmt.Errorf("adaptor %s: %w", adaptor.kind, err)
+		}
+		adaptors[i] = built
+	}
+
+	subsets := [][]indicator_adaptor.IndicatorAdaptor{adaptors}
+	if def.combination == CombineCombinations {
+		subsets = algorithm.AdaptorSubsets(ctx, adaptors)
+		// Every subset runs on clones, so release what the adaptors built here hold, such as plugin processes.
+		for _, adaptor := range adaptors {
+			if closer, ok := adaptor.(io.Closer); ok {
+				closer.Close()
+			}
+		}
+	}
+
+	strategy := &Strategy{
+		Name:        def.name,
+		Combination: def.combination,
+		Exit:        def.exit,
+		Sizing:      def.sizing,
+	}
+	for _, subset := range subsets {
+		algo, err := registry.NewAlgorithm(ctx, def.algorithm.kind, def.algorithm.values, subset, m)
+		if err != nil {
+			return nil, fmt.Errorf("algorithm %s: %w", def.algorithm.kind, err)
+		}
+		strategy.Algorithms = append(strategy.Algorithms, algo)
+	}
+	return strategy, nil
+}
+
+// parse decodes and validates every strategy definition in data.
+func parse(file string, data []byte) ([]*definition, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		if file != "" {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		return nil, err
+	}
+
+	d := &decoder{file: file}
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		d.errorf(&document, "", "strategy file is empty")
+		return nil, d.errs
+	}
+
+	root := document.Content[0]
+	var definitions []*definition
+	if root.Kind == yaml.MappingNode && d.hasKey(root, "strategies") {
+		fields := d.mapping(root, "", []string{"strategies"})
+		list := fields["strategies"]
+		if list.Kind != yaml.SequenceNode || len(list.Content) == 0 {
+			d.errorf(list, "strategies", "must be a non-empty list")
+		} else {
+			for i, node := range list.Content {
+				definitions = append(definitions, d.strategy(node, fmt.Sprintf("strategies[%d]", i)))
+			}
+		}
+	} else {
+		definitions = append(definitions, d.strategy(root, ""))
+	}
+
+	if len(d.errs) > 0 {
+		return nil, d.errs
+	}
+	return definitions, nil
+}
+
+// decoder walks a YAML node tree and records validation errors with their positions.
+type decoder struct {
+	file string
+	errs ValidationErrors
+}
+
+func (d *decoder) errorf(node *yaml.Node, field, format string, args ...interface{}) {
+	d.errs = append(d.errs, &ValidationError{
+		File:   d.file,
+		Line:   node.Line,
+		Column: node.Column,
+		Field:  field,
+		Msg:    fmt.Sprintf(format, args...),
+	})
+}
+
+func (d *decoder) hasKey(node *yaml.Node, key string) bool {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return true
+		}
+	}
+	return false
+}
+
+// mapping returns the values of a mapping node by key, reporting unknown and duplicate keys.
+func (d *decoder) mapping(node *yaml.Node, field string, allowed []string) map[string]*yaml.Node {
+	fields := make(map[string]*yaml.Node)
+	if node.Kind != yaml.MappingNode {
+		d.errorf(node, field, "must be a mapping")
+		return fields
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if !contains(allowed, key.Value) {
+			d.errorf(key, joinField(field, key.Value), "unknown field, expected one of: %s", strings.Join(allowed, ", "))
+			continue
+		}
+		if _, exists := fields[key.Value]; exists {
+			d.errorf(key, joinField(field, key.Value), "duplicate field")
+			continue
+		}
+		fields[key.Value] = value
+	}
+	return fields
+}
+
+func (d *decoder) str(node *yaml.Node, field string) (string, bool) {
+	if node.Kind != yaml.ScalarNode || node.Tag != "!!str" {
+		d.errorf(node, field, "must be a string")
+		return "", false
+	}
+	return node.Value, true
+}
+
+func (d *decoder) float(node *yaml.Node, field string, min, max float64) (float64, bool) {
+	var value float64
+	if node.Kind != yaml.ScalarNode || (node.Tag != "!!int" && node.Tag != "!!float") || node.Decode(&value) != nil {
+		d.errorf(node, field, "must be a number")
+		return 0, false
+	}
+	if math.IsNaN(value) || value < min || value > max {
+		d.errorf(node, field, "must be between %v and %v, got %v", min, max, node.Value)
+		return 0, false
+	}
+	return value, true
+}
+
+func (d *decoder) int(node *yaml.Node, field string, min, max float64) (int, bool) {
+	var value int
+	if node.Kind != yaml.ScalarNode || node.Tag != "!!int" || node.Decode(&value) != nil {
+		d.errorf(node, field, "must be an integer")
+		return 0, false
+	}
+	if float64(value) < min || float64(value) > max {
+		d.errorf(node, field, "must be between %v and %v, got %d", min, max, value)
+		return 0, false
+	}
+	return value, true
+}
+
+func (d *decoder) strategy(node *yaml.Node, field string) *definition {
+	def := &definition{
+		combination: CombineAll,
+		sizing:      Sizing{Method: FixedQuantity, Value: 1},
+	}
+	fields := d.mapping(node, field, strategyKeys)
+	if node.Kind != yaml.MappingNode {
+		return def
+	}
+
+	if value, ok := fields["name"]; ok {
+		def.name, _ = d.str(value, joinField(field, "name"))
+	} else {
+		d.errorf(node, field, "missing required field \"name\"")
+	}
+
+	if value, ok := fields["combination"]; ok {
+		if rule, ok := d.str(value, joinField(field, "combination")); ok {
+			def.combination = CombinationRule(rule)
+			if def.combination != CombineAll && def.combination != CombineCombinations {
+				d.errorf(value, joinField(field, "combination"), "unknown combination rule %q, expected %q or %q", rule, CombineAll, CombineCombinations)
+			}
+		}
+	}
+
+	usesAdaptors := true
+	if value, ok := fields["algorithm"]; ok {
+		algorithmField := joinField(field, "algorithm")
+		if kind, typeNode, ok := d.componentType(value, algorithmField); ok {
+			entry, found := registry.Algorithm(kind)
+			if !found {
+				d.errorf(typeNode, joinField(algorithmField, "type"), "unknown algorithm type %q, expected one of: %s", kind, strings.Join(registry.AlgorithmNames(), ", "))
+			} else {
+				def.algorithm.kind = kind
+				def.algorithm.values, _ = d.params(value, algorithmField, entry.Params, entry.Resolve)
+				usesAdaptors = entry.UsesAdaptors
+			}
+		}
+	} else {
+		def.algorithm.kind = "combination"
+	}
+
+	if value, ok := fields["adaptors"]; ok {
+		adaptorsField := joinField(field, "adaptors")
+		if !usesAdaptors {
+			d.errorf(value, adaptorsField, "algorithm %s does not take adaptors", def.algorithm.kind)
+		} else if value.Kind != yaml.SequenceNode || len(value.Content) == 0 {
+			d.errorf(value, adaptorsField, "must be a non-empty list")
+		} else {
+			for i, adaptorNode := range value.Content {
+				if adaptor, ok := d.adaptor(adaptorNode, fmt.Sprintf("%s[%d]", adaptorsField, i)); ok {
+					def.adaptors = append(def.adaptors, adaptor)
+				}
+			}
+		}
+	} else if usesAdaptors {
+		d.errorf(node, field, "missing required field \"adaptors\"")
+	}
+	if value, ok := fields["combination"]; ok && !usesAdaptors && def.combination == CombineCombinations {
+		d.errorf(value, joinField(field, "combination"), "algorithm %s does not take adaptors to combine", def.algorithm.kind)
+	}
+
+	if value, ok := fields["exit"]; ok {
+		def.exit = d.exit(value, joinField(field, "exit"))
+	}
+	if value, ok := fields["sizing"]; ok {
+		def.sizing = d.sizing(value, joinField(field, "sizing"))
+	}
+	return def
+}
+
+// componentType returns the type of an adaptor or algorithm entry together with its node.
+func (d *decoder) componentType(node *yaml.Node, field string) (string, *yaml.Node, bool) {
+	fields := d.mapping(node, field, componentKeys)
+	typeNode, ok := fields["type"]
+	if !ok {
+		if node.Kind == yaml.MappingNode {
+			d.errorf(node, field, "missing required field \"type\"")
+		}
+		return "", nil, false
+	}
+	kind, ok := d.str(typeNode, joinField(field, "type"))
+	return kind, typeNode, ok
+}
+
+func (d *decoder) adaptor(node *yaml.Node, field string) (componentDefinition, bool) {
+	kind, typeNode, ok := d.componentType(node, field)
+	if !ok {
+		return componentDefinition{}, false
+	}
+	entry, ok := registry.Adaptor(kind)
+	if !ok {
+		d.errorf(typeNode, joinField(field, "type"), "unknown adaptor type %q, expected one of: %s", kind, strings.Join(registry.AdaptorNames(), ", "))
+		return componentDefinition{}, false
+	}
+	values, ok := d.params(node, field, entry.Params, entry.Resolve)
+	return componentDefinition{kind: kind, values: values}, ok
+}
+
+// params decodes the "params" mapping of a component and checks it with resolve, the registry's
+// validation of the component. A problem is reported at the node of the parameter it concerns.
+func (d *decoder) params(node *yaml.Node, field string, declared []registry.Param, resolve func(map[string]interface{}) (registry.Params, error)) (map[string]interface{}, bool) {
+	paramsField := joinField(field, "params")
+	paramsNode := &yaml.Node{Kind: yaml.MappingNode, Line: node.Line, Column: node.Column}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == "params" {
+			paramsNode = node.Content[i+1]
+		}
+	}
+	if paramsNode.Kind != yaml.MappingNode {
+		d.errorf(paramsNode, paramsField, "must be a mapping")
+		return nil, false
+	}
+
+	values := make(map[string]interface{})
+	keys := make(map[string]*yaml.Node)
+	nodes := make(map[string]*yaml.Node)
+	for i := 0; i+1 < len(paramsNode.Content); i += 2 {
+		key, value := paramsNode.Content[i], paramsNode.Content[i+1]
+		if _, exists := values[key.Value]; exists {
+			d.errorf(key, joinField(paramsField, key.Value), "duplicate field")
+			return nil, false
+		}
+		var decoded interface{}
+		if err := value.Decode(&decoded); err != nil {
+			d.errorf(value, joinField(paramsField, key.Value), "%s", err)
+			return nil, false
+		}
+		values[key.Value], keys[key.Value], nodes[key.Value] = decoded, key, value
+	}
+
+	if _, err := resolve(values); err != nil {
+		var paramErr *registry.ParamError
+		if !errors.As(err, &paramErr) {
+			d.errorf(paramsNode, paramsField, "%s", err)
+			return nil, false
+		}
+		errNode, errField := paramsNode, paramsField
+		if paramErr.Param != "" {
+			errField = joinField(paramsField, paramErr.Param)
+			if value, ok := nodes[paramErr.Param]; ok {
+				errNode = value
+				if !isDeclared(declared, paramErr.Param) {
+					errNode = keys[paramErr.Param]
+				}
+			}
+		}
+		d.errorf(errNode, errField, "%s", paramErr.Msg)
+		return nil, false
+	}
+	return values, true
+}
+
+func (d *decoder) exit(node *yaml.Node, field string) ExitRules {
+	var exit ExitRules
+	fields := d.mapping(node, field, exitKeys)
+	if value, ok := fields["stop_loss_pct"]; ok {
+		exit.StopLossPercent, _ = d.float(value, joinField(field, "stop_loss_pct"), 0, 100)
+	}
+	if value, ok := fields["take_profit_pct"]; ok {
+		exit.TakeProfitPercent, _ = d.float(value, joinField(field, "take_profit_pct"), 0, math.MaxFloat64)
+	}
+	if value, ok := fields["max_bars"]; ok {
+		exit.MaxBars, _ = d.int(value, joinField(field, "max_bars"), 0, math.MaxInt32)
+	}
+	return exit
+}
+
+func (d *decoder) sizing(node *yaml.Node, field string) Sizing {
+	sizing := Sizing{Method: FixedQuantity, Value: 1}
+	fields := d.mapping(node, field, sizingKeys)
+	if value, ok := fields["method"]; ok {
+		if method, ok := d.str(value, joinField(field, "method")); ok {
+			sizing.Method = SizingMethod(method)
+			if sizing.Method != FixedQuantity && sizing.Method != FixedFraction {
+				d.errorf(value, joinField(field, "method"), "unknown sizing method %q, expected %q or %q", method, FixedQuantity, FixedFraction)
+			}
+		}
+	}
+	if value, ok := fields["value"]; ok {
+		max := math.MaxFloat64
+		if sizing.Method == FixedFraction {
+			max = 1
+		}
+		if size, ok := d.float(value, joinField(field, "value"), 0, max); ok {
+			if size == 0 {
+				d.errorf(value, joinField(field, "value"), "must be greater than 0")
+			}
+			sizing.Value = size
+		}
+	} else if node.Kind == yaml.MappingNode {
+		d.errorf(node, field, "missing required field \"value\"")
+	}
+	return sizing
+}
+
+func isDeclared(declared []registry.Param, name string) bool {
+	for _, param := range declared {
+		if param.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func joinField(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+package strategy_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/config"
+	"github.com/vd09/trading-algorithm-backtesting-system/monitor"
+	"github.com/vd09/trading-algorithm-backtesting-system/strategy"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+func TestLoadFileYAML(t *testing.T) {
+	mock := test_utils.NewMockMetricsCollector(t)
+	strategies, err := strategy.LoadFile(context.Background(), "testdata/rsi_ema.yaml", mock)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	test_utils.AssertEqual(t, 2, len(strategies), "Number of strategies does not match")
+
+	trend := strategies[0]
+	test_utils.AssertEqual(t, "rsi_ema_trend", trend.Name, "Strategy name does not match")
+	test_utils.AssertEqual(t, 1, len(trend.Algorithms), "Combination rule all should build one algorithm")
+	test_utils.AssertEqual(t, "RSI_P(14)_OBT(70.000000)_OST(30.000000)_L(100)_EMA_9_21", trend.Algorithms[0].Name(), "Algorithm name does not match")
+	test_utils.AssertEqual(t, strategy.ExitRules{StopLossPercent: 2, TakeProfitPercent: 5, MaxBars: 20}, trend.Exit, "Exit rules do not match")
+	test_utils.AssertEqual(t, strategy.Sizing{Method: strategy.FixedFraction, Value: 0.1}, trend.Sizing, "Sizing does not match")
+
+	search := strategies[1]
+	test_utils.AssertEqual(t, strategy.CombineCombinations, search.Combination, "Combination rule does not match")
+	test_utils.AssertEqual(t, 3, len(search.Algorithms), "Combination rule combinations should build every subset")
+	test_utils.AssertEqual(t, strategy.Sizing{Method: strategy.FixedQuantity, Value: 1}, search.Sizing, "Default sizing does not match")
+}
+
+func TestLoadSharesMonitorAcrossCombinations(t *testing.T) {
+	// Prometheus registers collectors globally, so every strategy must reuse the caller's monitor.
+	config.InitConfig()
+	m := monitor.NewPrometheusMonitoring()
+	content := `strategies:
+  - name: first_search
+    combination: combinations
+    adaptors:
+      - type: rsi
+      - type: macd
+  - name: second_search
+    combination: combinations
+    adaptors:
+      - type: macd
+      - type: supertrend
+`
+	strategies, err := strategy.Load(context.Background(), []byte(content), m)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	test_utils.AssertEqual(t, 2, len(strategies), "Number of strategies does not match")
+	for _, s := range strategies {
+		test_utils.AssertEqual(t, 3, len(s.Algorithms), "Combination rule combinations should build every subset")
+	}
+}
+
+func TestLoadFileJSON(t *testing.T) {
+	mock := test_utils.NewMockMetricsCollector(t)
+	strategies, err := strategy.LoadFile(context.Background(), "testdata/macd.json", mock)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	test_utils.AssertEqual(t, 1, len(strategies), "Number of strategies does not match")
+	test_utils.AssertEqual(t, "MACD_12_26_9", strategies[0].Algorithms[0].Name(), "Algorithm name does not match")
+	test_utils.AssertEqual(t, 10, strategies[0].Exit.MaxBars, "Max bars does not match")
+}
+
+func TestLoadReportsLineOfInvalidValue(t *testing.T) {
+	mock := test_utils.NewMockMetricsCollector(t)
+	content := `name: broken
+adaptors:
+  - type: rsi
+    params:
+      period: 1
+  - type: unknown_indicator
+sizing:
+  method: fixed_fraction
+  value: 2
+`
+	_, err := strategy.Load(context.Background(), []byte(content), mock)
+
+	var validationErrors strategy.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		t.Fatalf("expected validation errors, got: %v", err)
+	}
+	test_utils.AssertEqual(t, 3, len(validationErrors), "Number of validation errors does not match")
+
+	test_utils.AssertEqual(t, 5, validationErrors[0].Line, "Invalid period line does not match")
+	test_utils.AssertEqual(t, "adaptors[0].params.period", validationErrors[0].Field, "Invalid period field does not match")
+	test_utils.AssertEqual(t, 6, validationErrors[1].Line, "Unknown adaptor line does not match")
+	test_utils.AssertEqual(t, 9, validationErrors[2].Line, "Invalid sizing line does not match")
+	test_utils.AssertTrue(t, strings.HasPrefix(validationErrors[0].Error(), "5:15: adaptors[0].params.period:"), "Unexpected message "+validationErrors[0].Error())
+}
+
+func TestLoadRejectsUnknownFields(t *testing.T) {
+	mock := test_utils.NewMockMetricsCollector(t)
+	content := `{
+  "name": "typo",
+  "adaptors": [{"type": "macd", "params": {"short_period": 30, "long_period": 26}}],
+  "exits": {"max_bars": 5}
+}`
+	_, err := strategy.Load(context.Background(), []byte(content), mock)
+
+	var validationErrors strategy.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		t.Fatalf("expected validation errors, got: %v", err)
+	}
+	test_utils.AssertEqual(t, 2, len(validationErrors), "Number of validation errors does not match")
+	test_utils.AssertEqual(t, "exits", validationErrors[0].Field, "Unknown field does not match")
+	test_utils.AssertEqual(t, 4, validationErrors[0].Line, "Unknown field line does not match")
+	test_utils.AssertEqual(t, 3, validationErrors[1].Line, "Invalid MACD periods line does not match")
+}
+
+func TestLoadAlgorithmFromRegistry(t *testing.T) {
+	mock := test_utils.NewMockMetricsCollector(t)
+	content := `strategies:
+  - name: confirmed
+    algorithm:
+      type: sequential
+      params: {within: 2}
+    adaptors:
+      - type: rsi
+      - type: expression
+        params:
+          buy: rsi(14) < 30
+  - name: confirmed_search
+    combination: combinations
+    algorithm:
+      type: stacking
+    adaptors:
+      - type: rsi
+      - type: macd
+`
+	strategies, err := strategy.Load(context.Background(), []byte(content), mock)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	test_utils.AssertEqual(t, 1, len(strategies[0].Algorithms), "Combination rule all should build one algorithm")
+	test_utils.AssertTrue(t, strings.HasPrefix(strategies[0].Algorithms[0].Name(), "Sequence("), "Expected a sequential algorithm, got "+strategies[0].Algorithms[0].Name())
+	test_utils.AssertEqual(t, 3, len(strategies[1].Algorithms), "Combination rule combinations should build every subset")
+	for _, algo := range strategies[1].Algorithms {
+		test_utils.AssertTrue(t, strings.HasPrefix(algo.Name(), "Stacking_"), "Expected a stacking algorithm, got "+algo.Name())
+	}
+}
+
+func TestLoadReportsRegistryErrors(t *testing.T) {
+	mock := test_utils.NewMockMetricsCollector(t)
+	content := `strategies:
+  - name: bad_rule
+    adaptors:
+      - type: expression
+        params:
+          sell: rsi(14) >
+  - name: bad_algorithm
+    algorithm:
+      type: voting
+    adaptors:
+      - type: rsi
+  - name: model_with_adaptors
+    algorithm:
+      type: ml
+      params: {model: model.json}
+    adaptors:
+      - type: rsi
+`
+	_, err := strategy.Load(context.Background(), []byte(content), mock)
+
+	var validationErrors strategy.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		t.Fatalf("expected validation errors, got: %v", err)
+	}
+	test_utils.AssertEqual(t, 3, len(validationErrors), "Number of validation errors does not match")
+	test_utils.AssertEqual(t, "strategies[0].adaptors[0].params.sell", validationErrors[0].Field, "Invalid rule field does not match")
+	test_utils.AssertEqual(t, 6, validationErrors[0].Line, "Invalid rule line does not match")
+	test_utils.AssertEqual(t, "strategies[1].algorithm.type", validationErrors[1].Field, "Unknown algorithm field does not match")
+	test_utils.AssertEqual(t, 9, validationErrors[1].Line, "Unknown algorithm line does not match")
+	test_utils.AssertEqual(t, "strategies[2].adaptors", validationErrors[2].Field, "Adaptors of an ml algorithm field does not match")
+	test_utils.AssertEqual(t, 17, validationErrors[2].Line, "Adaptors of an ml algorithm line does not match")
+}
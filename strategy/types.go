@@ -0,0 +1,60 @@
+package strategy
+
+import (
+	"github.com/vd09/trading-algorithm-backtesting-system/algorithm"
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+)
+
+// CombinationRule decides how the adaptors of a strategy are turned into trading algorithms.
+type CombinationRule string
+
+const (
+	// CombineAll builds a single algorithm over every adaptor.
+	CombineAll CombinationRule = "all"
+	// CombineCombinations builds one algorithm for every non-empty subset of the adaptors.
+	CombineCombinations CombinationRule = "combinations"
+)
+
+// SizingMethod represents the supported position sizing methods.
+type SizingMethod string
+
+const (
+	FixedQuantity SizingMethod = "fixed_quantity"
+	FixedFraction SizingMethod = "fixed_fraction"
+)
+
+// ExitRules describes when an open position should be closed, as percentages of the entry price.
+// A zero value disables the corresponding rule.
+type ExitRules struct {
+	StopLossPercent   float64
+	TakeProfitPercent float64
+	MaxBars           int
+}
+
+// Sizing describes how large a position opened by the strategy should be.
+type Sizing struct {
+	Method SizingMethod
+	Value  float64
+}
+
+// Quantity returns the size of a position opened at price, scaled by the strength of the signal.
+// Fixed fraction sizes invest Value times the capital.
+func (s Sizing) Quantity(capital, price float64, signal model.TradingSignal) float64 {
+	quantity := s.Value
+	if s.Method == FixedFraction {
+		if price <= 0 {
+			return 0
+		}
+		quantity = capital * s.Value / price
+	}
+	return quantity * signal.Strength
+}
+
+// Strategy is a validated strategy definition together with the algorithms built from it.
+type Strategy struct {
+	Name        string
+	Combination CombinationRule
+	Exit        ExitRules
+	Sizing      Sizing
+	Algorithms  []algorithm.TradingAlgorithm
+}
@@ -0,0 +1,20 @@
+package strategy_test
+
+import (
+	"testing"
+
+	"github.com/vd09/trading-algorithm-backtesting-system/model"
+	"github.com/vd09/trading-algorithm-backtesting-system/strategy"
+	"github.com/vd09/trading-algorithm-backtesting-system/utils/test_utils"
+)
+
+func TestSizingQuantity(t *testing.T) {
+	signal := model.TradingSignal{Action: model.Buy, Strength: 0.5}
+
+	fixed := strategy.Sizing{Method: strategy.FixedQuantity, Value: 10}
+	test_utils.AssertEqual(t, 5.0, fixed.Quantity(1000, 50, signal), "Fixed quantity should scale with the signal strength")
+
+	fraction := strategy.Sizing{Method: strategy.FixedFraction, Value: 0.1}
+	test_utils.AssertEqual(t, 1.0, fraction.Quantity(1000, 50, signal), "Fixed fraction should invest a share of the capital")
+	test_utils.AssertEqual(t, 0.0, fraction.Quantity(1000, 0, signal), "Fixed fraction needs a price")
+}
@@ -12,3 +12,12 @@ func B2I(b bool) int {
 func B2F(b bool) float64 {
 	return float64(B2I(b))
 }
+
+// AppendWindow appends the value and drops the oldest ones beyond size.
+func AppendWindow[T any](values []T, value T, size int) []T {
+	values = append(values, value)
+	if len(values) > size {
+		values = values[len(values)-size:]
+	}
+	return values
+}